@@ -0,0 +1,131 @@
+package announcements
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+// Endpoints collects all of the endpoints that compose the announcements service.
+type Endpoints struct {
+	ListAnnouncementsEndpoint  endpoint.Endpoint
+	GetAnnouncementEndpoint    endpoint.Endpoint
+	CreateAnnouncementEndpoint endpoint.Endpoint
+	UpdateAnnouncementEndpoint endpoint.Endpoint
+	DeleteAnnouncementEndpoint endpoint.Endpoint
+}
+
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		ListAnnouncementsEndpoint:  MakeListAnnouncementsEndpoint(s),
+		GetAnnouncementEndpoint:    MakeGetAnnouncementEndpoint(s),
+		CreateAnnouncementEndpoint: MakeCreateAnnouncementEndpoint(s),
+		UpdateAnnouncementEndpoint: MakeUpdateAnnouncementEndpoint(s),
+		DeleteAnnouncementEndpoint: MakeDeleteAnnouncementEndpoint(s),
+	}
+}
+
+func MakeListAnnouncementsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(listAnnouncementsRequest)
+		page, e := s.ListAnnouncements(ctx, req.ClassID, req.Query)
+		return listAnnouncementsResponse{page, e}, nil
+	}
+}
+
+type listAnnouncementsRequest struct {
+	ClassID uuid.UUID
+	Query   Query
+}
+
+type listAnnouncementsResponse struct {
+	Page  *Page
+	Error error
+}
+
+func (r listAnnouncementsResponse) error() error {
+	return r.Error
+}
+
+func MakeGetAnnouncementEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getAnnouncementRequest)
+		a, e := s.GetAnnouncement(ctx, req.ClassID, req.AnnouncementID)
+		return announcementResponse{a, e}, nil
+	}
+}
+
+type getAnnouncementRequest struct {
+	ClassID        uuid.UUID
+	AnnouncementID uuid.UUID
+}
+
+// announcementResponse carries a single announcement.
+type announcementResponse struct {
+	Announcement *models.Announcement
+	Error        error
+}
+
+func (r announcementResponse) error() error {
+	return r.Error
+}
+
+func MakeCreateAnnouncementEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createAnnouncementRequest)
+		a, e := s.CreateAnnouncement(ctx, req.ClassID, req.Draft)
+		return createAnnouncementResponse{a, e}, nil
+	}
+}
+
+type createAnnouncementRequest struct {
+	ClassID uuid.UUID
+	Draft   Draft
+}
+
+// createAnnouncementResponse is written with 201 Created.
+type createAnnouncementResponse struct {
+	Announcement *models.Announcement
+	Error        error
+}
+
+func (r createAnnouncementResponse) error() error {
+	return r.Error
+}
+
+func MakeUpdateAnnouncementEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(updateAnnouncementRequest)
+		a, e := s.UpdateAnnouncement(ctx, req.ClassID, req.AnnouncementID, req.Edit)
+		return announcementResponse{a, e}, nil
+	}
+}
+
+type updateAnnouncementRequest struct {
+	ClassID        uuid.UUID
+	AnnouncementID uuid.UUID
+	Edit           Edit
+}
+
+func MakeDeleteAnnouncementEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(deleteAnnouncementRequest)
+		e := s.DeleteAnnouncement(ctx, req.ClassID, req.AnnouncementID)
+		return deleteAnnouncementResponse{e}, nil
+	}
+}
+
+type deleteAnnouncementRequest struct {
+	ClassID        uuid.UUID
+	AnnouncementID uuid.UUID
+}
+
+type deleteAnnouncementResponse struct {
+	Error error
+}
+
+func (r deleteAnnouncementResponse) error() error {
+	return r.Error
+}
@@ -0,0 +1,73 @@
+package announcements
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/eventbus"
+	"github.com/studiously/classsvc/outbox"
+)
+
+const (
+	SubjCreateAnnouncement = "classes.announcements.create"
+	SubjUpdateAnnouncement = "classes.announcements.update"
+	SubjDeleteAnnouncement = "classes.announcements.delete"
+	// SubjPublishAnnouncement is published when an announcement becomes visible to the members of
+	// its class. Notification services fan out on this subject.
+	SubjPublishAnnouncement = "classes.announcements.publish"
+)
+
+// EventVersion is the version of the announcement event types.
+const EventVersion = 1
+
+// Announcement events are CloudEvents of these types. Their data is the announcement, except
+// for deletions, whose data has the class_id and announcement_id.
+var (
+	TypeCreateAnnouncement  = eventbus.Type{Name: "announcement.created", Version: EventVersion}
+	TypeUpdateAnnouncement  = eventbus.Type{Name: "announcement.updated", Version: EventVersion}
+	TypeDeleteAnnouncement  = eventbus.Type{Name: "announcement.deleted", Version: EventVersion}
+	TypePublishAnnouncement = eventbus.Type{Name: "announcement.published", Version: EventVersion}
+)
+
+// events maps every kind of change to the subject and type of its event.
+var events = map[ChangeKind]struct {
+	subject string
+	typ     eventbus.Type
+}{
+	ChangeCreate:  {SubjCreateAnnouncement, TypeCreateAnnouncement},
+	ChangeUpdate:  {SubjUpdateAnnouncement, TypeUpdateAnnouncement},
+	ChangeDelete:  {SubjDeleteAnnouncement, TypeDeleteAnnouncement},
+	ChangePublish: {SubjPublishAnnouncement, TypePublishAnnouncement},
+}
+
+// Outbox returns a RecordFunc that writes an event for every change to the outbox, in the
+// transaction that makes the change, to be relayed to the bus. Events are ordered with the
+// other events of the class.
+func Outbox() RecordFunc {
+	return func(ctx context.Context, tx *sql.Tx, change Change) error {
+		event, ok := events[change.Kind]
+		if !ok {
+			return fmt.Errorf("no subject for %s", change.Kind)
+		}
+		a := change.Announcement
+		var data interface{} = a
+		if change.Kind == ChangeDelete {
+			data = struct {
+				ClassID        uuid.UUID `json:"class_id"`
+				AnnouncementID uuid.UUID `json:"announcement_id"`
+			}{a.ClassID, a.ID}
+		}
+		payload, err := eventbus.EncodeCloudEvent(event.typ, subject(a.ClassID, a.ID), data)
+		if err != nil {
+			return err
+		}
+		return outbox.Write(tx, event.subject, a.ClassID.String(), payload)
+	}
+}
+
+// subject is the CloudEvents subject of an announcement.
+func subject(classID, announcementID uuid.UUID) string {
+	return "classes/" + classID.String() + "/announcements/" + announcementID.String()
+}
@@ -0,0 +1,85 @@
+package announcements
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/studiously/classsvc/models"
+)
+
+// Scheduler publishes announcements once their scheduled time arrives.
+type Scheduler struct {
+	db     *sql.DB
+	record RecordFunc
+}
+
+// NewScheduler returns a Scheduler that records a ChangePublish with record for every
+// announcement it publishes, in the transaction that publishes it. record may be nil.
+func NewScheduler(db *sql.DB, record RecordFunc) *Scheduler {
+	return &Scheduler{db, record}
+}
+
+// Publish publishes every announcement that is due and returns how many were published.
+// Each announcement is published exactly once, even with several schedulers running.
+func (s *Scheduler) Publish(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := tx.Query("UPDATE announcements SET published = TRUE " +
+		"WHERE NOT published AND publish_at <= now() RETURNING " + columns + ";")
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	var due []*models.Announcement
+	for rows.Next() {
+		a, err := scan(rows)
+		if err != nil {
+			rows.Close()
+			tx.Rollback()
+			return 0, err
+		}
+		due = append(due, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if s.record != nil {
+		for _, a := range due {
+			if err := s.record(ctx, tx, Change{ChangePublish, a}); err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return len(due), nil
+}
+
+// Run publishes due announcements every interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration, logger log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := s.Publish(ctx)
+		if err != nil {
+			logger.Log("error", err)
+		} else if n > 0 {
+			logger.Log("published", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
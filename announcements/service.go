@@ -0,0 +1,92 @@
+package announcements
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+var (
+	ErrUnauthorized = errors.New("token invalid or not found")
+	ErrNotFound     = errors.New("announcement not found or user is not allowed to access it")
+	ErrForbidden    = errors.New("only teachers and owners can manage announcements")
+	ErrInvalidBody  = errors.New("announcement must be between 1 and 2000 characters")
+	ErrPublished    = errors.New("announcement has already been published and cannot be rescheduled")
+)
+
+// MaxBodyLength is the maximum length of an announcement in characters.
+const MaxBodyLength = 2000
+
+// Draft is an announcement that has not been posted yet.
+type Draft struct {
+	Body   string `json:"body"`
+	Pinned bool   `json:"pinned"`
+	// PublishAt schedules the announcement. Nil publishes it immediately.
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+// Edit changes an announcement. Nil fields are left unchanged.
+type Edit struct {
+	Body      *string    `json:"body,omitempty"`
+	Pinned    *bool      `json:"pinned,omitempty"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+// Query selects a page of announcements.
+type Query struct {
+	Offset int
+	Limit  int
+}
+
+// Page is a page of announcements, pinned announcements first and then newest first.
+type Page struct {
+	Announcements []*models.Announcement `json:"announcements"`
+	// Total is the number of announcements across all pages.
+	Total int `json:"total"`
+}
+
+// ChangeKind names a kind of change to an announcement.
+type ChangeKind string
+
+const (
+	ChangeCreate ChangeKind = "create"
+	ChangeUpdate ChangeKind = "update"
+	ChangeDelete ChangeKind = "delete"
+	// ChangePublish is recorded once an announcement becomes visible to the members of its
+	// class, either when it is posted or when its scheduled time arrives.
+	ChangePublish ChangeKind = "publish"
+)
+
+// Change is a change to an announcement. Announcement is the announcement after the change, or
+// before it for deletions.
+type Change struct {
+	Kind         ChangeKind
+	Announcement *models.Announcement
+}
+
+// RecordFunc records a change as part of the transaction that makes it, so that the change is
+// only recorded if it is committed. An error rolls the change back.
+type RecordFunc func(ctx context.Context, tx *sql.Tx, change Change) error
+
+type Middleware func(Service) Service
+
+// Service manages the announcements feed of classes.
+// Members read announcements; teachers and owners post, edit and delete them.
+type Service interface {
+	// ListAnnouncements gets a page of the announcements of a class.
+	// Announcements scheduled for later are only listed for teachers and owners.
+	ListAnnouncements(ctx context.Context, classID uuid.UUID, q Query) (*Page, error)
+	// GetAnnouncement gets an announcement.
+	GetAnnouncement(ctx context.Context, classID, announcementID uuid.UUID) (*models.Announcement, error)
+	// CreateAnnouncement posts an announcement to a class.
+	CreateAnnouncement(ctx context.Context, classID uuid.UUID, draft Draft) (*models.Announcement, error)
+	// UpdateAnnouncement edits an announcement. Published announcements can be edited and
+	// pinned, but not rescheduled.
+	UpdateAnnouncement(ctx context.Context, classID, announcementID uuid.UUID, edit Edit) (*models.Announcement, error)
+	// DeleteAnnouncement deletes an announcement.
+	DeleteAnnouncement(ctx context.Context, classID, announcementID uuid.UUID) error
+}
@@ -0,0 +1,295 @@
+package announcements
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+// columns lists the columns of announcements in the order scan reads them.
+const columns = "id, class_id, author_id, body, pinned, publish_at, published, created_at, updated_at"
+
+type postgresService struct {
+	*sql.DB
+	record RecordFunc
+}
+
+func New(db *sql.DB) Service {
+	return &postgresService{db, nil}
+}
+
+// NewWithRecorder returns a Service that records every change to an announcement with record,
+// in the transaction that makes it. Scheduled announcements are published by a Scheduler.
+func NewWithRecorder(db *sql.DB, record RecordFunc) Service {
+	return &postgresService{db, record}
+}
+
+func (s *postgresService) ListAnnouncements(ctx context.Context, classID uuid.UUID, q Query) (*Page, error) {
+	member, err := s.member(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	where := "class_id = $1"
+	if !canManage(member) {
+		where += " AND publish_at <= now()"
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Limit < 0 {
+		q.Limit = 0
+	}
+	page := &Page{Announcements: []*models.Announcement{}}
+	err = s.QueryRowContext(ctx, "SELECT count(*) FROM announcements WHERE "+where+";", classID).Scan(&page.Total)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.QueryContext(ctx, "SELECT "+columns+" FROM announcements WHERE "+where+
+		" ORDER BY pinned DESC, publish_at DESC, id OFFSET $2 LIMIT $3;", classID, q.Offset, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Announcements = append(page.Announcements, a)
+	}
+	return page, rows.Err()
+}
+
+func (s *postgresService) GetAnnouncement(ctx context.Context, classID, announcementID uuid.UUID) (*models.Announcement, error) {
+	member, err := s.member(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	a, err := announcement(s, classID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage(member) && a.PublishAt.After(time.Now()) {
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+func (s *postgresService) CreateAnnouncement(ctx context.Context, classID uuid.UUID, draft Draft) (*models.Announcement, error) {
+	member, err := s.member(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage(member) {
+		return nil, ErrForbidden
+	}
+	body, err := validBody(draft.Body)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	a := &models.Announcement{
+		ID:        uuid.New(),
+		ClassID:   classID,
+		AuthorID:  member.UserID,
+		Body:      body,
+		Pinned:    draft.Pinned,
+		PublishAt: now,
+		Published: true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if draft.PublishAt != nil && draft.PublishAt.After(now) {
+		a.PublishAt = *draft.PublishAt
+		a.Published = false
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = a.Insert(tx)
+	if err == nil {
+		err = s.recordChange(ctx, tx, ChangeCreate, a)
+	}
+	if err == nil && a.Published {
+		err = s.recordChange(ctx, tx, ChangePublish, a)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *postgresService) UpdateAnnouncement(ctx context.Context, classID, announcementID uuid.UUID, edit Edit) (*models.Announcement, error) {
+	member, err := s.member(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage(member) {
+		return nil, ErrForbidden
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Lock the announcement so that the Scheduler cannot publish it while it is rescheduled.
+	_, err = tx.Exec("SELECT 1 FROM announcements WHERE id=$1 FOR UPDATE;", announcementID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	a, err := announcement(tx, classID, announcementID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	wasPublished := a.Published
+	if edit.Body != nil {
+		a.Body, err = validBody(*edit.Body)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if edit.Pinned != nil {
+		a.Pinned = *edit.Pinned
+	}
+	now := time.Now()
+	if edit.PublishAt != nil {
+		if a.Published {
+			tx.Rollback()
+			return nil, ErrPublished
+		}
+		a.PublishAt = *edit.PublishAt
+		if !a.PublishAt.After(now) {
+			a.PublishAt = now
+			a.Published = true
+		}
+	}
+	a.UpdatedAt = now
+	err = a.Update(tx)
+	if err == nil {
+		err = s.recordChange(ctx, tx, ChangeUpdate, a)
+	}
+	if err == nil && a.Published && !wasPublished {
+		err = s.recordChange(ctx, tx, ChangePublish, a)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *postgresService) DeleteAnnouncement(ctx context.Context, classID, announcementID uuid.UUID) error {
+	member, err := s.member(ctx, classID)
+	if err != nil {
+		return err
+	}
+	if !canManage(member) {
+		return ErrForbidden
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	a, err := announcement(tx, classID, announcementID)
+	if err == nil {
+		err = a.Delete(tx)
+	}
+	if err == nil {
+		err = s.recordChange(ctx, tx, ChangeDelete, a)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// member gets the current user's membership of a class. Users outside the class cannot see
+// that it exists.
+func (s *postgresService) member(ctx context.Context, classID uuid.UUID) (*models.Member, error) {
+	subj, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	member, err := models.MemberByUserIDClassID(s, subj, classID)
+	switch err {
+	case nil:
+		return member, nil
+	case sql.ErrNoRows:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (s *postgresService) recordChange(ctx context.Context, tx *sql.Tx, kind ChangeKind, a *models.Announcement) error {
+	if s.record == nil {
+		return nil
+	}
+	return s.record(ctx, tx, Change{kind, a})
+}
+
+// announcement gets an announcement of a class.
+func announcement(db models.XODB, classID, announcementID uuid.UUID) (*models.Announcement, error) {
+	a, err := models.AnnouncementByID(db, announcementID)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, err
+	case a.ClassID != classID:
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+// canManage reports whether a member can post, edit and delete announcements.
+func canManage(member *models.Member) bool {
+	return member.Owner || member.Role == models.UserRoleTeacher
+}
+
+func validBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if n := utf8.RuneCountInString(body); n == 0 || n > MaxBodyLength {
+		return "", ErrInvalidBody
+	}
+	return body, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (*models.Announcement, error) {
+	var a models.Announcement
+	err := row.Scan(&a.ID, &a.ClassID, &a.AuthorID, &a.Body, &a.Pinned, &a.PublishAt, &a.Published, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,256 @@
+package announcements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/introspector"
+)
+
+var ErrBadRequest = errors.New("the request is malformed or invalid")
+
+const (
+	// defaultLimit is the page size used when a request does not set limit.
+	defaultLimit = 20
+	// maxLimit limits the page size of requests.
+	maxLimit = 100
+)
+
+// MakeHTTPHandler serves the announcements of a class under /classes/{classID}/announcements.
+func MakeHTTPHandler(s Service, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	return MakeIdempotentHTTPHandler(s, nil, introspection, logger)
+}
+
+// MakeIdempotentHTTPHandler serves announcements like MakeHTTPHandler, and serves mutating requests
+// with a classsvc.IdempotencyKeyHeader at most once per key with idempotency, if it is not nil.
+func MakeIdempotentHTTPHandler(s Service, idempotency *classsvc.Idempotency, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	if idempotency != nil {
+		e = claim(e, idempotency)
+	}
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorLogger(logger),
+		httptransport.ServerErrorEncoder(encodeError),
+		httptransport.ServerBefore(introspector.ToHTTPContext()),
+	}
+
+	// GET /classes/:classID/announcements
+	// List announcements, paged with offset and limit.
+	r.Methods("GET").Path("/classes/{classID}/announcements").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.announcements.list")(e.ListAnnouncementsEndpoint),
+		DecodeListAnnouncementsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// POST /classes/:classID/announcements
+	// Post an announcement, optionally pinned or scheduled with publish_at.
+	r.Methods("POST").Path("/classes/{classID}/announcements").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.announcements.create")(e.CreateAnnouncementEndpoint),
+		DecodeCreateAnnouncementRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// GET /classes/:classID/announcements/:announcementID
+	r.Methods("GET").Path("/classes/{classID}/announcements/{announcementID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.announcements.get")(e.GetAnnouncementEndpoint),
+		DecodeGetAnnouncementRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// PATCH /classes/:classID/announcements/:announcementID
+	// Edit, pin or reschedule an announcement.
+	r.Methods("PATCH").Path("/classes/{classID}/announcements/{announcementID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.announcements.update")(e.UpdateAnnouncementEndpoint),
+		DecodeUpdateAnnouncementRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// DELETE /classes/:classID/announcements/:announcementID
+	r.Methods("DELETE").Path("/classes/{classID}/announcements/{announcementID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.announcements.delete")(e.DeleteAnnouncementEndpoint),
+		DecodeDeleteAnnouncementRequest,
+		encodeResponse,
+		options...,
+	))
+
+	var h http.Handler = r
+	if idempotency != nil {
+		h = idempotency.Handler(h)
+	}
+	return classsvc.WithCorrelationID(h, logger)
+}
+
+// claim returns e with every endpoint claiming idempotency keys with i.
+func claim(e Endpoints, i *classsvc.Idempotency) Endpoints {
+	return Endpoints{
+		ListAnnouncementsEndpoint:  i.Claim(e.ListAnnouncementsEndpoint),
+		GetAnnouncementEndpoint:    i.Claim(e.GetAnnouncementEndpoint),
+		CreateAnnouncementEndpoint: i.Claim(e.CreateAnnouncementEndpoint),
+		UpdateAnnouncementEndpoint: i.Claim(e.UpdateAnnouncementEndpoint),
+		DeleteAnnouncementEndpoint: i.Claim(e.DeleteAnnouncementEndpoint),
+	}
+}
+
+func DecodeListAnnouncementsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, err := uuid.Parse(mux.Vars(r)["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	q := r.URL.Query()
+	query := Query{Limit: defaultLimit}
+	if v := q.Get("offset"); v != "" {
+		query.Offset, err = strconv.Atoi(v)
+		if err != nil || query.Offset < 0 {
+			return nil, ErrBadRequest
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		query.Limit, err = strconv.Atoi(v)
+		if err != nil || query.Limit < 0 {
+			return nil, ErrBadRequest
+		}
+	}
+	if query.Limit > maxLimit {
+		query.Limit = maxLimit
+	}
+	return listAnnouncementsRequest{classID, query}, nil
+}
+
+func DecodeGetAnnouncementRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, announcementID, err := announcementVars(r)
+	if err != nil {
+		return nil, err
+	}
+	return getAnnouncementRequest{classID, announcementID}, nil
+}
+
+func DecodeCreateAnnouncementRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, err := uuid.Parse(mux.Vars(r)["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	req := createAnnouncementRequest{ClassID: classID}
+	if err := json.NewDecoder(r.Body).Decode(&req.Draft); err != nil {
+		return nil, ErrBadRequest
+	}
+	return req, nil
+}
+
+func DecodeUpdateAnnouncementRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, announcementID, err := announcementVars(r)
+	if err != nil {
+		return nil, err
+	}
+	req := updateAnnouncementRequest{ClassID: classID, AnnouncementID: announcementID}
+	if err := json.NewDecoder(r.Body).Decode(&req.Edit); err != nil {
+		return nil, ErrBadRequest
+	}
+	return req, nil
+}
+
+func DecodeDeleteAnnouncementRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, announcementID, err := announcementVars(r)
+	if err != nil {
+		return nil, err
+	}
+	return deleteAnnouncementRequest{classID, announcementID}, nil
+}
+
+func announcementVars(r *http.Request) (classID, announcementID uuid.UUID, err error) {
+	vars := mux.Vars(r)
+	classID, err = uuid.Parse(vars["classID"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrBadRequest
+	}
+	announcementID, err = uuid.Parse(vars["announcementID"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrNotFound
+	}
+	return classID, announcementID, nil
+}
+
+// errorer is implemented by all concrete response types that may contain
+// errors.
+type errorer interface {
+	error() error
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if e, ok := response.(errorer); ok && e.error() != nil {
+		encodeError(ctx, e.error(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch resp := response.(type) {
+	case listAnnouncementsResponse:
+		return json.NewEncoder(w).Encode(resp.Page)
+	case createAnnouncementResponse:
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(resp.Announcement)
+	case announcementResponse:
+		return json.NewEncoder(w).Encode(resp.Announcement)
+	case deleteAnnouncementResponse:
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	default:
+		return json.NewEncoder(w).Encode(response)
+	}
+}
+
+// problems answers errors as problem+json, like the class service does.
+var problems = classsvc.Problems{Code: errorCode, Status: codeFrom}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	problems.EncodeError(ctx, err, w)
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	case ErrForbidden:
+		return http.StatusForbidden
+	case ErrInvalidBody, ErrBadRequest:
+		return http.StatusBadRequest
+	case ErrPublished:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorCode gives the stable code of err, which clients can match on.
+func errorCode(err error) string {
+	switch err {
+	case ErrNotFound:
+		return "not_found"
+	case ErrUnauthorized:
+		return "unauthorized"
+	case ErrForbidden:
+		return "forbidden"
+	case ErrInvalidBody:
+		return "invalid_body"
+	case ErrBadRequest:
+		return "bad_request"
+	case ErrPublished:
+		return "published"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,120 @@
+package classsvc
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+// ChangeKind identifies what a Change did.
+type ChangeKind string
+
+const (
+	ChangeCreateClass           ChangeKind = "CreateClass"
+	ChangeUpdateClass           ChangeKind = "UpdateClass"
+	ChangeDeleteClass           ChangeKind = "DeleteClass"
+	ChangeJoinClass             ChangeKind = "JoinClass"
+	ChangeLeaveClass            ChangeKind = "LeaveClass"
+	ChangeSetRole               ChangeKind = "SetRole"
+	ChangeTransferOwnership     ChangeKind = "TransferOwnership"
+	ChangeSetCapacity           ChangeKind = "SetCapacity"
+	ChangeJoinWaitlist          ChangeKind = "JoinWaitlist"
+	ChangeLeaveWaitlist         ChangeKind = "LeaveWaitlist"
+	ChangePromoteWaitlist       ChangeKind = "PromoteWaitlist"
+	ChangeSetEnrollmentSettings ChangeKind = "SetEnrollmentSettings"
+	ChangeLinkGuardian          ChangeKind = "LinkGuardian"
+	ChangeUnlinkGuardian        ChangeKind = "UnlinkGuardian"
+)
+
+// Change describes a change made by the Service. Before and After hold the full state of the
+// changed resource: a *ClassState, *models.Member, *models.WaitlistEntry, *Capacity,
+// *EnrollmentSettings or *models.GuardianLink. Before is nil for resources that were created and
+// After is nil for resources that were removed.
+type Change struct {
+	Kind ChangeKind
+	// System is set for changes the service makes on its own, such as promotions from a
+	// waitlist. They have no actor.
+	System  bool
+	ClassID *uuid.UUID
+	UserID  *uuid.UUID
+	Before  interface{}
+	After   interface{}
+}
+
+// RecordFunc records a change inside the transaction that makes it, so that the record is
+// committed or rolled back together with the change. An error aborts the change.
+type RecordFunc func(ctx context.Context, tx *sql.Tx, change Change) error
+
+// Records returns a RecordFunc that records every change with each of record in turn.
+func Records(record ...RecordFunc) RecordFunc {
+	return func(ctx context.Context, tx *sql.Tx, change Change) error {
+		for _, r := range record {
+			if err := r(ctx, tx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ClassState is the state of a class carried by class changes.
+type ClassState struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	CurrentUnit uuid.UUID `json:"current_unit"`
+	Active      bool      `json:"active"`
+	Version     int64     `json:"version"`
+}
+
+func classState(class *models.Class) *ClassState {
+	return &ClassState{class.ID, class.Name, class.CurrentUnit, class.Active, class.Version}
+}
+
+func (s *postgresService) recordChange(ctx context.Context, tx *sql.Tx, change Change) error {
+	if s.record == nil {
+		return nil
+	}
+	return s.record(ctx, tx, change)
+}
+
+// recordPromotions records the enrollment of users promoted from a waitlist.
+func (s *postgresService) recordPromotions(ctx context.Context, tx *sql.Tx, promoted []*models.WaitlistEntry) error {
+	for _, entry := range promoted {
+		member, err := models.MemberByUserIDClassID(tx, entry.UserID, entry.ClassID)
+		if err != nil {
+			return err
+		}
+		err = s.recordChange(ctx, tx, Change{
+			Kind:    ChangePromoteWaitlist,
+			System:  true,
+			ClassID: &entry.ClassID,
+			UserID:  &entry.UserID,
+			Before:  entry,
+			After:   member,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memberOrNil gets a member of a class, or nil if the user is not a member.
+func memberOrNil(db models.XODB, classID, userID uuid.UUID) (*models.Member, error) {
+	member, err := models.MemberByUserIDClassID(db, userID, classID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return member, err
+}
+
+// waitlistEntryOrNil gets the waitlist entry of a user, or nil if the user is not waiting.
+func waitlistEntryOrNil(db models.XODB, classID, userID uuid.UUID) (*models.WaitlistEntry, error) {
+	entry, err := models.WaitlistEntryByClassIDUserID(db, classID, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return entry, err
+}
@@ -0,0 +1,229 @@
+package classsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/circuitbreaker"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/sd"
+	"github.com/go-kit/kit/sd/lb"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+	"github.com/sony/gobreaker"
+	"github.com/studiously/introspector"
+)
+
+// Client calls the class service over HTTP, spread across every instance known to a
+// discovery source. Its fields may be changed before Endpoints is called.
+type Client struct {
+	// HTTPClient sends the requests.
+	HTTPClient *http.Client
+	// Timeout bounds a call, including its retries.
+	Timeout time.Duration
+	// AttemptTimeout bounds a single attempt at a call.
+	AttemptTimeout time.Duration
+	// MaxAttempts is the number of times a call is tried. POST and PATCH calls are sent with an
+	// idempotency key, so that the service answers their retries with the first response.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the delay before a call is tried again, which doubles
+	// with every failed attempt.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Breaker configures the circuit breaker kept for each instance. Its Name is replaced
+	// with the instance.
+	Breaker gobreaker.Settings
+
+	mtx      sync.Mutex
+	breakers map[string]*gobreaker.CircuitBreaker
+}
+
+// NewClient returns a Client with defaults suited to calls between services.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient:     &http.Client{},
+		Timeout:        10 * time.Second,
+		AttemptTimeout: 3 * time.Second,
+		MaxAttempts:    3,
+		MinBackoff:     50 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Breaker: gobreaker.Settings{
+			Timeout: 30 * time.Second,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= 5
+			},
+		},
+		breakers: make(map[string]*gobreaker.CircuitBreaker),
+	}
+}
+
+// Instances returns Endpoints that balance calls across a fixed set of instances, given as
+// host:port or as URLs.
+func (c *Client) Instances(instances ...string) Endpoints {
+	return c.Endpoints(sd.FixedInstancer(instances), log.NewNopLogger())
+}
+
+// Endpoints returns Endpoints that balance calls round-robin across the instances reported by
+// instancer, such as a Consul or DNS SRV instancer from go-kit's sd packages. Errors returned
+// by the service are mapped back to the errors of this package; only transport failures and
+// 5xx responses count against an instance's circuit breaker or are retried.
+func (c *Client) Endpoints(instancer sd.Instancer, logger log.Logger) Endpoints {
+	return Endpoints{
+		ListClassesEndpoint:           c.endpoint(instancer, "GET", EncodeListClassesRequest, decodeHTTPResponse(DecodeListClassesResponse, listClassesResponse{}), logger),
+		GetClassEndpoint:              c.endpoint(instancer, "GET", EncodeGetClassRequest, decodeHTTPResponse(DecodeGetClassResponse, getClassResponse{}), logger),
+		CreateClassEndpoint:           c.endpoint(instancer, "POST", EncodeCreateClassRequest, decodeHTTPResponse(DecodeCreateClassResponse, createClassResponse{}), logger),
+		UpdateClassEndpoint:           c.endpoint(instancer, "PATCH", EncodeUpdateClassRequest, decodeHTTPResponse(DecodeUpdateClassResponse, updateClassResponse{}), logger),
+		DeleteClassEndpoint:           c.endpoint(instancer, "DELETE", EncodeDeleteClassRequest, decodeHTTPResponse(DecodeDeleteClassResponse, deleteClassResponse{}), logger),
+		JoinClassEndpoint:             c.endpoint(instancer, "POST", EncodeJoinClassRequest, decodeHTTPResponse(DecodeJoinClassResponse, joinClassResponse{}), logger),
+		SetRoleEndpoint:               c.endpoint(instancer, "PATCH", EncodeSetRoleRequest, decodeHTTPResponse(DecodeSetRoleResponse, setRoleResponse{}), logger),
+		LeaveClassEndpoint:            c.endpoint(instancer, "DELETE", EncodeLeaveClassRequest, decodeHTTPResponse(DecodeLeaveClassResponse, leaveClassResponse{}), logger),
+		ListMembersEndpoint:           c.endpoint(instancer, "GET", EncodeListMembersRequest, decodeHTTPResponse(DecodeListMembersResponse, listMembersResponse{}), logger),
+		GetMemberEndpoint:             c.endpoint(instancer, "GET", EncodeGetMemberRequest, decodeHTTPResponse(DecodeGetMemberResponse, getMemberResponse{}), logger),
+		GetCapacityEndpoint:           c.endpoint(instancer, "GET", EncodeGetCapacityRequest, decodeHTTPResponse(DecodeGetCapacityResponse, getCapacityResponse{}), logger),
+		SetCapacityEndpoint:           c.endpoint(instancer, "PUT", EncodeSetCapacityRequest, decodeHTTPResponse(DecodeSetCapacityResponse, setCapacityResponse{}), logger),
+		ListWaitlistEndpoint:          c.endpoint(instancer, "GET", EncodeListWaitlistRequest, decodeHTTPResponse(DecodeListWaitlistResponse, listWaitlistResponse{}), logger),
+		GetEnrollmentSettingsEndpoint: c.endpoint(instancer, "GET", EncodeGetEnrollmentSettingsRequest, decodeHTTPResponse(DecodeGetEnrollmentSettingsResponse, getEnrollmentSettingsResponse{}), logger),
+		SetEnrollmentSettingsEndpoint: c.endpoint(instancer, "PUT", EncodeSetEnrollmentSettingsRequest, decodeHTTPResponse(DecodeSetEnrollmentSettingsResponse, setEnrollmentSettingsResponse{}), logger),
+		LinkGuardianEndpoint:          c.endpoint(instancer, "PUT", EncodeLinkGuardianRequest, decodeHTTPResponse(DecodeLinkGuardianResponse, linkGuardianResponse{}), logger),
+		UnlinkGuardianEndpoint:        c.endpoint(instancer, "DELETE", EncodeUnlinkGuardianRequest, decodeHTTPResponse(DecodeUnlinkGuardianResponse, unlinkGuardianResponse{}), logger),
+		ListStudentsEndpoint:          c.endpoint(instancer, "GET", EncodeListStudentsRequest, decodeHTTPResponse(DecodeListStudentsResponse, listStudentsResponse{}), logger),
+	}
+}
+
+func (c *Client) endpoint(instancer sd.Instancer, method string, enc httptransport.EncodeRequestFunc, dec httptransport.DecodeResponseFunc, logger log.Logger) endpoint.Endpoint {
+	factory := func(instance string) (endpoint.Endpoint, io.Closer, error) {
+		tgt, err := instanceURL(instance)
+		if err != nil {
+			return nil, nil, err
+		}
+		e := httptransport.NewClient(method, tgt, enc, dec,
+			httptransport.SetClient(c.HTTPClient),
+			httptransport.ClientBefore(introspector.FromHTTPContext(), lockOverrideFromContext, versionFromContext, correlationIDToHTTP, idempotencyKeyToHTTP),
+		).Endpoint()
+		e = attemptTimeout(c.AttemptTimeout)(e)
+		e = circuitbreaker.Gobreaker(c.breaker(instance))(e)
+		return e, nil, nil
+	}
+	balancer := lb.NewRoundRobin(sd.NewEndpointer(instancer, factory, logger))
+	retry := lb.RetryWithCallback(c.Timeout, balancer, func(n int, err error) (bool, error) {
+		if n >= c.MaxAttempts {
+			return false, nil
+		}
+		time.Sleep(c.backoff(n))
+		return true, nil
+	})
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		if (method == "POST" || method == "PATCH") && IdempotencyKey(ctx) == "" {
+			ctx = WithIdempotencyKey(ctx, uuid.New().String())
+		}
+		response, err := retry(ctx, request)
+		if retryErr, ok := err.(lb.RetryError); ok {
+			return nil, retryErr.Final
+		}
+		return response, err
+	}
+}
+
+// breaker returns the circuit breaker of an instance, which is shared by every method so that
+// an instance that is down is skipped by all of them.
+func (c *Client) breaker(instance string) *gobreaker.CircuitBreaker {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.breakers == nil {
+		c.breakers = make(map[string]*gobreaker.CircuitBreaker)
+	}
+	cb, ok := c.breakers[instance]
+	if !ok {
+		settings := c.Breaker
+		settings.Name = instance
+		cb = gobreaker.NewCircuitBreaker(settings)
+		c.breakers[instance] = cb
+	}
+	return cb
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.MinBackoff << uint(attempt-1)
+	if d > c.MaxBackoff || d <= 0 {
+		d = c.MaxBackoff
+	}
+	return d
+}
+
+func attemptTimeout(timeout time.Duration) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+
+func instanceURL(instance string) (*url.URL, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return nil, err
+	}
+	tgt.Path = ""
+	return tgt, nil
+}
+
+// decodeHTTPResponse wraps the decoder of responses of the same type as v. Errors returned by
+// the service are set as the Error of the response, like those of a local Service; 5xx
+// responses are returned as errors, so that they count as failures of the instance.
+func decodeHTTPResponse(dec httptransport.DecodeResponseFunc, v interface{}) httptransport.DecodeResponseFunc {
+	t := reflect.TypeOf(v)
+	return func(ctx context.Context, resp *http.Response) (interface{}, error) {
+		if resp.StatusCode < 300 && resp.StatusCode != http.StatusAccepted {
+			return dec(ctx, resp)
+		}
+		err := errorFromHTTPResponse(resp)
+		if resp.StatusCode >= 500 {
+			return nil, err
+		}
+		response := reflect.New(t).Elem()
+		response.FieldByName("Error").Set(reflect.ValueOf(err))
+		return response.Interface(), nil
+	}
+}
+
+func errorFromHTTPResponse(resp *http.Response) error {
+	var p Problem
+	if err := json.NewDecoder(resp.Body).Decode(&p); err == nil && p.Code != "" {
+		if p.Code == "validation_failed" {
+			return &ValidationError{Fields: p.Errors}
+		}
+		for known, code := range errorCodes {
+			if code == p.Code {
+				return known
+			}
+		}
+		return errors.New(p.Detail)
+	}
+	// Routes that do not exist, and proxies in front of the service, reply without a body.
+	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		return errors.New(http.StatusText(resp.StatusCode))
+	}
+}
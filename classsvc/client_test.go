@@ -0,0 +1,181 @@
+package classsvc
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+// activeTokens accepts every token, with every scope it is asked for.
+type activeTokens struct {
+	subject uuid.UUID
+}
+
+func (i activeTokens) IntrospectToken(_ context.Context, _ string, scopes ...string) (*oauth2.Introspection, error) {
+	return &oauth2.Introspection{Active: true, Subject: i.subject.String(), Scope: strings.Join(scopes, " ")}, nil
+}
+
+// recordingService records the calls made to it, and fails them with err.
+type recordingService struct {
+	Service
+	err error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (s *recordingService) record(format string, args ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, fmt.Sprintf(format, args...))
+	return s.err
+}
+
+func (s *recordingService) CreateClass(_ context.Context, name string) (*uuid.UUID, error) {
+	id := uuid.Nil
+	return &id, s.record("CreateClass %s", name)
+}
+
+func (s *recordingService) UpdateClass(_ context.Context, classID uuid.UUID, name *string, _ *uuid.UUID) error {
+	return s.record("UpdateClass %s %s", classID, *name)
+}
+
+func (s *recordingService) DeleteClass(_ context.Context, classID uuid.UUID) error {
+	return s.record("DeleteClass %s", classID)
+}
+
+func (s *recordingService) JoinClass(_ context.Context, classID uuid.UUID) error {
+	return s.record("JoinClass %s", classID)
+}
+
+func (s *recordingService) LeaveClass(_ context.Context, userID *uuid.UUID, classID uuid.UUID) error {
+	return s.record("LeaveClass %s %s", classID, *userID)
+}
+
+func (s *recordingService) SetRole(_ context.Context, classID, userID uuid.UUID, role models.UserRole) error {
+	return s.record("SetRole %s %s %s", classID, userID, role)
+}
+
+func (s *recordingService) SetCapacity(_ context.Context, classID uuid.UUID, capacity Capacity) error {
+	return s.record("SetCapacity %s %d", classID, *capacity.Seats)
+}
+
+func (s *recordingService) SetEnrollmentSettings(_ context.Context, classID uuid.UUID, settings EnrollmentSettings) error {
+	return s.record("SetEnrollmentSettings %s %t", classID, settings.Locked)
+}
+
+func (s *recordingService) LinkGuardian(_ context.Context, guardianID, studentID uuid.UUID) error {
+	return s.record("LinkGuardian %s %s", guardianID, studentID)
+}
+
+func (s *recordingService) UnlinkGuardian(_ context.Context, guardianID, studentID uuid.UUID) error {
+	return s.record("UnlinkGuardian %s %s", guardianID, studentID)
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	classID, userID := uuid.New(), uuid.New()
+	name, seats := "Algebra I", 30
+	tests := []struct {
+		name string
+		call func(ctx context.Context, s Service) error
+		want string
+	}{
+		{"CreateClass", func(ctx context.Context, s Service) error {
+			_, err := s.CreateClass(ctx, name)
+			return err
+		}, "CreateClass Algebra I"},
+		{"UpdateClass", func(ctx context.Context, s Service) error {
+			return s.UpdateClass(ctx, classID, &name, nil)
+		}, fmt.Sprintf("UpdateClass %s Algebra I", classID)},
+		{"DeleteClass", func(ctx context.Context, s Service) error {
+			return s.DeleteClass(ctx, classID)
+		}, fmt.Sprintf("DeleteClass %s", classID)},
+		{"JoinClass", func(ctx context.Context, s Service) error {
+			return s.JoinClass(ctx, classID)
+		}, fmt.Sprintf("JoinClass %s", classID)},
+		{"LeaveClass", func(ctx context.Context, s Service) error {
+			return s.LeaveClass(ctx, &userID, classID)
+		}, fmt.Sprintf("LeaveClass %s %s", classID, userID)},
+		{"SetRole", func(ctx context.Context, s Service) error {
+			return s.SetRole(ctx, classID, userID, models.UserRoleTeacher)
+		}, fmt.Sprintf("SetRole %s %s teacher", classID, userID)},
+		{"SetCapacity", func(ctx context.Context, s Service) error {
+			return s.SetCapacity(ctx, classID, Capacity{Seats: &seats})
+		}, fmt.Sprintf("SetCapacity %s 30", classID)},
+		{"SetEnrollmentSettings", func(ctx context.Context, s Service) error {
+			return s.SetEnrollmentSettings(ctx, classID, EnrollmentSettings{Locked: true})
+		}, fmt.Sprintf("SetEnrollmentSettings %s true", classID)},
+		{"LinkGuardian", func(ctx context.Context, s Service) error {
+			return s.LinkGuardian(ctx, userID, classID)
+		}, fmt.Sprintf("LinkGuardian %s %s", userID, classID)},
+		{"UnlinkGuardian", func(ctx context.Context, s Service) error {
+			return s.UnlinkGuardian(ctx, userID, classID)
+		}, fmt.Sprintf("UnlinkGuardian %s %s", userID, classID)},
+	}
+
+	service := &recordingService{}
+	server := httptest.NewServer(MakeHTTPHandler(service, activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer server.Close()
+	direct, err := MakeClientEndpoints(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients := map[string]Service{
+		"MakeClientEndpoints": direct,
+		"Client":              NewClient().Instances(server.URL),
+	}
+	ctx := context.WithValue(context.Background(), introspector.TokenContextKey, "token")
+
+	for clientName, client := range clients {
+		for _, tt := range tests {
+			t.Run(clientName+"/"+tt.name, func(t *testing.T) {
+				service.calls = nil
+				if err := tt.call(ctx, client); err != nil {
+					t.Fatalf("%s failed: %v", tt.name, err)
+				}
+				if want := []string{tt.want}; !reflect.DeepEqual(service.calls, want) {
+					t.Errorf("service got %q, want %q", service.calls, want)
+				}
+			})
+		}
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	classID := uuid.New()
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{ErrNotFound, ErrNotFound},
+		{ErrForbidden, ErrForbidden},
+		{ErrRosterLocked, ErrRosterLocked},
+		{ErrVersionMismatch, ErrVersionMismatch},
+		{fmt.Errorf("pq: relation \"members\" does not exist"), ErrInternal},
+	}
+	for _, tt := range tests {
+		service := &recordingService{err: tt.err}
+		server := httptest.NewServer(MakeHTTPHandler(service, activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+		client, err := MakeClientEndpoints(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), introspector.TokenContextKey, "token")
+		err = client.DeleteClass(ctx, classID)
+		server.Close()
+		if err == nil || err.Error() != tt.want.Error() {
+			t.Errorf("DeleteClass failing with %q returned %v, want %v", tt.err, err, tt.want)
+		}
+	}
+}
@@ -2,14 +2,20 @@ package classsvc
 
 import (
 	"context"
-	"net/url"
+	"fmt"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-kit/kit/endpoint"
 	httptransport "github.com/go-kit/kit/transport/http"
+	natstransport "github.com/go-kit/kit/transport/nats"
 	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
 	"github.com/studiously/classsvc/models"
 	"github.com/studiously/introspector"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Endpoints collects all of the endpoints that compose a class service. It's
@@ -27,64 +33,115 @@ import (
 // construct individual endpoints using transport/http.NewClient, combine them
 // into an Endpoints, and return it to the caller as a Service.
 type Endpoints struct {
-	ListClassesEndpoint endpoint.Endpoint
-	GetClassEndpoint    endpoint.Endpoint
-	CreateClassEndpoint endpoint.Endpoint
-	UpdateClassEndpoint endpoint.Endpoint
-	DeleteClassEndpoint endpoint.Endpoint
-	JoinClassEndpoint   endpoint.Endpoint
-	SetRoleEndpoint     endpoint.Endpoint
-	LeaveClassEndpoint  endpoint.Endpoint
-	ListMembersEndpoint endpoint.Endpoint
-	GetMemberEndpoint   endpoint.Endpoint
+	ListClassesEndpoint           endpoint.Endpoint
+	GetClassEndpoint              endpoint.Endpoint
+	CreateClassEndpoint           endpoint.Endpoint
+	UpdateClassEndpoint           endpoint.Endpoint
+	DeleteClassEndpoint           endpoint.Endpoint
+	JoinClassEndpoint             endpoint.Endpoint
+	SetRoleEndpoint               endpoint.Endpoint
+	LeaveClassEndpoint            endpoint.Endpoint
+	ListMembersEndpoint           endpoint.Endpoint
+	GetMemberEndpoint             endpoint.Endpoint
+	GetCapacityEndpoint           endpoint.Endpoint
+	SetCapacityEndpoint           endpoint.Endpoint
+	ListWaitlistEndpoint          endpoint.Endpoint
+	GetEnrollmentSettingsEndpoint endpoint.Endpoint
+	SetEnrollmentSettingsEndpoint endpoint.Endpoint
+	LinkGuardianEndpoint          endpoint.Endpoint
+	UnlinkGuardianEndpoint        endpoint.Endpoint
+	ListStudentsEndpoint          endpoint.Endpoint
 }
 
 func MakeServerEndpoints(s Service) Endpoints {
 	return Endpoints{
-		ListClassesEndpoint: MakeListClassesEndpoint(s),
-		GetClassEndpoint:    MakeGetClassEndpoint(s),
-		CreateClassEndpoint: MakeCreateClassEndpoint(s),
-		UpdateClassEndpoint: MakeUpdateClassEndpoint(s),
-		DeleteClassEndpoint: MakeDeleteClassEndpoint(s),
-		JoinClassEndpoint:   MakeJoinClassEndpoint(s),
-		SetRoleEndpoint:     MakeSetRoleEndpoint(s),
-		LeaveClassEndpoint:  MakeLeaveClassEndpoint(s),
-		ListMembersEndpoint: MakeListMembersEndpoint(s),
-		GetMemberEndpoint:   MakeGetMemberEndpoint(s),
+		ListClassesEndpoint:           MakeListClassesEndpoint(s),
+		GetClassEndpoint:              MakeGetClassEndpoint(s),
+		CreateClassEndpoint:           MakeCreateClassEndpoint(s),
+		UpdateClassEndpoint:           MakeUpdateClassEndpoint(s),
+		DeleteClassEndpoint:           MakeDeleteClassEndpoint(s),
+		JoinClassEndpoint:             MakeJoinClassEndpoint(s),
+		SetRoleEndpoint:               MakeSetRoleEndpoint(s),
+		LeaveClassEndpoint:            MakeLeaveClassEndpoint(s),
+		ListMembersEndpoint:           MakeListMembersEndpoint(s),
+		GetMemberEndpoint:             MakeGetMemberEndpoint(s),
+		GetCapacityEndpoint:           MakeGetCapacityEndpoint(s),
+		SetCapacityEndpoint:           MakeSetCapacityEndpoint(s),
+		ListWaitlistEndpoint:          MakeListWaitlistEndpoint(s),
+		GetEnrollmentSettingsEndpoint: MakeGetEnrollmentSettingsEndpoint(s),
+		SetEnrollmentSettingsEndpoint: MakeSetEnrollmentSettingsEndpoint(s),
+		LinkGuardianEndpoint:          MakeLinkGuardianEndpoint(s),
+		UnlinkGuardianEndpoint:        MakeUnlinkGuardianEndpoint(s),
+		ListStudentsEndpoint:          MakeListStudentsEndpoint(s),
 	}
 }
 
 // MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
 // the corresponding method on the remote instance, via a transport/http.Client.
-// Useful in a classsvc client.
+// Useful in a classsvc client. Callers of several instances should use a Client,
+// which also retries calls and trips circuit breakers.
 func MakeClientEndpoints(instance string) (Endpoints, error) {
-	if !strings.HasPrefix(instance, "http") {
-		instance = "http://" + instance
-	}
-	tgt, err := url.Parse(instance)
+	tgt, err := instanceURL(instance)
 	if err != nil {
 		return Endpoints{}, err
 	}
-	tgt.Path = ""
 
 	options := []httptransport.ClientOption{
-		httptransport.ClientBefore(introspector.FromHTTPContext()),
+		httptransport.ClientBefore(introspector.FromHTTPContext(), lockOverrideFromContext, versionFromContext, correlationIDToHTTP),
 	}
 
 	return Endpoints{
-		ListClassesEndpoint: httptransport.NewClient("GET", tgt, EncodeListClassesRequest, DecodeListClassesResponse, options...).Endpoint(),
-		GetClassEndpoint:    httptransport.NewClient("GET", tgt, EncodeGetClassRequest, DecodeGetClassResponse, options...).Endpoint(),
-		CreateClassEndpoint: httptransport.NewClient("POST", tgt, EncodeCreateClassRequest, DecodeCreateClassResponse, options...).Endpoint(),
-		UpdateClassEndpoint: httptransport.NewClient("PATCH", tgt, EncodeUpdateClassRequest, DecodeUpdateClassResponse, options...).Endpoint(),
-		DeleteClassEndpoint: httptransport.NewClient("DELETE", tgt, EncodeDeleteClassRequest, DecodeDeleteClassResponse, options...).Endpoint(),
-		JoinClassEndpoint:   httptransport.NewClient("POST", tgt, EncodeJoinClassRequest, DecodeJoinClassResponse, options...).Endpoint(),
-		SetRoleEndpoint:     httptransport.NewClient("PATCH", tgt, EncodeSetRoleRequest, DecodeSetRoleResponse, options...).Endpoint(),
-		LeaveClassEndpoint:  httptransport.NewClient("DELETE", tgt, EncodeLeaveClassRequest, DecodeLeaveClassResponse, options...).Endpoint(),
-		ListMembersEndpoint: httptransport.NewClient("GET", tgt, EncodeListMembersRequest, DecodeListMembersResponse, options...).Endpoint(),
-		GetMemberEndpoint:   httptransport.NewClient("GET", tgt, EncodeGetMemberRequest, DecodeGetMemberResponse, options...).Endpoint(),
+		ListClassesEndpoint:           httptransport.NewClient("GET", tgt, EncodeListClassesRequest, decodeHTTPResponse(DecodeListClassesResponse, listClassesResponse{}), options...).Endpoint(),
+		GetClassEndpoint:              httptransport.NewClient("GET", tgt, EncodeGetClassRequest, decodeHTTPResponse(DecodeGetClassResponse, getClassResponse{}), options...).Endpoint(),
+		CreateClassEndpoint:           httptransport.NewClient("POST", tgt, EncodeCreateClassRequest, decodeHTTPResponse(DecodeCreateClassResponse, createClassResponse{}), options...).Endpoint(),
+		UpdateClassEndpoint:           httptransport.NewClient("PATCH", tgt, EncodeUpdateClassRequest, decodeHTTPResponse(DecodeUpdateClassResponse, updateClassResponse{}), options...).Endpoint(),
+		DeleteClassEndpoint:           httptransport.NewClient("DELETE", tgt, EncodeDeleteClassRequest, decodeHTTPResponse(DecodeDeleteClassResponse, deleteClassResponse{}), options...).Endpoint(),
+		JoinClassEndpoint:             httptransport.NewClient("POST", tgt, EncodeJoinClassRequest, decodeHTTPResponse(DecodeJoinClassResponse, joinClassResponse{}), options...).Endpoint(),
+		SetRoleEndpoint:               httptransport.NewClient("PATCH", tgt, EncodeSetRoleRequest, decodeHTTPResponse(DecodeSetRoleResponse, setRoleResponse{}), options...).Endpoint(),
+		LeaveClassEndpoint:            httptransport.NewClient("DELETE", tgt, EncodeLeaveClassRequest, decodeHTTPResponse(DecodeLeaveClassResponse, leaveClassResponse{}), options...).Endpoint(),
+		ListMembersEndpoint:           httptransport.NewClient("GET", tgt, EncodeListMembersRequest, decodeHTTPResponse(DecodeListMembersResponse, listMembersResponse{}), options...).Endpoint(),
+		GetMemberEndpoint:             httptransport.NewClient("GET", tgt, EncodeGetMemberRequest, decodeHTTPResponse(DecodeGetMemberResponse, getMemberResponse{}), options...).Endpoint(),
+		GetCapacityEndpoint:           httptransport.NewClient("GET", tgt, EncodeGetCapacityRequest, decodeHTTPResponse(DecodeGetCapacityResponse, getCapacityResponse{}), options...).Endpoint(),
+		SetCapacityEndpoint:           httptransport.NewClient("PUT", tgt, EncodeSetCapacityRequest, decodeHTTPResponse(DecodeSetCapacityResponse, setCapacityResponse{}), options...).Endpoint(),
+		ListWaitlistEndpoint:          httptransport.NewClient("GET", tgt, EncodeListWaitlistRequest, decodeHTTPResponse(DecodeListWaitlistResponse, listWaitlistResponse{}), options...).Endpoint(),
+		GetEnrollmentSettingsEndpoint: httptransport.NewClient("GET", tgt, EncodeGetEnrollmentSettingsRequest, decodeHTTPResponse(DecodeGetEnrollmentSettingsResponse, getEnrollmentSettingsResponse{}), options...).Endpoint(),
+		SetEnrollmentSettingsEndpoint: httptransport.NewClient("PUT", tgt, EncodeSetEnrollmentSettingsRequest, decodeHTTPResponse(DecodeSetEnrollmentSettingsResponse, setEnrollmentSettingsResponse{}), options...).Endpoint(),
+		LinkGuardianEndpoint:          httptransport.NewClient("PUT", tgt, EncodeLinkGuardianRequest, decodeHTTPResponse(DecodeLinkGuardianResponse, linkGuardianResponse{}), options...).Endpoint(),
+		UnlinkGuardianEndpoint:        httptransport.NewClient("DELETE", tgt, EncodeUnlinkGuardianRequest, decodeHTTPResponse(DecodeUnlinkGuardianResponse, unlinkGuardianResponse{}), options...).Endpoint(),
+		ListStudentsEndpoint:          httptransport.NewClient("GET", tgt, EncodeListStudentsRequest, decodeHTTPResponse(DecodeListStudentsResponse, listStudentsResponse{}), options...).Endpoint(),
 	}, nil
 }
 
+// MakeNATSClientEndpoints returns an Endpoints struct where each endpoint sends a request on
+// the corresponding RPC subject over nc, via a transport/nats.Publisher, and waits up to timeout
+// for the reply.
+func MakeNATSClientEndpoints(nc *nats.Conn, timeout time.Duration) Endpoints {
+	options := []natstransport.PublisherOption{
+		natstransport.PublisherTimeout(timeout),
+	}
+
+	return Endpoints{
+		ListClassesEndpoint:           natstransport.NewPublisher(nc, RPCListClasses, encodeNATSRequest, decodeNATSResponse(listClassesResponse{}), options...).Endpoint(),
+		GetClassEndpoint:              natstransport.NewPublisher(nc, RPCGetClass, encodeNATSRequest, decodeNATSResponse(getClassResponse{}), options...).Endpoint(),
+		CreateClassEndpoint:           natstransport.NewPublisher(nc, RPCCreateClass, encodeNATSRequest, decodeNATSResponse(createClassResponse{}), options...).Endpoint(),
+		UpdateClassEndpoint:           natstransport.NewPublisher(nc, RPCUpdateClass, encodeNATSRequest, decodeNATSResponse(updateClassResponse{}), options...).Endpoint(),
+		DeleteClassEndpoint:           natstransport.NewPublisher(nc, RPCDeleteClass, encodeNATSRequest, decodeNATSResponse(deleteClassResponse{}), options...).Endpoint(),
+		JoinClassEndpoint:             natstransport.NewPublisher(nc, RPCJoinClass, encodeNATSRequest, decodeNATSResponse(joinClassResponse{}), options...).Endpoint(),
+		SetRoleEndpoint:               natstransport.NewPublisher(nc, RPCSetRole, encodeNATSRequest, decodeNATSResponse(setRoleResponse{}), options...).Endpoint(),
+		LeaveClassEndpoint:            natstransport.NewPublisher(nc, RPCLeaveClass, encodeNATSRequest, decodeNATSResponse(leaveClassResponse{}), options...).Endpoint(),
+		ListMembersEndpoint:           natstransport.NewPublisher(nc, RPCListMembers, encodeNATSRequest, decodeNATSResponse(listMembersResponse{}), options...).Endpoint(),
+		GetMemberEndpoint:             natstransport.NewPublisher(nc, RPCGetMember, encodeNATSRequest, decodeNATSResponse(getMemberResponse{}), options...).Endpoint(),
+		GetCapacityEndpoint:           natstransport.NewPublisher(nc, RPCGetCapacity, encodeNATSRequest, decodeNATSResponse(getCapacityResponse{}), options...).Endpoint(),
+		SetCapacityEndpoint:           natstransport.NewPublisher(nc, RPCSetCapacity, encodeNATSRequest, decodeNATSResponse(setCapacityResponse{}), options...).Endpoint(),
+		ListWaitlistEndpoint:          natstransport.NewPublisher(nc, RPCListWaitlist, encodeNATSRequest, decodeNATSResponse(listWaitlistResponse{}), options...).Endpoint(),
+		GetEnrollmentSettingsEndpoint: natstransport.NewPublisher(nc, RPCGetEnrollmentSettings, encodeNATSRequest, decodeNATSResponse(getEnrollmentSettingsResponse{}), options...).Endpoint(),
+		SetEnrollmentSettingsEndpoint: natstransport.NewPublisher(nc, RPCSetEnrollmentSettings, encodeNATSRequest, decodeNATSResponse(setEnrollmentSettingsResponse{}), options...).Endpoint(),
+		LinkGuardianEndpoint:          natstransport.NewPublisher(nc, RPCLinkGuardian, encodeNATSRequest, decodeNATSResponse(linkGuardianResponse{}), options...).Endpoint(),
+		UnlinkGuardianEndpoint:        natstransport.NewPublisher(nc, RPCUnlinkGuardian, encodeNATSRequest, decodeNATSResponse(unlinkGuardianResponse{}), options...).Endpoint(),
+		ListStudentsEndpoint:          natstransport.NewPublisher(nc, RPCListStudents, encodeNATSRequest, decodeNATSResponse(listStudentsResponse{}), options...).Endpoint(),
+	}
+}
+
 func (e Endpoints) ListClasses(ctx context.Context) ([]uuid.UUID, error) {
 	response, err := e.ListClassesEndpoint(ctx, nil)
 	if err != nil {
@@ -204,6 +261,86 @@ func (e Endpoints) GetMember(ctx context.Context, classID, userID uuid.UUID) (*m
 	return resp.Member, resp.Error
 }
 
+func (e Endpoints) GetCapacity(ctx context.Context, classID uuid.UUID) (*Capacity, error) {
+	request := getCapacityRequest{ClassID: classID}
+	response, err := e.GetCapacityEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	resp := response.(getCapacityResponse)
+	return resp.Capacity, resp.Error
+}
+
+func (e Endpoints) SetCapacity(ctx context.Context, classID uuid.UUID, capacity Capacity) error {
+	request := setCapacityRequest{ClassID: classID, Capacity: capacity}
+	response, err := e.SetCapacityEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	resp := response.(setCapacityResponse)
+	return resp.Error
+}
+
+func (e Endpoints) ListWaitlist(ctx context.Context, classID uuid.UUID) ([]*models.WaitlistEntry, error) {
+	request := listWaitlistRequest{ClassID: classID}
+	response, err := e.ListWaitlistEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	resp := response.(listWaitlistResponse)
+	return resp.Waitlist, resp.Error
+}
+
+func (e Endpoints) GetEnrollmentSettings(ctx context.Context, classID uuid.UUID) (*EnrollmentSettings, error) {
+	request := getEnrollmentSettingsRequest{ClassID: classID}
+	response, err := e.GetEnrollmentSettingsEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	resp := response.(getEnrollmentSettingsResponse)
+	return resp.Settings, resp.Error
+}
+
+func (e Endpoints) SetEnrollmentSettings(ctx context.Context, classID uuid.UUID, settings EnrollmentSettings) error {
+	request := setEnrollmentSettingsRequest{ClassID: classID, Settings: settings}
+	response, err := e.SetEnrollmentSettingsEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	resp := response.(setEnrollmentSettingsResponse)
+	return resp.Error
+}
+
+func (e Endpoints) LinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) error {
+	request := linkGuardianRequest{GuardianID: guardianID, StudentID: studentID}
+	response, err := e.LinkGuardianEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	resp := response.(linkGuardianResponse)
+	return resp.Error
+}
+
+func (e Endpoints) UnlinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) error {
+	request := unlinkGuardianRequest{GuardianID: guardianID, StudentID: studentID}
+	response, err := e.UnlinkGuardianEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	resp := response.(unlinkGuardianResponse)
+	return resp.Error
+}
+
+func (e Endpoints) ListStudents(ctx context.Context, guardianID uuid.UUID) ([]uuid.UUID, error) {
+	request := listStudentsRequest{GuardianID: guardianID}
+	response, err := e.ListStudentsEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	resp := response.(listStudentsResponse)
+	return resp.Students, resp.Error
+}
+
 func MakeListClassesEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		classes, e := s.ListClasses(ctx)
@@ -241,9 +378,19 @@ func (r getClassResponse) error() error {
 	return r.Error
 }
 
+func (r getClassResponse) version() (int64, bool) {
+	if r.Class == nil {
+		return 0, false
+	}
+	return r.Class.Version, true
+}
+
 func MakeCreateClassEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(createClassRequest)
+		if e := req.validate(); e != nil {
+			return createClassResponse{Error: e}, nil
+		}
 		id, e := s.CreateClass(ctx, req.Name)
 		return createClassResponse{id, e}, nil
 	}
@@ -253,6 +400,12 @@ type createClassRequest struct {
 	Name string `json:"name"`
 }
 
+func (r *createClassRequest) validate() error {
+	var v validation
+	r.Name = v.name("/name", r.Name)
+	return v.err()
+}
+
 type createClassResponse struct {
 	ClassID *uuid.UUID `json:"class_id,omitempty"`
 	Error   error `json:"error,omitempty"`
@@ -265,6 +418,9 @@ func (r createClassResponse) error() error {
 func MakeUpdateClassEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(updateClassRequest)
+		if e := req.validate(); e != nil {
+			return updateClassResponse{e}, nil
+		}
 		e := s.UpdateClass(ctx, req.ClassID, req.Name, req.CurrentUnit)
 		return updateClassResponse{e}, nil
 	}
@@ -276,6 +432,15 @@ type updateClassRequest struct {
 	CurrentUnit *uuid.UUID `json:"current_unit,omitempty"`
 }
 
+func (r *updateClassRequest) validate() error {
+	var v validation
+	if r.Name != nil {
+		name := v.name("/class", *r.Name)
+		r.Name = &name
+	}
+	return v.err()
+}
+
 type updateClassResponse struct {
 	Error error `json:"error,omitempty"`
 }
@@ -348,7 +513,10 @@ func (r leaveClassResponse) error() error {
 func MakeSetRoleEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(setRoleRequest)
-		e := s.SetRole(ctx, req.UserID, req.ClassID, req.Role)
+		if e := req.validate(); e != nil {
+			return setRoleResponse{e}, nil
+		}
+		e := s.SetRole(ctx, req.ClassID, req.UserID, req.Role)
 		return setRoleResponse{e}, nil
 	}
 }
@@ -359,6 +527,12 @@ type setRoleRequest struct {
 	Role    models.UserRole `json:"role"`
 }
 
+func (r *setRoleRequest) validate() error {
+	var v validation
+	v.role("/role", r.Role)
+	return v.err()
+}
+
 type setRoleResponse struct {
 	Error error `json:"error,omitempty"`
 }
@@ -406,6 +580,265 @@ type getMemberResponse struct {
 	Error  error `json:"error,omitempty"`
 }
 
+func (r getMemberResponse) error() error {
+	return r.Error
+}
+
+func (r getMemberResponse) version() (int64, bool) {
+	if r.Member == nil {
+		return 0, false
+	}
+	return r.Member.Version, true
+}
+
+func MakeGetCapacityEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getCapacityRequest)
+		capacity, e := s.GetCapacity(ctx, req.ClassID)
+		return getCapacityResponse{capacity, e}, nil
+	}
+}
+
+type getCapacityRequest struct {
+	ClassID uuid.UUID
+}
+
+type getCapacityResponse struct {
+	Capacity *Capacity `json:"capacity,omitempty"`
+	Error    error     `json:"error,omitempty"`
+}
+
+func (r getCapacityResponse) error() error {
+	return r.Error
+}
+
+func MakeSetCapacityEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(setCapacityRequest)
+		if e := req.validate(); e != nil {
+			return setCapacityResponse{e}, nil
+		}
+		e := s.SetCapacity(ctx, req.ClassID, req.Capacity)
+		return setCapacityResponse{e}, nil
+	}
+}
+
+type setCapacityRequest struct {
+	ClassID  uuid.UUID
+	Capacity Capacity
+}
+
+func (r *setCapacityRequest) validate() error {
+	var v validation
+	if r.Capacity.Seats != nil {
+		v.seats("/seats", *r.Capacity.Seats)
+	}
+	for role, n := range r.Capacity.Roles {
+		v.role("/roles", role)
+		v.seats("/roles/"+role.String(), n)
+	}
+	return v.err()
+}
+
+type setCapacityResponse struct {
+	Error error `json:"error,omitempty"`
+}
+
+func (r setCapacityResponse) error() error {
+	return r.Error
+}
+
+func MakeListWaitlistEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(listWaitlistRequest)
+		waitlist, e := s.ListWaitlist(ctx, req.ClassID)
+		return listWaitlistResponse{waitlist, e}, nil
+	}
+}
+
+type listWaitlistRequest struct {
+	ClassID uuid.UUID
+}
+
+type listWaitlistResponse struct {
+	Waitlist []*models.WaitlistEntry `json:"waitlist"`
+	Error    error                   `json:"error,omitempty"`
+}
+
+func (r listWaitlistResponse) error() error {
+	return r.Error
+}
+
+func MakeGetEnrollmentSettingsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getEnrollmentSettingsRequest)
+		settings, e := s.GetEnrollmentSettings(ctx, req.ClassID)
+		return getEnrollmentSettingsResponse{settings, e}, nil
+	}
+}
+
+type getEnrollmentSettingsRequest struct {
+	ClassID uuid.UUID
+}
+
+type getEnrollmentSettingsResponse struct {
+	Settings *EnrollmentSettings `json:"enrollment,omitempty"`
+	Error    error               `json:"error,omitempty"`
+}
+
+func (r getEnrollmentSettingsResponse) error() error {
+	return r.Error
+}
+
+func MakeSetEnrollmentSettingsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(setEnrollmentSettingsRequest)
+		e := s.SetEnrollmentSettings(ctx, req.ClassID, req.Settings)
+		return setEnrollmentSettingsResponse{e}, nil
+	}
+}
+
+type setEnrollmentSettingsRequest struct {
+	ClassID  uuid.UUID
+	Settings EnrollmentSettings
+}
+
+type setEnrollmentSettingsResponse struct {
+	Error error `json:"error,omitempty"`
+}
+
+func (r setEnrollmentSettingsResponse) error() error {
+	return r.Error
+}
+
+func MakeLinkGuardianEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(linkGuardianRequest)
+		e := s.LinkGuardian(ctx, req.GuardianID, req.StudentID)
+		return linkGuardianResponse{e}, nil
+	}
+}
+
+type linkGuardianRequest struct {
+	GuardianID uuid.UUID
+	StudentID  uuid.UUID
+}
+
+type linkGuardianResponse struct {
+	Error error `json:"error,omitempty"`
+}
+
+func (r linkGuardianResponse) error() error {
+	return r.Error
+}
+
+func MakeUnlinkGuardianEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(unlinkGuardianRequest)
+		e := s.UnlinkGuardian(ctx, req.GuardianID, req.StudentID)
+		return unlinkGuardianResponse{e}, nil
+	}
+}
+
+type unlinkGuardianRequest struct {
+	GuardianID uuid.UUID
+	StudentID  uuid.UUID
+}
+
+type unlinkGuardianResponse struct {
+	Error error `json:"error,omitempty"`
+}
+
+func (r unlinkGuardianResponse) error() error {
+	return r.Error
+}
+
+func MakeListStudentsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(listStudentsRequest)
+		students, e := s.ListStudents(ctx, req.GuardianID)
+		return listStudentsResponse{students, e}, nil
+	}
+}
+
+type listStudentsRequest struct {
+	GuardianID uuid.UUID
+}
+
+type listStudentsResponse struct {
+	Students []uuid.UUID `json:"students"`
+	Error    error       `json:"error,omitempty"`
+}
+
+func (r listStudentsResponse) error() error {
+	return r.Error
+}
+
+const (
+	// maxNameLength bounds the names of classes, in characters.
+	maxNameLength = 100
+	// maxSeats bounds seat limits, well above the size of any class.
+	maxSeats = 10000
+)
+
+// ValidateName checks a class name and returns it normalized, like the CreateClass and
+// UpdateClass endpoints do, for transports that call the Service without going through them.
+// field names the name in the *ValidationError returned if it is invalid.
+func ValidateName(field, name string) (string, error) {
+	var v validation
+	name = v.name(field, name)
+	return name, v.err()
+}
+
+// validation collects the problems found with the fields of a request, so that they can all be
+// reported at once.
+type validation struct {
+	fields []FieldError
+}
+
+func (v *validation) fail(field, reason string) {
+	v.fields = append(v.fields, FieldError{Field: field, Reason: reason})
+}
+
+// err returns a *ValidationError listing the problems found, or nil if there were none.
+func (v *validation) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// name normalizes a name to NFC and trims the space around it, then checks that it is neither
+// empty nor too long and has no control characters.
+func (v *validation) name(field, name string) string {
+	if !utf8.ValidString(name) {
+		v.fail(field, "must be valid UTF-8")
+		return name
+	}
+	name = strings.TrimSpace(norm.NFC.String(name))
+	switch {
+	case name == "":
+		v.fail(field, "must not be empty")
+	case utf8.RuneCountInString(name) > maxNameLength:
+		v.fail(field, fmt.Sprintf("must be at most %d characters long", maxNameLength))
+	case strings.IndexFunc(name, unicode.IsControl) >= 0:
+		v.fail(field, "must not contain control characters")
+	}
+	return name
+}
+
+func (v *validation) role(field string, role models.UserRole) {
+	if role != models.UserRoleStudent && role != models.UserRoleTeacher {
+		v.fail(field, `must be "student" or "teacher"`)
+	}
+}
+
+func (v *validation) seats(field string, n int) {
+	if n < 0 || n > maxSeats {
+		v.fail(field, fmt.Sprintf("must be between 0 and %d", maxSeats))
+	}
+}
+
 //func MakeGetRoleEndpoint(s Service) endpoint.Endpoint {
 //	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 //		req := request.(getRoleRequest)
@@ -0,0 +1,331 @@
+package classsvc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/studiously/introspector"
+)
+
+// IdempotencyKeyHeader carries a key, chosen by the client, that makes a mutating request safe
+// to retry: the first response to a key is stored, and replayed to later requests with the
+// same key from the same user.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses that were replayed for an IdempotencyKeyHeader.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength bounds idempotency keys, which are usually UUIDs.
+const maxIdempotencyKeyLength = 255
+
+var (
+	ErrIdempotencyKeyReused = errors.New("the idempotency key was already used for a different request")
+	ErrIdempotencyKeyInUse  = errors.New("a request with the idempotency key is still being served")
+	ErrRequestTooLarge      = errors.New("the request body is too large")
+)
+
+// Idempotency stores the responses to mutating requests made with an IdempotencyKeyHeader.
+// Its fields may be changed before it is used.
+type Idempotency struct {
+	db *sql.DB
+
+	// TTL is how long a response is replayed for after it is stored.
+	TTL time.Duration
+	// Lease is how long a request with a key may be served before another request with the key
+	// takes over, in case the first one never finished.
+	Lease time.Duration
+}
+
+// NewIdempotency returns an Idempotency that stores responses in db for a day.
+func NewIdempotency(db *sql.DB) *Idempotency {
+	return &Idempotency{
+		db:    db,
+		TTL:   24 * time.Hour,
+		Lease: time.Minute,
+	}
+}
+
+// storedResponse is a response replayed for an idempotency key.
+type storedResponse struct {
+	status  int
+	headers http.Header
+	body    []byte
+}
+
+// begin claims key for a request by userID whose method, path, If-Match and body hash to hash.
+// It returns the stored response if the key was already used for the same request, or nil if the
+// request should be served, after which finish must be called.
+func (i *Idempotency) begin(ctx context.Context, userID uuid.UUID, key string, hash []byte) (*storedResponse, error) {
+	_, err := i.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE user_id=$1 AND key=$2 "+
+		"AND (expires_at <= now() OR (status IS NULL AND created_at <= $3));", userID, key, time.Now().Add(-i.Lease))
+	if err != nil {
+		return nil, err
+	}
+	res, err := i.db.ExecContext(ctx, "INSERT INTO idempotency_keys (user_id, key, request_hash, expires_at) VALUES ($1, $2, $3, $4) "+
+		"ON CONFLICT (user_id, key) DO NOTHING;", userID, key, hash, time.Now().Add(i.TTL))
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 1 {
+		return nil, nil
+	}
+
+	var (
+		storedHash []byte
+		status     sql.NullInt64
+		headers    []byte
+		stored     storedResponse
+	)
+	err = i.db.QueryRowContext(ctx, "SELECT request_hash, status, headers, body FROM idempotency_keys WHERE user_id=$1 AND key=$2;", userID, key).
+		Scan(&storedHash, &status, &headers, &stored.body)
+	switch {
+	case err == sql.ErrNoRows:
+		// The key expired between the two statements; its next use will claim it.
+		return nil, ErrIdempotencyKeyInUse
+	case err != nil:
+		return nil, err
+	case !bytes.Equal(storedHash, hash):
+		return nil, ErrIdempotencyKeyReused
+	case !status.Valid:
+		return nil, ErrIdempotencyKeyInUse
+	}
+	stored.status = int(status.Int64)
+	if err := json.Unmarshal(headers, &stored.headers); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// finish stores the response to the request that claimed key. Server errors are not stored,
+// so that the request may be retried.
+func (i *Idempotency) finish(ctx context.Context, userID uuid.UUID, key string, response storedResponse) error {
+	if response.status >= http.StatusInternalServerError {
+		_, err := i.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE user_id=$1 AND key=$2;", userID, key)
+		return err
+	}
+	headers, err := json.Marshal(response.headers)
+	if err != nil {
+		return err
+	}
+	_, err = i.db.ExecContext(ctx, "UPDATE idempotency_keys SET status=$3, headers=$4, body=$5 WHERE user_id=$1 AND key=$2;",
+		userID, key, response.status, headers, response.body)
+	return err
+}
+
+// Purge deletes the keys that have expired, returning how many there were.
+func (i *Idempotency) Purge(ctx context.Context) (int64, error) {
+	res, err := i.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= now();")
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Run purges expired keys every interval until ctx is done.
+func (i *Idempotency) Run(ctx context.Context, interval time.Duration, logger log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if n, err := i.Purge(ctx); err != nil {
+			logger.Log("error", err)
+		} else if n > 0 {
+			logger.Log("purged", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// idempotentRequest is a request with an idempotency key. Handler puts it in the context of the
+// request, and Claim claims the key once the user is known.
+type idempotentRequest struct {
+	key  string
+	hash []byte
+
+	// userID is set once the key is claimed, and stored once a stored response is found.
+	userID  uuid.UUID
+	claimed bool
+	stored  *storedResponse
+}
+
+// errReplayed stops a request whose response is replayed from being served.
+var errReplayed = errors.New("response replayed for idempotency key")
+
+// Handler serves the mutating requests that carry an IdempotencyKeyHeader at most once per key
+// and user, replaying the stored response to the others. The key is claimed by Claim, in the
+// endpoints, once the token has been introspected.
+func (i *Idempotency) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || r.Method == "GET" || r.Method == "HEAD" || r.Method == "OPTIONS" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			encodeError(r.Context(), &ValidationError{Fields: []FieldError{{
+				Field:  IdempotencyKeyHeader,
+				Reason: "must be at most 255 characters long",
+			}}}, w)
+			return
+		}
+		if r.ContentLength > maxBodySize {
+			encodeError(r.Context(), ErrRequestTooLarge, w)
+			return
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil {
+			encodeError(r.Context(), ErrBadRequest, w)
+			return
+		}
+		if len(body) > maxBodySize {
+			encodeError(r.Context(), ErrRequestTooLarge, w)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		// The precondition is part of the request: a retry with another If-Match is a different
+		// request, not one whose response can be replayed.
+		h := sha256.New()
+		io.WriteString(h, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\n")
+		io.WriteString(h, "If-Match: "+strings.TrimSpace(r.Header.Get("If-Match"))+"\n")
+		h.Write(body)
+		req := &idempotentRequest{key: key, hash: h.Sum(nil)}
+		rec := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), idempotentRequestContextKey, req)))
+
+		if req.stored != nil {
+			for name, values := range req.stored.headers {
+				w.Header()[name] = values
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(req.stored.status)
+			w.Write(req.stored.body)
+			return
+		}
+		headers := http.Header{}
+		for name, values := range rec.header {
+			w.Header()[name] = values
+			if name != CorrelationIDHeader {
+				headers[name] = values
+			}
+		}
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes())
+		if !req.claimed {
+			return
+		}
+		// The client has its response, so it is stored even if it is gone.
+		ctx := context.Background()
+		if err := i.finish(ctx, req.userID, req.key, storedResponse{rec.status, headers, rec.body.Bytes()}); err != nil {
+			loggerFrom(r.Context()).Log("msg", "could not store response for idempotency key", "error", err)
+		}
+	})
+}
+
+// Claim claims the idempotency key of the request, if it has one, for the user the token was
+// issued to, before calling next. If the key was already used for the same request, next is not
+// called and the stored response is replayed instead. next must be wrapped by the introspector,
+// and served through Handler.
+func (i *Idempotency) Claim(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := ctx.Value(idempotentRequestContextKey).(*idempotentRequest)
+		if !ok || req.claimed || req.stored != nil {
+			return next(ctx, request)
+		}
+		userID, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
+		if !ok {
+			return next(ctx, request)
+		}
+		stored, err := i.begin(ctx, userID, req.key, req.hash)
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			req.stored = stored
+			return nil, errReplayed
+		}
+		req.userID, req.claimed = userID, true
+		return next(ctx, request)
+	}
+}
+
+// endpoints returns e with every endpoint claiming idempotency keys. The endpoints must be
+// wrapped by the introspector, and served through Handler.
+func (i *Idempotency) endpoints(e Endpoints) Endpoints {
+	return Endpoints{
+		GetClassEndpoint:              i.Claim(e.GetClassEndpoint),
+		CreateClassEndpoint:           i.Claim(e.CreateClassEndpoint),
+		ListClassesEndpoint:           i.Claim(e.ListClassesEndpoint),
+		UpdateClassEndpoint:           i.Claim(e.UpdateClassEndpoint),
+		DeleteClassEndpoint:           i.Claim(e.DeleteClassEndpoint),
+		ListMembersEndpoint:           i.Claim(e.ListMembersEndpoint),
+		JoinClassEndpoint:             i.Claim(e.JoinClassEndpoint),
+		LeaveClassEndpoint:            i.Claim(e.LeaveClassEndpoint),
+		SetRoleEndpoint:               i.Claim(e.SetRoleEndpoint),
+		GetMemberEndpoint:             i.Claim(e.GetMemberEndpoint),
+		GetCapacityEndpoint:           i.Claim(e.GetCapacityEndpoint),
+		SetCapacityEndpoint:           i.Claim(e.SetCapacityEndpoint),
+		ListWaitlistEndpoint:          i.Claim(e.ListWaitlistEndpoint),
+		GetEnrollmentSettingsEndpoint: i.Claim(e.GetEnrollmentSettingsEndpoint),
+		SetEnrollmentSettingsEndpoint: i.Claim(e.SetEnrollmentSettingsEndpoint),
+		LinkGuardianEndpoint:          i.Claim(e.LinkGuardianEndpoint),
+		UnlinkGuardianEndpoint:        i.Claim(e.UnlinkGuardianEndpoint),
+		ListStudentsEndpoint:          i.Claim(e.ListStudentsEndpoint),
+	}
+}
+
+// WithIdempotencyKey sets the IdempotencyKeyHeader sent with calls made by a Client, so that
+// callers can retry a call themselves, even from another process, and get its first response.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey, key)
+}
+
+// IdempotencyKey returns the idempotency key set by WithIdempotencyKey, if any.
+func IdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey).(string)
+	return key
+}
+
+func idempotencyKeyToHTTP(ctx context.Context, r *http.Request) context.Context {
+	if key := IdempotencyKey(ctx); key != "" && r.Method != "GET" {
+		r.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return ctx
+}
+
+// responseRecorder keeps a response until it is known whether it is sent, or a stored one is
+// replayed instead.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
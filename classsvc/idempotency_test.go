@@ -0,0 +1,199 @@
+package classsvc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/dbtest"
+)
+
+// countingService counts the calls to JoinClass, which waits for release, if set, and fails
+// with err. entered is sent to, if set, once a call has started.
+type countingService struct {
+	Service
+	err     error
+	entered chan struct{}
+	release chan struct{}
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *countingService) JoinClass(_ context.Context, _ uuid.UUID) error {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	if s.entered != nil {
+		s.entered <- struct{}{}
+	}
+	if s.release != nil {
+		<-s.release
+	}
+	return s.err
+}
+
+func (s *countingService) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func TestIdempotencyHandlerRefuses(t *testing.T) {
+	// Neither request reaches the database, or the handler it wraps.
+	h := NewIdempotency(nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("%s %s was served", r.Method, r.URL)
+	}))
+	tests := []struct {
+		name   string
+		key    string
+		body   string
+		status int
+		code   string
+	}{
+		{"long key", strings.Repeat("k", maxIdempotencyKeyLength+1), "{}", http.StatusBadRequest, "validation_failed"},
+		{"large body", "key", strings.Repeat(" ", maxBodySize+1), http.StatusRequestEntityTooLarge, "request_too_large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/v2/classes/", strings.NewReader(tt.body))
+			r.Header.Set(IdempotencyKeyHeader, tt.key)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), `"code":"`+tt.code+`"`) {
+				t.Errorf("response = %d %s; want %d %s", w.Code, w.Body, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestIdempotencyHandlerPassesThrough(t *testing.T) {
+	served := 0
+	h := NewIdempotency(nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+	}))
+	for _, r := range []*http.Request{
+		httptest.NewRequest("POST", "/v2/classes/", strings.NewReader("{}")),
+		httptest.NewRequest("GET", "/v2/classes/", nil),
+	} {
+		if r.Method == "GET" {
+			r.Header.Set(IdempotencyKeyHeader, "key")
+		}
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if served != 2 {
+		t.Errorf("served %d requests; want requests without a key and reads passed through", served)
+	}
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	service := &countingService{err: ErrWaitlisted}
+	srv := httptest.NewServer(MakeIdempotentHTTPHandler(service, NewIdempotency(db), activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	path := srv.URL + "/v2/classes/" + uuid.New().String() + "/join"
+
+	first, firstBody := send(t, "POST", path, "", IdempotencyKeyHeader, "join-1")
+	replay, replayBody := send(t, "POST", path, "", IdempotencyKeyHeader, "join-1")
+	if service.count() != 1 {
+		t.Fatalf("JoinClass called %d times; want once", service.count())
+	}
+	if first.Header.Get(IdempotentReplayedHeader) != "" || replay.Header.Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("%s = %q, then %q; want it only on the replay", IdempotentReplayedHeader,
+			first.Header.Get(IdempotentReplayedHeader), replay.Header.Get(IdempotentReplayedHeader))
+	}
+	if replay.StatusCode != first.StatusCode || string(replayBody) != string(firstBody) {
+		t.Errorf("replay = %d %s; want %d %s", replay.StatusCode, replayBody, first.StatusCode, firstBody)
+	}
+
+	// Requests with another key are served.
+	send(t, "POST", path, "", IdempotencyKeyHeader, "join-2")
+	if service.count() != 2 {
+		t.Errorf("JoinClass called %d times; want a request with another key served", service.count())
+	}
+}
+
+func TestIdempotencyKeyReused(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	service := &countingService{}
+	srv := httptest.NewServer(MakeIdempotentHTTPHandler(service, NewIdempotency(db), activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	path := srv.URL + "/v2/classes/" + uuid.New().String() + "/join"
+	send(t, "POST", path, "", IdempotencyKeyHeader, "join", "If-Match", `"1"`)
+
+	tests := []struct {
+		name    string
+		path    string
+		headers []string
+	}{
+		{"other path", srv.URL + "/v2/classes/" + uuid.New().String() + "/join", []string{IdempotencyKeyHeader, "join", "If-Match", `"1"`}},
+		{"other If-Match", path, []string{IdempotencyKeyHeader, "join", "If-Match", `"2"`}},
+		{"no If-Match", path, []string{IdempotencyKeyHeader, "join"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := send(t, "POST", tt.path, "", tt.headers...)
+			if resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(string(body), `"code":"idempotency_key_reused"`) {
+				t.Errorf("response = %d %s; want 422 idempotency_key_reused", resp.StatusCode, body)
+			}
+		})
+	}
+	if service.count() != 1 {
+		t.Errorf("JoinClass called %d times; want once", service.count())
+	}
+}
+
+func TestIdempotencyKeyInUse(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	service := &countingService{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	srv := httptest.NewServer(MakeIdempotentHTTPHandler(service, NewIdempotency(db), activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	path := srv.URL + "/v2/classes/" + uuid.New().String() + "/join"
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r, _ := http.NewRequest("POST", path, nil)
+		r.Header.Set("Authorization", "Bearer token")
+		r.Header.Set(IdempotencyKeyHeader, "join")
+		if resp, err := http.DefaultClient.Do(r); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-service.entered
+	resp, body := send(t, "POST", path, "", IdempotencyKeyHeader, "join")
+	close(service.release)
+	<-done
+	if resp.StatusCode != http.StatusConflict || !strings.Contains(string(body), `"code":"idempotency_key_in_use"`) {
+		t.Errorf("response = %d %s; want 409 idempotency_key_in_use", resp.StatusCode, body)
+	}
+}
+
+func TestIdempotencyServerErrorsNotStored(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	service := &countingService{err: errors.New("pq: could not serialize access")}
+	srv := httptest.NewServer(MakeIdempotentHTTPHandler(service, NewIdempotency(db), activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	path := srv.URL + "/v2/classes/" + uuid.New().String() + "/join"
+
+	for i := 0; i < 2; i++ {
+		resp, _ := send(t, "POST", path, "", IdempotencyKeyHeader, "join")
+		if resp.StatusCode != http.StatusInternalServerError || resp.Header.Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("response %d = %d, replayed %q; want a fresh 500", i, resp.StatusCode, resp.Header.Get(IdempotentReplayedHeader))
+		}
+	}
+	if service.count() != 2 {
+		t.Errorf("JoinClass called %d times; want the retry served", service.count())
+	}
+}
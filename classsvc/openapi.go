@@ -0,0 +1,1163 @@
+package classsvc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// OpenAPISpec is the OpenAPI 3 description of the routes served by MakeHTTPHandler. Requests
+// are validated against it before they are decoded, so it must be kept in step with the
+// decoders in transport_http.go and transport_http_v2.go.
+const OpenAPISpec = `openapi: 3.0.0
+info:
+  title: Studiously class service
+  version: "1.0"
+  description: |
+    Classes, their members, seat limits, waitlists, enrollment windows and guardians.
+
+    Every route requires an OAuth2 access token issued by Hydra, sent as "Authorization: Bearer <token>",
+    with the scope listed for the route. Tokens with the "classes.admin" scope act as organization admins.
+
+    Errors are returned as application/problem+json (RFC 7807) with the status listed for the route.
+    Clients should match on their "code", which is stable; "detail" is for people. Every response
+    carries an X-Correlation-ID, which is also in problems; quote it when reporting a problem.
+
+    POST, PUT, PATCH and DELETE requests may carry an Idempotency-Key of up to 255 characters, such
+    as a UUID, to be retried safely. The first response to a key is stored for each user and
+    replayed, with "Idempotent-Replayed: true", to requests that repeat it; reusing a key for a
+    different request, including one with another If-Match, is refused with 422 and code
+    idempotency_key_reused, and repeating it while
+    the first request is still being served with 409 and code idempotency_key_in_use. Bodies of
+    requests with a key are limited to 64 KiB; larger ones are refused with 413 and code
+    request_too_large.
+
+    Classes and members are served with an ETag holding their version. Sending it back as If-Match
+    with a PATCH or DELETE refuses the change with 412 and code version_mismatch if they have
+    changed since, so that concurrent editors do not overwrite each other.
+
+    Version 1 of the API is served under /v1, and at the routes without a prefix when no other
+    version is asked for in the API-Version header. Version 2 is served under /v2, and at the
+    routes without a prefix when "API-Version: 2" is sent. The routes listed under /v2 change
+    shape in version 2; every other route is served under /v2 as it is in version 1.
+paths:
+  /classes/:
+    get:
+      operationId: listClasses
+      summary: List the classes the caller is enrolled in, and those of the students they are a guardian of.
+      security:
+        - oauth2: [classes.list]
+      responses:
+        "200":
+          description: The IDs of the classes.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  Classes:
+                    type: array
+                    items:
+                      $ref: "#/components/schemas/UUID"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "500":
+          $ref: "#/components/responses/Internal"
+    post:
+      operationId: createClass
+      summary: Create a class, with the caller as its owner.
+      security:
+        - oauth2: [classes.create]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  minLength: 1
+                  maxLength: 100
+      responses:
+        "200":
+          description: The class was created.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  class_id:
+                    $ref: "#/components/schemas/UUID"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    get:
+      operationId: getClass
+      summary: Get a class. Guardians may get the classes of their students.
+      security:
+        - oauth2: [classes.get]
+      responses:
+        "200":
+          description: The class.
+          headers:
+            ETag:
+              $ref: "#/components/headers/ETag"
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  class:
+                    $ref: "#/components/schemas/Class"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "500":
+          $ref: "#/components/responses/Internal"
+    patch:
+      operationId: updateClass
+      summary: Rename a class or change its current unit.
+      security:
+        - oauth2: [classes.update]
+      parameters:
+        - $ref: "#/components/parameters/If-Match"
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                class:
+                  description: The new name of the class.
+                  type: string
+                  minLength: 1
+                  maxLength: 100
+                current_unit:
+                  $ref: "#/components/schemas/UUID"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "412":
+          $ref: "#/components/responses/PreconditionFailed"
+        "500":
+          $ref: "#/components/responses/Internal"
+    delete:
+      operationId: deleteClass
+      summary: Deactivate a class. Only its owner may delete it.
+      security:
+        - oauth2: [classes.delete]
+      parameters:
+        - $ref: "#/components/parameters/If-Match"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "412":
+          $ref: "#/components/responses/PreconditionFailed"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}/members:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    get:
+      operationId: listMembers
+      summary: List the members of a class. Guardians only see the teachers, the owner and their own students.
+      security:
+        - oauth2: [classes.members.list]
+      responses:
+        "200":
+          description: The members.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  members:
+                    type: array
+                    items:
+                      $ref: "#/components/schemas/Member"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}/join:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    post:
+      operationId: joinClass
+      summary: Enroll the caller in a class. If the class is full, the caller is put on its waitlist.
+      security:
+        - oauth2: [classes.join]
+      parameters:
+        - $ref: "#/components/parameters/override_lock"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "202":
+          description: The class is full; the caller was put on its waitlist.
+          content:
+            application/problem+json:
+              schema:
+                $ref: "#/components/schemas/Problem"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "409":
+          $ref: "#/components/responses/ClassFull"
+        "423":
+          $ref: "#/components/responses/Locked"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}/leave:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    delete:
+      operationId: leaveClass
+      summary: Remove the caller from a class or its waitlist. A freed seat goes to the next user on the waitlist.
+      security:
+        - oauth2: [classes.leave]
+      parameters:
+        - $ref: "#/components/parameters/If-Match"
+        - $ref: "#/components/parameters/override_lock"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "423":
+          $ref: "#/components/responses/Locked"
+        "412":
+          $ref: "#/components/responses/PreconditionFailed"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}/leave/{userID}:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+      - $ref: "#/components/parameters/userID"
+    delete:
+      operationId: removeMember
+      summary: Remove another user from a class or its waitlist. The caller must outrank them.
+      security:
+        - oauth2: [classes.leave]
+      parameters:
+        - $ref: "#/components/parameters/If-Match"
+        - $ref: "#/components/parameters/override_lock"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "423":
+          $ref: "#/components/responses/Locked"
+        "412":
+          $ref: "#/components/responses/PreconditionFailed"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}/members/{userID}:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+      - $ref: "#/components/parameters/userID"
+    get:
+      operationId: getMember
+      summary: Get a member of a class.
+      security:
+        - oauth2: [classes.members.get]
+      responses:
+        "200":
+          description: The member.
+          headers:
+            ETag:
+              $ref: "#/components/headers/ETag"
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  member:
+                    $ref: "#/components/schemas/Member"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "500":
+          $ref: "#/components/responses/Internal"
+    patch:
+      operationId: setRole
+      summary: Set the role of a member. The caller must outrank them.
+      security:
+        - oauth2: [classes.members.update]
+      parameters:
+        - $ref: "#/components/parameters/If-Match"
+        - $ref: "#/components/parameters/override_lock"
+      requestBody:
+        required: true
+        description: The new role, as a bare JSON string.
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Role"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "423":
+          $ref: "#/components/responses/Locked"
+        "412":
+          $ref: "#/components/responses/PreconditionFailed"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}/capacity:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    get:
+      operationId: getCapacity
+      summary: Get the seat limits of a class.
+      security:
+        - oauth2: [classes.capacity.get]
+      responses:
+        "200":
+          description: The seat limits.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  capacity:
+                    $ref: "#/components/schemas/Capacity"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "500":
+          $ref: "#/components/responses/Internal"
+    put:
+      operationId: setCapacity
+      summary: Set the seat limits of a class. Only its owner may set them; raising a limit enrolls waitlisted users.
+      security:
+        - oauth2: [classes.capacity.update]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Capacity"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}/waitlist:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    get:
+      operationId: listWaitlist
+      summary: List the users waiting for a seat, in the order they will be enrolled.
+      security:
+        - oauth2: [classes.waitlist.list]
+      responses:
+        "200":
+          description: The waitlist.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  waitlist:
+                    type: array
+                    items:
+                      $ref: "#/components/schemas/WaitlistEntry"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /classes/{classID}/enrollment:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    get:
+      operationId: getEnrollmentSettings
+      summary: Get the enrollment window and roster lock of a class.
+      security:
+        - oauth2: [classes.enrollment.get]
+      responses:
+        "200":
+          description: The enrollment settings.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  enrollment:
+                    $ref: "#/components/schemas/EnrollmentSettings"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "500":
+          $ref: "#/components/responses/Internal"
+    put:
+      operationId: setEnrollmentSettings
+      summary: Set the enrollment window and roster lock of a class. Only its owner or an organization admin may set them.
+      security:
+        - oauth2: [classes.enrollment.update]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/EnrollmentSettings"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /guardians/{guardianID}/students:
+    parameters:
+      - $ref: "#/components/parameters/guardianID"
+    get:
+      operationId: listStudents
+      summary: List the students linked to a guardian.
+      security:
+        - oauth2: [classes.guardians.list]
+      responses:
+        "200":
+          description: The IDs of the students.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  students:
+                    type: array
+                    items:
+                      $ref: "#/components/schemas/UUID"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /guardians/{guardianID}/students/{studentID}:
+    parameters:
+      - $ref: "#/components/parameters/guardianID"
+      - $ref: "#/components/parameters/studentID"
+    put:
+      operationId: linkGuardian
+      summary: Link a guardian to a student. Only organization admins may link guardians.
+      security:
+        - oauth2: [classes.guardians.update]
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "500":
+          $ref: "#/components/responses/Internal"
+    delete:
+      operationId: unlinkGuardian
+      summary: Unlink a guardian from a student. Only organization admins may unlink guardians.
+      security:
+        - oauth2: [classes.guardians.update]
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /v2/classes/:
+    get:
+      operationId: listClassesV2
+      summary: List the classes the caller is enrolled in, and those of the students they are a guardian of.
+      description: Version 2 lists the classes under "classes" rather than "Classes".
+      security:
+        - oauth2: [classes.list]
+      responses:
+        "200":
+          description: The IDs of the classes.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  classes:
+                    type: array
+                    items:
+                      $ref: "#/components/schemas/UUID"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "500":
+          $ref: "#/components/responses/Internal"
+    post:
+      operationId: createClassV2
+      summary: Create a class, with the caller as its owner.
+      description: Version 2 answers with 201 Created and the Location of the class.
+      security:
+        - oauth2: [classes.create]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  minLength: 1
+                  maxLength: 100
+      responses:
+        "201":
+          description: The class was created.
+          headers:
+            Location:
+              description: The path of the class under /v2.
+              schema:
+                type: string
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  class_id:
+                    $ref: "#/components/schemas/UUID"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /v2/classes/{classID}:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    patch:
+      operationId: updateClassV2
+      summary: Rename a class or change its current unit.
+      description: Version 2 takes the new name as "name" rather than "class".
+      security:
+        - oauth2: [classes.update]
+      parameters:
+        - $ref: "#/components/parameters/If-Match"
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                  minLength: 1
+                  maxLength: 100
+                current_unit:
+                  $ref: "#/components/schemas/UUID"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "412":
+          $ref: "#/components/responses/PreconditionFailed"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /v2/classes/{classID}/join:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+    post:
+      operationId: joinClassV2
+      summary: Enroll the caller in a class. If the class is full, the caller is put on its waitlist.
+      description: 'Version 2 answers a waitlisted caller with {"waitlisted": true} rather than as an error.'
+      security:
+        - oauth2: [classes.join]
+      parameters:
+        - $ref: "#/components/parameters/override_lock"
+      responses:
+        "200":
+          description: The caller was enrolled.
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/JoinResult"
+        "202":
+          description: The class is full; the caller was put on its waitlist.
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/JoinResult"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "409":
+          $ref: "#/components/responses/ClassFull"
+        "423":
+          $ref: "#/components/responses/Locked"
+        "500":
+          $ref: "#/components/responses/Internal"
+  /v2/classes/{classID}/members/{userID}:
+    parameters:
+      - $ref: "#/components/parameters/classID"
+      - $ref: "#/components/parameters/userID"
+    patch:
+      operationId: setRoleV2
+      summary: Set the role of a member. The caller must outrank them.
+      description: 'Version 2 takes the new role as {"role": "teacher"} rather than as a bare string.'
+      security:
+        - oauth2: [classes.members.update]
+      parameters:
+        - $ref: "#/components/parameters/If-Match"
+        - $ref: "#/components/parameters/override_lock"
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [role]
+              properties:
+                role:
+                  $ref: "#/components/schemas/Role"
+      responses:
+        "200":
+          $ref: "#/components/responses/Empty"
+        "400":
+          $ref: "#/components/responses/BadRequest"
+        "401":
+          $ref: "#/components/responses/Unauthorized"
+        "403":
+          $ref: "#/components/responses/Forbidden"
+        "404":
+          $ref: "#/components/responses/NotFound"
+        "423":
+          $ref: "#/components/responses/Locked"
+        "412":
+          $ref: "#/components/responses/PreconditionFailed"
+        "500":
+          $ref: "#/components/responses/Internal"
+components:
+  securitySchemes:
+    oauth2:
+      type: oauth2
+      description: Tokens are issued by the Hydra cluster at HYDRA_CLUSTER_URL.
+      flows:
+        authorizationCode:
+          authorizationUrl: /oauth2/auth
+          tokenUrl: /oauth2/token
+          scopes:
+            classes.admin: Act as an organization admin.
+            classes.list: List classes.
+            classes.get: Get classes.
+            classes.create: Create classes.
+            classes.update: Update classes.
+            classes.delete: Delete classes.
+            classes.join: Join classes.
+            classes.leave: Leave classes and remove members.
+            classes.members.list: List members.
+            classes.members.get: Get members.
+            classes.members.update: Set the roles of members.
+            classes.capacity.get: Get seat limits.
+            classes.capacity.update: Set seat limits.
+            classes.waitlist.list: List waitlists.
+            classes.enrollment.get: Get enrollment settings.
+            classes.enrollment.update: Set enrollment settings.
+            classes.guardians.list: List the students of guardians.
+            classes.guardians.update: Link and unlink guardians.
+  parameters:
+    classID:
+      name: classID
+      in: path
+      required: true
+      schema:
+        $ref: "#/components/schemas/UUID"
+    userID:
+      name: userID
+      in: path
+      required: true
+      schema:
+        $ref: "#/components/schemas/UUID"
+    guardianID:
+      name: guardianID
+      in: path
+      required: true
+      schema:
+        $ref: "#/components/schemas/UUID"
+    studentID:
+      name: studentID
+      in: path
+      required: true
+      schema:
+        $ref: "#/components/schemas/UUID"
+    override_lock:
+      name: override_lock
+      in: query
+      description: Change a locked roster or one outside its enrollment window. Only the owner of the class or an organization admin may override.
+      schema:
+        type: boolean
+    If-Match:
+      name: If-Match
+      in: header
+      description: The ETag the class or member was read with. The change is refused with 412 if it has changed since.
+      schema:
+        type: string
+  headers:
+    ETag:
+      description: The version of the class or member, for If-Match.
+      schema:
+        type: string
+  schemas:
+    UUID:
+      type: string
+      pattern: "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$"
+    Role:
+      type: string
+      enum: [student, teacher]
+    JoinResult:
+      type: object
+      properties:
+        waitlisted:
+          description: Whether the caller was put on the waitlist rather than enrolled.
+          type: boolean
+    Class:
+      type: object
+      properties:
+        id:
+          $ref: "#/components/schemas/UUID"
+        name:
+          type: string
+        current_unit:
+          $ref: "#/components/schemas/UUID"
+        version:
+          description: Changes with every update of the class. Its ETag is this version in quotes.
+          type: integer
+          format: int64
+    Member:
+      type: object
+      properties:
+        user_id:
+          $ref: "#/components/schemas/UUID"
+        class_id:
+          $ref: "#/components/schemas/UUID"
+        role:
+          $ref: "#/components/schemas/Role"
+        owner:
+          type: boolean
+        joined_at:
+          type: string
+          format: date-time
+        version:
+          description: Changes with every update of the member. Its ETag is this version in quotes.
+          type: integer
+          format: int64
+    WaitlistEntry:
+      type: object
+      properties:
+        id:
+          type: integer
+          format: int64
+        class_id:
+          $ref: "#/components/schemas/UUID"
+        user_id:
+          $ref: "#/components/schemas/UUID"
+        role:
+          $ref: "#/components/schemas/Role"
+        created_at:
+          type: string
+          format: date-time
+    Capacity:
+      type: object
+      properties:
+        seats:
+          description: The number of members regardless of role. Omit for no limit.
+          type: integer
+          minimum: 0
+          maximum: 10000
+        roles:
+          description: The number of members with each role.
+          type: object
+          properties:
+            student:
+              type: integer
+              minimum: 0
+              maximum: 10000
+            teacher:
+              type: integer
+              minimum: 0
+              maximum: 10000
+          additionalProperties: false
+    EnrollmentSettings:
+      type: object
+      properties:
+        opens_at:
+          type: string
+          format: date-time
+        closes_at:
+          type: string
+          format: date-time
+        locked:
+          type: boolean
+    Problem:
+      type: object
+      required: [type, title, status, code]
+      properties:
+        type:
+          type: string
+          format: uri
+        title:
+          type: string
+        status:
+          type: integer
+        detail:
+          type: string
+        code:
+          type: string
+          enum:
+            - not_found
+            - unauthorized
+            - forbidden
+            - user_enrolled
+            - must_set_owner
+            - waitlisted
+            - class_full
+            - invalid_capacity
+            - invalid_enrollment_window
+            - self_guardian
+            - bad_request
+            - validation_failed
+            - roster_locked
+            - enrollment_closed
+            - unsupported_version
+            - idempotency_key_reused
+            - idempotency_key_in_use
+            - request_too_large
+            - version_mismatch
+            - internal
+        correlation_id:
+          type: string
+        errors:
+          description: The fields that failed validation.
+          type: array
+          items:
+            type: object
+            properties:
+              field:
+                description: A JSON pointer into the body, or the name of a parameter.
+                type: string
+              reason:
+                type: string
+        error:
+          description: Repeats detail, for clients written before problems were introduced.
+          type: string
+  responses:
+    Empty:
+      description: The request succeeded.
+      content:
+        application/json:
+          schema:
+            type: object
+    BadRequest:
+      description: The request is malformed or invalid.
+      content:
+        application/problem+json:
+          schema:
+            $ref: "#/components/schemas/Problem"
+    Unauthorized:
+      description: The token is invalid or missing.
+      content:
+        application/problem+json:
+          schema:
+            $ref: "#/components/schemas/Problem"
+    Forbidden:
+      description: The caller may not perform the action.
+      content:
+        application/problem+json:
+          schema:
+            $ref: "#/components/schemas/Problem"
+    NotFound:
+      description: The resource does not exist, or the caller may not see it.
+      content:
+        application/problem+json:
+          schema:
+            $ref: "#/components/schemas/Problem"
+    ClassFull:
+      description: The class has no seats left for the role.
+      content:
+        application/problem+json:
+          schema:
+            $ref: "#/components/schemas/Problem"
+    Locked:
+      description: The roster is locked or enrollment is closed.
+      content:
+        application/problem+json:
+          schema:
+            $ref: "#/components/schemas/Problem"
+    PreconditionFailed:
+      description: The class or member has changed since the version given in If-Match.
+      content:
+        application/problem+json:
+          schema:
+            $ref: "#/components/schemas/Problem"
+    Internal:
+      description: The service failed.
+      content:
+        application/problem+json:
+          schema:
+            $ref: "#/components/schemas/Problem"
+`
+
+var (
+	openAPI       *openapi3.Swagger
+	openAPIRouter *openapi3filter.Router
+)
+
+func init() {
+	var err error
+	openAPI, err = openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(OpenAPISpec))
+	if err != nil {
+		panic("classsvc: invalid OpenAPI spec: " + err.Error())
+	}
+	// The filter only checks the parameters shared by a path when its operation declares some of
+	// its own, so give every operation a copy of them.
+	for _, item := range openAPI.Paths {
+		for _, op := range item.Operations() {
+			for _, p := range item.Parameters {
+				if op.Parameters.GetByInAndName(p.Value.In, p.Value.Name) == nil {
+					op.Parameters = append(op.Parameters, p)
+				}
+			}
+		}
+	}
+	// WithSwagger validates the spec, and panics if it is invalid.
+	openAPIRouter = openapi3filter.NewRouter().WithSwagger(openAPI)
+}
+
+// validateRequests rejects requests that do not match OpenAPISpec with 400 Bad Request, naming
+// the parameter or body that is invalid, before they reach the decoders. Requests are matched
+// against the paths of the spec under prefix, which is "/v2" for version 2, once the version
+// prefix has been stripped from them. Requests for routes the spec does not describe are passed
+// through, so that they 404 or 405 as before.
+func validateRequests(next http.Handler, prefix string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := *r.URL
+		u.Path, u.RawPath = prefix+u.Path, ""
+		route, pathParams, err := openAPIRouter.FindRoute(r.Method, &u)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		if r.Header.Get("Content-Type") == "" {
+			// The Go client sends JSON without saying so.
+			r.Header.Set("Content-Type", "application/json")
+		}
+		err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				// Tokens are checked by the introspector of each route.
+				AuthenticationFunc: func(context.Context, *openapi3filter.AuthenticationInput) error { return nil },
+			},
+		})
+		if err != nil {
+			encodeError(r.Context(), validationErrorFrom(err), w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validationErrorFrom names the field that failed validation and says what is wrong with it,
+// without the schema dump that the filter's own messages carry.
+func validationErrorFrom(err error) error {
+	reqErr, ok := err.(*openapi3filter.RequestError)
+	if !ok {
+		return ErrBadRequest
+	}
+	var field FieldError
+	field.Reason = reqErr.Reason
+	if schemaErr, ok := reqErr.Err.(*openapi3.SchemaError); ok {
+		field.Reason = schemaErr.Reason
+		pointer := schemaErr.JSONPointer()
+		if missing := missingProperty(schemaErr); missing != "" {
+			// The filter reports missing properties on the object that lacks them.
+			pointer, field.Reason = append(pointer, missing), "is required"
+		}
+		if len(pointer) > 0 {
+			field.Field = "/" + strings.Join(pointer, "/")
+		}
+	} else if reqErr.Err != nil {
+		if field.Reason != "" {
+			field.Reason += ": "
+		}
+		field.Reason += reqErr.Err.Error()
+	}
+	switch {
+	case reqErr.Parameter != nil:
+		field.Field = reqErr.Parameter.Name
+	case field.Field == "":
+		// The body as a whole, such as one that is not JSON.
+		field.Field = "/"
+	}
+	return &ValidationError{Fields: []FieldError{field}}
+}
+
+// missingProperty returns the first required property that err reports missing, if any.
+func missingProperty(err *openapi3.SchemaError) string {
+	value, ok := err.Value.(map[string]interface{})
+	if err.SchemaField != "required" || !ok || err.Schema == nil {
+		return ""
+	}
+	for _, name := range err.Schema.Required {
+		if _, ok := value[name]; !ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// MakeDocsHandler serves OpenAPISpec at /openapi.yaml and /openapi.json, and a browsable
+// reference of it at /docs.
+func MakeDocsHandler() http.Handler {
+	m := http.NewServeMux()
+	m.HandleFunc("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		w.Write([]byte(OpenAPISpec))
+	})
+	m.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(openAPI)
+	})
+	m.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(docsPage))
+	})
+	return m
+}
+
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+<title>Studiously class service</title>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+<redoc spec-url="/openapi.yaml"></redoc>
+<script src="https://cdn.jsdelivr.net/npm/redoc@2/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
@@ -0,0 +1,214 @@
+package classsvc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+)
+
+// ProblemTypeBase prefixes the type URI of every problem, which ends with its code.
+const ProblemTypeBase = "https://schemas.studiously.io/classsvc/problems/"
+
+// CorrelationIDHeader carries the ID that ties a response to the log lines of its request.
+// Callers may send their own; otherwise one is generated.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// Problem is an error response as described by RFC 7807, served as application/problem+json.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Code identifies the problem. Unlike Detail it never changes, so clients should match on it.
+	Code          string       `json:"code"`
+	CorrelationID string       `json:"correlation_id,omitempty"`
+	Errors        []FieldError `json:"errors,omitempty"`
+	// Error repeats Detail for version 1 clients, which read it before problems were introduced.
+	Error string `json:"error,omitempty"`
+}
+
+// FieldError describes what is wrong with one field of a request.
+type FieldError struct {
+	// Field is a JSON pointer into the body, such as "/name", or the name of a path or query
+	// parameter.
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// ValidationError is returned for requests with invalid fields, listing every problem found.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		problems[i] = f.Field + " " + f.Reason
+	}
+	return "the request has invalid fields: " + strings.Join(problems, "; ")
+}
+
+// errorCodes are the stable codes of the errors of this package.
+var errorCodes = map[error]string{
+	ErrNotFound:                "not_found",
+	ErrUnauthorized:            "unauthorized",
+	ErrForbidden:               "forbidden",
+	ErrUserEnrolled:            "user_enrolled",
+	ErrMustSetOwner:            "must_set_owner",
+	ErrWaitlisted:              "waitlisted",
+	ErrClassFull:               "class_full",
+	ErrInvalidCapacity:         "invalid_capacity",
+	ErrInvalidEnrollmentWindow: "invalid_enrollment_window",
+	ErrSelfGuardian:            "self_guardian",
+	ErrBadRequest:              "bad_request",
+	ErrRosterLocked:            "roster_locked",
+	ErrEnrollmentClosed:        "enrollment_closed",
+	ErrUnsupportedVersion:      "unsupported_version",
+	ErrIdempotencyKeyReused:    "idempotency_key_reused",
+	ErrIdempotencyKeyInUse:     "idempotency_key_in_use",
+	ErrRequestTooLarge:         "request_too_large",
+	ErrVersionMismatch:         "version_mismatch",
+	ErrInternal:                "internal",
+}
+
+// PublicError returns err if it may be shown to clients, as the errors of this package may, and
+// ErrInternal otherwise, so that nothing about database and other failures leaks.
+func PublicError(err error) error {
+	if _, ok := err.(*ValidationError); ok {
+		return err
+	}
+	if _, ok := errorCodes[err]; ok {
+		return err
+	}
+	return ErrInternal
+}
+
+// ErrorCode returns the stable code clients can match err on. Errors PublicError hides have the
+// code of ErrInternal.
+func ErrorCode(err error) string {
+	if _, ok := err.(*ValidationError); ok {
+		return "validation_failed"
+	}
+	return errorCodes[PublicError(err)]
+}
+
+// problemFrom describes err for clients. Errors this package does not know, such as those of
+// the database, are reported as ErrInternal so that nothing about them leaks.
+func problemFrom(ctx context.Context, err error) Problem {
+	p := newProblem(ctx, err, errorCodes[err], codeFrom(err))
+	if APIVersion(ctx) == "1" {
+		p.Error = p.Detail
+	}
+	return p
+}
+
+// newProblem describes err, which has code and status. Errors without a code are reported as
+// ErrInternal.
+func newProblem(ctx context.Context, err error, code string, status int) Problem {
+	p := Problem{
+		Detail:        err.Error(),
+		CorrelationID: CorrelationID(ctx),
+	}
+	if verr, ok := err.(*ValidationError); ok {
+		code, status, p.Errors = "validation_failed", http.StatusBadRequest, verr.Fields
+	}
+	if code == "" {
+		code, status, p.Detail = errorCodes[ErrInternal], http.StatusInternalServerError, ErrInternal.Error()
+	}
+	p.Code, p.Type = code, ProblemTypeBase+code
+	p.Status, p.Title = status, http.StatusText(status)
+	return p
+}
+
+// writeProblem answers with p, logging err if it is an internal error.
+func writeProblem(ctx context.Context, w http.ResponseWriter, err error, p Problem) {
+	if p.Status == http.StatusInternalServerError {
+		loggerFrom(ctx).Log("msg", "internal error", "error", err)
+	}
+	w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Problems describes the errors of the HTTP handlers of other packages, such as announcements
+// and webhooks, as problems with the same codes, correlation IDs and logging as the class
+// service. Handlers using it should be wrapped with WithCorrelationID.
+type Problems struct {
+	// Code gives the stable code of an error of the package, or "" for errors that may not be
+	// shown to clients. Those are reported as ErrInternal. Errors of this package, such as
+	// those Provisioning returns, keep their own codes, and their own status unless Status
+	// has none for them.
+	Code func(error) string
+	// Status gives the HTTP status of an error of the package.
+	Status func(error) int
+}
+
+// Problem describes err for clients. Error repeats Detail, as the handlers of other packages
+// answered with it before problems were introduced.
+func (ps Problems) Problem(ctx context.Context, err error) Problem {
+	code, status := ps.Code(err), ps.Status(err)
+	if known, ok := errorCodes[err]; ok && code == "" {
+		code = known
+		if status == http.StatusInternalServerError {
+			status = codeFrom(err)
+		}
+	}
+	p := newProblem(ctx, err, code, status)
+	p.Error = p.Detail
+	return p
+}
+
+// EncodeError answers with err as a problem. It is a go-kit ErrorEncoder.
+func (ps Problems) EncodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	writeProblem(ctx, w, err, ps.Problem(ctx, err))
+}
+
+// CorrelationID returns the correlation ID of the HTTP request being served, if any.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDContextKey).(string)
+	return id
+}
+
+// APIVersion returns the version of the HTTP API being served, if any.
+func APIVersion(ctx context.Context) string {
+	version, _ := ctx.Value(apiVersionContextKey).(string)
+	return version
+}
+
+// WithCorrelationID gives every request a correlation ID, echoed in CorrelationIDHeader, and a
+// logger that records it, for errors that are logged while the request is served.
+func WithCorrelationID(next http.Handler, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(CorrelationIDHeader)
+		if id == "" || len(id) > 128 {
+			id = uuid.New().String()
+		}
+		w.Header().Set(CorrelationIDHeader, id)
+		ctx := context.WithValue(r.Context(), correlationIDContextKey, id)
+		ctx = context.WithValue(ctx, loggerContextKey, log.With(logger, "correlation_id", id))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// correlationIDToHTTP passes the correlation ID of the request being served on to the class
+// service, so that the log lines of both share it.
+func correlationIDToHTTP(ctx context.Context, r *http.Request) context.Context {
+	if id := CorrelationID(ctx); id != "" {
+		r.Header.Set(CorrelationIDHeader, id)
+	}
+	return ctx
+}
+
+func loggerFrom(ctx context.Context) log.Logger {
+	if logger, ok := ctx.Value(loggerContextKey).(log.Logger); ok {
+		return logger
+	}
+	return log.NewNopLogger()
+}
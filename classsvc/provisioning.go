@@ -0,0 +1,301 @@
+package classsvc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+// Provisioning changes classes inside a transaction on behalf of a provisioning system, such as
+// a SCIM identity provider, rather than of a user. It applies the same seat limits, waitlists and
+// roster locks as the Service, and records every change as a system change.
+type Provisioning struct {
+	tx *sql.Tx
+	s  *postgresService
+}
+
+// Provision returns a Provisioning that changes classes inside tx and records the changes with
+// record, which may be nil.
+func Provision(tx *sql.Tx, record RecordFunc) *Provisioning {
+	return &Provisioning{tx, &postgresService{nil, record}}
+}
+
+// CreateClass creates an active class with no members.
+func (p *Provisioning) CreateClass(ctx context.Context, name string) (*models.Class, error) {
+	class := models.Class{
+		ID:          uuid.New(),
+		Name:        name,
+		CurrentUnit: uuid.Nil,
+		Active:      true,
+	}
+	err := saveClass(p.tx, &class)
+	if err != nil {
+		return nil, err
+	}
+	err = p.s.recordChange(ctx, p.tx, Change{Kind: ChangeCreateClass, System: true, ClassID: &class.ID, After: classState(&class)})
+	if err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// RenameClass renames an active class.
+func (p *Provisioning) RenameClass(ctx context.Context, classID uuid.UUID, name string) error {
+	class, err := lockClass(p.tx, classID)
+	if err != nil {
+		return err
+	}
+	if class.Name == name {
+		return nil
+	}
+	before := classState(class)
+	class.Name = name
+	err = saveClass(p.tx, class)
+	if err != nil {
+		return err
+	}
+	return p.s.recordChange(ctx, p.tx, Change{Kind: ChangeUpdateClass, System: true, ClassID: &classID, Before: before, After: classState(class)})
+}
+
+// ActivateClass activates a class that was deactivated, such as one a provisioning system lists
+// again after deleting it. Its members were removed when it was deactivated, so it has none.
+// Active classes are left as they are.
+func (p *Provisioning) ActivateClass(ctx context.Context, classID uuid.UUID) error {
+	_, err := p.tx.Exec("SELECT 1 FROM classes WHERE id=$1 FOR UPDATE;", classID)
+	if err != nil {
+		return err
+	}
+	class, err := models.ClassByID(p.tx, classID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil || class.Active {
+		return err
+	}
+	before := classState(class)
+	class.Active = true
+	err = saveClass(p.tx, class)
+	if err != nil {
+		return err
+	}
+	return p.s.recordChange(ctx, p.tx, Change{Kind: ChangeUpdateClass, System: true, ClassID: &classID, Before: before, After: classState(class)})
+}
+
+// Enroll enrolls a user in a class with role, like JoinClass: if the class has no seat left for
+// the role, the user is put on its waitlist and ErrWaitlisted is returned. Users already enrolled
+// or waitlisted are left as they are. Enrolling fails with ErrRosterLocked or ErrEnrollmentClosed
+// while the roster may not change.
+func (p *Provisioning) Enroll(ctx context.Context, classID, userID uuid.UUID, role models.UserRole) error {
+	class, err := lockClass(p.tx, classID)
+	if err != nil {
+		return err
+	}
+	_, err = models.MemberByUserIDClassID(p.tx, userID, classID)
+	switch err {
+	case nil:
+		return nil
+	case sql.ErrNoRows:
+	default:
+		return err
+	}
+	_, err = models.WaitlistEntryByClassIDUserID(p.tx, classID, userID)
+	switch err {
+	case nil:
+		return ErrWaitlisted
+	case sql.ErrNoRows:
+	default:
+		return err
+	}
+	err = rosterOpen(ctx, class, nil)
+	if err != nil {
+		return err
+	}
+	ok, err := hasSeat(p.tx, class, role)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		entry := models.WaitlistEntry{
+			ClassID:   classID,
+			UserID:    userID,
+			Role:      role,
+			CreatedAt: time.Now(),
+		}
+		err = entry.Insert(p.tx)
+		if err == nil {
+			err = p.s.recordChange(ctx, p.tx, Change{Kind: ChangeJoinWaitlist, System: true, ClassID: &classID, UserID: &userID, After: &entry})
+		}
+		if err != nil {
+			return err
+		}
+		return ErrWaitlisted
+	}
+	member := models.Member{
+		UserID:   userID,
+		ClassID:  classID,
+		Role:     role,
+		JoinedAt: time.Now(),
+	}
+	err = saveMember(p.tx, &member)
+	if err != nil {
+		return err
+	}
+	return p.s.recordChange(ctx, p.tx, Change{Kind: ChangeJoinClass, System: true, ClassID: &classID, UserID: &userID, After: &member})
+}
+
+// Remove removes a user from a class or its waitlist, like LeaveClass, giving a freed seat to
+// the next user on the waitlist. The owner cannot be removed until another owner is set. Users
+// who are neither enrolled nor waitlisted are ignored.
+func (p *Provisioning) Remove(ctx context.Context, classID, userID uuid.UUID) error {
+	class, err := lockClass(p.tx, classID)
+	if err != nil {
+		return err
+	}
+	member, err := models.MemberByUserIDClassID(p.tx, userID, classID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		entry, err := waitlistEntryOrNil(p.tx, classID, userID)
+		if err != nil || entry == nil {
+			return err
+		}
+		err = entry.Delete(p.tx)
+		if err != nil {
+			return err
+		}
+		return p.s.recordChange(ctx, p.tx, Change{Kind: ChangeLeaveWaitlist, System: true, ClassID: &classID, UserID: &userID, Before: entry})
+	default:
+		return err
+	}
+	if member.Owner {
+		return ErrMustSetOwner
+	}
+	err = rosterOpen(ctx, class, nil)
+	if err != nil {
+		return err
+	}
+	err = member.Delete(p.tx)
+	if err != nil {
+		return err
+	}
+	err = p.s.recordChange(ctx, p.tx, Change{Kind: ChangeLeaveClass, System: true, ClassID: &classID, UserID: &userID, Before: member})
+	if err != nil {
+		return err
+	}
+	promoted, err := promote(p.tx, class)
+	if err != nil {
+		return err
+	}
+	return p.s.recordPromotions(ctx, p.tx, promoted)
+}
+
+// SetRole changes the role of a member, like the Service's SetRole. It fails with ErrClassFull
+// if the new role has no seat left.
+func (p *Provisioning) SetRole(ctx context.Context, classID, userID uuid.UUID, role models.UserRole) error {
+	class, err := lockClass(p.tx, classID)
+	if err != nil {
+		return err
+	}
+	member, err := models.MemberByUserIDClassID(p.tx, userID, classID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil || member.Role == role {
+		return err
+	}
+	err = rosterOpen(ctx, class, nil)
+	if err != nil {
+		return err
+	}
+	full, err := roleFull(p.tx, classID, role)
+	if err != nil {
+		return err
+	}
+	if full {
+		return ErrClassFull
+	}
+	before := *member
+	member.Role = role
+	err = saveMember(p.tx, member)
+	if err != nil {
+		return err
+	}
+	return p.s.recordChange(ctx, p.tx, Change{Kind: ChangeSetRole, System: true, ClassID: &classID, UserID: &userID, Before: &before, After: member})
+}
+
+// SetOwner makes a user the owner of a class, enrolling them as a teacher if needed, or leaves
+// the class without an owner if userID is nil. The previous owner stays enrolled.
+func (p *Provisioning) SetOwner(ctx context.Context, classID uuid.UUID, userID *uuid.UUID) error {
+	_, err := lockClass(p.tx, classID)
+	if err != nil {
+		return err
+	}
+	members, err := models.MembersByClassID(p.tx, classID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if !m.Owner || (userID != nil && m.UserID == *userID) {
+			continue
+		}
+		before := *m
+		m.Owner = false
+		err = saveMember(p.tx, m)
+		if err == nil {
+			err = p.s.recordChange(ctx, p.tx, Change{Kind: ChangeTransferOwnership, System: true, ClassID: &classID, UserID: &m.UserID, Before: &before, After: m})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if userID == nil {
+		return nil
+	}
+	err = p.Enroll(ctx, classID, *userID, models.UserRoleTeacher)
+	switch err {
+	case nil:
+	case ErrWaitlisted:
+		return ErrClassFull
+	default:
+		return err
+	}
+	member, err := models.MemberByUserIDClassID(p.tx, *userID, classID)
+	if err != nil || member.Owner {
+		return err
+	}
+	before := *member
+	member.Owner = true
+	err = saveMember(p.tx, member)
+	if err != nil {
+		return err
+	}
+	return p.s.recordChange(ctx, p.tx, Change{Kind: ChangeTransferOwnership, System: true, ClassID: &classID, UserID: userID, Before: &before, After: member})
+}
+
+// DeactivateClass deactivates a class and removes its members, like DeleteClass.
+func (p *Provisioning) DeactivateClass(ctx context.Context, classID uuid.UUID) error {
+	class, err := lockClass(p.tx, classID)
+	if err != nil {
+		return err
+	}
+	return p.s.deactivate(ctx, p.tx, class, true)
+}
+
+// deactivate deactivates class and removes its members. system is set for changes made by a
+// provisioning system.
+func (s *postgresService) deactivate(ctx context.Context, tx *sql.Tx, class *models.Class, system bool) error {
+	before := classState(class)
+	class.Active = false
+	err := saveClass(tx, class)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM members WHERE class_id=$1;", class.ID)
+	if err != nil {
+		return err
+	}
+	return s.recordChange(ctx, tx, Change{Kind: ChangeDeleteClass, System: system, ClassID: &class.ID, Before: before, After: classState(class)})
+}
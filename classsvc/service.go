@@ -2,6 +2,7 @@ package classsvc
 
 import (
 	"context"
+	"time"
 
 	"errors"
 	"github.com/google/uuid"
@@ -15,32 +16,136 @@ var (
 	ErrMustSetOwner = errors.New("cannot demote self from owner unless new owner is set")
 	ErrUserEnrolled = errors.New("user is already enrolled in class")
 	ErrInternal     = errors.New("internal server error")
+	// ErrWaitlisted is returned by JoinClass when the class is full and the user has been put on its waitlist.
+	ErrWaitlisted      = errors.New("class is full; user has been added to the waitlist")
+	ErrClassFull       = errors.New("class has no seats left for role")
+	ErrInvalidCapacity = errors.New("capacity must not be negative")
+	// ErrRosterLocked and ErrEnrollmentClosed are returned when a roster change is refused
+	// because the roster is locked or the enrollment window is closed.
+	ErrRosterLocked            = errors.New("roster is locked")
+	ErrEnrollmentClosed        = errors.New("enrollment is closed")
+	ErrInvalidEnrollmentWindow = errors.New("enrollment must close after it opens")
+	ErrSelfGuardian            = errors.New("user cannot be their own guardian")
+	// ErrVersionMismatch is returned when a change is made against a version of a class or
+	// member, set with WithVersion, that is no longer current.
+	ErrVersionMismatch = errors.New("resource has changed since the given version")
 )
 
+// AdminScope is the OAuth2 scope held by organization admins, who may manage the roster of
+// any class and override roster locks.
+const AdminScope = "classes.admin"
+
+// EnrollmentSettings control when the roster of a class may change.
+type EnrollmentSettings struct {
+	// OpensAt and ClosesAt bound the enrollment window. Either may be nil to leave it open-ended.
+	OpensAt  *time.Time `json:"opens_at,omitempty"`
+	ClosesAt *time.Time `json:"closes_at,omitempty"`
+	// Locked freezes the roster regardless of the window.
+	Locked bool `json:"locked"`
+}
+
+type contextKey int
+
+const (
+	lockOverrideContextKey contextKey = iota
+	correlationIDContextKey
+	apiVersionContextKey
+	loggerContextKey
+	idempotencyKeyContextKey
+	idempotentRequestContextKey
+	versionContextKey
+)
+
+// WithLockOverride marks a request as overriding the roster lock and enrollment window.
+// Only the owner of the class or an organization admin may override; for anyone else the
+// request fails with ErrForbidden.
+func WithLockOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lockOverrideContextKey, true)
+}
+
+// LockOverride reports whether ctx overrides the roster lock.
+func LockOverride(ctx context.Context) bool {
+	override, _ := ctx.Value(lockOverrideContextKey).(bool)
+	return override
+}
+
+// WithVersion makes UpdateClass, DeleteClass, SetRole and LeaveClass fail with
+// ErrVersionMismatch unless the class or member they change is still at version, as read from
+// its Version. This keeps concurrent editors from overwriting each other.
+func WithVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, versionContextKey, version)
+}
+
+// Version returns the version set by WithVersion, if any.
+func Version(ctx context.Context) (int64, bool) {
+	version, ok := ctx.Value(versionContextKey).(int64)
+	return version, ok
+}
+
+// Capacity limits the number of members of a class.
+type Capacity struct {
+	// Seats limits the number of members regardless of role. Nil means unlimited.
+	Seats *int `json:"seats,omitempty"`
+	// Roles limits the number of members with a given role.
+	Roles map[models.UserRole]int `json:"roles,omitempty"`
+}
+
 type Middleware func(Service) Service
 
 // Service represents a Studiously class service.
 type Service interface {
-	// ListClasses gets all classes the current user is enrolled in.
+	// ListClasses gets all classes the current user is enrolled in, as well as the classes of
+	// students the current user is a guardian of.
 	ListClasses(ctx context.Context) ([]uuid.UUID, error)
 	// GetClass gets details for a specific class.
+	// Guardians may get the classes of their linked students.
 	GetClass(ctx context.Context, classID uuid.UUID) (*models.Class, error)
 	// CreateClass creates a class and enrolls the current user in it as an administrator.
 	CreateClass(ctx context.Context, name string) (*uuid.UUID, error)
 	// UpdateClass updates a class.
+	// Like DeleteClass, SetRole and LeaveClass, it fails with ErrVersionMismatch if ctx was
+	// given a version with WithVersion that the class or member is no longer at.
 	UpdateClass(ctx context.Context, classID uuid.UUID, name *string, currentUnit *uuid.UUID) error
 	// DeleteClass deactivates a class.
 	DeleteClass(ctx context.Context, classID uuid.UUID) error
 	// JoinClass enrolls the current user in a class.
+	// If the class is full, the user is added to the end of its waitlist and ErrWaitlisted is returned.
+	// Joining fails with ErrRosterLocked or ErrEnrollmentClosed while the roster may not change.
 	JoinClass(ctx context.Context, classID uuid.UUID) (error)
 	// LeaveClass causes a user to be un-enrolled from a class.
 	// If user is not nil, then LeaveClass removes the other user, requiring the current user to have elevated permissions.
+	// Users on the waitlist are removed from it. A freed seat is given to the next user on the waitlist.
+	// Leaving is subject to the roster lock and enrollment window, like JoinClass.
 	LeaveClass(ctx context.Context, userID *uuid.UUID, classID uuid.UUID) error
 	// SetRole sets the role of a user in a class.
 	// The current user must have a higher role than the target user.
+	// Roles cannot change while the roster is locked or enrollment is closed unless the lock is overridden.
 	SetRole(ctx context.Context, classID uuid.UUID, userID uuid.UUID, role models.UserRole) error
 	// ListMembers lists all members of a class and their role.
+	// Guardians of a student in the class only see its teachers, its owner and their own linked students.
 	ListMembers(ctx context.Context, classID uuid.UUID) ([]*models.Member, error)
 	// GetMember gets a member of a class.
 	GetMember(ctx context.Context, classID, userID uuid.UUID) (member *models.Member, err error)
+	// GetCapacity gets the seat limits of a class.
+	GetCapacity(ctx context.Context, classID uuid.UUID) (*Capacity, error)
+	// SetCapacity sets the seat limits of a class. Only the owner can set limits.
+	// Lowering a limit below the current number of members does not remove anyone; raising it
+	// enrolls waitlisted users into the new seats.
+	SetCapacity(ctx context.Context, classID uuid.UUID, capacity Capacity) error
+	// ListWaitlist lists the users waiting for a seat in a class, in the order they will be enrolled.
+	ListWaitlist(ctx context.Context, classID uuid.UUID) ([]*models.WaitlistEntry, error)
+	// GetEnrollmentSettings gets the enrollment window and roster lock of a class.
+	GetEnrollmentSettings(ctx context.Context, classID uuid.UUID) (*EnrollmentSettings, error)
+	// SetEnrollmentSettings sets the enrollment window and roster lock of a class.
+	// Only the owner or an organization admin can change them.
+	SetEnrollmentSettings(ctx context.Context, classID uuid.UUID, settings EnrollmentSettings) error
+	// LinkGuardian links a guardian to a student, giving the guardian read-only access to the
+	// student's classes. Only organization admins can link guardians.
+	LinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) error
+	// UnlinkGuardian removes the link between a guardian and a student. Only organization admins
+	// can unlink guardians.
+	UnlinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) error
+	// ListStudents lists the students linked to a guardian. Guardians can list their own students;
+	// organization admins can list the students of any guardian.
+	ListStudents(ctx context.Context, guardianID uuid.UUID) ([]uuid.UUID, error)
 }
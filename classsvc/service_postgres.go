@@ -3,9 +3,11 @@ package classsvc
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/ory/hydra/oauth2"
 	"github.com/studiously/classsvc/models"
 	"github.com/studiously/introspector"
@@ -13,10 +15,17 @@ import (
 
 type postgresService struct {
 	*sql.DB
+	record RecordFunc
 }
 
 func New(db *sql.DB) Service {
-	return &postgresService{db}
+	return &postgresService{db, nil}
+}
+
+// NewWithRecorder returns a Service that records every change with record, inside the
+// transaction that makes the change.
+func NewWithRecorder(db *sql.DB, record RecordFunc) Service {
+	return &postgresService{db, record}
 }
 
 func (s *postgresService) GetClass(ctx context.Context, classID uuid.UUID) (*models.Class, error) {
@@ -27,7 +36,13 @@ func (s *postgresService) GetClass(ctx context.Context, classID uuid.UUID) (*mod
 	}
 	_, err = models.MemberByUserIDClassID(s, subj, classID)
 	if err != nil {
-		return nil, ErrNotFound
+		guardian, err := guardianOf(s, subj, classID)
+		if err != nil {
+			return nil, err
+		}
+		if !guardian {
+			return nil, ErrNotFound
+		}
 	}
 	return models.ClassByID(s, classID)
 }
@@ -48,18 +63,24 @@ func (s *postgresService) CreateClass(ctx context.Context, name string) (*uuid.U
 		CurrentUnit: uuid.Nil,
 		Active:      true,
 	}
-	err = class.Save(tx)
+	err = saveClass(tx, &class)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 	member := models.Member{
-		UserID:  subj,
-		ClassID: class.ID,
-		Role:    models.UserRoleStudent,
-		Owner:   true,
+		UserID:   subj,
+		ClassID:  class.ID,
+		Role:     models.UserRoleStudent,
+		Owner:    true,
+		JoinedAt: time.Now(),
 	}
-	err = member.Save(tx)
+	err = saveMember(tx, &member)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = s.recordChange(ctx, tx, Change{Kind: ChangeCreateClass, ClassID: &class.ID, After: classState(&class)})
 	if err != nil {
 		tx.Rollback()
 		return nil, err
@@ -90,17 +111,41 @@ func (s *postgresService) UpdateClass(ctx context.Context, classID uuid.UUID, na
 	if !member.Owner {
 		return ErrForbidden
 	}
-	class, err := models.ClassByID(s, classID)
+	tx, err := s.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	class, err := lockClass(tx, classID)
+	if err == nil {
+		err = versionMatches(ctx, class.Version)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	before := classState(class)
 	if name != nil {
 		class.Name = *name
 	}
 	if currentUnit != nil {
 		class.CurrentUnit = *currentUnit // TODO validate current unit?
 	}
-	return class.Update(s)
+	err = saveClass(tx, class)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = s.recordChange(ctx, tx, Change{Kind: ChangeUpdateClass, ClassID: &classID, Before: before, After: classState(class)})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
 }
 
 func (s *postgresService) DeleteClass(ctx context.Context, classID uuid.UUID) error {
@@ -117,104 +162,376 @@ func (s *postgresService) DeleteClass(ctx context.Context, classID uuid.UUID) er
 	if !member.Owner {
 		return ErrForbidden
 	}
-	class, err := models.ClassByID(s, classID)
+	tx, err := s.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	tx, err := s.BeginTx(ctx, nil)
+	class, err := lockClass(tx, classID)
+	if err == nil {
+		err = versionMatches(ctx, class.Version)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = s.deactivate(ctx, tx, class, false)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
-	class.Active = false
-	class.Save(tx)
-	tx.Exec("DELETE FROM members WHERE class_id=$1;", class.ID)
 	err = tx.Commit()
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
-	return err
+	return nil
 }
 
 func (s *postgresService) ListMembers(ctx context.Context, classID uuid.UUID) ([]*models.Member, error) {
 	_, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
+	switch err {
+	case nil:
+		return models.MembersByClassID(s, classID)
+	case sql.ErrNoRows:
+	default:
+		return nil, err
+	}
+	guardian, err := guardianOf(s, subj(ctx), classID)
 	if err != nil {
-		switch err {
-		case sql.ErrNoRows:
-			// Either user is not in class or class does not exist.
-			return nil, ErrForbidden
-		default:
-			return nil, err
+		return nil, err
+	}
+	if !guardian {
+		// Either user is not in class or class does not exist.
+		return nil, ErrForbidden
+	}
+	links, err := models.GuardianLinksByGuardianID(s, subj(ctx))
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[uuid.UUID]bool, len(links))
+	for _, link := range links {
+		linked[link.StudentID] = true
+	}
+	members, err := models.MembersByClassID(s, classID)
+	if err != nil {
+		return nil, err
+	}
+	// Guardians see who teaches and owns the class of their students, but not the other students.
+	var results []*models.Member
+	for _, member := range members {
+		if member.Role == models.UserRoleTeacher || member.Owner || linked[member.UserID] {
+			results = append(results, member)
 		}
 	}
-	return models.MembersByClassID(s, classID)
+	return results, nil
 }
 
 func (s *postgresService) JoinClass(ctx context.Context, classID uuid.UUID) error {
-	_, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// A waitlisted join is committed but still reported with ErrWaitlisted.
+	userID := subj(ctx)
+	joinErr := join(ctx, tx, classID, userID)
+	if joinErr != nil && joinErr != ErrWaitlisted {
+		tx.Rollback()
+		return joinErr
+	}
+	change := Change{Kind: ChangeJoinClass, ClassID: &classID, UserID: &userID}
+	if joinErr == ErrWaitlisted {
+		change.Kind = ChangeJoinWaitlist
+		change.After, err = models.WaitlistEntryByClassIDUserID(tx, classID, userID)
+	} else {
+		change.After, err = models.MemberByUserIDClassID(tx, userID, classID)
+	}
 	if err == nil {
+		err = s.recordChange(ctx, tx, change)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return joinErr
+}
+
+func join(ctx context.Context, tx *sql.Tx, classID, userID uuid.UUID) error {
+	class, err := lockClass(tx, classID)
+	if err != nil {
+		return err
+	}
+	_, err = models.MemberByUserIDClassID(tx, userID, classID)
+	switch err {
+	case nil:
 		return ErrUserEnrolled
+	case sql.ErrNoRows:
+	default:
+		return err
+	}
+	err = rosterOpen(ctx, class, nil)
+	if err != nil {
+		return err
+	}
+	_, err = models.WaitlistEntryByClassIDUserID(tx, classID, userID)
+	switch err {
+	case nil:
+		return ErrWaitlisted
+	case sql.ErrNoRows:
+	default:
+		return err
+	}
+	ok, err := hasSeat(tx, class, models.UserRoleStudent)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		entry := models.WaitlistEntry{
+			ClassID:   classID,
+			UserID:    userID,
+			Role:      models.UserRoleStudent,
+			CreatedAt: time.Now(),
+		}
+		err = entry.Insert(tx)
+		if err != nil {
+			return err
+		}
+		return ErrWaitlisted
 	}
 	member := models.Member{
-		UserID:  subj(ctx),
-		ClassID: classID,
-		Role:    models.UserRoleStudent,
+		UserID:   userID,
+		ClassID:  classID,
+		Role:     models.UserRoleStudent,
+		JoinedAt: time.Now(),
 	}
-	return member.Save(s)
+	return saveMember(tx, &member)
 }
 
 func (s *postgresService) LeaveClass(ctx context.Context, userID *uuid.UUID, classID uuid.UUID) error {
-	self, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
+	tx, err := s.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	if userID != nil && *userID != subj(ctx) {
-		if !self.Owner {
-			return ErrForbidden
+	target := subj(ctx)
+	if userID != nil {
+		target = *userID
+	}
+	// Read the membership or waitlist entry being removed under the class lock.
+	_, err = lockClass(tx, classID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	member, err := memberOrNil(tx, classID, target)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	entry, err := waitlistEntryOrNil(tx, classID, target)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	promoted, err := leave(ctx, tx, subj(ctx), userID, classID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	change := Change{Kind: ChangeLeaveClass, ClassID: &classID, UserID: &target, Before: member}
+	if member == nil {
+		change.Kind, change.Before = ChangeLeaveWaitlist, entry
+	}
+	err = s.recordChange(ctx, tx, change)
+	if err == nil {
+		err = s.recordPromotions(ctx, tx, promoted)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func leave(ctx context.Context, tx *sql.Tx, self uuid.UUID, userID *uuid.UUID, classID uuid.UUID) ([]*models.WaitlistEntry, error) {
+	class, err := lockClass(tx, classID)
+	if err != nil {
+		return nil, err
+	}
+	me, err := models.MemberByUserIDClassID(tx, self, classID)
+	if userID != nil && *userID != self {
+		switch {
+		case err == nil:
+		case err == sql.ErrNoRows && isAdmin(ctx):
+			me = nil
+		case err == sql.ErrNoRows:
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+		if (me == nil || !me.Owner) && !isAdmin(ctx) {
+			return nil, ErrForbidden
+		}
+		target, err := models.MemberByUserIDClassID(tx, *userID, classID)
+		switch err {
+		case nil:
+		case sql.ErrNoRows:
+			return nil, leaveWaitlist(tx, classID, *userID)
+		default:
+			return nil, err
+		}
+		err = rosterOpen(ctx, class, me)
+		if err == nil {
+			err = versionMatches(ctx, target.Version)
 		}
-		target, err := models.MemberByUserIDClassID(s, *userID, classID)
 		if err != nil {
-			switch err {
-			case sql.ErrNoRows:
-				return ErrNotFound
-			default:
-				return err
-			}
-		}
-		return target.Delete(s)
+			return nil, err
+		}
+		err = target.Delete(tx)
+		if err != nil {
+			return nil, err
+		}
 	} else {
-		if self.Owner {
-			return ErrMustSetOwner
+		switch err {
+		case nil:
+		case sql.ErrNoRows:
+			return nil, leaveWaitlist(tx, classID, self)
+		default:
+			return nil, err
+		}
+		if me.Owner {
+			return nil, ErrMustSetOwner
 		}
-		return self.Delete(s)
+		err = rosterOpen(ctx, class, me)
+		if err == nil {
+			err = versionMatches(ctx, me.Version)
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = me.Delete(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return promote(tx, class)
+}
+
+// leaveWaitlist removes a user who is not enrolled in a class from its waitlist.
+func leaveWaitlist(tx *sql.Tx, classID, userID uuid.UUID) error {
+	entry, err := models.WaitlistEntryByClassIDUserID(tx, classID, userID)
+	switch err {
+	case nil:
+		return entry.Delete(tx)
+	case sql.ErrNoRows:
+		return ErrNotFound
+	default:
+		return err
 	}
 }
 
 func (s *postgresService) SetRole(ctx context.Context, classID uuid.UUID, userID uuid.UUID, role models.UserRole) error {
 	self, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
-	if err != nil {
+	switch {
+	case err == nil:
+	case err == sql.ErrNoRows && isAdmin(ctx):
+		self = nil
+	case err == sql.ErrNoRows:
+		return ErrForbidden
+	default:
 		return err
 	}
-	// Only the owner can set roles.
-	if !self.Owner {
+	// Only the owner or an organization admin can set roles.
+	if (self == nil || !self.Owner) && !isAdmin(ctx) {
 		return ErrForbidden
 	}
-	target, err := models.MemberByUserIDClassID(s, userID, classID)
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	class, err := lockClass(tx, classID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = rosterOpen(ctx, class, self)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	target, err := models.MemberByUserIDClassID(tx, userID, classID)
+	switch err {
+	case nil:
+		err = versionMatches(ctx, target.Version)
+	case sql.ErrNoRows:
+		err = ErrNotFound
+	}
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
+	if target.Role != role {
+		full, err := roleFull(tx, classID, role)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if full {
+			tx.Rollback()
+			return ErrClassFull
+		}
+	}
+	before := *target
 	target.Role = role
-	return target.Save(s)
+	err = saveMember(tx, target)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = s.recordChange(ctx, tx, Change{Kind: ChangeSetRole, ClassID: &classID, UserID: &userID, Before: &before, After: target})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
 }
+
 func (s *postgresService) ListClasses(ctx context.Context) ([]uuid.UUID, error) {
 	members, err := models.MembersByUserID(s, subj(ctx))
 	if err != nil {
 		return nil, err
 	}
+	links, err := models.GuardianLinksByGuardianID(s, subj(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		enrollments, err := models.MembersByUserID(s, link.StudentID)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, enrollments...)
+	}
 	var results []uuid.UUID
-	for i := range members {
-		results[i] = members[i].ClassID
+	seen := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		if !seen[member.ClassID] {
+			seen[member.ClassID] = true
+			results = append(results, member.ClassID)
+		}
 	}
 	return results, nil
 }
@@ -242,6 +559,487 @@ func (s *postgresService) GetMember(ctx context.Context, classID, userID uuid.UU
 	return member, nil
 }
 
+func (s *postgresService) GetCapacity(ctx context.Context, classID uuid.UUID) (*Capacity, error) {
+	_, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+	return capacityOf(s, classID)
+}
+
+func capacityOf(db models.XODB, classID uuid.UUID) (*Capacity, error) {
+	class, err := models.ClassByID(db, classID)
+	if err != nil {
+		return nil, err
+	}
+	var capacity Capacity
+	if class.Capacity.Valid {
+		seats := int(class.Capacity.Int64)
+		capacity.Seats = &seats
+	}
+	roles, err := models.RoleCapacitiesByClassID(db, classID)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) > 0 {
+		capacity.Roles = make(map[models.UserRole]int, len(roles))
+		for _, rc := range roles {
+			capacity.Roles[rc.Role] = rc.Capacity
+		}
+	}
+	return &capacity, nil
+}
+
+func (s *postgresService) SetCapacity(ctx context.Context, classID uuid.UUID, capacity Capacity) error {
+	if capacity.Seats != nil && *capacity.Seats < 0 {
+		return ErrInvalidCapacity
+	}
+	for _, n := range capacity.Roles {
+		if n < 0 {
+			return ErrInvalidCapacity
+		}
+	}
+	member, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+	if !member.Owner {
+		return ErrForbidden
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = lockClass(tx, classID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	before, err := capacityOf(tx, classID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	promoted, err := setCapacity(tx, classID, capacity)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	after, err := capacityOf(tx, classID)
+	if err == nil {
+		err = s.recordChange(ctx, tx, Change{Kind: ChangeSetCapacity, ClassID: &classID, Before: before, After: after})
+	}
+	if err == nil {
+		err = s.recordPromotions(ctx, tx, promoted)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func setCapacity(tx *sql.Tx, classID uuid.UUID, capacity Capacity) ([]*models.WaitlistEntry, error) {
+	class, err := lockClass(tx, classID)
+	if err != nil {
+		return nil, err
+	}
+	class.Capacity = sql.NullInt64{}
+	if capacity.Seats != nil {
+		class.Capacity = sql.NullInt64{Int64: int64(*capacity.Seats), Valid: true}
+	}
+	err = saveClass(tx, class)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec("DELETE FROM role_capacities WHERE class_id=$1;", classID)
+	if err != nil {
+		return nil, err
+	}
+	for role, n := range capacity.Roles {
+		rc := models.RoleCapacity{ClassID: classID, Role: role, Capacity: n}
+		err = rc.Insert(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return promote(tx, class)
+}
+
+func (s *postgresService) ListWaitlist(ctx context.Context, classID uuid.UUID) ([]*models.WaitlistEntry, error) {
+	member, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+	if !member.Owner && member.Role != models.UserRoleTeacher {
+		return nil, ErrForbidden
+	}
+	return models.WaitlistEntriesByClassID(s, classID)
+}
+
+// saveClass saves class and reads back the version the bump_version trigger gave it, so that
+// the changes recorded and the ETags served carry the current version.
+func saveClass(tx *sql.Tx, class *models.Class) error {
+	err := class.Save(tx)
+	if err != nil {
+		return err
+	}
+	return tx.QueryRow("SELECT version FROM classes WHERE id=$1;", class.ID).Scan(&class.Version)
+}
+
+// saveMember is the saveClass of members.
+func saveMember(tx *sql.Tx, member *models.Member) error {
+	err := member.Save(tx)
+	if err != nil {
+		return err
+	}
+	return tx.QueryRow("SELECT version FROM members WHERE user_id=$1 AND class_id=$2;", member.UserID, member.ClassID).Scan(&member.Version)
+}
+
+// lockClass gets an active class, locking it until tx ends so that concurrent
+// enrollments cannot take the same seat.
+func lockClass(tx *sql.Tx, classID uuid.UUID) (*models.Class, error) {
+	_, err := tx.Exec("SELECT 1 FROM classes WHERE id=$1 FOR UPDATE;", classID)
+	if err != nil {
+		return nil, err
+	}
+	class, err := models.ClassByID(tx, classID)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, err
+	case !class.Active:
+		return nil, ErrNotFound
+	}
+	return class, nil
+}
+
+// hasSeat reports whether a class can take another member with role.
+func hasSeat(tx *sql.Tx, class *models.Class, role models.UserRole) (bool, error) {
+	if class.Capacity.Valid {
+		var n int64
+		err := tx.QueryRow("SELECT count(*) FROM members WHERE class_id=$1;", class.ID).Scan(&n)
+		if err != nil {
+			return false, err
+		}
+		if n >= class.Capacity.Int64 {
+			return false, nil
+		}
+	}
+	full, err := roleFull(tx, class.ID, role)
+	return !full, err
+}
+
+// roleFull reports whether the members with role have taken all seats set aside for role.
+func roleFull(tx *sql.Tx, classID uuid.UUID, role models.UserRole) (bool, error) {
+	rc, err := models.RoleCapacityByClassIDRole(tx, classID, role)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return false, nil
+	default:
+		return false, err
+	}
+	var n int
+	err = tx.QueryRow("SELECT count(*) FROM members WHERE class_id=$1 AND role=$2;", classID, role).Scan(&n)
+	if err != nil {
+		return false, err
+	}
+	return n >= rc.Capacity, nil
+}
+
+// promote enrolls waitlisted users, in waitlist order, for as long as there are seats for them.
+// A user waiting for a role that is full does not hold up users waiting for other roles.
+func promote(tx *sql.Tx, class *models.Class) ([]*models.WaitlistEntry, error) {
+	entries, err := models.WaitlistEntriesByClassID(tx, class.ID)
+	if err != nil {
+		return nil, err
+	}
+	var promoted []*models.WaitlistEntry
+	for _, entry := range entries {
+		ok, err := hasSeat(tx, class, entry.Role)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		member := models.Member{
+			UserID:   entry.UserID,
+			ClassID:  entry.ClassID,
+			Role:     entry.Role,
+			JoinedAt: time.Now(),
+		}
+		err = saveMember(tx, &member)
+		if err != nil {
+			return nil, err
+		}
+		err = entry.Delete(tx)
+		if err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, entry)
+	}
+	return promoted, nil
+}
+
+func (s *postgresService) GetEnrollmentSettings(ctx context.Context, classID uuid.UUID) (*EnrollmentSettings, error) {
+	_, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
+	switch {
+	case err == nil:
+	case err == sql.ErrNoRows && isAdmin(ctx):
+	case err == sql.ErrNoRows:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+	class, err := models.ClassByID(s, classID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+	return enrollmentOf(class), nil
+}
+
+func enrollmentOf(class *models.Class) *EnrollmentSettings {
+	settings := EnrollmentSettings{Locked: class.RosterLocked}
+	if class.EnrollmentOpensAt.Valid {
+		opensAt := class.EnrollmentOpensAt.Time
+		settings.OpensAt = &opensAt
+	}
+	if class.EnrollmentClosesAt.Valid {
+		closesAt := class.EnrollmentClosesAt.Time
+		settings.ClosesAt = &closesAt
+	}
+	return &settings
+}
+
+func (s *postgresService) SetEnrollmentSettings(ctx context.Context, classID uuid.UUID, settings EnrollmentSettings) error {
+	if settings.OpensAt != nil && settings.ClosesAt != nil && !settings.ClosesAt.After(*settings.OpensAt) {
+		return ErrInvalidEnrollmentWindow
+	}
+	member, err := models.MemberByUserIDClassID(s, subj(ctx), classID)
+	switch {
+	case err == nil:
+		if !member.Owner && !isAdmin(ctx) {
+			return ErrForbidden
+		}
+	case err == sql.ErrNoRows && isAdmin(ctx):
+	case err == sql.ErrNoRows:
+		return ErrNotFound
+	default:
+		return err
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	class, err := lockClass(tx, classID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	before := enrollmentOf(class)
+	class.EnrollmentOpensAt = pq.NullTime{}
+	if settings.OpensAt != nil {
+		class.EnrollmentOpensAt = pq.NullTime{Time: *settings.OpensAt, Valid: true}
+	}
+	class.EnrollmentClosesAt = pq.NullTime{}
+	if settings.ClosesAt != nil {
+		class.EnrollmentClosesAt = pq.NullTime{Time: *settings.ClosesAt, Valid: true}
+	}
+	class.RosterLocked = settings.Locked
+	err = saveClass(tx, class)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = s.recordChange(ctx, tx, Change{Kind: ChangeSetEnrollmentSettings, ClassID: &classID, Before: before, After: enrollmentOf(class)})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// rosterOpen returns ErrRosterLocked or ErrEnrollmentClosed if the roster of class may not
+// change now. Owners and organization admins may override both by marking the context with
+// WithLockOverride; me is the current user's membership, if any.
+func rosterOpen(ctx context.Context, class *models.Class, me *models.Member) error {
+	if LockOverride(ctx) {
+		if (me != nil && me.Owner) || isAdmin(ctx) {
+			return nil
+		}
+		return ErrForbidden
+	}
+	if class.RosterLocked {
+		return ErrRosterLocked
+	}
+	now := time.Now()
+	if class.EnrollmentOpensAt.Valid && now.Before(class.EnrollmentOpensAt.Time) {
+		return ErrEnrollmentClosed
+	}
+	if class.EnrollmentClosesAt.Valid && !now.Before(class.EnrollmentClosesAt.Time) {
+		return ErrEnrollmentClosed
+	}
+	return nil
+}
+
+// versionMatches returns ErrVersionMismatch if ctx was given a version with WithVersion other
+// than current. Callers hold the class lock, so the version cannot change before they write.
+func versionMatches(ctx context.Context, current int64) error {
+	if version, ok := Version(ctx); ok && version != current {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// isAdmin reports whether the current token was granted AdminScope.
+func isAdmin(ctx context.Context) bool {
+	introspection, ok := ctx.Value(introspector.OAuth2IntrospectionContextKey).(oauth2.Introspection)
+	if !ok {
+		return false
+	}
+	for _, scope := range strings.Fields(introspection.Scope) {
+		if scope == AdminScope {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *postgresService) LinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) error {
+	if !isAdmin(ctx) {
+		return ErrForbidden
+	}
+	if guardianID == studentID {
+		return ErrSelfGuardian
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = models.GuardianLinkByGuardianIDStudentID(tx, guardianID, studentID)
+	switch err {
+	case nil:
+		tx.Rollback()
+		return nil
+	case sql.ErrNoRows:
+	default:
+		tx.Rollback()
+		return err
+	}
+	link := models.GuardianLink{
+		GuardianID: guardianID,
+		StudentID:  studentID,
+		CreatedAt:  time.Now(),
+	}
+	err = link.Insert(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = s.recordChange(ctx, tx, Change{Kind: ChangeLinkGuardian, UserID: &studentID, After: &link})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (s *postgresService) UnlinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) error {
+	if !isAdmin(ctx) {
+		return ErrForbidden
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	link, err := models.GuardianLinkByGuardianIDStudentID(tx, guardianID, studentID)
+	if err != nil {
+		tx.Rollback()
+		switch err {
+		case sql.ErrNoRows:
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+	err = link.Delete(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = s.recordChange(ctx, tx, Change{Kind: ChangeUnlinkGuardian, UserID: &studentID, Before: link})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (s *postgresService) ListStudents(ctx context.Context, guardianID uuid.UUID) ([]uuid.UUID, error) {
+	if guardianID != subj(ctx) && !isAdmin(ctx) {
+		return nil, ErrForbidden
+	}
+	links, err := models.GuardianLinksByGuardianID(s, guardianID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]uuid.UUID, len(links))
+	for i, link := range links {
+		results[i] = link.StudentID
+	}
+	return results, nil
+}
+
+// guardianOf reports whether a user is the guardian of a student enrolled in a class.
+func guardianOf(db models.XODB, guardianID, classID uuid.UUID) (bool, error) {
+	var guardian bool
+	err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM guardian_links g JOIN members m ON m.user_id = g.student_id `+
+		`WHERE g.guardian_id = $1 AND m.class_id = $2)`, guardianID, classID).Scan(&guardian)
+	return guardian, err
+}
+
 func subj(ctx context.Context) uuid.UUID {
 	return ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
 }
@@ -0,0 +1,404 @@
+package classsvc
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/dbtest"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+// as returns the context of a request made by userID with scopes, as the introspector middleware
+// leaves it.
+func as(userID uuid.UUID, scopes ...string) context.Context {
+	ctx := context.WithValue(context.Background(), introspector.OAuth2IntrospectionContextKey, oauth2.Introspection{
+		Active:  true,
+		Subject: userID.String(),
+		Scope:   strings.Join(scopes, " "),
+	})
+	return context.WithValue(ctx, introspector.SubjectContextKey, userID)
+}
+
+// newClass creates a class owned by owner.
+func newClass(t *testing.T, s Service, owner uuid.UUID) uuid.UUID {
+	classID, err := s.CreateClass(as(owner), "Algebra I")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *classID
+}
+
+// memberIDs returns the users enrolled in a class.
+func memberIDs(t *testing.T, db *sql.DB, classID uuid.UUID) map[uuid.UUID]bool {
+	members, err := models.MembersByClassID(db, classID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		ids[m.UserID] = true
+	}
+	return ids
+}
+
+// waiting returns the users on the waitlist of a class, in order.
+func waiting(t *testing.T, s Service, owner, classID uuid.UUID) []uuid.UUID {
+	entries, err := s.ListWaitlist(as(owner), classID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []uuid.UUID
+	for _, entry := range entries {
+		ids = append(ids, entry.UserID)
+	}
+	return ids
+}
+
+func TestWaitlist(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	s := New(db)
+	owner, a, b, c := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	classID := newClass(t, s, owner)
+
+	// The owner takes one of the two seats.
+	seats := 2
+	if err := s.SetCapacity(as(owner), classID, Capacity{Seats: &seats}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.JoinClass(as(a), classID); err != nil {
+		t.Fatalf("first join = %v; want a seat", err)
+	}
+	for _, userID := range []uuid.UUID{b, c, b} {
+		if err := s.JoinClass(as(userID), classID); err != ErrWaitlisted {
+			t.Fatalf("join of full class = %v; want %v", err, ErrWaitlisted)
+		}
+	}
+	if got, want := waiting(t, s, owner, classID), []uuid.UUID{b, c}; !reflect.DeepEqual(got, want) {
+		t.Errorf("waitlist = %v; want %v", got, want)
+	}
+	if _, err := s.ListWaitlist(as(a), classID); err != ErrForbidden {
+		t.Errorf("students listing the waitlist = %v; want %v", err, ErrForbidden)
+	}
+
+	// A freed seat goes to the head of the waitlist.
+	if err := s.LeaveClass(as(a), nil, classID); err != nil {
+		t.Fatal(err)
+	}
+	if members := memberIDs(t, db, classID); members[a] || !members[b] || members[c] {
+		t.Errorf("members = %v; want b promoted in place of a", members)
+	}
+	if got, want := waiting(t, s, owner, classID), []uuid.UUID{c}; !reflect.DeepEqual(got, want) {
+		t.Errorf("waitlist = %v; want %v", got, want)
+	}
+
+	// Raising the limit promotes everyone who fits.
+	seats = 4
+	if err := s.SetCapacity(as(owner), classID, Capacity{Seats: &seats}); err != nil {
+		t.Fatal(err)
+	}
+	if members := memberIDs(t, db, classID); !members[c] {
+		t.Errorf("members = %v; want c promoted into a new seat", members)
+	}
+	if got := waiting(t, s, owner, classID); len(got) != 0 {
+		t.Errorf("waitlist = %v; want it empty", got)
+	}
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	s := New(db)
+	owner, a := uuid.New(), uuid.New()
+	classID := newClass(t, s, owner)
+	seats := 1
+	if err := s.SetCapacity(as(owner), classID, Capacity{Seats: &seats}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.JoinClass(as(a), classID); err != ErrWaitlisted {
+		t.Fatalf("join of full class = %v; want %v", err, ErrWaitlisted)
+	}
+	if err := s.LeaveClass(as(a), nil, classID); err != nil {
+		t.Fatal(err)
+	}
+	if got := waiting(t, s, owner, classID); len(got) != 0 {
+		t.Errorf("waitlist = %v; want it empty", got)
+	}
+	if err := s.LeaveClass(as(a), nil, classID); err != ErrNotFound {
+		t.Errorf("leaving again = %v; want %v", err, ErrNotFound)
+	}
+}
+
+func TestRoleCapacity(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	s := New(db)
+	owner, a, b := uuid.New(), uuid.New(), uuid.New()
+	classID := newClass(t, s, owner)
+	for _, userID := range []uuid.UUID{a, b} {
+		if err := s.JoinClass(as(userID), classID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	capacity := Capacity{Roles: map[models.UserRole]int{models.UserRoleTeacher: 1}}
+	if err := s.SetCapacity(as(owner), classID, capacity); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetCapacity(as(a), classID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*got, capacity) {
+		t.Errorf("capacity = %+v; want %+v", *got, capacity)
+	}
+	if err := s.SetRole(as(owner), classID, a, models.UserRoleTeacher); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetRole(as(owner), classID, b, models.UserRoleTeacher); err != ErrClassFull {
+		t.Errorf("second teacher = %v; want %v", err, ErrClassFull)
+	}
+}
+
+func TestSetCapacityInvalid(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	s := New(db)
+	owner, a := uuid.New(), uuid.New()
+	classID := newClass(t, s, owner)
+	if err := s.JoinClass(as(a), classID); err != nil {
+		t.Fatal(err)
+	}
+	seats := -1
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		capacity Capacity
+		want     error
+	}{
+		{"negative seats", as(owner), Capacity{Seats: &seats}, ErrInvalidCapacity},
+		{"negative role seats", as(owner), Capacity{Roles: map[models.UserRole]int{models.UserRoleStudent: -1}}, ErrInvalidCapacity},
+		{"not the owner", as(a), Capacity{}, ErrForbidden},
+		{"not a member", as(uuid.New()), Capacity{}, ErrNotFound},
+	}
+	for _, tt := range tests {
+		if err := s.SetCapacity(tt.ctx, classID, tt.capacity); err != tt.want {
+			t.Errorf("%s: err = %v; want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRosterOpen(t *testing.T) {
+	owner := &models.Member{Owner: true}
+	teacher := &models.Member{Role: models.UserRoleTeacher}
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		class models.Class
+		me    *models.Member
+		want  error
+	}{
+		{"open", as(uuid.New()), models.Class{}, nil, nil},
+		{"locked", as(uuid.New()), models.Class{RosterLocked: true}, teacher, ErrRosterLocked},
+		{"not open yet", as(uuid.New()), models.Class{EnrollmentOpensAt: pq.NullTime{Time: future, Valid: true}}, nil, ErrEnrollmentClosed},
+		{"closed", as(uuid.New()), models.Class{EnrollmentClosesAt: pq.NullTime{Time: past, Valid: true}}, nil, ErrEnrollmentClosed},
+		{"within the window", as(uuid.New()), models.Class{
+			EnrollmentOpensAt:  pq.NullTime{Time: past, Valid: true},
+			EnrollmentClosesAt: pq.NullTime{Time: future, Valid: true},
+		}, nil, nil},
+		{"locked without override", as(uuid.New()), models.Class{RosterLocked: true}, owner, ErrRosterLocked},
+		{"owner override", WithLockOverride(as(uuid.New())), models.Class{RosterLocked: true}, owner, nil},
+		{"admin override", WithLockOverride(as(uuid.New(), AdminScope)), models.Class{EnrollmentClosesAt: pq.NullTime{Time: past, Valid: true}}, nil, nil},
+		{"teacher override", WithLockOverride(as(uuid.New())), models.Class{RosterLocked: true}, teacher, ErrForbidden},
+		{"override of an open roster", WithLockOverride(as(uuid.New())), models.Class{}, nil, ErrForbidden},
+	}
+	for _, tt := range tests {
+		if err := rosterOpen(tt.ctx, &tt.class, tt.me); err != tt.want {
+			t.Errorf("%s: err = %v; want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRosterLock(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	s := New(db)
+	owner, a, b := uuid.New(), uuid.New(), uuid.New()
+	classID := newClass(t, s, owner)
+	if err := s.JoinClass(as(a), classID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetEnrollmentSettings(as(a), classID, EnrollmentSettings{Locked: true}); err != ErrForbidden {
+		t.Errorf("students locking the roster = %v; want %v", err, ErrForbidden)
+	}
+	if err := s.SetEnrollmentSettings(as(owner), classID, EnrollmentSettings{Locked: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.JoinClass(as(b), classID); err != ErrRosterLocked {
+		t.Errorf("join = %v; want %v", err, ErrRosterLocked)
+	}
+	if err := s.LeaveClass(as(a), nil, classID); err != ErrRosterLocked {
+		t.Errorf("leave = %v; want %v", err, ErrRosterLocked)
+	}
+	if err := s.LeaveClass(WithLockOverride(as(a)), nil, classID); err != ErrForbidden {
+		t.Errorf("leave overriding the lock as a student = %v; want %v", err, ErrForbidden)
+	}
+	if err := s.LeaveClass(as(owner), &a, classID); err != ErrRosterLocked {
+		t.Errorf("removal by the owner = %v; want %v", err, ErrRosterLocked)
+	}
+	if err := s.LeaveClass(WithLockOverride(as(owner)), &a, classID); err != nil {
+		t.Errorf("removal by the owner overriding the lock = %v; want it removed", err)
+	}
+	if members := memberIDs(t, db, classID); members[a] {
+		t.Errorf("members = %v; want a removed", members)
+	}
+
+	// Admins may unlock any class, and an enrollment window applies once it is.
+	closed := time.Now().Add(-time.Minute)
+	opened := closed.Add(-time.Hour)
+	settings := EnrollmentSettings{OpensAt: &opened, ClosesAt: &closed}
+	if err := s.SetEnrollmentSettings(as(uuid.New(), AdminScope), classID, settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.JoinClass(as(b), classID); err != ErrEnrollmentClosed {
+		t.Errorf("join after the window = %v; want %v", err, ErrEnrollmentClosed)
+	}
+	if err := s.SetEnrollmentSettings(as(owner), classID, EnrollmentSettings{OpensAt: &closed, ClosesAt: &opened}); err != ErrInvalidEnrollmentWindow {
+		t.Errorf("window closing before it opens = %v; want %v", err, ErrInvalidEnrollmentWindow)
+	}
+}
+
+func TestGuardianVisibility(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	s := New(db)
+	owner, teacher, student, classmate, guardian := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	admin := as(uuid.New(), AdminScope)
+	classID := newClass(t, s, owner)
+	for _, userID := range []uuid.UUID{teacher, student, classmate} {
+		if err := s.JoinClass(as(userID), classID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SetRole(as(owner), classID, teacher, models.UserRoleTeacher); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.LinkGuardian(as(guardian), guardian, student); err != ErrForbidden {
+		t.Errorf("guardians linking themselves = %v; want %v", err, ErrForbidden)
+	}
+	if err := s.LinkGuardian(admin, student, student); err != ErrSelfGuardian {
+		t.Errorf("self link = %v; want %v", err, ErrSelfGuardian)
+	}
+	if _, err := s.ListMembers(as(guardian), classID); err != ErrForbidden {
+		t.Errorf("members before the link = %v; want %v", err, ErrForbidden)
+	}
+	if err := s.LinkGuardian(admin, guardian, student); err != nil {
+		t.Fatal(err)
+	}
+
+	classes, err := s.ListClasses(as(guardian))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(classes, []uuid.UUID{classID}) {
+		t.Errorf("classes = %v; want the class of the student", classes)
+	}
+	if _, err := s.GetClass(as(guardian), classID); err != nil {
+		t.Errorf("class = %v; want it visible", err)
+	}
+	members, err := s.ListMembers(as(guardian), classID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		seen[m.UserID] = true
+	}
+	if want := map[uuid.UUID]bool{owner: true, teacher: true, student: true}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("members = %v; want the owner, the teacher and the linked student only", seen)
+	}
+	students, err := s.ListStudents(as(guardian), guardian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(students, []uuid.UUID{student}) {
+		t.Errorf("students = %v; want %v", students, []uuid.UUID{student})
+	}
+	if _, err := s.ListStudents(as(classmate), guardian); err != ErrForbidden {
+		t.Errorf("students of another guardian = %v; want %v", err, ErrForbidden)
+	}
+
+	// Visibility is read-only.
+	name := "Geometry"
+	if err := s.UpdateClass(as(guardian), classID, &name, nil); err != ErrNotFound {
+		t.Errorf("update by a guardian = %v; want %v", err, ErrNotFound)
+	}
+	if err := s.LeaveClass(as(guardian), &student, classID); err != ErrNotFound {
+		t.Errorf("removal by a guardian = %v; want %v", err, ErrNotFound)
+	}
+
+	if err := s.UnlinkGuardian(admin, guardian, student); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetClass(as(guardian), classID); err != ErrNotFound {
+		t.Errorf("class after unlinking = %v; want %v", err, ErrNotFound)
+	}
+	if err := s.UnlinkGuardian(admin, guardian, student); err != ErrNotFound {
+		t.Errorf("unlinking again = %v; want %v", err, ErrNotFound)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	s := New(db)
+	owner, a := uuid.New(), uuid.New()
+	classID := newClass(t, s, owner)
+	if err := s.JoinClass(as(a), classID); err != nil {
+		t.Fatal(err)
+	}
+
+	class, err := s.GetClass(as(owner), classID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := class.Version
+	name := "Algebra II"
+	if err := s.UpdateClass(WithVersion(as(owner), read), classID, &name, nil); err != nil {
+		t.Fatalf("update at the version read = %v", err)
+	}
+	class, err = s.GetClass(as(owner), classID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if class.Version == read {
+		t.Fatalf("version = %d after an update; want it changed", class.Version)
+	}
+	if err := s.UpdateClass(WithVersion(as(owner), read), classID, &name, nil); err != ErrVersionMismatch {
+		t.Errorf("update at a stale version = %v; want %v", err, ErrVersionMismatch)
+	}
+	if err := s.DeleteClass(WithVersion(as(owner), read), classID); err != ErrVersionMismatch {
+		t.Errorf("delete at a stale version = %v; want %v", err, ErrVersionMismatch)
+	}
+
+	member, err := s.GetMember(as(owner), classID, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetRole(WithVersion(as(owner), member.Version+1), classID, a, models.UserRoleTeacher); err != ErrVersionMismatch {
+		t.Errorf("set role at another version = %v; want %v", err, ErrVersionMismatch)
+	}
+	if err := s.SetRole(WithVersion(as(owner), member.Version), classID, a, models.UserRoleTeacher); err != nil {
+		t.Errorf("set role at the version read = %v", err)
+	}
+}
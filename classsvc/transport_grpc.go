@@ -0,0 +1,1089 @@
+package classsvc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/google/uuid"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/classsvc/pb"
+	"github.com/studiously/introspector"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// authorizationMetadataKey carries the bearer token of the caller, like the HTTP
+	// Authorization header.
+	authorizationMetadataKey = "authorization"
+	// lockOverrideMetadataKey is the gRPC counterpart of ?override_lock=true.
+	lockOverrideMetadataKey = "override-lock"
+	// versionMetadataKey is the gRPC counterpart of the If-Match header. It carries the version
+	// a change is made against, as a bare number.
+	versionMetadataKey = "if-match"
+)
+
+type grpcServer struct {
+	listClasses           grpctransport.Handler
+	getClass              grpctransport.Handler
+	createClass           grpctransport.Handler
+	updateClass           grpctransport.Handler
+	deleteClass           grpctransport.Handler
+	joinClass             grpctransport.Handler
+	leaveClass            grpctransport.Handler
+	setRole               grpctransport.Handler
+	listMembers           grpctransport.Handler
+	getMember             grpctransport.Handler
+	getCapacity           grpctransport.Handler
+	setCapacity           grpctransport.Handler
+	listWaitlist          grpctransport.Handler
+	getEnrollmentSettings grpctransport.Handler
+	setEnrollmentSettings grpctransport.Handler
+	linkGuardian          grpctransport.Handler
+	unlinkGuardian        grpctransport.Handler
+	listStudents          grpctransport.Handler
+}
+
+// MakeGRPCServer makes the class service available as a pb.ClassesServer. Every RPC requires
+// the same scope as its HTTP route.
+func MakeGRPCServer(s Service, introspection oauth2.Introspector, logger log.Logger) pb.ClassesServer {
+	e := MakeServerEndpoints(s)
+	options := []grpctransport.ServerOption{
+		grpctransport.ServerErrorLogger(logger),
+		grpctransport.ServerBefore(tokenToGRPCContext, lockOverrideToGRPCContext, versionToGRPCContext),
+	}
+	server := func(scope string, e endpoint.Endpoint, dec grpctransport.DecodeRequestFunc, enc grpctransport.EncodeResponseFunc) grpctransport.Handler {
+		return grpctransport.NewServer(introspector.New(introspection, scope)(e), dec, enc, options...)
+	}
+
+	return &grpcServer{
+		listClasses:           server("classes.list", e.ListClassesEndpoint, decodeGRPCListClassesRequest, encodeGRPCListClassesResponse),
+		getClass:              server("classes.get", e.GetClassEndpoint, decodeGRPCGetClassRequest, encodeGRPCGetClassResponse),
+		createClass:           server("classes.create", e.CreateClassEndpoint, decodeGRPCCreateClassRequest, encodeGRPCCreateClassResponse),
+		updateClass:           server("classes.update", e.UpdateClassEndpoint, decodeGRPCUpdateClassRequest, encodeGRPCUpdateClassResponse),
+		deleteClass:           server("classes.delete", e.DeleteClassEndpoint, decodeGRPCDeleteClassRequest, encodeGRPCDeleteClassResponse),
+		joinClass:             server("classes.join", e.JoinClassEndpoint, decodeGRPCJoinClassRequest, encodeGRPCJoinClassResponse),
+		leaveClass:            server("classes.leave", e.LeaveClassEndpoint, decodeGRPCLeaveClassRequest, encodeGRPCLeaveClassResponse),
+		setRole:               server("classes.members.update", e.SetRoleEndpoint, decodeGRPCSetRoleRequest, encodeGRPCSetRoleResponse),
+		listMembers:           server("classes.members.list", e.ListMembersEndpoint, decodeGRPCListMembersRequest, encodeGRPCListMembersResponse),
+		getMember:             server("classes.members.get", e.GetMemberEndpoint, decodeGRPCGetMemberRequest, encodeGRPCGetMemberResponse),
+		getCapacity:           server("classes.capacity.get", e.GetCapacityEndpoint, decodeGRPCGetCapacityRequest, encodeGRPCGetCapacityResponse),
+		setCapacity:           server("classes.capacity.update", e.SetCapacityEndpoint, decodeGRPCSetCapacityRequest, encodeGRPCSetCapacityResponse),
+		listWaitlist:          server("classes.waitlist.list", e.ListWaitlistEndpoint, decodeGRPCListWaitlistRequest, encodeGRPCListWaitlistResponse),
+		getEnrollmentSettings: server("classes.enrollment.get", e.GetEnrollmentSettingsEndpoint, decodeGRPCGetEnrollmentSettingsRequest, encodeGRPCGetEnrollmentSettingsResponse),
+		setEnrollmentSettings: server("classes.enrollment.update", e.SetEnrollmentSettingsEndpoint, decodeGRPCSetEnrollmentSettingsRequest, encodeGRPCSetEnrollmentSettingsResponse),
+		linkGuardian:          server("classes.guardians.update", e.LinkGuardianEndpoint, decodeGRPCLinkGuardianRequest, encodeGRPCLinkGuardianResponse),
+		unlinkGuardian:        server("classes.guardians.update", e.UnlinkGuardianEndpoint, decodeGRPCUnlinkGuardianRequest, encodeGRPCUnlinkGuardianResponse),
+		listStudents:          server("classes.guardians.list", e.ListStudentsEndpoint, decodeGRPCListStudentsRequest, encodeGRPCListStudentsResponse),
+	}
+}
+
+// serviceError is returned by the response encoders in place of a reply when the service
+// failed, so that serve can turn it into a status without go-kit logging it as a transport
+// error.
+type serviceError struct {
+	err error
+}
+
+func serve(ctx context.Context, h grpctransport.Handler, req interface{}) (interface{}, error) {
+	_, rep, err := h.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := rep.(serviceError); ok {
+		return nil, status.Error(grpcCodeFrom(e.err), PublicError(e.err).Error())
+	}
+	return rep, nil
+}
+
+func (s *grpcServer) ListClasses(ctx context.Context, req *pb.ListClassesRequest) (*pb.ListClassesReply, error) {
+	rep, err := serve(ctx, s.listClasses, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.ListClassesReply), nil
+}
+
+func (s *grpcServer) GetClass(ctx context.Context, req *pb.GetClassRequest) (*pb.GetClassReply, error) {
+	rep, err := serve(ctx, s.getClass, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.GetClassReply), nil
+}
+
+func (s *grpcServer) CreateClass(ctx context.Context, req *pb.CreateClassRequest) (*pb.CreateClassReply, error) {
+	rep, err := serve(ctx, s.createClass, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.CreateClassReply), nil
+}
+
+func (s *grpcServer) UpdateClass(ctx context.Context, req *pb.UpdateClassRequest) (*pb.UpdateClassReply, error) {
+	rep, err := serve(ctx, s.updateClass, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.UpdateClassReply), nil
+}
+
+func (s *grpcServer) DeleteClass(ctx context.Context, req *pb.DeleteClassRequest) (*pb.DeleteClassReply, error) {
+	rep, err := serve(ctx, s.deleteClass, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.DeleteClassReply), nil
+}
+
+func (s *grpcServer) JoinClass(ctx context.Context, req *pb.JoinClassRequest) (*pb.JoinClassReply, error) {
+	rep, err := serve(ctx, s.joinClass, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.JoinClassReply), nil
+}
+
+func (s *grpcServer) LeaveClass(ctx context.Context, req *pb.LeaveClassRequest) (*pb.LeaveClassReply, error) {
+	rep, err := serve(ctx, s.leaveClass, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.LeaveClassReply), nil
+}
+
+func (s *grpcServer) SetRole(ctx context.Context, req *pb.SetRoleRequest) (*pb.SetRoleReply, error) {
+	rep, err := serve(ctx, s.setRole, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.SetRoleReply), nil
+}
+
+func (s *grpcServer) ListMembers(ctx context.Context, req *pb.ListMembersRequest) (*pb.ListMembersReply, error) {
+	rep, err := serve(ctx, s.listMembers, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.ListMembersReply), nil
+}
+
+func (s *grpcServer) GetMember(ctx context.Context, req *pb.GetMemberRequest) (*pb.GetMemberReply, error) {
+	rep, err := serve(ctx, s.getMember, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.GetMemberReply), nil
+}
+
+func (s *grpcServer) GetCapacity(ctx context.Context, req *pb.GetCapacityRequest) (*pb.GetCapacityReply, error) {
+	rep, err := serve(ctx, s.getCapacity, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.GetCapacityReply), nil
+}
+
+func (s *grpcServer) SetCapacity(ctx context.Context, req *pb.SetCapacityRequest) (*pb.SetCapacityReply, error) {
+	rep, err := serve(ctx, s.setCapacity, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.SetCapacityReply), nil
+}
+
+func (s *grpcServer) ListWaitlist(ctx context.Context, req *pb.ListWaitlistRequest) (*pb.ListWaitlistReply, error) {
+	rep, err := serve(ctx, s.listWaitlist, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.ListWaitlistReply), nil
+}
+
+func (s *grpcServer) GetEnrollmentSettings(ctx context.Context, req *pb.GetEnrollmentSettingsRequest) (*pb.GetEnrollmentSettingsReply, error) {
+	rep, err := serve(ctx, s.getEnrollmentSettings, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.GetEnrollmentSettingsReply), nil
+}
+
+func (s *grpcServer) SetEnrollmentSettings(ctx context.Context, req *pb.SetEnrollmentSettingsRequest) (*pb.SetEnrollmentSettingsReply, error) {
+	rep, err := serve(ctx, s.setEnrollmentSettings, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.SetEnrollmentSettingsReply), nil
+}
+
+func (s *grpcServer) LinkGuardian(ctx context.Context, req *pb.LinkGuardianRequest) (*pb.LinkGuardianReply, error) {
+	rep, err := serve(ctx, s.linkGuardian, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.LinkGuardianReply), nil
+}
+
+func (s *grpcServer) UnlinkGuardian(ctx context.Context, req *pb.UnlinkGuardianRequest) (*pb.UnlinkGuardianReply, error) {
+	rep, err := serve(ctx, s.unlinkGuardian, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.UnlinkGuardianReply), nil
+}
+
+func (s *grpcServer) ListStudents(ctx context.Context, req *pb.ListStudentsRequest) (*pb.ListStudentsReply, error) {
+	rep, err := serve(ctx, s.listStudents, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.ListStudentsReply), nil
+}
+
+// NewGRPCClient returns a Service backed by a gRPC server at the other end of conn. Service
+// errors are mapped back to the errors of this package, so callers can compare them as they
+// would with a local Service.
+func NewGRPCClient(conn *grpc.ClientConn) Service {
+	options := []grpctransport.ClientOption{
+		grpctransport.ClientBefore(tokenFromGRPCContext, lockOverrideFromGRPCContext, versionFromGRPCContext),
+	}
+	client := func(method string, enc grpctransport.EncodeRequestFunc, dec grpctransport.DecodeResponseFunc, reply interface{}) endpoint.Endpoint {
+		e := grpctransport.NewClient(conn, "pb.Classes", method, enc, dec, reply, options...).Endpoint()
+		return errorFromGRPCStatus(e)
+	}
+
+	return Endpoints{
+		ListClassesEndpoint:           client("ListClasses", encodeGRPCListClassesRequest, decodeGRPCListClassesResponse, pb.ListClassesReply{}),
+		GetClassEndpoint:              client("GetClass", encodeGRPCGetClassRequest, decodeGRPCGetClassResponse, pb.GetClassReply{}),
+		CreateClassEndpoint:           client("CreateClass", encodeGRPCCreateClassRequest, decodeGRPCCreateClassResponse, pb.CreateClassReply{}),
+		UpdateClassEndpoint:           client("UpdateClass", encodeGRPCUpdateClassRequest, decodeGRPCUpdateClassResponse, pb.UpdateClassReply{}),
+		DeleteClassEndpoint:           client("DeleteClass", encodeGRPCDeleteClassRequest, decodeGRPCDeleteClassResponse, pb.DeleteClassReply{}),
+		JoinClassEndpoint:             client("JoinClass", encodeGRPCJoinClassRequest, decodeGRPCJoinClassResponse, pb.JoinClassReply{}),
+		SetRoleEndpoint:               client("SetRole", encodeGRPCSetRoleRequest, decodeGRPCSetRoleResponse, pb.SetRoleReply{}),
+		LeaveClassEndpoint:            client("LeaveClass", encodeGRPCLeaveClassRequest, decodeGRPCLeaveClassResponse, pb.LeaveClassReply{}),
+		ListMembersEndpoint:           client("ListMembers", encodeGRPCListMembersRequest, decodeGRPCListMembersResponse, pb.ListMembersReply{}),
+		GetMemberEndpoint:             client("GetMember", encodeGRPCGetMemberRequest, decodeGRPCGetMemberResponse, pb.GetMemberReply{}),
+		GetCapacityEndpoint:           client("GetCapacity", encodeGRPCGetCapacityRequest, decodeGRPCGetCapacityResponse, pb.GetCapacityReply{}),
+		SetCapacityEndpoint:           client("SetCapacity", encodeGRPCSetCapacityRequest, decodeGRPCSetCapacityResponse, pb.SetCapacityReply{}),
+		ListWaitlistEndpoint:          client("ListWaitlist", encodeGRPCListWaitlistRequest, decodeGRPCListWaitlistResponse, pb.ListWaitlistReply{}),
+		GetEnrollmentSettingsEndpoint: client("GetEnrollmentSettings", encodeGRPCGetEnrollmentSettingsRequest, decodeGRPCGetEnrollmentSettingsResponse, pb.GetEnrollmentSettingsReply{}),
+		SetEnrollmentSettingsEndpoint: client("SetEnrollmentSettings", encodeGRPCSetEnrollmentSettingsRequest, decodeGRPCSetEnrollmentSettingsResponse, pb.SetEnrollmentSettingsReply{}),
+		LinkGuardianEndpoint:          client("LinkGuardian", encodeGRPCLinkGuardianRequest, decodeGRPCLinkGuardianResponse, pb.LinkGuardianReply{}),
+		UnlinkGuardianEndpoint:        client("UnlinkGuardian", encodeGRPCUnlinkGuardianRequest, decodeGRPCUnlinkGuardianResponse, pb.UnlinkGuardianReply{}),
+		ListStudentsEndpoint:          client("ListStudents", encodeGRPCListStudentsRequest, decodeGRPCListStudentsResponse, pb.ListStudentsReply{}),
+	}
+}
+
+// errorFromGRPCStatus maps status errors returned by the server back to the errors of this
+// package. Anything it does not recognize is returned unchanged.
+func errorFromGRPCStatus(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		response, err := next(ctx, request)
+		if err == nil {
+			return response, nil
+		}
+		st, ok := status.FromError(err)
+		if !ok {
+			return nil, err
+		}
+		for _, known := range knownErrors {
+			if grpcCodeFrom(known) == st.Code() && known.Error() == st.Message() {
+				return nil, known
+			}
+		}
+		return nil, err
+	}
+}
+
+var knownErrors = []error{
+	ErrNotFound, ErrUnauthorized, ErrForbidden, ErrUserEnrolled, ErrMustSetOwner, ErrClassFull,
+	ErrInvalidCapacity, ErrInvalidEnrollmentWindow, ErrSelfGuardian, ErrBadRequest,
+	ErrRosterLocked, ErrEnrollmentClosed, ErrVersionMismatch, ErrInternal,
+}
+
+func grpcCodeFrom(err error) codes.Code {
+	if _, ok := err.(*ValidationError); ok {
+		return codes.InvalidArgument
+	}
+	switch err {
+	case ErrNotFound:
+		return codes.NotFound
+	case ErrUnauthorized:
+		return codes.Unauthenticated
+	case ErrForbidden:
+		return codes.PermissionDenied
+	case ErrUserEnrolled:
+		return codes.AlreadyExists
+	case ErrMustSetOwner, ErrInvalidCapacity, ErrInvalidEnrollmentWindow, ErrSelfGuardian, ErrBadRequest:
+		return codes.InvalidArgument
+	case ErrClassFull:
+		return codes.ResourceExhausted
+	case ErrRosterLocked, ErrEnrollmentClosed:
+		return codes.FailedPrecondition
+	case ErrVersionMismatch:
+		return codes.Aborted
+	default:
+		return codes.Internal
+	}
+}
+
+// tokenToGRPCContext moves the bearer token from the authorization metadata into the context,
+// where the introspector middleware looks for it.
+func tokenToGRPCContext(ctx context.Context, md metadata.MD) context.Context {
+	for _, v := range md.Get(authorizationMetadataKey) {
+		if len(v) > 7 && strings.EqualFold(v[:7], "bearer ") {
+			return context.WithValue(ctx, introspector.TokenContextKey, v[7:])
+		}
+	}
+	return ctx
+}
+
+// tokenFromGRPCContext is the client side of tokenToGRPCContext.
+func tokenFromGRPCContext(ctx context.Context, md *metadata.MD) context.Context {
+	if token, ok := ctx.Value(introspector.TokenContextKey).(string); ok && token != "" {
+		md.Set(authorizationMetadataKey, "Bearer "+token)
+	}
+	return ctx
+}
+
+// lockOverrideToGRPCContext honors override-lock: true metadata, like ?override_lock=true.
+func lockOverrideToGRPCContext(ctx context.Context, md metadata.MD) context.Context {
+	for _, v := range md.Get(lockOverrideMetadataKey) {
+		if v == "true" {
+			return WithLockOverride(ctx)
+		}
+	}
+	return ctx
+}
+
+// lockOverrideFromGRPCContext is the client side of lockOverrideToGRPCContext.
+func lockOverrideFromGRPCContext(ctx context.Context, md *metadata.MD) context.Context {
+	if LockOverride(ctx) {
+		md.Set(lockOverrideMetadataKey, "true")
+	}
+	return ctx
+}
+
+// versionToGRPCContext honors if-match metadata, like the If-Match header. Values that are not
+// a version match no version.
+func versionToGRPCContext(ctx context.Context, md metadata.MD) context.Context {
+	values := md.Get(versionMetadataKey)
+	if len(values) == 0 {
+		return ctx
+	}
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimSpace(values[0]), `"`), 10, 64)
+	if err != nil {
+		version = -1
+	}
+	return WithVersion(ctx, version)
+}
+
+// versionFromGRPCContext is the client side of versionToGRPCContext.
+func versionFromGRPCContext(ctx context.Context, md *metadata.MD) context.Context {
+	if version, ok := Version(ctx); ok {
+		md.Set(versionMetadataKey, strconv.FormatInt(version, 10))
+	}
+	return ctx
+}
+
+func parseGRPCUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.UUID{}, status.Error(codes.InvalidArgument, ErrBadRequest.Error())
+	}
+	return id, nil
+}
+
+func decodeGRPCListClassesRequest(_ context.Context, _ interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func encodeGRPCListClassesResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(listClassesResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	return &pb.ListClassesReply{Classes: uuidsToPB(resp.Classes)}, nil
+}
+
+func encodeGRPCListClassesRequest(_ context.Context, _ interface{}) (interface{}, error) {
+	return &pb.ListClassesRequest{}, nil
+}
+
+func decodeGRPCListClassesResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	classes, err := uuidsFromPB(reply.(*pb.ListClassesReply).Classes)
+	return listClassesResponse{Classes: classes}, err
+}
+
+func decodeGRPCGetClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	classID, err := parseGRPCUUID(request.(*pb.GetClassRequest).ClassId)
+	return getClassRequest{ClassID: classID}, err
+}
+
+func encodeGRPCGetClassResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(getClassResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	return &pb.GetClassReply{Class: classToPB(resp.Class)}, nil
+}
+
+func encodeGRPCGetClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.GetClassRequest{ClassId: request.(getClassRequest).ClassID.String()}, nil
+}
+
+func decodeGRPCGetClassResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	class, err := classFromPB(reply.(*pb.GetClassReply).Class)
+	return getClassResponse{Class: class}, err
+}
+
+func decodeGRPCCreateClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return createClassRequest{Name: request.(*pb.CreateClassRequest).Name}, nil
+}
+
+func encodeGRPCCreateClassResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(createClassResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	return &pb.CreateClassReply{ClassId: resp.ClassID.String()}, nil
+}
+
+func encodeGRPCCreateClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.CreateClassRequest{Name: request.(createClassRequest).Name}, nil
+}
+
+func decodeGRPCCreateClassResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	classID, err := uuid.Parse(reply.(*pb.CreateClassReply).ClassId)
+	if err != nil {
+		return nil, err
+	}
+	return createClassResponse{ClassID: &classID}, nil
+}
+
+func decodeGRPCUpdateClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.UpdateClassRequest)
+	classID, err := parseGRPCUUID(req.ClassId)
+	if err != nil {
+		return nil, err
+	}
+	r := updateClassRequest{ClassID: classID}
+	if req.Name != nil {
+		r.Name = &req.Name.Value
+	}
+	if req.CurrentUnit != nil {
+		unit, err := parseGRPCUUID(req.CurrentUnit.Value)
+		if err != nil {
+			return nil, err
+		}
+		r.CurrentUnit = &unit
+	}
+	return r, nil
+}
+
+func encodeGRPCUpdateClassResponse(_ context.Context, response interface{}) (interface{}, error) {
+	if err := response.(updateClassResponse).Error; err != nil {
+		return serviceError{err}, nil
+	}
+	return &pb.UpdateClassReply{}, nil
+}
+
+func encodeGRPCUpdateClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	r := request.(updateClassRequest)
+	req := &pb.UpdateClassRequest{ClassId: r.ClassID.String()}
+	if r.Name != nil {
+		req.Name = &wrappers.StringValue{Value: *r.Name}
+	}
+	if r.CurrentUnit != nil {
+		req.CurrentUnit = &wrappers.StringValue{Value: r.CurrentUnit.String()}
+	}
+	return req, nil
+}
+
+func decodeGRPCUpdateClassResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return updateClassResponse{}, nil
+}
+
+func decodeGRPCDeleteClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	classID, err := parseGRPCUUID(request.(*pb.DeleteClassRequest).ClassId)
+	return deleteClassRequest{ClassID: classID}, err
+}
+
+func encodeGRPCDeleteClassResponse(_ context.Context, response interface{}) (interface{}, error) {
+	if err := response.(deleteClassResponse).Error; err != nil {
+		return serviceError{err}, nil
+	}
+	return &pb.DeleteClassReply{}, nil
+}
+
+func encodeGRPCDeleteClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.DeleteClassRequest{ClassId: request.(deleteClassRequest).ClassID.String()}, nil
+}
+
+func decodeGRPCDeleteClassResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return deleteClassResponse{}, nil
+}
+
+func decodeGRPCJoinClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	classID, err := parseGRPCUUID(request.(*pb.JoinClassRequest).ClassId)
+	return joinClassRequest{ClassID: classID}, err
+}
+
+// encodeGRPCJoinClassResponse reports ErrWaitlisted in the reply, since being put on the
+// waitlist is a successful outcome of joining rather than a failure.
+func encodeGRPCJoinClassResponse(_ context.Context, response interface{}) (interface{}, error) {
+	switch err := response.(joinClassResponse).Error; err {
+	case nil:
+		return &pb.JoinClassReply{}, nil
+	case ErrWaitlisted:
+		return &pb.JoinClassReply{Waitlisted: true}, nil
+	default:
+		return serviceError{err}, nil
+	}
+}
+
+func encodeGRPCJoinClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.JoinClassRequest{ClassId: request.(joinClassRequest).ClassID.String()}, nil
+}
+
+func decodeGRPCJoinClassResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	if reply.(*pb.JoinClassReply).Waitlisted {
+		return joinClassResponse{Error: ErrWaitlisted}, nil
+	}
+	return joinClassResponse{}, nil
+}
+
+func decodeGRPCLeaveClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.LeaveClassRequest)
+	classID, err := parseGRPCUUID(req.ClassId)
+	if err != nil {
+		return nil, err
+	}
+	r := leaveClassRequest{ClassID: classID}
+	if req.UserId != "" {
+		userID, err := parseGRPCUUID(req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		r.UserID = &userID
+	}
+	return r, nil
+}
+
+func encodeGRPCLeaveClassResponse(_ context.Context, response interface{}) (interface{}, error) {
+	if err := response.(leaveClassResponse).Error; err != nil {
+		return serviceError{err}, nil
+	}
+	return &pb.LeaveClassReply{}, nil
+}
+
+func encodeGRPCLeaveClassRequest(_ context.Context, request interface{}) (interface{}, error) {
+	r := request.(leaveClassRequest)
+	req := &pb.LeaveClassRequest{ClassId: r.ClassID.String()}
+	if r.UserID != nil {
+		req.UserId = r.UserID.String()
+	}
+	return req, nil
+}
+
+func decodeGRPCLeaveClassResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return leaveClassResponse{}, nil
+}
+
+func decodeGRPCSetRoleRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.SetRoleRequest)
+	classID, err := parseGRPCUUID(req.ClassId)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := parseGRPCUUID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	role, err := roleFromPB(req.Role)
+	if err != nil {
+		return nil, err
+	}
+	return setRoleRequest{UserID: userID, ClassID: classID, Role: role}, nil
+}
+
+func encodeGRPCSetRoleResponse(_ context.Context, response interface{}) (interface{}, error) {
+	if err := response.(setRoleResponse).Error; err != nil {
+		return serviceError{err}, nil
+	}
+	return &pb.SetRoleReply{}, nil
+}
+
+func encodeGRPCSetRoleRequest(_ context.Context, request interface{}) (interface{}, error) {
+	r := request.(setRoleRequest)
+	return &pb.SetRoleRequest{ClassId: r.ClassID.String(), UserId: r.UserID.String(), Role: pb.Role(r.Role)}, nil
+}
+
+func decodeGRPCSetRoleResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return setRoleResponse{}, nil
+}
+
+func decodeGRPCListMembersRequest(_ context.Context, request interface{}) (interface{}, error) {
+	classID, err := parseGRPCUUID(request.(*pb.ListMembersRequest).ClassId)
+	return listMembersRequest{ClassID: classID}, err
+}
+
+func encodeGRPCListMembersResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(listMembersResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	members := make([]*pb.Member, len(resp.Members))
+	for i, m := range resp.Members {
+		members[i] = memberToPB(m)
+	}
+	return &pb.ListMembersReply{Members: members}, nil
+}
+
+func encodeGRPCListMembersRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.ListMembersRequest{ClassId: request.(listMembersRequest).ClassID.String()}, nil
+}
+
+func decodeGRPCListMembersResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	var resp listMembersResponse
+	for _, m := range reply.(*pb.ListMembersReply).Members {
+		member, err := memberFromPB(m)
+		if err != nil {
+			return nil, err
+		}
+		resp.Members = append(resp.Members, member)
+	}
+	return resp, nil
+}
+
+func decodeGRPCGetMemberRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.GetMemberRequest)
+	classID, err := parseGRPCUUID(req.ClassId)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := parseGRPCUUID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return getMemberRequest{UserID: userID, ClassID: classID}, nil
+}
+
+func encodeGRPCGetMemberResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(getMemberResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	return &pb.GetMemberReply{Member: memberToPB(resp.Member)}, nil
+}
+
+func encodeGRPCGetMemberRequest(_ context.Context, request interface{}) (interface{}, error) {
+	r := request.(getMemberRequest)
+	return &pb.GetMemberRequest{ClassId: r.ClassID.String(), UserId: r.UserID.String()}, nil
+}
+
+func decodeGRPCGetMemberResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	member, err := memberFromPB(reply.(*pb.GetMemberReply).Member)
+	return getMemberResponse{Member: member}, err
+}
+
+func decodeGRPCGetCapacityRequest(_ context.Context, request interface{}) (interface{}, error) {
+	classID, err := parseGRPCUUID(request.(*pb.GetCapacityRequest).ClassId)
+	return getCapacityRequest{ClassID: classID}, err
+}
+
+func encodeGRPCGetCapacityResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(getCapacityResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	return &pb.GetCapacityReply{Capacity: capacityToPB(resp.Capacity)}, nil
+}
+
+func encodeGRPCGetCapacityRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.GetCapacityRequest{ClassId: request.(getCapacityRequest).ClassID.String()}, nil
+}
+
+func decodeGRPCGetCapacityResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	capacity, err := capacityFromPB(reply.(*pb.GetCapacityReply).Capacity)
+	if err != nil {
+		return nil, err
+	}
+	return getCapacityResponse{Capacity: &capacity}, nil
+}
+
+func decodeGRPCSetCapacityRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.SetCapacityRequest)
+	classID, err := parseGRPCUUID(req.ClassId)
+	if err != nil {
+		return nil, err
+	}
+	capacity, err := capacityFromPB(req.Capacity)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, ErrBadRequest.Error())
+	}
+	return setCapacityRequest{ClassID: classID, Capacity: capacity}, nil
+}
+
+func encodeGRPCSetCapacityResponse(_ context.Context, response interface{}) (interface{}, error) {
+	if err := response.(setCapacityResponse).Error; err != nil {
+		return serviceError{err}, nil
+	}
+	return &pb.SetCapacityReply{}, nil
+}
+
+func encodeGRPCSetCapacityRequest(_ context.Context, request interface{}) (interface{}, error) {
+	r := request.(setCapacityRequest)
+	return &pb.SetCapacityRequest{ClassId: r.ClassID.String(), Capacity: capacityToPB(&r.Capacity)}, nil
+}
+
+func decodeGRPCSetCapacityResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return setCapacityResponse{}, nil
+}
+
+func decodeGRPCListWaitlistRequest(_ context.Context, request interface{}) (interface{}, error) {
+	classID, err := parseGRPCUUID(request.(*pb.ListWaitlistRequest).ClassId)
+	return listWaitlistRequest{ClassID: classID}, err
+}
+
+func encodeGRPCListWaitlistResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(listWaitlistResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	waitlist := make([]*pb.WaitlistEntry, len(resp.Waitlist))
+	for i, we := range resp.Waitlist {
+		waitlist[i] = &pb.WaitlistEntry{
+			Id:        we.ID,
+			ClassId:   we.ClassID.String(),
+			UserId:    we.UserID.String(),
+			Role:      pb.Role(we.Role),
+			CreatedAt: timestampToPB(we.CreatedAt),
+		}
+	}
+	return &pb.ListWaitlistReply{Waitlist: waitlist}, nil
+}
+
+func encodeGRPCListWaitlistRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.ListWaitlistRequest{ClassId: request.(listWaitlistRequest).ClassID.String()}, nil
+}
+
+func decodeGRPCListWaitlistResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	var resp listWaitlistResponse
+	for _, we := range reply.(*pb.ListWaitlistReply).Waitlist {
+		entry := &models.WaitlistEntry{ID: we.Id, CreatedAt: timestampFromPB(we.CreatedAt)}
+		var err error
+		if entry.ClassID, err = uuid.Parse(we.ClassId); err != nil {
+			return nil, err
+		}
+		if entry.UserID, err = uuid.Parse(we.UserId); err != nil {
+			return nil, err
+		}
+		if entry.Role, err = roleFromPB(we.Role); err != nil {
+			return nil, err
+		}
+		resp.Waitlist = append(resp.Waitlist, entry)
+	}
+	return resp, nil
+}
+
+func decodeGRPCGetEnrollmentSettingsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	classID, err := parseGRPCUUID(request.(*pb.GetEnrollmentSettingsRequest).ClassId)
+	return getEnrollmentSettingsRequest{ClassID: classID}, err
+}
+
+func encodeGRPCGetEnrollmentSettingsResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(getEnrollmentSettingsResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	return &pb.GetEnrollmentSettingsReply{Settings: enrollmentSettingsToPB(resp.Settings)}, nil
+}
+
+func encodeGRPCGetEnrollmentSettingsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.GetEnrollmentSettingsRequest{ClassId: request.(getEnrollmentSettingsRequest).ClassID.String()}, nil
+}
+
+func decodeGRPCGetEnrollmentSettingsResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	settings := enrollmentSettingsFromPB(reply.(*pb.GetEnrollmentSettingsReply).Settings)
+	return getEnrollmentSettingsResponse{Settings: &settings}, nil
+}
+
+func decodeGRPCSetEnrollmentSettingsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.SetEnrollmentSettingsRequest)
+	classID, err := parseGRPCUUID(req.ClassId)
+	if err != nil {
+		return nil, err
+	}
+	return setEnrollmentSettingsRequest{ClassID: classID, Settings: enrollmentSettingsFromPB(req.Settings)}, nil
+}
+
+func encodeGRPCSetEnrollmentSettingsResponse(_ context.Context, response interface{}) (interface{}, error) {
+	if err := response.(setEnrollmentSettingsResponse).Error; err != nil {
+		return serviceError{err}, nil
+	}
+	return &pb.SetEnrollmentSettingsReply{}, nil
+}
+
+func encodeGRPCSetEnrollmentSettingsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	r := request.(setEnrollmentSettingsRequest)
+	return &pb.SetEnrollmentSettingsRequest{ClassId: r.ClassID.String(), Settings: enrollmentSettingsToPB(&r.Settings)}, nil
+}
+
+func decodeGRPCSetEnrollmentSettingsResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return setEnrollmentSettingsResponse{}, nil
+}
+
+func decodeGRPCLinkGuardianRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.LinkGuardianRequest)
+	guardianID, err := parseGRPCUUID(req.GuardianId)
+	if err != nil {
+		return nil, err
+	}
+	studentID, err := parseGRPCUUID(req.StudentId)
+	if err != nil {
+		return nil, err
+	}
+	return linkGuardianRequest{GuardianID: guardianID, StudentID: studentID}, nil
+}
+
+func encodeGRPCLinkGuardianResponse(_ context.Context, response interface{}) (interface{}, error) {
+	if err := response.(linkGuardianResponse).Error; err != nil {
+		return serviceError{err}, nil
+	}
+	return &pb.LinkGuardianReply{}, nil
+}
+
+func encodeGRPCLinkGuardianRequest(_ context.Context, request interface{}) (interface{}, error) {
+	r := request.(linkGuardianRequest)
+	return &pb.LinkGuardianRequest{GuardianId: r.GuardianID.String(), StudentId: r.StudentID.String()}, nil
+}
+
+func decodeGRPCLinkGuardianResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return linkGuardianResponse{}, nil
+}
+
+func decodeGRPCUnlinkGuardianRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.UnlinkGuardianRequest)
+	guardianID, err := parseGRPCUUID(req.GuardianId)
+	if err != nil {
+		return nil, err
+	}
+	studentID, err := parseGRPCUUID(req.StudentId)
+	if err != nil {
+		return nil, err
+	}
+	return unlinkGuardianRequest{GuardianID: guardianID, StudentID: studentID}, nil
+}
+
+func encodeGRPCUnlinkGuardianResponse(_ context.Context, response interface{}) (interface{}, error) {
+	if err := response.(unlinkGuardianResponse).Error; err != nil {
+		return serviceError{err}, nil
+	}
+	return &pb.UnlinkGuardianReply{}, nil
+}
+
+func encodeGRPCUnlinkGuardianRequest(_ context.Context, request interface{}) (interface{}, error) {
+	r := request.(unlinkGuardianRequest)
+	return &pb.UnlinkGuardianRequest{GuardianId: r.GuardianID.String(), StudentId: r.StudentID.String()}, nil
+}
+
+func decodeGRPCUnlinkGuardianResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return unlinkGuardianResponse{}, nil
+}
+
+func decodeGRPCListStudentsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	guardianID, err := parseGRPCUUID(request.(*pb.ListStudentsRequest).GuardianId)
+	return listStudentsRequest{GuardianID: guardianID}, err
+}
+
+func encodeGRPCListStudentsResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(listStudentsResponse)
+	if resp.Error != nil {
+		return serviceError{resp.Error}, nil
+	}
+	return &pb.ListStudentsReply{Students: uuidsToPB(resp.Students)}, nil
+}
+
+func encodeGRPCListStudentsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.ListStudentsRequest{GuardianId: request.(listStudentsRequest).GuardianID.String()}, nil
+}
+
+func decodeGRPCListStudentsResponse(_ context.Context, reply interface{}) (interface{}, error) {
+	students, err := uuidsFromPB(reply.(*pb.ListStudentsReply).Students)
+	return listStudentsResponse{Students: students}, err
+}
+
+func uuidsToPB(ids []uuid.UUID) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.String()
+	}
+	return s
+}
+
+func uuidsFromPB(s []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(s))
+	for i, v := range s {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func timestampToPB(t time.Time) *timestamp.Timestamp {
+	return &timestamp.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
+
+func timestampFromPB(ts *timestamp.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return time.Unix(ts.Seconds, int64(ts.Nanos)).UTC()
+}
+
+func roleFromPB(r pb.Role) (models.UserRole, error) {
+	switch r {
+	case pb.Role_STUDENT:
+		return models.UserRoleStudent, nil
+	case pb.Role_TEACHER:
+		return models.UserRoleTeacher, nil
+	default:
+		return 0, status.Error(codes.InvalidArgument, ErrBadRequest.Error())
+	}
+}
+
+func classToPB(c *models.Class) *pb.Class {
+	if c == nil {
+		return nil
+	}
+	return &pb.Class{Id: c.ID.String(), Name: c.Name, CurrentUnit: c.CurrentUnit.String()}
+}
+
+func classFromPB(c *pb.Class) (*models.Class, error) {
+	if c == nil {
+		return nil, nil
+	}
+	class := &models.Class{Name: c.Name}
+	var err error
+	if class.ID, err = uuid.Parse(c.Id); err != nil {
+		return nil, err
+	}
+	if class.CurrentUnit, err = uuid.Parse(c.CurrentUnit); err != nil {
+		return nil, err
+	}
+	return class, nil
+}
+
+func memberToPB(m *models.Member) *pb.Member {
+	if m == nil {
+		return nil
+	}
+	return &pb.Member{
+		UserId:   m.UserID.String(),
+		ClassId:  m.ClassID.String(),
+		Role:     pb.Role(m.Role),
+		Owner:    m.Owner,
+		JoinedAt: timestampToPB(m.JoinedAt),
+	}
+}
+
+func memberFromPB(m *pb.Member) (*models.Member, error) {
+	if m == nil {
+		return nil, nil
+	}
+	member := &models.Member{Owner: m.Owner, JoinedAt: timestampFromPB(m.JoinedAt)}
+	var err error
+	if member.UserID, err = uuid.Parse(m.UserId); err != nil {
+		return nil, err
+	}
+	if member.ClassID, err = uuid.Parse(m.ClassId); err != nil {
+		return nil, err
+	}
+	if member.Role, err = roleFromPB(m.Role); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// capacityToPB keys role limits by the role's name, since protobuf maps cannot be keyed by
+// enums.
+func capacityToPB(c *Capacity) *pb.Capacity {
+	if c == nil {
+		return nil
+	}
+	capacity := &pb.Capacity{Roles: make(map[string]int32, len(c.Roles))}
+	if c.Seats != nil {
+		capacity.Seats = &wrappers.Int32Value{Value: int32(*c.Seats)}
+	}
+	for role, n := range c.Roles {
+		capacity.Roles[role.String()] = int32(n)
+	}
+	return capacity
+}
+
+func capacityFromPB(c *pb.Capacity) (Capacity, error) {
+	var capacity Capacity
+	if c == nil {
+		return capacity, nil
+	}
+	if c.Seats != nil {
+		seats := int(c.Seats.Value)
+		capacity.Seats = &seats
+	}
+	if len(c.Roles) > 0 {
+		capacity.Roles = make(map[models.UserRole]int, len(c.Roles))
+		for name, n := range c.Roles {
+			var role models.UserRole
+			if err := role.UnmarshalText([]byte(name)); err != nil {
+				return Capacity{}, err
+			}
+			capacity.Roles[role] = int(n)
+		}
+	}
+	return capacity, nil
+}
+
+func enrollmentSettingsToPB(s *EnrollmentSettings) *pb.EnrollmentSettings {
+	if s == nil {
+		return nil
+	}
+	settings := &pb.EnrollmentSettings{Locked: s.Locked}
+	if s.OpensAt != nil {
+		settings.OpensAt = timestampToPB(*s.OpensAt)
+	}
+	if s.ClosesAt != nil {
+		settings.ClosesAt = timestampToPB(*s.ClosesAt)
+	}
+	return settings
+}
+
+func enrollmentSettingsFromPB(s *pb.EnrollmentSettings) EnrollmentSettings {
+	var settings EnrollmentSettings
+	if s == nil {
+		return settings
+	}
+	settings.Locked = s.Locked
+	if s.OpensAt != nil {
+		t := timestampFromPB(s.OpensAt)
+		settings.OpensAt = &t
+	}
+	if s.ClosesAt != nil {
+		t := timestampFromPB(s.ClosesAt)
+		settings.ClosesAt = &t
+	}
+	return settings
+}
@@ -0,0 +1,65 @@
+package classsvc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/pb"
+	"github.com/studiously/introspector"
+	"google.golang.org/grpc"
+)
+
+// versionService records the version each UpdateClass is made against.
+type versionService struct {
+	Service
+	version    int64
+	hasVersion bool
+}
+
+func (s *versionService) UpdateClass(ctx context.Context, _ uuid.UUID, _ *string, _ *uuid.UUID) error {
+	s.version, s.hasVersion = Version(ctx)
+	return nil
+}
+
+func TestGRPCClientVersion(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &versionService{}
+	g := grpc.NewServer()
+	pb.RegisterClassesServer(g, MakeGRPCServer(s, activeTokens{uuid.New()}, log.NewNopLogger()))
+	go g.Serve(ln)
+	defer g.Stop()
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := NewGRPCClient(conn)
+
+	name := "Algebra I"
+	ctx := context.WithValue(context.Background(), introspector.TokenContextKey, "token")
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantVersion int64
+		wantOK      bool
+	}{
+		{"unconditional", ctx, 0, false},
+		{"version", WithVersion(ctx, 7), 7, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := client.UpdateClass(tt.ctx, uuid.New(), &name, nil); err != nil {
+				t.Fatal(err)
+			}
+			if s.version != tt.wantVersion || s.hasVersion != tt.wantOK {
+				t.Errorf("version = %d, %t; want %d, %t", s.version, s.hasVersion, tt.wantVersion, tt.wantOK)
+			}
+		})
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	httptransport "github.com/go-kit/kit/transport/http"
@@ -21,13 +25,25 @@ var (
 	ErrBadRequest = errors.New("the request is malformed or invalid")
 )
 
-func MakeHTTPHandler(s Service, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+// MakeHTTPHandler serves version 1 of the HTTP API under /v1, and version 2 under /v2. Routes
+// without a prefix are served by the version named in APIVersionHeader, or else by version 1,
+// which will be removed after sunset if it is set.
+func MakeHTTPHandler(s Service, introspection oauth2.Introspector, sunset time.Time, logger log.Logger) http.Handler {
+	return MakeIdempotentHTTPHandler(s, nil, introspection, sunset, logger)
+}
+
+// MakeIdempotentHTTPHandler serves the HTTP API like MakeHTTPHandler, and serves mutating
+// requests with an IdempotencyKeyHeader at most once per key with idempotency, if it is not nil.
+func MakeIdempotentHTTPHandler(s Service, idempotency *Idempotency, introspection oauth2.Introspector, sunset time.Time, logger log.Logger) http.Handler {
 	r := mux.NewRouter()
 	e := MakeServerEndpoints(s)
+	if idempotency != nil {
+		e = idempotency.endpoints(e)
+	}
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(logger),
 		httptransport.ServerErrorEncoder(encodeError),
-		httptransport.ServerBefore(introspector.ToHTTPContext()),
+		httptransport.ServerBefore(introspector.ToHTTPContext(), lockOverrideToContext, versionToContext),
 	}
 
 	// GET /classes/
@@ -104,7 +120,70 @@ func MakeHTTPHandler(s Service, introspection oauth2.Introspector, logger log.Lo
 		options...
 	))
 
-	return r
+	r.Methods("GET").Path("/classes/{classID}/capacity").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.capacity.get")(e.GetCapacityEndpoint),
+		DecodeGetCapacityRequest,
+		encodeResponse,
+		options...
+	))
+
+	r.Methods("PUT").Path("/classes/{classID}/capacity").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.capacity.update")(e.SetCapacityEndpoint),
+		DecodeSetCapacityRequest,
+		encodeResponse,
+		options...
+	))
+
+	r.Methods("GET").Path("/classes/{classID}/waitlist").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.waitlist.list")(e.ListWaitlistEndpoint),
+		DecodeListWaitlistRequest,
+		encodeResponse,
+		options...
+	))
+
+	r.Methods("GET").Path("/classes/{classID}/enrollment").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.enrollment.get")(e.GetEnrollmentSettingsEndpoint),
+		DecodeGetEnrollmentSettingsRequest,
+		encodeResponse,
+		options...
+	))
+
+	r.Methods("PUT").Path("/classes/{classID}/enrollment").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.enrollment.update")(e.SetEnrollmentSettingsEndpoint),
+		DecodeSetEnrollmentSettingsRequest,
+		encodeResponse,
+		options...
+	))
+
+	// PUT /guardians/:guardianID/students/:studentID
+	// Link a guardian to a student. Requires the organization admin scope.
+	r.Methods("PUT").Path("/guardians/{guardianID}/students/{studentID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.guardians.update")(e.LinkGuardianEndpoint),
+		DecodeLinkGuardianRequest,
+		encodeResponse,
+		options...
+	))
+
+	r.Methods("DELETE").Path("/guardians/{guardianID}/students/{studentID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.guardians.update")(e.UnlinkGuardianEndpoint),
+		DecodeUnlinkGuardianRequest,
+		encodeResponse,
+		options...
+	))
+
+	r.Methods("GET").Path("/guardians/{guardianID}/students").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.guardians.list")(e.ListStudentsEndpoint),
+		DecodeListStudentsRequest,
+		encodeResponse,
+		options...
+	))
+
+	v1 := validateRequests(r, "")
+	h := negotiateVersion(v1, makeV2Handler(e, v1, introspection, options), sunset)
+	if idempotency != nil {
+		h = idempotency.Handler(h)
+	}
+	return WithCorrelationID(h, logger)
 }
 
 func EncodeGetClassRequest(ctx context.Context, req *http.Request, request interface{}) error {
@@ -142,7 +221,7 @@ func DecodeCreateClassResponse(_ context.Context, resp *http.Response) (interfac
 
 func DecodeCreateClassRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var req createClassRequest
-	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+	if e := decodeJSONBody(r, &req); e != nil {
 		return nil, e
 	}
 	return req, nil
@@ -177,10 +256,15 @@ func DecodeUpdateClassResponse(_ context.Context, resp *http.Response) (interfac
 }
 
 func DecodeUpdateClassRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, err := uuid.Parse(mux.Vars(r)["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
 	var req updateClassRequest
-	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+	if e := decodeJSONBody(r, &req); e != nil {
 		return nil, e
 	}
+	req.ClassID = classID
 	return req, nil
 }
 
@@ -279,15 +363,13 @@ func DecodeLeaveClassRequest(_ context.Context, r *http.Request) (interface{}, e
 		return leaveClassRequest{}, ErrBadRequest
 	}
 	req.ClassID = classID
-	userS, ok := vars["userID"]
-	userID := uuid.Nil
-	if ok {
-		userID, err = uuid.Parse(userS)
+	if userS, ok := vars["userID"]; ok {
+		userID, err := uuid.Parse(userS)
 		if err != nil {
 			return nil, ErrBadRequest
 		}
+		req.UserID = &userID
 	}
-	req.UserID = &userID
 	return req, nil
 }
 
@@ -296,7 +378,7 @@ func EncodeSetRoleRequest(ctx context.Context, req *http.Request, request interf
 	classID := url.QueryEscape(r.ClassID.String())
 	userID := url.QueryEscape(r.UserID.String())
 	req.Method, req.URL.Path = "PATCH", "/classes/"+classID+"/members/"+userID
-	return encodeRequest(ctx, req, request)
+	return encodeRequest(ctx, req, r.Role)
 }
 
 func DecodeSetRoleResponse(_ context.Context, resp *http.Response) (interface{}, error) {
@@ -319,7 +401,7 @@ func DecodeSetRoleRequest(_ context.Context, r *http.Request) (interface{}, erro
 		return nil, ErrBadRequest
 	}
 	req.UserID = user
-	if e := json.NewDecoder(r.Body).Decode(&req.Role); e != nil {
+	if e := decodeJSONBody(r, &req.Role); e != nil {
 		return nil, e
 	}
 	return req, nil
@@ -352,6 +434,251 @@ func DecodeGetMemberRequest(_ context.Context, r *http.Request) (interface{}, er
 	return getMemberRequest{ClassID: classID, UserID: userID}, nil
 }
 
+func EncodeGetCapacityRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	r := request.(getCapacityRequest)
+	classID := url.QueryEscape(r.ClassID.String())
+	req.Method, req.URL.Path = "GET", "/classes/"+classID+"/capacity"
+	return encodeRequest(ctx, req, request)
+}
+
+func DecodeGetCapacityResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	var response getCapacityResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func DecodeGetCapacityRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	classID, err := uuid.Parse(vars["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	return getCapacityRequest{classID}, nil
+}
+
+func EncodeSetCapacityRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	r := request.(setCapacityRequest)
+	classID := url.QueryEscape(r.ClassID.String())
+	req.Method, req.URL.Path = "PUT", "/classes/"+classID+"/capacity"
+	return encodeRequest(ctx, req, r.Capacity)
+}
+
+func DecodeSetCapacityResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	var response setCapacityResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func DecodeSetCapacityRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req setCapacityRequest
+	vars := mux.Vars(r)
+	classID, err := uuid.Parse(vars["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	req.ClassID = classID
+	if e := decodeJSONBody(r, &req.Capacity); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func EncodeListWaitlistRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	r := request.(listWaitlistRequest)
+	classID := url.QueryEscape(r.ClassID.String())
+	req.Method, req.URL.Path = "GET", "/classes/"+classID+"/waitlist"
+	return encodeRequest(ctx, req, request)
+}
+
+func DecodeListWaitlistResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	var response listWaitlistResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func DecodeListWaitlistRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	classID, err := uuid.Parse(vars["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	return listWaitlistRequest{classID}, nil
+}
+
+func EncodeGetEnrollmentSettingsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	r := request.(getEnrollmentSettingsRequest)
+	classID := url.QueryEscape(r.ClassID.String())
+	req.Method, req.URL.Path = "GET", "/classes/"+classID+"/enrollment"
+	return encodeRequest(ctx, req, request)
+}
+
+func DecodeGetEnrollmentSettingsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	var response getEnrollmentSettingsResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func DecodeGetEnrollmentSettingsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	classID, err := uuid.Parse(vars["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	return getEnrollmentSettingsRequest{classID}, nil
+}
+
+func EncodeSetEnrollmentSettingsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	r := request.(setEnrollmentSettingsRequest)
+	classID := url.QueryEscape(r.ClassID.String())
+	req.Method, req.URL.Path = "PUT", "/classes/"+classID+"/enrollment"
+	return encodeRequest(ctx, req, r.Settings)
+}
+
+func DecodeSetEnrollmentSettingsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	var response setEnrollmentSettingsResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func DecodeSetEnrollmentSettingsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req setEnrollmentSettingsRequest
+	vars := mux.Vars(r)
+	classID, err := uuid.Parse(vars["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	req.ClassID = classID
+	if e := decodeJSONBody(r, &req.Settings); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func EncodeLinkGuardianRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	r := request.(linkGuardianRequest)
+	guardianID := url.QueryEscape(r.GuardianID.String())
+	studentID := url.QueryEscape(r.StudentID.String())
+	req.Method, req.URL.Path = "PUT", "/guardians/"+guardianID+"/students/"+studentID
+	return encodeRequest(ctx, req, request)
+}
+
+func DecodeLinkGuardianResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	var response linkGuardianResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func DecodeLinkGuardianRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	guardianID, studentID, err := guardianLinkVars(r)
+	if err != nil {
+		return nil, err
+	}
+	return linkGuardianRequest{guardianID, studentID}, nil
+}
+
+func EncodeUnlinkGuardianRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	r := request.(unlinkGuardianRequest)
+	guardianID := url.QueryEscape(r.GuardianID.String())
+	studentID := url.QueryEscape(r.StudentID.String())
+	req.Method, req.URL.Path = "DELETE", "/guardians/"+guardianID+"/students/"+studentID
+	return encodeRequest(ctx, req, request)
+}
+
+func DecodeUnlinkGuardianResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	var response unlinkGuardianResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func DecodeUnlinkGuardianRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	guardianID, studentID, err := guardianLinkVars(r)
+	if err != nil {
+		return nil, err
+	}
+	return unlinkGuardianRequest{guardianID, studentID}, nil
+}
+
+func guardianLinkVars(r *http.Request) (guardianID, studentID uuid.UUID, err error) {
+	vars := mux.Vars(r)
+	guardianID, err = uuid.Parse(vars["guardianID"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrBadRequest
+	}
+	studentID, err = uuid.Parse(vars["studentID"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrBadRequest
+	}
+	return guardianID, studentID, nil
+}
+
+func EncodeListStudentsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	r := request.(listStudentsRequest)
+	guardianID := url.QueryEscape(r.GuardianID.String())
+	req.Method, req.URL.Path = "GET", "/guardians/"+guardianID+"/students"
+	return encodeRequest(ctx, req, request)
+}
+
+func DecodeListStudentsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	var response listStudentsResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func DecodeListStudentsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	guardianID, err := uuid.Parse(vars["guardianID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	return listStudentsRequest{guardianID}, nil
+}
+
+// lockOverrideToContext honors ?override_lock=true, which lets owners and organization admins
+// change a locked roster.
+func lockOverrideToContext(ctx context.Context, r *http.Request) context.Context {
+	if r.URL.Query().Get("override_lock") == "true" {
+		return WithLockOverride(ctx)
+	}
+	return ctx
+}
+
+// lockOverrideFromContext is the client side of lockOverrideToContext.
+func lockOverrideFromContext(ctx context.Context, r *http.Request) context.Context {
+	if LockOverride(ctx) {
+		q := r.URL.Query()
+		q.Set("override_lock", "true")
+		r.URL.RawQuery = q.Encode()
+	}
+	return ctx
+}
+
+// versionToContext honors If-Match, which makes changes to a class or member fail unless it is
+// still at the version of the ETag it was read with. "*" matches any version.
+func versionToContext(ctx context.Context, r *http.Request) context.Context {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return ctx
+	}
+	version, err := strconv.ParseInt(strings.Trim(ifMatch, `"`), 10, 64)
+	if err != nil || len(ifMatch) < 2 || ifMatch[0] != '"' || ifMatch[len(ifMatch)-1] != '"' {
+		// ETags that were not issued by this service, including weak ones, match no version.
+		version = -1
+	}
+	return WithVersion(ctx, version)
+}
+
+// versionFromContext is the client side of versionToContext.
+func versionFromContext(ctx context.Context, r *http.Request) context.Context {
+	if version, ok := Version(ctx); ok {
+		r.Header.Set("If-Match", etag(version))
+	}
+	return ctx
+}
+
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
 //func EncodeGetRoleRequest(ctx context.Context, req *http.Request, request interface{}) error {
 //	r := request.(getRoleRequest)
 //	classID := url.QueryEscape(r.ClassID.String())
@@ -444,6 +771,12 @@ type errorer interface {
 	error() error
 }
 
+// versioner is implemented by the responses of resources that can be changed with If-Match.
+// Their version is sent as the ETag.
+type versioner interface {
+	version() (int64, bool)
+}
+
 // encodeResponse is the common method to Encode all response types to the
 // client. I chose to do it this way because, since we're using JSON, there's no
 // reason to provide anything more specific. It's certainly possible to
@@ -455,6 +788,11 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, response interfa
 		encodeError(ctx, e.error(), w)
 		return nil
 	}
+	if v, ok := response.(versioner); ok {
+		if version, ok := v.version(); ok {
+			w.Header().Set("ETag", etag(version))
+		}
+	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	return json.NewEncoder(w).Encode(response)
 }
@@ -472,18 +810,35 @@ func encodeRequest(_ context.Context, req *http.Request, request interface{}) er
 	return nil
 }
 
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+// maxBodySize bounds request bodies, which are never more than a few fields.
+const maxBodySize = 64 << 10
+
+// decodeJSONBody decodes the body of r into v. Bodies that are not JSON, or do not fit v, are
+// reported as a *ValidationError of the body as a whole.
+func decodeJSONBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(v); err != nil {
+		return &ValidationError{Fields: []FieldError{{Field: "/", Reason: "must be valid JSON: " + err.Error()}}}
+	}
+	return nil
+}
+
+// encodeError writes err as a Problem. Internal errors are logged in full, with the correlation
+// ID that clients are given in their place.
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	if err == nil {
 		panic("encodeError with nil error")
 	}
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(codeFrom(err))
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"error": err.Error(),
-	})
+	if err == errReplayed {
+		// The stored response is written in its place by the idempotency handler.
+		return
+	}
+	writeProblem(ctx, w, err, problemFrom(ctx, err))
 }
 
 func codeFrom(err error) int {
+	if _, ok := err.(*ValidationError); ok {
+		return http.StatusBadRequest
+	}
 	switch err {
 	case ErrNotFound:
 		return http.StatusNotFound
@@ -495,6 +850,22 @@ func codeFrom(err error) int {
 		return http.StatusBadRequest
 	case ErrMustSetOwner:
 		return http.StatusBadRequest
+	case ErrWaitlisted:
+		return http.StatusAccepted
+	case ErrClassFull:
+		return http.StatusConflict
+	case ErrInvalidCapacity, ErrInvalidEnrollmentWindow, ErrSelfGuardian, ErrBadRequest, ErrUnsupportedVersion:
+		return http.StatusBadRequest
+	case ErrRosterLocked, ErrEnrollmentClosed:
+		return http.StatusLocked
+	case ErrIdempotencyKeyReused:
+		return http.StatusUnprocessableEntity
+	case ErrIdempotencyKeyInUse:
+		return http.StatusConflict
+	case ErrRequestTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrVersionMismatch:
+		return http.StatusPreconditionFailed
 	case ErrInternal:
 		return http.StatusInternalServerError
 	default:
@@ -0,0 +1,374 @@
+package classsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+// stubService answers ListClasses with classes, and JoinClass with err.
+type stubService struct {
+	Service
+	classes []uuid.UUID
+	err     error
+}
+
+func (s *stubService) ListClasses(_ context.Context) ([]uuid.UUID, error) {
+	return s.classes, s.err
+}
+
+func (s *stubService) JoinClass(_ context.Context, _ uuid.UUID) error {
+	return s.err
+}
+
+// send makes a request with a bearer token and headers, given as name and value pairs, and
+// returns the response with its body read.
+func send(t *testing.T, method, url, body string, headers ...string) (*http.Response, []byte) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer token")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, b
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	classID := uuid.New()
+	sunset := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(MakeHTTPHandler(&stubService{classes: []uuid.UUID{classID}}, activeTokens{uuid.New()}, sunset, log.NewNopLogger()))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		headers []string
+		version string
+		// key is the key classes are listed under in the version served.
+		key string
+	}{
+		{"unversioned", "/classes/", nil, "1", "Classes"},
+		{"v1 prefix", "/v1/classes/", nil, "1", "Classes"},
+		{"v2 prefix", "/v2/classes/", nil, "2", "classes"},
+		{"v2 header", "/classes/", []string{APIVersionHeader, "2"}, "2", "classes"},
+		{"prefix over header", "/v1/classes/", []string{APIVersionHeader, "2"}, "1", "Classes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := send(t, "GET", srv.URL+tt.path, "", tt.headers...)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d; want %d: %s", resp.StatusCode, http.StatusOK, body)
+			}
+			if got := resp.Header.Get(APIVersionHeader); got != tt.version {
+				t.Errorf("%s = %q; want %q", APIVersionHeader, got, tt.version)
+			}
+			var classes map[string][]uuid.UUID
+			if err := json.Unmarshal(body, &classes); err != nil {
+				t.Fatal(err)
+			}
+			if got := classes[tt.key]; len(got) != 1 || got[0] != classID {
+				t.Errorf("body = %s; want the class under %q", body, tt.key)
+			}
+			deprecated := resp.Header.Get("Deprecation") == "true"
+			if deprecated != (tt.version == "1") {
+				t.Errorf("Deprecation = %q; want it only on version 1", resp.Header.Get("Deprecation"))
+			}
+			if deprecated {
+				if got, want := resp.Header.Get("Sunset"), "Tue, 01 Jan 2030 00:00:00 GMT"; got != want {
+					t.Errorf("Sunset = %q; want %q", got, want)
+				}
+				if got, want := resp.Header.Get("Link"), `</v2/classes/>; rel="successor-version"`; got != want {
+					t.Errorf("Link = %q; want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNegotiateUnsupportedVersion(t *testing.T) {
+	srv := httptest.NewServer(MakeHTTPHandler(&stubService{}, activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	resp, body := send(t, "GET", srv.URL+"/classes/", "", APIVersionHeader, "3")
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), `"code":"unsupported_version"`) {
+		t.Errorf("response = %d %s; want 400 unsupported_version", resp.StatusCode, body)
+	}
+}
+
+func TestV2JoinWaitlisted(t *testing.T) {
+	srv := httptest.NewServer(MakeHTTPHandler(&stubService{err: ErrWaitlisted}, activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	resp, body := send(t, "POST", srv.URL+"/v2/classes/"+uuid.New().String()+"/join", "")
+	if resp.StatusCode != http.StatusAccepted || strings.TrimSpace(string(body)) != `{"waitlisted":true}` {
+		t.Errorf("response = %d %s; want 202 {\"waitlisted\":true}", resp.StatusCode, body)
+	}
+}
+
+func TestProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		path   string
+		status int
+		code   string
+		detail string
+	}{
+		{"known v1", ErrNotFound, "/v1/classes/", http.StatusNotFound, "not_found", ErrNotFound.Error()},
+		{"known v2", ErrNotFound, "/v2/classes/", http.StatusNotFound, "not_found", ErrNotFound.Error()},
+		{"conflict", ErrClassFull, "/v2/classes/", http.StatusConflict, "class_full", ErrClassFull.Error()},
+		{"internal", errors.New(`pq: relation "classes" does not exist`), "/v2/classes/", http.StatusInternalServerError, "internal", ErrInternal.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(MakeHTTPHandler(&stubService{err: tt.err}, activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+			defer srv.Close()
+			resp, body := send(t, "GET", srv.URL+tt.path, "", CorrelationIDHeader, "request-1")
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d; want %d: %s", resp.StatusCode, tt.status, body)
+			}
+			if got, want := resp.Header.Get("Content-Type"), "application/problem+json; charset=utf-8"; got != want {
+				t.Errorf("Content-Type = %q; want %q", got, want)
+			}
+			if got := resp.Header.Get(CorrelationIDHeader); got != "request-1" {
+				t.Errorf("%s = %q; want it echoed", CorrelationIDHeader, got)
+			}
+			if strings.Contains(string(body), "pq:") {
+				t.Errorf("body = %s; leaks the database error", body)
+			}
+			var p Problem
+			if err := json.Unmarshal(body, &p); err != nil {
+				t.Fatal(err)
+			}
+			want := Problem{
+				Type:          ProblemTypeBase + tt.code,
+				Title:         http.StatusText(tt.status),
+				Status:        tt.status,
+				Detail:        tt.detail,
+				Code:          tt.code,
+				CorrelationID: "request-1",
+			}
+			if strings.HasPrefix(tt.path, "/v1/") {
+				// Version 1 clients read the error field.
+				want.Error = tt.detail
+			}
+			if p.Type != want.Type || p.Title != want.Title || p.Status != want.Status || p.Detail != want.Detail ||
+				p.Code != want.Code || p.CorrelationID != want.CorrelationID || p.Error != want.Error {
+				t.Errorf("problem = %+v; want %+v", p, want)
+			}
+		})
+	}
+}
+
+func TestProblemCorrelationIDGenerated(t *testing.T) {
+	srv := httptest.NewServer(MakeHTTPHandler(&stubService{err: ErrForbidden}, activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	resp, body := send(t, "GET", srv.URL+"/v2/classes/", "")
+	var p Problem
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatal(err)
+	}
+	id := resp.Header.Get(CorrelationIDHeader)
+	if _, err := uuid.Parse(id); err != nil || p.CorrelationID != id {
+		t.Errorf("%s = %q, correlation_id = %q; want the same generated UUID", CorrelationIDHeader, id, p.CorrelationID)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{ErrRosterLocked, "roster_locked"},
+		{ErrVersionMismatch, "version_mismatch"},
+		{&ValidationError{Fields: []FieldError{{Field: "/name", Reason: "is empty"}}}, "validation_failed"},
+		{errors.New("dial tcp: connection refused"), "internal"},
+	}
+	for _, tt := range tests {
+		if got := ErrorCode(tt.err); got != tt.code {
+			t.Errorf("ErrorCode(%v) = %q; want %q", tt.err, got, tt.code)
+		}
+	}
+}
+
+func TestProblemsOfOtherPackages(t *testing.T) {
+	errHidden := errors.New("webhooks: private address")
+	ps := Problems{
+		Code: func(err error) string {
+			if err == errHidden {
+				return "private_address"
+			}
+			return ""
+		},
+		Status: func(err error) int {
+			if err == errHidden {
+				return http.StatusBadRequest
+			}
+			return http.StatusInternalServerError
+		},
+	}
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{errHidden, http.StatusBadRequest, "private_address"},
+		// Errors of this package, such as those of Provisioning, keep their codes and status.
+		{ErrRosterLocked, http.StatusLocked, "roster_locked"},
+		{errors.New("pq: deadlock detected"), http.StatusInternalServerError, "internal"},
+	}
+	for _, tt := range tests {
+		p := ps.Problem(context.Background(), tt.err)
+		if p.Status != tt.status || p.Code != tt.code || p.Error != p.Detail {
+			t.Errorf("Problem(%v) = %+v; want %d %s with error repeating detail", tt.err, p, tt.status, tt.code)
+		}
+	}
+}
+
+func TestValidationFailures(t *testing.T) {
+	srv := httptest.NewServer(MakeHTTPHandler(&stubService{}, activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	classID, userID := uuid.New().String(), uuid.New().String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		// field is the field the failure is reported on.
+		field string
+	}{
+		{"not JSON", "POST", "/v2/classes/", "{", "/"},
+		{"missing name", "POST", "/v2/classes/", `{}`, "/name"},
+		{"empty name", "POST", "/v2/classes/", `{"name":""}`, "/name"},
+		{"blank name", "POST", "/v2/classes/", `{"name":"   "}`, "/name"},
+		{"control characters", "POST", "/classes/", `{"name":"a\u0007b"}`, "/name"},
+		{"long name", "POST", "/v2/classes/", `{"name":"` + strings.Repeat("a", 101) + `"}`, "/name"},
+		{"blank v1 update", "PATCH", "/v1/classes/" + classID, `{"class":" "}`, "/class"},
+		{"invalid class ID", "GET", "/v2/classes/not-a-uuid", "", "classID"},
+		{"unknown role", "PATCH", "/v2/classes/" + classID + "/members/" + userID, `{"role":"admin"}`, "/role"},
+		{"missing role", "PATCH", "/v2/classes/" + classID + "/members/" + userID, `{}`, "/role"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := send(t, tt.method, srv.URL+tt.path, tt.body)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("status = %d; want %d: %s", resp.StatusCode, http.StatusBadRequest, body)
+			}
+			var p Problem
+			if err := json.Unmarshal(body, &p); err != nil {
+				t.Fatal(err)
+			}
+			if p.Code != "validation_failed" || len(p.Errors) != 1 || p.Errors[0].Field != tt.field || p.Errors[0].Reason == "" {
+				t.Errorf("problem = %s; want validation_failed on %q", body, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"  Algebra I  ", "Algebra I", true},
+		// "e" followed by a combining acute accent is normalized to "\u00e9".
+		{"Caf\u0065\u0301", "Caf\u00e9", true},
+		{strings.Repeat("\u00e9", 100), strings.Repeat("\u00e9", 100), true},
+		{strings.Repeat("a", 101), "", false},
+		{"\t\n", "", false},
+		{"a\x00b", "", false},
+		{"\xff", "", false},
+	}
+	for _, tt := range tests {
+		got, err := ValidateName("/name", tt.name)
+		if (err == nil) != tt.ok || (tt.ok && got != tt.want) {
+			t.Errorf("ValidateName(%q) = %q, %v; want %q, ok %v", tt.name, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+// versionedService serves a class at version, and records the version UpdateClass is given.
+type versionedService struct {
+	Service
+	version int64
+
+	given    int64
+	hasGiven bool
+}
+
+func (s *versionedService) GetClass(_ context.Context, classID uuid.UUID) (*models.Class, error) {
+	return &models.Class{ID: classID, Name: "Algebra I", Active: true, Version: s.version}, nil
+}
+
+func (s *versionedService) UpdateClass(ctx context.Context, _ uuid.UUID, _ *string, _ *uuid.UUID) error {
+	s.given, s.hasGiven = Version(ctx)
+	if s.hasGiven && s.given != s.version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+func TestIfMatch(t *testing.T) {
+	service := &versionedService{version: 7}
+	srv := httptest.NewServer(MakeHTTPHandler(service, activeTokens{uuid.New()}, time.Time{}, log.NewNopLogger()))
+	defer srv.Close()
+	path := srv.URL + "/v2/classes/" + uuid.New().String()
+
+	resp, _ := send(t, "GET", path, "")
+	if got := resp.Header.Get("ETag"); got != `"7"` {
+		t.Errorf("ETag = %q; want %q", got, `"7"`)
+	}
+
+	tests := []struct {
+		ifMatch string
+		status  int
+		// version is the version UpdateClass is given, or 0 for none.
+		version int64
+	}{
+		{"", http.StatusOK, 0},
+		{"*", http.StatusOK, 0},
+		{`"7"`, http.StatusOK, 7},
+		{` "7" `, http.StatusOK, 7},
+		{`"6"`, http.StatusPreconditionFailed, 6},
+		// ETags this service did not issue match no version.
+		{`W/"7"`, http.StatusPreconditionFailed, -1},
+		{"7", http.StatusPreconditionFailed, -1},
+		{`"seven"`, http.StatusPreconditionFailed, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ifMatch, func(t *testing.T) {
+			service.given, service.hasGiven = 0, false
+			resp, body := send(t, "PATCH", path, `{"name":"Algebra II"}`, "If-Match", tt.ifMatch)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d; want %d: %s", resp.StatusCode, tt.status, body)
+			}
+			if tt.status == http.StatusPreconditionFailed && !strings.Contains(string(body), `"code":"version_mismatch"`) {
+				t.Errorf("body = %s; want version_mismatch", body)
+			}
+			if service.hasGiven != (tt.version != 0) || service.given != tt.version {
+				t.Errorf("version given = %d, %v; want %d", service.given, service.hasGiven, tt.version)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package classsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+// APIVersionHeader chooses the version of the HTTP API for routes without a /v1 or /v2 prefix.
+// Responses carry it too, naming the version that served them.
+const APIVersionHeader = "API-Version"
+
+var (
+	ErrUnsupportedVersion = errors.New("the requested API version is not supported")
+)
+
+// negotiateVersion routes a request to the handler of the version it asks for, by path prefix
+// or else by APIVersionHeader. Requests that ask for neither get version 1, so that clients
+// written before versioning keep working. Version 1 responses are marked deprecated, with the
+// date it will be removed if sunset is set.
+func negotiateVersion(v1, v2 http.Handler, sunset time.Time) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := r.Header.Get(APIVersionHeader)
+		path := r.URL.Path
+		for _, prefix := range []string{"1", "2"} {
+			if strings.HasPrefix(path, "/v"+prefix+"/") {
+				version, path = prefix, strings.TrimPrefix(path, "/v"+prefix)
+				break
+			}
+		}
+		w.Header().Add("Vary", APIVersionHeader)
+
+		var next http.Handler
+		switch version {
+		case "", "1":
+			version, next = "1", v1
+			w.Header().Set("Deprecation", "true")
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			w.Header().Set("Link", `</v2`+path+`>; rel="successor-version"`)
+		case "2":
+			next = v2
+		default:
+			encodeError(r.Context(), ErrUnsupportedVersion, w)
+			return
+		}
+		w.Header().Set(APIVersionHeader, version)
+
+		r2 := r.WithContext(context.WithValue(r.Context(), apiVersionContextKey, version))
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path, r2.URL.RawPath = path, ""
+		next.ServeHTTP(w, r2)
+	})
+}
+
+// makeV2Handler serves the routes whose shapes changed in version 2, on the same endpoints as
+// version 1. Every other route is served by v1.
+func makeV2Handler(e Endpoints, v1 http.Handler, introspection oauth2.Introspector, options []httptransport.ServerOption) http.Handler {
+	r := mux.NewRouter()
+
+	// GET /v2/classes/
+	// Classes are listed under "classes" rather than "Classes".
+	r.Methods("GET").Path("/classes/").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.list")(e.ListClassesEndpoint),
+		DecodeListClassesRequest,
+		encodeV2ListClassesResponse,
+		options...,
+	))
+
+	// POST /v2/classes/
+	// Created classes are answered with 201 Created and their Location.
+	r.Methods("POST").Path("/classes/").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.create")(e.CreateClassEndpoint),
+		DecodeCreateClassRequest,
+		encodeV2CreateClassResponse,
+		options...,
+	))
+
+	// PATCH /v2/classes/{classID}
+	// The new name is sent as "name" rather than "class".
+	r.Methods("PATCH").Path("/classes/{classID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.update")(e.UpdateClassEndpoint),
+		DecodeV2UpdateClassRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// POST /v2/classes/{classID}/join
+	// Being put on the waitlist is answered with {"waitlisted": true} rather than as an error.
+	r.Methods("POST").Path("/classes/{classID}/join").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.join")(e.JoinClassEndpoint),
+		DecodeJoinClassRequest,
+		encodeV2JoinClassResponse,
+		options...,
+	))
+
+	// PATCH /v2/classes/{classID}/members/{userID}
+	// The new role is sent as {"role": "teacher"} rather than as a bare string.
+	r.Methods("PATCH").Path("/classes/{classID}/members/{userID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.members.update")(e.SetRoleEndpoint),
+		DecodeV2SetRoleRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// A catch-all route rather than the NotFoundHandler, so that methods of version 1 on the
+	// paths above are not refused.
+	r.PathPrefix("/").Handler(v1)
+	return validateRequests(r, "/v2")
+}
+
+func encodeV2ListClassesResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(listClassesResponse)
+	if resp.Error != nil {
+		encodeError(ctx, resp.Error, w)
+		return nil
+	}
+	classes := resp.Classes
+	if classes == nil {
+		classes = []uuid.UUID{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"classes": classes,
+	})
+}
+
+func encodeV2CreateClassResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(createClassResponse)
+	if resp.Error != nil {
+		encodeError(ctx, resp.Error, w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Location", "/v2/classes/"+resp.ClassID.String())
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(resp)
+}
+
+func encodeV2JoinClassResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(joinClassResponse)
+	if resp.Error != nil && resp.Error != ErrWaitlisted {
+		encodeError(ctx, resp.Error, w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if resp.Error == ErrWaitlisted {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"waitlisted": resp.Error == ErrWaitlisted,
+	})
+}
+
+func DecodeV2UpdateClassRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, err := uuid.Parse(mux.Vars(r)["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	var body struct {
+		Name        *string    `json:"name"`
+		CurrentUnit *uuid.UUID `json:"current_unit"`
+	}
+	if err := decodeJSONBody(r, &body); err != nil {
+		return nil, err
+	}
+	return updateClassRequest{ClassID: classID, Name: body.Name, CurrentUnit: body.CurrentUnit}, nil
+}
+
+func DecodeV2SetRoleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	classID, err := uuid.Parse(vars["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	userID, err := uuid.Parse(vars["userID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	var body struct {
+		Role string `json:"role"`
+	}
+	if err := decodeJSONBody(r, &body); err != nil {
+		return nil, err
+	}
+	// A missing or unknown role is left zero, and refused when the request is validated.
+	var role models.UserRole
+	role.UnmarshalText([]byte(body.Role))
+	return setRoleRequest{ClassID: classID, UserID: userID, Role: role}, nil
+}
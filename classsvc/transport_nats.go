@@ -0,0 +1,211 @@
+package classsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	natstransport "github.com/go-kit/kit/transport/nats"
+	"github.com/nats-io/nats.go"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/introspector"
+)
+
+// Subjects on which the class service answers NATS requests. Each takes the JSON request of the
+// corresponding endpoint wrapped in a natsRequest, and replies with its JSON response or with
+// {"error": "..."}.
+const (
+	RPCListClasses           = "classsvc.rpc.ListClasses"
+	RPCGetClass              = "classsvc.rpc.GetClass"
+	RPCCreateClass           = "classsvc.rpc.CreateClass"
+	RPCUpdateClass           = "classsvc.rpc.UpdateClass"
+	RPCDeleteClass           = "classsvc.rpc.DeleteClass"
+	RPCJoinClass             = "classsvc.rpc.JoinClass"
+	RPCSetRole               = "classsvc.rpc.SetRole"
+	RPCLeaveClass            = "classsvc.rpc.LeaveClass"
+	RPCListMembers           = "classsvc.rpc.ListMembers"
+	RPCGetMember             = "classsvc.rpc.GetMember"
+	RPCGetCapacity           = "classsvc.rpc.GetCapacity"
+	RPCSetCapacity           = "classsvc.rpc.SetCapacity"
+	RPCListWaitlist          = "classsvc.rpc.ListWaitlist"
+	RPCGetEnrollmentSettings = "classsvc.rpc.GetEnrollmentSettings"
+	RPCSetEnrollmentSettings = "classsvc.rpc.SetEnrollmentSettings"
+	RPCLinkGuardian          = "classsvc.rpc.LinkGuardian"
+	RPCUnlinkGuardian        = "classsvc.rpc.UnlinkGuardian"
+	RPCListStudents          = "classsvc.rpc.ListStudents"
+)
+
+// RPCQueue is the queue group the RPC subscriptions join, so that each request is answered by
+// a single instance.
+const RPCQueue = "classsvc"
+
+// natsRequest is the envelope of every NATS request. NATS messages have no headers, so the
+// token, lock override and version that HTTP carries outside the body travel next to the request.
+type natsRequest struct {
+	Token        string          `json:"token,omitempty"`
+	OverrideLock bool            `json:"override_lock,omitempty"`
+	Version      *int64          `json:"version,omitempty"`
+	Request      json.RawMessage `json:"request,omitempty"`
+}
+
+// SubscribeNATS answers requests on the RPC subjects from nc. Every subject requires the same
+// scope as the corresponding HTTP route.
+func SubscribeNATS(nc *nats.Conn, s Service, introspection oauth2.Introspector, logger log.Logger) ([]*nats.Subscription, error) {
+	e := MakeServerEndpoints(s)
+	options := []natstransport.SubscriberOption{
+		natstransport.SubscriberErrorLogger(logger),
+		natstransport.SubscriberErrorEncoder(encodeNATSError),
+		natstransport.SubscriberBefore(natsRequestToContext),
+	}
+	subscriber := func(scope string, e endpoint.Endpoint, dec natstransport.DecodeRequestFunc) *natstransport.Subscriber {
+		return natstransport.NewSubscriber(introspector.New(introspection, scope)(e), dec, encodeNATSResponse, options...)
+	}
+
+	subscribers := map[string]*natstransport.Subscriber{
+		RPCListClasses:           subscriber("classes.list", e.ListClassesEndpoint, natstransport.NopRequestDecoder),
+		RPCGetClass:              subscriber("classes.get", e.GetClassEndpoint, decodeNATSRequest(getClassRequest{})),
+		RPCCreateClass:           subscriber("classes.create", e.CreateClassEndpoint, decodeNATSRequest(createClassRequest{})),
+		RPCUpdateClass:           subscriber("classes.update", e.UpdateClassEndpoint, decodeNATSRequest(updateClassRequest{})),
+		RPCDeleteClass:           subscriber("classes.delete", e.DeleteClassEndpoint, decodeNATSRequest(deleteClassRequest{})),
+		RPCJoinClass:             subscriber("classes.join", e.JoinClassEndpoint, decodeNATSRequest(joinClassRequest{})),
+		RPCSetRole:               subscriber("classes.members.update", e.SetRoleEndpoint, decodeNATSRequest(setRoleRequest{})),
+		RPCLeaveClass:            subscriber("classes.leave", e.LeaveClassEndpoint, decodeNATSRequest(leaveClassRequest{})),
+		RPCListMembers:           subscriber("classes.members.list", e.ListMembersEndpoint, decodeNATSRequest(listMembersRequest{})),
+		RPCGetMember:             subscriber("classes.members.get", e.GetMemberEndpoint, decodeNATSRequest(getMemberRequest{})),
+		RPCGetCapacity:           subscriber("classes.capacity.get", e.GetCapacityEndpoint, decodeNATSRequest(getCapacityRequest{})),
+		RPCSetCapacity:           subscriber("classes.capacity.update", e.SetCapacityEndpoint, decodeNATSRequest(setCapacityRequest{})),
+		RPCListWaitlist:          subscriber("classes.waitlist.list", e.ListWaitlistEndpoint, decodeNATSRequest(listWaitlistRequest{})),
+		RPCGetEnrollmentSettings: subscriber("classes.enrollment.get", e.GetEnrollmentSettingsEndpoint, decodeNATSRequest(getEnrollmentSettingsRequest{})),
+		RPCSetEnrollmentSettings: subscriber("classes.enrollment.update", e.SetEnrollmentSettingsEndpoint, decodeNATSRequest(setEnrollmentSettingsRequest{})),
+		RPCLinkGuardian:          subscriber("classes.guardians.update", e.LinkGuardianEndpoint, decodeNATSRequest(linkGuardianRequest{})),
+		RPCUnlinkGuardian:        subscriber("classes.guardians.update", e.UnlinkGuardianEndpoint, decodeNATSRequest(unlinkGuardianRequest{})),
+		RPCListStudents:          subscriber("classes.guardians.list", e.ListStudentsEndpoint, decodeNATSRequest(listStudentsRequest{})),
+	}
+
+	var subs []*nats.Subscription
+	for subject, s := range subscribers {
+		sub, err := nc.QueueSubscribe(subject, RPCQueue, s.ServeMsg(nc))
+		if err != nil {
+			for _, sub := range subs {
+				sub.Unsubscribe()
+			}
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// natsRequestToContext moves the token, lock override and version of a request into the context.
+func natsRequestToContext(ctx context.Context, msg *nats.Msg) context.Context {
+	var req natsRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		return ctx
+	}
+	if req.Token != "" {
+		ctx = context.WithValue(ctx, introspector.TokenContextKey, req.Token)
+	}
+	if req.OverrideLock {
+		ctx = WithLockOverride(ctx)
+	}
+	if req.Version != nil {
+		ctx = WithVersion(ctx, *req.Version)
+	}
+	return ctx
+}
+
+// decodeNATSRequest returns a decoder for requests of the same type as v.
+func decodeNATSRequest(v interface{}) natstransport.DecodeRequestFunc {
+	t := reflect.TypeOf(v)
+	return func(_ context.Context, msg *nats.Msg) (interface{}, error) {
+		var req natsRequest
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			return nil, ErrBadRequest
+		}
+		request := reflect.New(t)
+		if err := json.Unmarshal(req.Request, request.Interface()); err != nil {
+			return nil, ErrBadRequest
+		}
+		return request.Elem().Interface(), nil
+	}
+}
+
+func encodeNATSResponse(ctx context.Context, reply string, nc *nats.Conn, response interface{}) error {
+	if e, ok := response.(errorer); ok && e.error() != nil {
+		encodeNATSError(ctx, e.error(), reply, nc)
+		return nil
+	}
+	return natstransport.EncodeJSONResponse(ctx, reply, nc, response)
+}
+
+// encodeNATSError replies with the message and code of err. Errors that are not safe to show
+// are replied as ErrInternal.
+func encodeNATSError(_ context.Context, err error, reply string, nc *nats.Conn) {
+	b, _ := json.Marshal(map[string]interface{}{
+		"error": PublicError(err).Error(),
+		"code":  ErrorCode(err),
+	})
+	nc.Publish(reply, b)
+}
+
+// encodeNATSRequest wraps a request in a natsRequest along with the token, lock override and
+// version from the context.
+func encodeNATSRequest(ctx context.Context, msg *nats.Msg, request interface{}) error {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	token, _ := ctx.Value(introspector.TokenContextKey).(string)
+	req := natsRequest{
+		Token:        token,
+		OverrideLock: LockOverride(ctx),
+		Request:      b,
+	}
+	if version, ok := Version(ctx); ok {
+		req.Version = &version
+	}
+	msg.Data, err = json.Marshal(req)
+	return err
+}
+
+// decodeNATSResponse returns a decoder for responses of the same type as v. Errors replied by
+// the server are mapped back to the errors of this package.
+func decodeNATSResponse(v interface{}) natstransport.DecodeResponseFunc {
+	t := reflect.TypeOf(v)
+	return func(_ context.Context, msg *nats.Msg) (interface{}, error) {
+		var failure struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}
+		if err := json.Unmarshal(msg.Data, &failure); err != nil {
+			return nil, err
+		}
+		if failure.Error != "" {
+			return nil, errorFromMessage(failure.Code, failure.Error)
+		}
+		response := reflect.New(t)
+		if err := json.Unmarshal(msg.Data, response.Interface()); err != nil {
+			return nil, err
+		}
+		return response.Elem().Interface(), nil
+	}
+}
+
+// errorFromMessage maps an error replied by the server back to the errors of this package, by
+// its code or, for servers that reply without one, by its message.
+func errorFromMessage(code, msg string) error {
+	for known, c := range errorCodes {
+		if code != "" && c == code {
+			return known
+		}
+	}
+	for _, known := range append(knownErrors, ErrWaitlisted) {
+		if known.Error() == msg {
+			return known
+		}
+	}
+	return errors.New(msg)
+}
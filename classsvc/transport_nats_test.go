@@ -0,0 +1,33 @@
+package classsvc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestNATSRequestVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantVersion int64
+		wantOK      bool
+	}{
+		{"unconditional", context.Background(), 0, false},
+		{"version", WithVersion(context.Background(), 7), 7, true},
+		{"version zero", WithVersion(context.Background(), 0), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg nats.Msg
+			if err := encodeNATSRequest(tt.ctx, &msg, getClassRequest{}); err != nil {
+				t.Fatal(err)
+			}
+			version, ok := Version(natsRequestToContext(context.Background(), &msg))
+			if version != tt.wantVersion || ok != tt.wantOK {
+				t.Errorf("version = %d, %t; want %d, %t", version, ok, tt.wantVersion, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package classsvc
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+// Actions written to the audit log.
+const (
+	AuditRemoveMember      = "remove_member"
+	AuditTransferOwnership = "transfer_ownership"
+	AuditArchiveClass      = "archive_class"
+	AuditLeaveWaitlist     = "leave_waitlist"
+	AuditUnlinkGuardian    = "unlink_guardian"
+	AuditForgetExternalIDs = "forget_external_ids"
+)
+
+// ReasonUserDeleted is the audit reason for changes made because a user account was deleted.
+const ReasonUserDeleted = "user_deleted"
+
+// UserRemoval counts what RemoveUser did.
+type UserRemoval struct {
+	Memberships     int
+	Transferred     int
+	Archived        int
+	WaitlistEntries int
+	GuardianLinks   int
+	// ExternalIDs counts the external IDs, such as OneRoster sourcedIds and LTI subjects, that
+	// were mapped onto the user.
+	ExternalIDs int
+}
+
+// RemoveUser removes a user whose account has been deleted from every class, waitlist and
+// guardian link. Classes the user owns are handed to their senior-most teacher, the one who
+// joined first, or archived if they have no other teacher. The external IDs mapped onto the
+// user are forgotten, so that rosters still listing them do not enroll the user again.
+//
+// Everything happens in one transaction. Every step is written to the audit log and recorded
+// with record, which may be nil.
+func RemoveUser(ctx context.Context, db *sql.DB, record RecordFunc, userID uuid.UUID) (*UserRemoval, error) {
+	s := &postgresService{db, record}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	removal, err := s.removeUser(ctx, tx, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return removal, nil
+}
+
+func (s *postgresService) removeUser(ctx context.Context, tx *sql.Tx, userID uuid.UUID) (*UserRemoval, error) {
+	var removal UserRemoval
+	members, err := models.MembersByUserID(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Lock classes in a consistent order.
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ClassID.String() < members[j].ClassID.String()
+	})
+	for _, member := range members {
+		err = s.removeMember(ctx, tx, member, &removal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := tx.Query("DELETE FROM waitlist_entries WHERE user_id=$1 RETURNING id, class_id, user_id, role, created_at;", userID)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*models.WaitlistEntry
+	for rows.Next() {
+		var entry models.WaitlistEntry
+		if err := rows.Scan(&entry.ID, &entry.ClassID, &entry.UserID, &entry.Role, &entry.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		err = s.recordChange(ctx, tx, Change{Kind: ChangeLeaveWaitlist, System: true, ClassID: &entry.ClassID, UserID: &userID, Before: entry})
+		if err == nil {
+			err = audit(tx, ReasonUserDeleted, AuditLeaveWaitlist, userID, &entry.ClassID, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		removal.WaitlistEntries++
+	}
+
+	students, err := models.GuardianLinksByGuardianID(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	guardians, err := models.GuardianLinksByStudentID(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range append(students, guardians...) {
+		err = link.Delete(tx)
+		if err != nil {
+			return nil, err
+		}
+		other := link.StudentID
+		if other == userID {
+			other = link.GuardianID
+		}
+		err = s.recordChange(ctx, tx, Change{Kind: ChangeUnlinkGuardian, System: true, UserID: &link.StudentID, Before: link})
+		if err == nil {
+			err = audit(tx, ReasonUserDeleted, AuditUnlinkGuardian, userID, nil, &other)
+		}
+		if err != nil {
+			return nil, err
+		}
+		removal.GuardianLinks++
+	}
+
+	res, err := tx.Exec("DELETE FROM external_refs WHERE kind='user' AND id=$1;", userID)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	removal.ExternalIDs = int(n)
+	res, err = tx.Exec("DELETE FROM roster_members WHERE user_id=$1;", userID)
+	if err != nil {
+		return nil, err
+	}
+	n, err = res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if removal.ExternalIDs > 0 || n > 0 {
+		err = audit(tx, ReasonUserDeleted, AuditForgetExternalIDs, userID, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &removal, nil
+}
+
+// removeMember removes a deleted user from a class, handing ownership on first.
+func (s *postgresService) removeMember(ctx context.Context, tx *sql.Tx, member *models.Member, removal *UserRemoval) error {
+	classID, userID := member.ClassID, member.UserID
+	_, err := tx.Exec("SELECT 1 FROM classes WHERE id=$1 FOR UPDATE;", classID)
+	if err != nil {
+		return err
+	}
+	class, err := models.ClassByID(tx, classID)
+	if err != nil {
+		return err
+	}
+	if member.Owner {
+		successor, err := seniorTeacher(tx, classID, userID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case successor != nil:
+			before := *successor
+			successor.Owner = true
+			err = saveMember(tx, successor)
+			if err == nil {
+				err = s.recordChange(ctx, tx, Change{Kind: ChangeTransferOwnership, System: true, ClassID: &classID, UserID: &successor.UserID, Before: &before, After: successor})
+			}
+			if err == nil {
+				err = audit(tx, ReasonUserDeleted, AuditTransferOwnership, userID, &classID, &successor.UserID)
+			}
+			if err != nil {
+				return err
+			}
+			removal.Transferred++
+		case class.Active:
+			before := classState(class)
+			class.Active = false
+			err = saveClass(tx, class)
+			if err == nil {
+				err = s.recordChange(ctx, tx, Change{Kind: ChangeDeleteClass, System: true, ClassID: &classID, Before: before, After: classState(class)})
+			}
+			if err == nil {
+				err = audit(tx, ReasonUserDeleted, AuditArchiveClass, userID, &classID, nil)
+			}
+			if err != nil {
+				return err
+			}
+			removal.Archived++
+		}
+	}
+	err = member.Delete(tx)
+	if err == nil {
+		err = s.recordChange(ctx, tx, Change{Kind: ChangeLeaveClass, System: true, ClassID: &classID, UserID: &userID, Before: member})
+	}
+	if err == nil {
+		err = audit(tx, ReasonUserDeleted, AuditRemoveMember, userID, &classID, nil)
+	}
+	if err != nil {
+		return err
+	}
+	removal.Memberships++
+	if !class.Active {
+		return nil
+	}
+	promoted, err := promote(tx, class)
+	if err != nil {
+		return err
+	}
+	return s.recordPromotions(ctx, tx, promoted)
+}
+
+// seniorTeacher gets the teacher of a class who joined first, other than userID, or nil if the
+// class has no other teacher.
+func seniorTeacher(tx *sql.Tx, classID, userID uuid.UUID) (*models.Member, error) {
+	var teacherID uuid.UUID
+	err := tx.QueryRow("SELECT user_id FROM members WHERE class_id=$1 AND user_id<>$2 AND role=$3 "+
+		"ORDER BY joined_at, user_id LIMIT 1;", classID, userID, models.UserRoleTeacher).Scan(&teacherID)
+	switch err {
+	case nil:
+		return models.MemberByUserIDClassID(tx, teacherID, classID)
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+func audit(tx *sql.Tx, reason, action string, userID uuid.UUID, classID, targetID *uuid.UUID) error {
+	entry := models.AuditLog{
+		Reason:    reason,
+		Action:    action,
+		UserID:    userID,
+		ClassID:   classID,
+		TargetID:  targetID,
+		CreatedAt: time.Now(),
+	}
+	return entry.Insert(tx)
+}
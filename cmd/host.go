@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,7 +15,7 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
 	"github.com/go-kit/kit/metrics/prometheus"
-	"github.com/nats-io/go-nats"
+	"github.com/gorilla/mux"
 	"github.com/ory/hydra/oauth2"
 	"github.com/ory/hydra/sdk"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -21,14 +23,27 @@ import (
 	"github.com/rubenv/sql-migrate"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/studiously/classsvc/announcements"
 	"github.com/studiously/classsvc/classsvc"
 	"github.com/studiously/classsvc/ddl"
+	"github.com/studiously/classsvc/eventbus"
+	"github.com/studiously/classsvc/graphql"
+	"github.com/studiously/classsvc/lti"
 	"github.com/studiously/classsvc/middleware"
+	"github.com/studiously/classsvc/oneroster"
+	"github.com/studiously/classsvc/outbox"
+	"github.com/studiously/classsvc/pb"
+	"github.com/studiously/classsvc/roster"
+	"github.com/studiously/classsvc/scim"
+	"github.com/studiously/classsvc/stream"
+	"github.com/studiously/classsvc/webhooks"
+	"google.golang.org/grpc"
 )
 
 var (
 	addr      string
 	debugAddr string
+	grpcAddr  string
 )
 
 // hostCmd represents the host command
@@ -55,10 +70,84 @@ A Hydra server is required to perform token introspection and thus authorization
 - HYDRA_CLUSTER_URL: URL of Hydra cluster.
 - HYDRA_TLS_VERIFY: Whether the client should verify Hydra's TLS.
 
+LTI Controls
+============
+LTI 1.3 launches are served under /lti/. Platforms are registered with "classsvc lti register"; see "classsvc lti --help" for the LTI_* controls.
+
+SCIM
+====
+Classes are provisioned as SCIM 2.0 Groups under /scim/v2/Groups. Identity providers need a token with the "classes.scim" scope.
+
+Enrollment
+==========
+Owners set enrollment windows and lock rosters with PUT /classes/{classID}/enrollment. Tokens with the "classes.admin" scope act as organization admins; owners and admins change a locked roster by adding "override_lock=true" to the request.
+
+Guardians
+=========
+Organization admins link guardians to students with PUT /guardians/{guardianID}/students/{studentID}. Guardians can read their students' classes and see who teaches and owns them, but not the other students.
+
+API Versions
+============
+The class routes are served in two versions, under /v1 and /v2. Version 2 lists classes under "classes", answers POST /classes/ with 201 Created and a Location, takes the new name of a class as "name", takes roles as {"role": "teacher"} and answers joins with {"waitlisted": true|false}; every other route, including announcements, webhooks and streams, is the same in both. /openapi.yaml describes both versions. Routes without a prefix are served by the version named in the API-Version header, or by version 1 when it is absent, and responses name the version that served them in API-Version. Version 1 responses carry "Deprecation: true", a Link to their successor-version and, when API_V1_SUNSET is set to a date such as 2027-06-30, a Sunset header.
+
+API Reference
+=============
+The HTTP API of the class service is described by an OpenAPI 3 spec, served at /openapi.yaml and /openapi.json and browsable at /docs. Requests to the routes it describes are checked against it, and rejected with 400 Bad Request naming the invalid parameter or body, before they reach the service. The spec is in classsvc/openapi.go.
+
+Errors
+======
+The class routes answer errors as application/problem+json (RFC 7807). Every problem has a stable "code", such as roster_locked or validation_failed, that clients should match on instead of the "detail"; validation problems list the invalid fields under "errors". Every response carries an X-Correlation-ID, taken from the request when the caller sends one, which is also in the problem and in the log lines of the request. Internal errors are logged in full and answered with the "internal" code only. Version 1 problems repeat their detail as "error" for older clients. The announcement, webhook, stream, OneRoster and LTI routes answer errors the same way, always repeating the detail as "error"; a stream that fails after it has started sends the problem in its "close" event.
+
+Requests are validated before they reach the service, over every transport: class names are trimmed and normalized to NFC and must be 1 to 100 characters without control characters, roles must be "student" or "teacher", and seat limits must be between 0 and 10000. Bodies that are not JSON, or larger than 64 KiB, are refused with validation_failed too.
+
+Idempotency Keys
+================
+POST, PUT, PATCH and DELETE requests to the class, announcement and webhook routes may carry an Idempotency-Key header, such as a UUID, so that clients can retry them safely. The first response to each key and user is stored for IDEMPOTENCY_TTL (default 24h) and replayed, with "Idempotent-Replayed: true", to requests that repeat it; server errors are not stored, so those requests may be retried. Reusing a key for a different method, path, If-Match or body is refused with 422 and code idempotency_key_reused, and repeating it while the first request is still being served with 409 and code idempotency_key_in_use. Bodies of requests with a key are limited to 64 KiB and refused with 413 beyond that. Expired keys are purged every IDEMPOTENCY_INTERVAL (default 1h). The Go client sends a key with every POST and PATCH, and retries them.
+
+Concurrent Edits
+================
+Classes and members carry a version that the database bumps on every change. GET /classes/{classID} and GET /classes/{classID}/members/{userID} send it as their ETag; sending it back as If-Match with PATCH or DELETE on the class, or with PATCH on the member or DELETE on its leave route, refuses the change with 412 and code version_mismatch if someone else changed it first. Go callers read Version from the class or member and pass it with classsvc.WithVersion.
+
+GraphQL
+=======
+POST /graphql serves classes, their members and the caller's memberships as a graph, with mutations for creating, updating, deleting, joining and leaving classes and setting roles. The schema is in graphql/schema.go. Every field needs the scope of the matching HTTP route. Members and classes are loaded in batches for each query, so nesting does not multiply queries.
+
+gRPC
+====
+The class service is also served over gRPC on the --grpc-addr address, as the pb.Classes service described in pb/classsvc.proto. Callers send their token as "authorization: Bearer <token>" metadata and need the same scopes as over HTTP; "override-lock: true" metadata overrides a roster lock, and "if-match: <version>" metadata makes a change conditional like the If-Match header. Being put on the waitlist is reported by the waitlisted field of JoinClassReply rather than as an error.
+
+NATS Requests
+=============
+When a NATS cluster is configured, the class service also answers request-reply calls on the classsvc.rpc.* subjects, one per method (for example classsvc.rpc.GetClass), in the "classsvc" queue group. Requests are JSON of the form {"token": "...", "override_lock": false, "version": 3, "request": {...}}, where version, if set, makes a change conditional like the If-Match header, and need the same scopes as over HTTP. Replies are the JSON response of the method, or {"error": "..."}. Go callers use classsvc.MakeNATSClientEndpoints.
+
+Announcements
+=============
+Class announcements are served under /classes/{classID}/announcements. Scheduled announcements are published every ANNOUNCEMENTS_INTERVAL (default 1m).
+
+Streams
+=======
+Members follow the joins, leaves, role changes and updates of a class as Server-Sent Events from GET /classes/{classID}/stream, with a token holding the "classes.members.list" scope. Reconnecting clients resume after the Last-Event-ID they send; events are kept for STREAM_RETENTION (default 24h), after which clients are sent a "reset" event and should reload the class.
+
+Webhooks
+========
+Class owners subscribe to the events of their classes with POST /classes/{classID}/webhooks; organization admins subscribe to every class with POST /webhooks. Deliveries are signed with the webhook secret and retried with exponential backoff; see "classsvc webhooks --help" for the WEBHOOKS_* controls and a local test receiver.
+
+Roster Controls
+===============
+When ROSTER_FILE is set, classes and members are synced from it every ROSTER_INTERVAL; see "classsvc roster --help" for the ROSTER_* controls.
+
 Messaging Controls
 ==================
-A NATS cluster is required for messaging across services. Without it, stale data pertaining to deleted resources may remain in the database, merely becoming inaccessible.
+A NATS cluster is required for messaging across services. Without it, events are only delivered within the process, which suits a single node: stale data pertaining to resources deleted by other services may remain in the database, merely becoming inaccessible.
 - NATS_CLUSTER_URL: URL of NATS cluster.
+
+Every change to a class is published as a CloudEvents 1.0 event in structured JSON, with a stable type such as io.studiously.classsvc.member.joined.v1 and a dataschema under https://schemas.studiously.io/classsvc/. Its data carries the actor and the state before and after the change. The subjects and their payloads are listed in middleware/messaging.go. Events are written to an outbox and relayed to NATS every OUTBOX_INTERVAL, in order for each class, retrying until NATS accepts them; see "classsvc outbox --help" for the OUTBOX_* controls.
+
+When a user is deleted, the user service publishes their ID on USERS_DELETE_SUBJECT (default users.delete). The user is removed from every class, waitlist and guardian link. Classes they owned are handed to their longest-serving teacher, or archived if they have none. The OneRoster, roster sync and LTI IDs mapped onto them are forgotten, so that rosters still listing them do not enroll them again. Every step is written to the audit_log table.
+
+User deletions and the events delivered to webhooks are consumed at least once through JetStream durable consumers ("classsvc-users" and "webhooks"), so the NATS cluster needs JetStream streams capturing USERS_DELETE_SUBJECT and classes.>. Deletions and events that fail are delivered again after 30s. Subscriptions that fail at startup are retried every 10s.
+
+Announcement events are CloudEvents too, written to the outbox with the change and published on the classes.announcements.* subjects; notification services should subscribe to classes.announcements.publish.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		var logger log.Logger
@@ -89,22 +178,9 @@ A NATS cluster is required for messaging across services. Without it, stale data
 
 		var db *sql.DB
 		{
-			// Set up database
-			var driver = viper.GetString("database.driver")
-			var config = viper.GetString("database.config")
-
 			var err error
-			db, err = sql.Open(driver, config)
+			db, err = connectDatabase(logger)
 			if err != nil {
-				logger.Log("msg", "database connection failed", "error", err)
-				os.Exit(-1)
-			}
-			if err := pingDatabase(db); err != nil {
-				logger.Log("msg", "database ping attempts failed")
-				os.Exit(-1)
-			}
-			if err := setupDatabase(driver, db); err != nil {
-				logger.Log("msg", "database migrations failed", "error", err)
 				os.Exit(-1)
 			}
 		}
@@ -125,35 +201,163 @@ A NATS cluster is required for messaging across services. Without it, stale data
 			introspector = client.Introspection
 		}
 
-		var nc *nats.Conn
+		var toolConfig lti.Config
 		{
 			var err error
-			nc, err = nats.Connect(viper.GetString("nats.cluster_url"))
+			toolConfig, err = ltiConfig()
 			if err != nil {
-				logger.Log("msg", "could not connect to NATS cluster", "error", err, "cluster_url", viper.GetString("nats.cluster_url"))
-				// not fatal
-				// os.Exit(-1)
+				logger.Log("msg", "could not load LTI tool key", "error", err, "path", viper.GetString("lti.private_key_file"))
+				os.Exit(-1)
 			}
 		}
 
+		var announcementInterval time.Duration
+		{
+			var err error
+			announcementInterval, err = time.ParseDuration(viper.GetString("announcements.interval"))
+			if err != nil || announcementInterval <= 0 {
+				logger.Log("msg", "invalid announcements interval", "error", err, "interval", viper.GetString("announcements.interval"))
+				os.Exit(-1)
+			}
+		}
+
+		// Changes are recorded in the outbox and the class streams, in the same transaction as the change.
+		record := classsvc.Records(middleware.Outbox(), middleware.Stream())
+
+		var syncer *roster.Syncer
+		var interval time.Duration
+		if viper.GetString("roster.file") != "" {
+			policy, err := roster.ParseConflictPolicy(viper.GetString("roster.conflict_policy"))
+			if err != nil {
+				logger.Log("msg", "invalid roster conflict policy", "error", err)
+				os.Exit(-1)
+			}
+			interval, err = time.ParseDuration(viper.GetString("roster.interval"))
+			if err != nil || interval <= 0 {
+				logger.Log("msg", "invalid roster interval", "error", err, "interval", viper.GetString("roster.interval"))
+				os.Exit(-1)
+			}
+			syncer = roster.NewSyncerWithRecorder(db, policy, record)
+		}
+
+		var bus eventbus.EventBus
+		{
+			if url := viper.GetString("nats.cluster_url"); url != "" {
+				// The bus connects to NATS on its own, so outbox events are kept until NATS comes up.
+				bus = eventbus.NewNATS(url, 5*time.Second)
+			} else {
+				logger.Log("msg", "no NATS cluster configured, delivering events in process")
+				bus = eventbus.NewMemory()
+			}
+		}
+
+		var relay *outbox.Relay
+		var relayInterval time.Duration
+		{
+			var err error
+			relayInterval, err = time.ParseDuration(viper.GetString("outbox.interval"))
+			if err != nil || relayInterval <= 0 {
+				logger.Log("msg", "invalid outbox interval", "error", err, "interval", viper.GetString("outbox.interval"))
+				os.Exit(-1)
+			}
+			maxBackoff, err := time.ParseDuration(viper.GetString("outbox.max_backoff"))
+			if err != nil || maxBackoff <= 0 {
+				logger.Log("msg", "invalid outbox backoff", "error", err, "max_backoff", viper.GetString("outbox.max_backoff"))
+				os.Exit(-1)
+			}
+			relay = outbox.NewRelay(db, bus)
+			relay.MaxAttempts = viper.GetInt("outbox.max_attempts")
+			relay.MaxBackoff = maxBackoff
+		}
+
 		var service classsvc.Service
 		{
-			service = classsvc.New(db)
+			service = classsvc.NewWithRecorder(db, record)
+			service = middleware.Logging(logger)(service)
+			service = middleware.Instrumenting(requestCount, duration)(service)
+		}
+
+		// Subscriptions that fail, for example because NATS is not up yet, are retried until they succeed.
+		const resubscribeInterval = 10 * time.Second
 
-			if nc != nil {
-				mm, err := middleware.Messaging(nc)
+		if nb, ok := bus.(*eventbus.NATS); ok {
+			// Backend jobs may call the class service over NATS request-reply as well as HTTP.
+			natsLogger := log.With(logger, "transport", "NATS")
+			eventbus.Retry(context.Background(), resubscribeInterval, natsLogger, func() error {
+				nc, err := nb.Conn()
 				if err != nil {
-					logger.Log("msg", "could not start encoded connection to NATS", "error", err)
-					// again, not fatal
-					// os.Exit(-1)
-				} else {
-					service = mm(service)
+					return err
 				}
+				_, err = classsvc.SubscribeNATS(nc, service, introspector, natsLogger)
+				return err
+			})
+		}
 
+		{
+			// Users deleted elsewhere are removed from their classes; see classsvc.RemoveUser.
+			usersLogger := log.With(logger, "component", "users")
+			eventbus.Retry(context.Background(), resubscribeInterval, usersLogger, func() error {
+				_, err := middleware.UserDeletions(bus, viper.GetString("users.delete_subject"), db, record, usersLogger)
+				return err
+			})
+		}
+
+		var dispatcher *webhooks.Dispatcher
+		var dispatchInterval time.Duration
+		{
+			var err error
+			dispatchInterval, err = time.ParseDuration(viper.GetString("webhooks.interval"))
+			if err != nil || dispatchInterval <= 0 {
+				logger.Log("msg", "invalid webhooks interval", "error", err, "interval", viper.GetString("webhooks.interval"))
+				os.Exit(-1)
+			}
+			maxBackoff, err := time.ParseDuration(viper.GetString("webhooks.max_backoff"))
+			if err != nil || maxBackoff <= 0 {
+				logger.Log("msg", "invalid webhooks backoff", "error", err, "max_backoff", viper.GetString("webhooks.max_backoff"))
+				os.Exit(-1)
+			}
+			timeout, err := time.ParseDuration(viper.GetString("webhooks.timeout"))
+			if err != nil || timeout <= 0 {
+				logger.Log("msg", "invalid webhooks timeout", "error", err, "timeout", viper.GetString("webhooks.timeout"))
+				os.Exit(-1)
+			}
+			dispatcher = webhooks.NewDispatcher(db)
+			dispatcher.Client.Timeout = timeout
+			dispatcher.MaxAttempts = viper.GetInt("webhooks.max_attempts")
+			dispatcher.MaxBackoff = maxBackoff
+			if dispatcher.Lease < 2*timeout {
+				dispatcher.Lease = 2 * timeout
+			}
+			if viper.GetBool("webhooks.allow_insecure") {
+				// Local receivers, such as "classsvc webhooks stub", are only reachable without the public address check.
+				dispatcher.Client.Transport = http.DefaultTransport
 			}
 
-			service = middleware.Logging(logger)(service)
-			service = middleware.Instrumenting(requestCount, duration)(service)
+			// Published events are queued for the webhooks that subscribe to them.
+			webhooksLogger := log.With(logger, "component", "webhooks")
+			eventbus.Retry(context.Background(), resubscribeInterval, webhooksLogger, func() error {
+				_, err := webhooks.Subscribe(bus, db, webhooksLogger)
+				return err
+			})
+		}
+
+		var hub *stream.Hub
+		{
+			retention, err := time.ParseDuration(viper.GetString("stream.retention"))
+			if err != nil || retention <= 0 {
+				logger.Log("msg", "invalid stream retention", "error", err, "retention", viper.GetString("stream.retention"))
+				os.Exit(-1)
+			}
+			hub = stream.NewHub(db, viper.GetString("database.config"))
+			hub.Retention = retention
+		}
+
+		var announcementService announcements.Service
+		var scheduler *announcements.Scheduler
+		{
+			// Announcement events go through the outbox too, in the same transaction as the change.
+			announcementService = announcements.NewWithRecorder(db, announcements.Outbox())
+			scheduler = announcements.NewScheduler(db, announcements.Outbox())
 		}
 
 		errs := make(chan error)
@@ -172,12 +376,103 @@ A NATS cluster is required for messaging across services. Without it, stale data
 			errs <- fmt.Errorf("%s", <-c)
 		}()
 
-		var h = classsvc.MakeHTTPHandler(service, introspector, logger)
+		if syncer != nil {
+			go func() {
+				p := roster.NewFileProvider(viper.GetString("roster.name"), viper.GetString("roster.file"))
+				syncer.Run(context.Background(), p, interval, log.With(logger, "component", "roster"))
+			}()
+		}
+
+		go relay.Run(context.Background(), relayInterval, log.With(logger, "component", "outbox"))
+
+		go hub.Run(context.Background(), log.With(logger, "component", "stream"))
+
+		go dispatcher.Run(context.Background(), dispatchInterval, log.With(logger, "component", "webhooks"))
+
+		go scheduler.Run(context.Background(), announcementInterval, log.With(logger, "component", "announcements"))
+
+		var idempotency *classsvc.Idempotency
+		{
+			ttl, err := time.ParseDuration(viper.GetString("idempotency.ttl"))
+			if err != nil || ttl <= 0 {
+				logger.Log("msg", "invalid idempotency TTL", "error", err, "ttl", viper.GetString("idempotency.ttl"))
+				os.Exit(-1)
+			}
+			interval, err := time.ParseDuration(viper.GetString("idempotency.interval"))
+			if err != nil || interval <= 0 {
+				logger.Log("msg", "invalid idempotency interval", "error", err, "interval", viper.GetString("idempotency.interval"))
+				os.Exit(-1)
+			}
+			idempotency = classsvc.NewIdempotency(db)
+			idempotency.TTL = ttl
+			go idempotency.Run(context.Background(), interval, log.With(logger, "component", "idempotency"))
+		}
+
+		var v1Sunset time.Time
+		if sunset := viper.GetString("api.v1_sunset"); sunset != "" {
+			var err error
+			v1Sunset, err = time.Parse("2006-01-02", sunset)
+			if err != nil {
+				logger.Log("msg", "invalid API v1 sunset", "error", err, "sunset", sunset)
+				os.Exit(-1)
+			}
+		}
+
+		var h http.Handler
+		{
+			webhookService := webhooks.New(db)
+			if viper.GetBool("webhooks.allow_insecure") {
+				webhookService = webhooks.NewInsecure(db)
+			}
+			webhookHandler := webhooks.MakeIdempotentHTTPHandler(webhookService, idempotency, introspector, logger)
+
+			announcementHandler := announcements.MakeIdempotentHTTPHandler(announcementService, idempotency, introspector, logger)
+			streamHandler := stream.MakeHTTPHandler(stream.New(db), hub, introspector, logger)
+
+			// Announcements, webhooks and streams live under the class routes, so they are matched before the class service.
+			// They are the same in every version of the API, so they are served under each version prefix as well.
+			classes := mux.NewRouter()
+			for _, prefix := range []string{"", "/v1", "/v2"} {
+				classes.PathPrefix(prefix + "/classes/{classID}/announcements").Handler(http.StripPrefix(prefix, announcementHandler))
+				classes.PathPrefix(prefix + "/classes/{classID}/webhooks").Handler(http.StripPrefix(prefix, webhookHandler))
+				classes.Path(prefix + "/classes/{classID}/stream").Handler(http.StripPrefix(prefix, streamHandler))
+			}
+			classes.NotFoundHandler = classsvc.MakeIdempotentHTTPHandler(service, idempotency, introspector, v1Sunset, logger)
+
+			m := http.NewServeMux()
+			m.Handle("/oneroster/", oneroster.MakeHTTPHandler(oneroster.NewWithRecorder(db, record), introspector, logger))
+			m.Handle("/scim/", scim.MakeHTTPHandler(scim.NewWithRecorder(db, record), introspector, logger))
+			m.Handle("/lti/", lti.MakeHTTPHandler(lti.NewWithRecorder(db, toolConfig, record), viper.GetString("lti.app_url"), introspector, logger))
+			graphqlService := graphql.New(db)
+			m.Handle("/graphql", graphql.MakeHTTPHandler(graphqlService, graphql.MakeSchema(graphqlService, service), introspector, logger))
+			docs := classsvc.MakeDocsHandler()
+			m.Handle("/openapi.yaml", docs)
+			m.Handle("/openapi.json", docs)
+			m.Handle("/docs", docs)
+			for _, prefix := range []string{"", "/v1", "/v2"} {
+				m.Handle(prefix+"/webhooks", http.StripPrefix(prefix, webhookHandler))
+				m.Handle(prefix+"/webhooks/", http.StripPrefix(prefix, webhookHandler))
+			}
+			m.Handle("/", classes)
+			h = m
+		}
 		go func(address string) {
 			logger.Log("transport", "HTTP", "addr", addr)
 			errs <- http.ListenAndServe(address, h)
 		}(addr)
 
+		go func(address string) {
+			logger.Log("transport", "gRPC", "addr", address)
+			ln, err := net.Listen("tcp", address)
+			if err != nil {
+				errs <- err
+				return
+			}
+			g := grpc.NewServer()
+			pb.RegisterClassesServer(g, classsvc.MakeGRPCServer(service, introspector, logger))
+			errs <- g.Serve(ln)
+		}(grpcAddr)
+
 		logger.Log("exit", <-errs)
 	},
 }
@@ -186,9 +481,37 @@ func init() {
 	RootCmd.AddCommand(hostCmd)
 
 	viper.SetDefault("hydra.tls_verify", true)
+	viper.SetDefault("announcements.interval", "1m")
+	viper.SetDefault("users.delete_subject", middleware.SubjDeleteUser)
+	viper.SetDefault("stream.retention", "24h")
+	viper.SetDefault("idempotency.ttl", "24h")
+	viper.SetDefault("idempotency.interval", "1h")
 
 	hostCmd.Flags().StringVarP(&addr, "bind-addr", "a", ":8080", "HTTP bind address")
 	hostCmd.Flags().StringVarP(&debugAddr, "debug-addr", "d", ":8081", "Debug and metrics listen address")
+	hostCmd.Flags().StringVarP(&grpcAddr, "grpc-addr", "g", ":8082", "gRPC bind address")
+}
+
+// connectDatabase opens the configured database, waits for it to come up and applies migrations.
+// Failures are logged before being returned.
+func connectDatabase(logger log.Logger) (*sql.DB, error) {
+	var driver = viper.GetString("database.driver")
+	var config = viper.GetString("database.config")
+
+	db, err := sql.Open(driver, config)
+	if err != nil {
+		logger.Log("msg", "database connection failed", "error", err)
+		return nil, err
+	}
+	if err := pingDatabase(db); err != nil {
+		logger.Log("msg", "database ping attempts failed")
+		return nil, err
+	}
+	if err := setupDatabase(driver, db); err != nil {
+		logger.Log("msg", "database migrations failed", "error", err)
+		return nil, err
+	}
+	return db, nil
 }
 
 func setupDatabase(driver string, db *sql.DB) error {
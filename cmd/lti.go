@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/studiously/classsvc/lti"
+	"github.com/studiously/classsvc/middleware"
+	"github.com/studiously/classsvc/models"
+)
+
+var platform models.LtiPlatform
+
+// ltiCmd represents the lti command
+var ltiCmd = &cobra.Command{
+	Use:   "lti",
+	Short: "Manage LTI 1.3 platform registrations and roster syncs.",
+	Long: `Manages the LMS platforms (Canvas, Moodle, ...) that may launch classsvc as an LTI 1.3 tool.
+
+The tool is configured with the following controls, which "classsvc host" also reads:
+
+- LTI_LAUNCH_URL: Public URL of POST /lti/launch, registered with platforms as the redirect URI.
+- LTI_APP_URL: URL users are redirected to after a successful launch, with a launch token the application
+  verifies against /lti/jwks.json. Requires LTI_PRIVATE_KEY_FILE. Without it, launches respond with JSON.
+- LTI_PRIVATE_KEY_FILE: PEM encoded RSA key used to sign launch tokens and service token requests, and published
+  at /lti/jwks.json.
+- LTI_KEY_ID: Key ID of the tool key. Defaults to "classsvc".`,
+}
+
+var ltiRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register or update a platform.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if platform.Issuer == "" || platform.ClientID == "" {
+			return fmt.Errorf("--issuer and --client-id are required")
+		}
+		logger := log.NewLogfmtLogger(os.Stderr)
+		db, err := connectDatabase(logger)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		return platform.Upsert(db)
+	},
+}
+
+var ltiSyncCmd = &cobra.Command{
+	Use:   "sync <class-id>",
+	Short: "Reconcile a linked class with its platform roster.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		classID, err := uuid.Parse(args[0])
+		if err != nil {
+			return err
+		}
+		config, err := ltiConfig()
+		if err != nil {
+			return err
+		}
+		logger := log.NewLogfmtLogger(os.Stderr)
+		db, err := connectDatabase(logger)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		summary, err := lti.NewWithRecorder(db, config, middleware.Outbox()).SyncMembers(context.Background(), classID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("members: %d added, %d updated, %d removed, %d waitlisted\n", summary.Added, summary.Updated, summary.Removed, summary.Waitlisted)
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(ltiCmd)
+	ltiCmd.AddCommand(ltiRegisterCmd)
+	ltiCmd.AddCommand(ltiSyncCmd)
+
+	viper.SetDefault("lti.key_id", "classsvc")
+
+	ltiRegisterCmd.Flags().StringVar(&platform.Issuer, "issuer", "", "issuer (iss) of the platform's launch tokens")
+	ltiRegisterCmd.Flags().StringVar(&platform.ClientID, "client-id", "", "client ID the platform assigned to the tool")
+	ltiRegisterCmd.Flags().StringSliceVar((*[]string)(&platform.DeploymentIDs), "deployment-id", nil, "deployment ID allowed to launch the tool (repeatable)")
+	ltiRegisterCmd.Flags().StringVar(&platform.AuthLoginURL, "auth-login-url", "", "platform OIDC authorization endpoint")
+	ltiRegisterCmd.Flags().StringVar(&platform.AuthTokenURL, "auth-token-url", "", "platform OAuth 2 token endpoint")
+	ltiRegisterCmd.Flags().StringVar(&platform.JwksURL, "jwks-url", "", "platform public key set URL")
+}
+
+// ltiConfig builds the tool configuration from the LTI controls.
+func ltiConfig() (lti.Config, error) {
+	config := lti.Config{
+		LaunchURL: viper.GetString("lti.launch_url"),
+		KeyID:     viper.GetString("lti.key_id"),
+	}
+	if path := viper.GetString("lti.private_key_file"); path != "" {
+		pem, err := ioutil.ReadFile(path)
+		if err != nil {
+			return config, err
+		}
+		config.Key, err = jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
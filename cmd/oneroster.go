@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-kit/kit/log"
+	"github.com/spf13/cobra"
+	"github.com/studiously/classsvc/middleware"
+	"github.com/studiously/classsvc/oneroster"
+)
+
+var (
+	exportOptions oneroster.ExportOptions
+)
+
+// onerosterCmd represents the oneroster command
+var onerosterCmd = &cobra.Command{
+	Use:   "oneroster",
+	Short: "Import or export IMS OneRoster 1.1 CSV bundles.",
+	Long: `Exchanges rosters with a student information system using IMS OneRoster 1.1 CSV bundles.
+
+Both subcommands connect to the database configured by DATABASE_DRIVER and DATABASE_CONFIG (see "classsvc host --help").`,
+}
+
+var onerosterImportCmd = &cobra.Command{
+	Use:   "import <bundle.zip>",
+	Short: "Apply a OneRoster bundle to classes and members.",
+	Long: `Reads orgs.csv, classes.csv, users.csv and enrollments.csv from a OneRoster zip bundle.
+
+Classes are created or renamed to match classes.csv, and classes marked "tobedeleted" are deactivated. Enrollments are added as members, with teacher, administrator, aide and proctor mapped to the teacher role and student to the student role. The first primary teacher of a class without an owner becomes its owner.
+
+A user's sourcedId must be their user ID, or be mapped onto it in external_refs with source "oneroster" and kind "user". Enrollments of other users are skipped with a warning.
+
+When manifest.csv marks classes.csv as "bulk", imported classes missing from it are deactivated. When it marks enrollments.csv as "bulk", members added by earlier imports who are missing from it are removed from the classes in the bundle; members who joined some other way are kept.
+
+Classes and members are changed like users change them, so seat limits, waitlists, roster locks and enrollment windows apply. Enrollments in a full class are put on its waitlist, and changes refused by a locked roster or closed enrollment window are skipped with a warning. Every change is written to the outbox, to be published with the events of the service.
+
+The whole bundle is applied in a single transaction.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := log.NewLogfmtLogger(os.Stderr)
+		db, err := connectDatabase(logger)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		summary, err := oneroster.NewWithRecorder(db, middleware.Outbox()).Import(context.Background(), f, fi.Size())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("classes: %d created, %d updated, %d deactivated\n", summary.ClassesCreated, summary.ClassesUpdated, summary.ClassesDeactivated)
+		fmt.Printf("members: %d added, %d updated, %d removed, %d waitlisted\n", summary.MembersAdded, summary.MembersUpdated, summary.MembersRemoved, summary.MembersWaitlisted)
+		for _, w := range summary.Warnings {
+			fmt.Println("warning:", w)
+		}
+		return nil
+	},
+}
+
+var onerosterExportCmd = &cobra.Command{
+	Use:   "export <bundle.zip>",
+	Short: "Write all active classes as a OneRoster bundle.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := log.NewLogfmtLogger(os.Stderr)
+		db, err := connectDatabase(logger)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		f, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		if err := oneroster.New(db).Export(context.Background(), f, exportOptions); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(onerosterCmd)
+	onerosterCmd.AddCommand(onerosterImportCmd)
+	onerosterCmd.AddCommand(onerosterExportCmd)
+
+	onerosterExportCmd.Flags().StringVar(&exportOptions.OrgSourcedID, "org", "", "sourcedId of the school org written to orgs.csv")
+	onerosterExportCmd.Flags().StringVar(&exportOptions.OrgName, "org-name", "", "name of the school org written to orgs.csv")
+	onerosterExportCmd.Flags().StringVar(&exportOptions.TermSourcedID, "term", "", "sourcedId of the academic session classes belong to")
+	onerosterExportCmd.Flags().StringVar(&exportOptions.TermTitle, "term-title", "", "title of the academic session")
+	onerosterExportCmd.Flags().StringVar(&exportOptions.SchoolYear, "school-year", "", "school year of the academic session")
+}
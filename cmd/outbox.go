@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/studiously/classsvc/outbox"
+)
+
+// outboxCmd represents the outbox command
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Inspect events that could not be published.",
+	Long: `Events are written to an outbox in the same transaction as the change they describe, and "classsvc host" relays them to NATS. Events that fail OUTBOX_MAX_ATTEMPTS times are dead-lettered so that later events for the same class are not held back.
+
+The relay is configured with the following controls:
+
+- OUTBOX_INTERVAL: Time between relay passes, e.g. "500ms". Defaults to "1s".
+- OUTBOX_MAX_ATTEMPTS: Number of times an event is tried before it is dead-lettered. Defaults to 10.
+- OUTBOX_MAX_BACKOFF: Longest delay between attempts. Defaults to "10m".`,
+}
+
+var outboxDeadLettersCmd = &cobra.Command{
+	Use:   "dead-letters",
+	Short: "List dead-lettered events.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := log.NewLogfmtLogger(os.Stderr)
+		db, err := connectDatabase(logger)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		letters, err := outbox.DeadLetters(db)
+		if err != nil {
+			return err
+		}
+		for _, l := range letters {
+			fmt.Printf("%d\t%s\t%s\t%d attempts\t%s\n", l.ID, l.FailedAt.Format(time.RFC3339), l.Subject, l.Attempts, l.LastError)
+		}
+		return nil
+	},
+}
+
+var outboxRedriveCmd = &cobra.Command{
+	Use:   "redrive ID...",
+	Short: "Move dead-lettered events back into the outbox.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := log.NewLogfmtLogger(os.Stderr)
+		db, err := connectDatabase(logger)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		for _, arg := range args {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid event ID %q", arg)
+			}
+			if err := outbox.Redrive(db, id); err != nil {
+				return fmt.Errorf("event %d: %v", id, err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(outboxCmd)
+	outboxCmd.AddCommand(outboxDeadLettersCmd)
+	outboxCmd.AddCommand(outboxRedriveCmd)
+
+	viper.SetDefault("outbox.interval", "1s")
+	viper.SetDefault("outbox.max_attempts", 10)
+	viper.SetDefault("outbox.max_backoff", "10m")
+}
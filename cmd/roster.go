@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-kit/kit/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/studiously/classsvc/middleware"
+	"github.com/studiously/classsvc/roster"
+)
+
+// rosterCmd represents the roster command
+var rosterCmd = &cobra.Command{
+	Use:   "roster",
+	Short: "Sync classes and members from external rosters.",
+	Long: `Reconciles classes and members with an external roster provider.
+
+The provider is configured with the following controls, which "classsvc host" also reads to sync on a schedule:
+
+- ROSTER_FILE: Path of a JSON roster file. Scheduled syncs are disabled without it.
+- ROSTER_NAME: Name of the provider. External IDs are scoped to it, so it must not change once synced. Defaults to "file".
+- ROSTER_INTERVAL: Time between scheduled syncs, e.g. "30m". Defaults to "1h".
+- ROSTER_CONFLICT_POLICY: "local" keeps member changes made in classsvc since the last sync; "provider" overwrites them. Defaults to "local".
+
+User IDs in the roster must be user IDs, or be mapped onto them in external_refs with source "roster:<name>" and kind "user". Enrollments of other users are skipped and reported as conflicts.
+
+Members are changed like users change them, so seat limits, waitlists, roster locks and enrollment windows apply, and every change is written to the outbox. Changes refused by a locked roster or closed enrollment window are reported as conflicts and tried again on the next sync.`,
+}
+
+var rosterSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Run a single sync.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if viper.GetString("roster.file") == "" {
+			return fmt.Errorf("--file or ROSTER_FILE is required")
+		}
+		policy, err := roster.ParseConflictPolicy(viper.GetString("roster.conflict_policy"))
+		if err != nil {
+			return err
+		}
+		logger := log.NewLogfmtLogger(os.Stderr)
+		db, err := connectDatabase(logger)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		p := roster.NewFileProvider(viper.GetString("roster.name"), viper.GetString("roster.file"))
+		report, err := roster.NewSyncerWithRecorder(db, policy, middleware.Outbox()).Sync(context.Background(), p)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("classes: %d created, %d updated, %d deactivated\n", report.ClassesCreated, report.ClassesUpdated, report.ClassesDeactivated)
+		fmt.Printf("members: %d added, %d updated, %d removed, %d waitlisted\n", report.MembersAdded, report.MembersUpdated, report.MembersRemoved, report.MembersWaitlisted)
+		for _, c := range report.Conflicts {
+			fmt.Println("conflict:", c)
+		}
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(rosterCmd)
+	rosterCmd.AddCommand(rosterSyncCmd)
+
+	viper.SetDefault("roster.name", "file")
+	viper.SetDefault("roster.interval", "1h")
+	viper.SetDefault("roster.conflict_policy", "local")
+
+	rosterSyncCmd.Flags().String("file", "", "JSON roster file")
+	rosterSyncCmd.Flags().String("name", "file", "provider name")
+	rosterSyncCmd.Flags().String("policy", "local", `conflict policy, "local" or "provider"`)
+	viper.BindPFlag("roster.file", rosterSyncCmd.Flags().Lookup("file"))
+	viper.BindPFlag("roster.name", rosterSyncCmd.Flags().Lookup("name"))
+	viper.BindPFlag("roster.conflict_policy", rosterSyncCmd.Flags().Lookup("policy"))
+}
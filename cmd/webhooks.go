@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/studiously/classsvc/eventbus"
+	"github.com/studiously/classsvc/webhooks"
+)
+
+var (
+	stubAddr   string
+	stubSecret string
+	stubStatus int
+)
+
+// webhooksCmd represents the webhooks command
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Work with outbound webhooks.",
+	Long: `Class owners and organization admins subscribe webhooks to class events through the HTTP API. "classsvc host" signs every delivery and retries failures with exponential backoff; the delivery log of a webhook is served under /webhooks/{webhookID}/deliveries, from which deliveries can be redelivered.
+
+Deliveries are dispatched with the following controls:
+
+- WEBHOOKS_INTERVAL: Time between dispatch passes, e.g. "1s". Defaults to "5s".
+- WEBHOOKS_MAX_ATTEMPTS: Number of times a delivery is tried before it is marked failed. Defaults to 8.
+- WEBHOOKS_MAX_BACKOFF: Longest delay between attempts. Defaults to "1h".
+- WEBHOOKS_TIMEOUT: Time a receiver has to respond to a delivery. Defaults to "10s".
+- WEBHOOKS_ALLOW_INSECURE: Accept http URLs and deliver to private, loopback and link-local addresses, e.g. to a local "classsvc webhooks stub". For development only. Defaults to false.
+
+Webhook URLs must otherwise use https, and deliveries are only sent to public addresses. Redirects are not followed.`,
+}
+
+var webhooksStubCmd = &cobra.Command{
+	Use:   "stub",
+	Short: "Run a local webhook receiver that prints the deliveries it accepts.",
+	Long:  `Runs an HTTP server that verifies the signature of every delivery with the given secret and prints its event. Point a webhook at it to test the whole flow locally. Use --status to answer with an error and watch deliveries being retried.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if stubSecret == "" {
+			return fmt.Errorf("--secret is required")
+		}
+		logger := log.NewLogfmtLogger(os.Stderr)
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if err := webhooks.Verify(stubSecret, r.Header.Get(webhooks.HeaderSignature), body, 5*time.Minute); err != nil {
+				logger.Log("delivery", r.Header.Get(webhooks.HeaderDelivery), "error", err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			e, err := eventbus.DecodeCloudEvent(body, nil)
+			if err != nil {
+				logger.Log("delivery", r.Header.Get(webhooks.HeaderDelivery), "error", err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", r.Header.Get(webhooks.HeaderDelivery), e.Time.Format(time.RFC3339), e.Type, e.Subject, e.Data)
+			w.WriteHeader(stubStatus)
+		})
+		logger.Log("transport", "HTTP", "addr", stubAddr)
+		return http.ListenAndServe(stubAddr, h)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(webhooksCmd)
+	webhooksCmd.AddCommand(webhooksStubCmd)
+
+	webhooksStubCmd.Flags().StringVarP(&stubAddr, "bind-addr", "a", ":9090", "HTTP bind address")
+	webhooksStubCmd.Flags().StringVar(&stubSecret, "secret", "", "Secret the webhook was created with")
+	webhooksStubCmd.Flags().IntVar(&stubStatus, "status", http.StatusNoContent, "Status to answer verified deliveries with")
+
+	viper.SetDefault("webhooks.interval", "5s")
+	viper.SetDefault("webhooks.max_attempts", 8)
+	viper.SetDefault("webhooks.max_backoff", "1h")
+	viper.SetDefault("webhooks.timeout", "10s")
+	viper.SetDefault("webhooks.allow_insecure", false)
+}
@@ -0,0 +1,109 @@
+// Package dbtest provides a Postgres database for the tests that exercise the
+// services against their schema.
+//
+// The database is given by TEST_DATABASE_CONFIG, a URL like DATABASE_CONFIG.
+// Every table in it is emptied, so it must not hold anything worth keeping.
+// Tests that need it are skipped when it is not set:
+//
+//	TEST_DATABASE_CONFIG=postgres://localhost/classsvc_test?sslmode=disable go test ./...
+package dbtest
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/rubenv/sql-migrate"
+	"github.com/studiously/classsvc/ddl"
+)
+
+// ConfigEnv names the environment variable holding the URL of the test database.
+const ConfigEnv = "TEST_DATABASE_CONFIG"
+
+// lockKey identifies the advisory lock that keeps the tests of different packages, which go test
+// runs at the same time, from emptying the database under each other.
+const lockKey = 0x636c617373737663
+
+var (
+	lockOnce sync.Once
+	lockErr  error
+)
+
+// Open connects to the test database, applies the migrations and empties every table. The test
+// is skipped if no database is configured.
+func Open(t *testing.T) *sql.DB {
+	config := os.Getenv(ConfigEnv)
+	if config == "" {
+		t.Skip(ConfigEnv + " is not set")
+	}
+	lockOnce.Do(func() {
+		lockErr = lock(config)
+	})
+	if lockErr != nil {
+		t.Fatal(lockErr)
+	}
+	db, err := sql.Open("postgres", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrations := &migrate.AssetMigrationSource{
+		Asset:    ddl.Asset,
+		AssetDir: ddl.AssetDir,
+		Dir:      "postgres",
+	}
+	if _, err := migrate.Exec(db, "postgres", migrations, migrate.Up); err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	if err := truncate(db); err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	return db
+}
+
+// lock takes the database for the test binary. The lock is held by a transaction that is never
+// ended, so it is released when the binary exits.
+func lock(config string) error {
+	db, err := sql.Open("postgres", config)
+	if err != nil {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		db.Close()
+		return err
+	}
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock($1);", lockKey)
+	if err != nil {
+		tx.Rollback()
+		db.Close()
+	}
+	return err
+}
+
+// truncate empties every table but the migration log.
+func truncate(db *sql.DB) error {
+	rows, err := db.Query("SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'gorp_migrations';")
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, `"`+table+`"`)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(tables) == 0 {
+		return err
+	}
+	_, err = db.Exec("TRUNCATE " + strings.Join(tables, ", ") + " CASCADE;")
+	return err
+}
@@ -3,6 +3,19 @@
 // ddl.go
 // ddl_gen.go
 // postgres/1_init.sql
+// postgres/2_external_refs.sql
+// postgres/3_lti.sql
+// postgres/4_roster_members.sql
+// postgres/5_capacity.sql
+// postgres/6_enrollment_windows.sql
+// postgres/7_guardian_links.sql
+// postgres/8_announcements.sql
+// postgres/9_outbox.sql
+// postgres/10_user_removal.sql
+// postgres/11_webhooks.sql
+// postgres/12_class_events.sql
+// postgres/13_idempotency_keys.sql
+// postgres/14_versions.sql
 // DO NOT EDIT!
 
 package ddl
@@ -130,6 +143,266 @@ func postgres1_initSql() (*asset, error) {
 	return a, nil
 }
 
+var _postgres2_external_refsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x90\x41\x0e\x82\x30\x10\x45\xf7\x3d\xc5\x2c\x25\xc2\x09\x58\x81\x6d\x4c\x23\x16\x52\xdb\x04\x56\x84\x48\x35\x8d\x0a\xa6\x60\xe4\xf8\x56\x88\x04\x50\x9b\xe9\xea\xcf\xff\x6f\x66\x3c\x0f\xd6\x37\x7d\x36\x45\xab\x40\xde\xd1\x86\x93\x40\x10\x10\x41\x18\x11\x50\x5d\xab\x4c\x55\x5c\x73\xa3\x4e\x0d\xac\x10\x40\x53\x3f\xcc\x51\x41\xff\x04\x49\x05\xb0\xd8\x7e\x19\x45\xae\x15\x2f\xba\x2a\x01\xfe\x88\x63\x96\x2e\xbf\x45\x3d\xfa\x00\xa4\xa4\x78\x26\x26\x9c\xee\x03\x9e\xc1\x8e\x64\xab\x81\xef\xf6\x28\x77\x9a\xe9\x20\xc7\x47\x9f\xe9\x29\xc3\x24\x9d\x4f\x9f\x0f\xce\xfc\x6d\xb4\xed\xb6\x3a\x1b\x1d\xb3\xc5\x8e\xf2\x40\xd9\x16\x42\xc1\x09\x81\x05\xcc\x32\x2c\xc1\x9b\x9c\x0b\xd7\xcf\x0a\x61\x1e\x27\xbf\xce\xe5\xa3\x17\x37\x5f\xbb\x27\x5a\x01\x00\x00")
+
+func postgres2_external_refsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres2_external_refsSql,
+		"postgres/2_external_refs.sql",
+	)
+}
+
+func postgres2_external_refsSql() (*asset, error) {
+	bytes, err := postgres2_external_refsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/2_external_refs.sql", size: 346, mode: os.FileMode(420), modTime: time.Unix(1792352174, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres3_ltiSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7d\x54\x5d\x6f\x82\x30\x14\x7d\xe7\x57\xdc\xf0\x32\xcd\xf0\x17\xf8\x84\x72\x35\x64\x88\x0e\xdb\xc4\x6d\x59\x08\xd3\x6e\x63\xf2\x61\x68\x8d\x2e\xcb\xfe\xfb\x0a\x8d\x40\x45\xd6\x90\x40\xda\xd3\xd3\x73\xcf\x3d\x65\x34\x82\xfb\x34\xfe\x28\x22\xc1\x80\x1e\x8c\x69\x80\x36\x41\x20\xf6\xc4\x43\x48\x44\x1c\x1e\x92\x48\xbc\xe7\x45\xca\x61\x60\x00\xc4\x9c\x1f\x59\x01\x97\x41\x70\x43\xe4\xcb\x5f\x12\xf0\xa9\xe7\x59\x12\xb1\x4d\x62\x96\x89\x30\xde\xf5\x22\x76\xec\x90\xe4\xdf\xa9\x42\xf1\x0a\xf1\xf2\x5a\x23\xc0\xc1\x99\x4d\x3d\x02\x77\x3f\xbf\x77\x25\x3c\x3a\x8a\xcf\x30\xc9\x3f\xe2\x2c\x3c\x16\xc9\x2d\xc2\x0a\x21\xf2\x3d\xeb\x45\x7c\x9d\xf6\xbc\x5a\xeb\x15\xb5\x0a\xdc\x85\x1d\x3c\xc1\x03\x3e\x0d\x54\x91\x56\x53\xca\xd0\x18\x8e\x8d\xae\x35\x5c\x48\xd3\x94\x2f\xd5\xa7\x46\x5e\x8d\xba\xa8\x16\x7d\x79\x5a\x96\x67\xdb\x7f\xe0\x96\xee\x74\x1f\xa4\x65\x75\x2f\xa4\x60\x52\xd8\x2e\x8c\x04\x10\x77\x81\x6b\x62\x2f\x56\xe4\xb9\x6b\x76\x96\x9f\x06\x3d\x55\x6e\xf3\x4c\xb0\xb3\x50\x75\x6e\x93\x88\xf3\xba\xb9\x00\x94\xba\x4e\x6f\x95\x57\x61\x51\x22\xff\xc9\x4a\x17\xa0\x45\xe5\x26\x83\x12\xa7\xc7\xad\x0d\x48\x59\xfa\xc6\x0a\xfe\x19\x1f\x78\x13\x8e\x6e\xd6\xaa\xa4\xcd\x96\x01\xba\x73\xbf\x94\x0f\x03\xf3\x52\xaa\x39\x84\x00\x67\x18\xa0\x3f\xc5\xb5\x32\xa0\x6c\xba\x59\xad\x2c\x7d\xc9\xe1\xa1\x34\x6c\x6a\xaf\xa7\xb6\x83\xe5\x0c\x5d\x39\x76\x33\xd3\x65\x56\xbe\x98\x16\x98\xb5\x01\xfa\x21\x57\xf7\xae\x67\x43\xe7\xec\x76\xff\xa8\xef\x3e\x52\x04\xd7\x77\x70\xa3\xb5\x31\x54\x64\xa1\x66\x6d\xd8\xf8\x28\x9f\xb3\x54\x2c\xc9\xb5\xe6\xd3\xb5\xeb\xcf\x61\x42\x02\x44\xa8\x2f\x88\xc6\x61\xb5\x9a\x51\x0a\x19\xb5\x7e\x2c\x4e\x7e\xca\x0c\x27\x58\xae\x6e\xe4\x6a\x7c\xbd\xa0\xae\x55\x67\xba\x36\x64\x6c\xfc\x01\xa0\x1d\xe0\xb9\xb4\x04\x00\x00")
+
+func postgres3_ltiSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres3_ltiSql,
+		"postgres/3_lti.sql",
+	)
+}
+
+func postgres3_ltiSql() (*asset, error) {
+	bytes, err := postgres3_ltiSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/3_lti.sql", size: 1204, mode: os.FileMode(420), modTime: time.Unix(1792352330, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres4_roster_membersSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x90\x4d\x8e\xc2\x30\x0c\x85\xf7\x39\x85\xd5\x55\x2b\xda\x13\xb0\x0a\x8d\x41\x15\x25\xa9\xdc\x44\x82\x55\xc5\x0c\x11\xaa\x44\x29\x4a\xf8\xb9\x3e\x69\x47\x1d\x90\x98\xf1\xf2\x7d\xcf\xf6\xb3\xb3\x0c\x66\x5d\x7b\x74\xfb\xab\x05\x73\x61\x39\x21\xd7\x08\x9a\x2f\x4a\x04\xd7\xfb\xab\x75\x4d\x67\xbb\x2f\xeb\x3c\xc4\x0c\xe0\xe2\xfa\x7b\x7b\xb0\x0e\x34\x6e\x35\x8c\x25\x95\x06\x69\xca\x32\x0d\xf8\xfb\xb4\xf7\xbe\x69\x0f\x60\x4c\x21\x3e\xf1\xcd\x87\x71\x81\xfe\x83\x5d\x7f\xb2\xa3\x68\x6a\xa4\x86\x54\x88\xf0\x8e\x2b\x2a\x36\x9c\x76\xb0\xc6\x5d\x3c\xe5\x48\x7f\x57\xa6\xd3\xf4\x64\xf0\x2e\x15\x61\xb1\x92\x83\x17\xe2\x68\xf2\x44\x09\x10\x2e\x91\x50\xe6\x58\xff\x74\xda\x70\x57\x34\x12\x25\x41\x60\x89\xe1\xfa\x9c\xd7\x39\x17\x38\x28\xa6\x12\xfc\xa5\xb0\x64\xce\x58\xf6\xf6\x31\xd1\x3f\xce\x4c\x90\xaa\xfe\xfc\xd8\x9c\x3d\x01\xe2\x9c\xf0\xbd\x5e\x01\x00\x00")
+
+func postgres4_roster_membersSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres4_roster_membersSql,
+		"postgres/4_roster_members.sql",
+	)
+}
+
+func postgres4_roster_membersSql() (*asset, error) {
+	bytes, err := postgres4_roster_membersSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/4_roster_members.sql", size: 350, mode: os.FileMode(420), modTime: time.Unix(1792352566, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres5_capacitySql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xbd\x53\xc1\x6e\x82\x40\x10\xbd\xf3\x15\x13\x2f\x62\xaa\x49\xcf\x92\x36\x59\xd9\x91\x6e\x84\x85\x2e\xbb\x49\xdb\x0b\x21\x4a\x1a\x12\xab\x06\x30\xb6\x7f\xdf\x05\x41\xb7\xa2\x69\xd2\x43\x37\x9c\x66\xde\xbc\x37\xf3\x98\x99\x4c\xe0\xee\x23\x7f\x2f\xd2\x2a\x03\xb5\xb3\x88\x2f\x51\x80\x24\x33\x1f\x61\xb9\x4e\xcb\x32\x2b\x81\x50\x0a\x6e\xe8\xab\x80\xc3\x32\xdd\xa5\xcb\xbc\xfa\x02\xc6\x25\x7a\x1a\xe9\x3e\xa1\xbb\x00\xfb\x14\x7f\x7c\x80\xfb\x91\x63\x59\xae\x40\x22\xb1\x25\x2a\xb6\xeb\x2c\x69\x21\xb9\x26\xb4\x2d\x38\x92\x27\xf9\x0a\x94\x62\x14\x9a\xc7\x43\x09\x5c\xf9\xfe\x58\xa7\xeb\x92\x26\xa8\x62\x14\x89\x08\x35\x8b\x99\xee\xf5\x71\xae\xbe\xde\x52\x5d\x14\x09\x16\x10\xf1\x0a\x0b\x7c\xb5\x3b\xf9\x71\xa3\xd4\xa4\xe7\xa1\x40\xe6\xf1\x3a\x0d\xf6\xa0\x03\x0c\x46\x20\x70\x8e\x02\xb9\x8b\xf1\xc9\x12\x7b\xd0\x64\x42\x0e\x14\x7d\xd4\x83\xba\x24\x76\x09\xc5\x3a\xa2\x22\x4a\xce\x11\xab\xe7\xc6\x21\xcd\xab\x75\x5e\x56\x49\xb6\xa9\x8a\xce\x0e\x6d\x44\xf7\x66\xcc\xd3\x33\x33\xe2\x9b\x43\x19\xbd\x8f\x4d\xf7\xc0\xf0\xef\xa7\x83\xfb\x32\x2b\x8e\x88\x9b\x90\x93\xc9\xa6\xcd\x86\xa8\x1e\x6e\x4e\x94\x2f\x61\x58\x56\xfb\x95\x6e\x77\x08\xd3\xe9\x19\xda\x34\x52\x64\x7a\x73\x56\x49\x5a\x81\x64\x01\xc6\x92\x04\x91\x7c\xbb\x42\xb1\xd9\x1e\xec\x7f\xb2\x59\x71\xf6\xac\x50\xaf\x06\xc5\x97\x9e\xdb\x49\xa7\x98\xb4\x06\xe9\xef\x53\xb7\xa5\x19\x7b\x3f\x46\xc5\x8c\x7b\x30\x93\x02\x11\x8c\x8d\x69\x0b\xb5\x62\x2b\xf8\x9b\xd2\x5f\x44\x1a\x7e\x6b\x62\x5c\x27\xdd\x1e\x36\x16\x15\x61\x74\x63\x8f\x1c\x33\x79\x71\x72\xce\xd5\xc3\x6e\xf0\x17\x97\xed\x58\xdf\x0a\x9b\x29\x20\x12\x04\x00\x00")
+
+func postgres5_capacitySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres5_capacitySql,
+		"postgres/5_capacity.sql",
+	)
+}
+
+func postgres5_capacitySql() (*asset, error) {
+	bytes, err := postgres5_capacitySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/5_capacity.sql", size: 1042, mode: os.FileMode(420), modTime: time.Unix(1792353041, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres6_enrollment_windowsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x95\x90\xb1\x0a\xc3\x20\x14\x45\x77\xbf\xe2\xed\xc5\x2f\x70\x32\xd5\x40\xe1\x45\x43\xa2\x4b\x97\x10\xd2\x47\x29\x35\x1a\x54\xe8\xef\xb7\x4b\xa1\x43\xa0\x64\xbe\xf7\x1e\x38\x97\x73\x38\xad\x8f\x7b\x9e\x2b\x81\xdf\x98\x44\xa7\x07\x70\xb2\x41\x0d\x4b\x98\x4b\xa1\x02\x52\x29\x38\x5b\xf4\x9d\x01\x8a\x39\x85\xb0\x52\xac\x53\xda\x28\x96\x69\xae\xe0\x2e\x9d\x1e\x9d\xec\x7a\x77\x15\x07\xf6\x4b\x48\x9f\xf0\x28\x20\xa7\x52\x29\x4f\x21\x2d\x4f\xba\x41\x63\x2d\x6a\x69\xc0\x58\x07\xc6\x23\x82\xd2\xad\xf4\xe8\xa0\x95\x38\x6a\xc1\x18\xff\xb1\x53\xe9\x15\x77\xf1\x6a\xb0\xfd\x2e\x5f\xfc\xad\xef\xf9\x1c\x5a\x7d\x5f\x14\xec\x0d\xc3\xe1\xd3\xe7\x89\x01\x00\x00")
+
+func postgres6_enrollment_windowsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres6_enrollment_windowsSql,
+		"postgres/6_enrollment_windows.sql",
+	)
+}
+
+func postgres6_enrollment_windowsSql() (*asset, error) {
+	bytes, err := postgres6_enrollment_windowsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/6_enrollment_windows.sql", size: 393, mode: os.FileMode(420), modTime: time.Unix(1792353206, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres7_guardian_linksSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x90\xdd\x0a\x82\x40\x10\x85\xef\xf7\x29\xe6\x52\x49\x9f\xa0\xab\xb5\x9d\x62\x49\x57\x59\x77\xa1\xba\x11\x49\x09\xa9\xd6\xf0\x07\x7b\xfc\x56\xa8\x14\xa9\x61\xae\x86\x6f\xce\x99\x33\xbe\x0f\xab\x7b\x75\x69\xf2\xae\x04\xfd\x20\x1b\x89\x54\x21\x28\x1a\x84\x08\x97\x3e\x6f\x8a\x2a\x37\xd9\xad\x32\xd7\x16\x1c\x02\xd3\xa8\x2a\x40\x6b\xce\xe0\x5d\x22\x56\x20\x74\x18\x7a\x96\x69\xbb\xbe\x28\x4d\x37\x22\x7f\x99\x73\x53\x5a\xc7\x22\xcb\x3b\x00\xc5\x23\x4c\x15\x8d\x12\x75\xfa\x32\xc0\x70\x4b\x75\xa8\xc0\xd4\x83\xe3\x8e\x1b\x89\xe4\x11\x95\x47\xd8\xe3\xd1\x99\x5d\xe1\xcd\xec\x5c\xe2\xae\xc9\x27\x02\x17\x0c\x0f\x8b\x08\xd9\xc4\xda\x7e\x5a\xd5\x58\x2c\x53\xea\x94\x8b\x1d\x04\x4a\x22\x82\x33\xd3\xb6\xca\xfe\xec\x57\xac\x1e\x0c\x61\x32\x4e\x7e\xfe\x6a\x4d\x5e\xa2\x31\x1c\xe8\x58\x01\x00\x00")
+
+func postgres7_guardian_linksSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres7_guardian_linksSql,
+		"postgres/7_guardian_links.sql",
+	)
+}
+
+func postgres7_guardian_linksSql() (*asset, error) {
+	bytes, err := postgres7_guardian_linksSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/7_guardian_links.sql", size: 344, mode: os.FileMode(420), modTime: time.Unix(1792353573, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres8_announcementsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x95\x52\xdd\x6e\x82\x30\x14\xbe\xe7\x29\x4e\xb8\xc2\x4c\x9e\xc0\xab\x4a\x0f\x8e\xac\x16\x52\xda\x4c\x77\x63\x50\xc8\x24\xd1\x42\xf8\x89\xdb\xdb\xaf\xe0\x70\x18\xdd\xe2\x1a\x2e\x9a\xc3\xf7\x73\xfa\x9d\xe3\xba\xf0\x74\xcc\xdf\xab\xa4\xc9\x40\x95\x96\x27\x90\x48\x04\x49\xe6\x0c\x21\xd1\xba\x68\xf5\x2e\x3b\x66\xba\xa9\xc1\xb1\x00\xf2\x14\x86\xa3\x54\x40\x87\x3b\x0f\x25\x70\xc5\x18\x44\x22\x58\x12\xb1\x86\x17\x5c\x4f\x0d\x7c\x77\x48\xea\x7a\xd3\x93\xee\xc1\x3b\x48\xd2\x36\xfb\xa2\xea\x31\xbf\x41\xb6\x45\xfa\xf9\x5d\x96\xb8\x92\xf7\x20\x65\xae\x75\x76\xee\x6d\x1e\x86\x0c\x09\xbf\xee\x8b\xa2\x4f\x14\x93\xe0\x13\x16\x63\x4f\x68\xb7\x87\xbc\xde\x6f\x92\x06\x64\xb0\xc4\x58\x92\x65\x24\xdf\x6e\x09\xba\x38\x39\x93\x11\xa1\x33\x79\xcc\x61\x57\x65\x26\xd3\xf4\x71\x87\xb6\x4c\xff\x47\xf0\x43\x81\xc1\x82\x77\x61\x83\x63\x0f\x59\xdb\x13\x10\xe8\xa3\x40\xee\x61\x7c\x9e\x40\x66\x86\x67\xf7\x7f\x42\x6e\x64\x18\x9a\x09\x7b\x24\xf6\x08\xc5\xae\xa2\x22\x4a\x7e\x2a\xd6\x64\x66\x0d\x6b\x10\x70\x8a\xab\xeb\x35\xd8\x0c\x3e\xe6\xfb\x30\x4d\x18\xfe\xf5\x9a\xa8\x38\xe0\x0b\x98\x4b\x81\x08\xce\x00\x9e\x8e\x02\x37\xfa\x7f\xc8\xb7\xfa\x92\xf4\x43\x0e\x23\x5d\x78\x7d\x36\xcf\xee\x03\xbb\x68\x98\xb7\xb8\xa3\x0d\xa7\xc5\x49\x5b\x54\x84\xd1\xbd\x0d\x9f\x59\x5f\x34\x20\xd4\xc4\x0d\x03\x00\x00")
+
+func postgres8_announcementsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres8_announcementsSql,
+		"postgres/8_announcements.sql",
+	)
+}
+
+func postgres8_announcementsSql() (*asset, error) {
+	bytes, err := postgres8_announcementsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/8_announcements.sql", size: 781, mode: os.FileMode(420), modTime: time.Unix(1792353667, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres9_outboxSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x93\x5d\x6f\x82\x30\x14\x86\xef\xf9\x15\xe7\x52\x33\x4d\x76\xef\x55\x19\x67\xa4\x19\xa0\xa9\x25\xd1\xdd\x34\x55\x3a\xc3\x86\xd4\x94\x1a\xf5\xdf\xaf\x2c\xa2\x18\xd4\xd1\xf4\xa2\x49\xcf\x7b\x3e\xde\xa7\x1d\x8f\xe1\x65\x9b\x6f\x8c\xb4\x0a\xd2\x9d\xf7\xc6\x90\x70\x04\x4e\xfc\x08\x41\xef\xed\x4a\x1f\x61\xe0\x01\xe4\x19\xdc\x2c\x9f\x86\x73\x64\x94\x44\xee\x9c\x4c\x39\x24\x69\x14\xc1\x8c\xd1\x98\xb0\x25\x7c\xe0\x72\xe4\x34\xd5\x7e\xf5\xad\xd6\xf6\xa2\xe1\xb8\xe0\xcd\xb9\xd1\xd4\x71\xda\x64\xca\xe4\xe5\x46\xfc\xa8\xd3\xb3\xb8\x9d\x3c\x15\x5a\x5e\x1b\xf1\x97\x1c\xc9\x9d\xb8\xb5\x51\x6e\x9a\x4c\xc8\x73\x69\x4e\x63\x9c\x73\x12\xcf\xf8\xe7\xb5\xd7\x00\xdf\x49\x1a\x71\x28\xf5\x61\x30\xac\x55\xd2\x5a\xb5\xdd\xd9\xaa\xc9\x4e\x13\x8e\x21\xb2\x9b\xec\x17\xd5\x6b\xad\x28\xd5\xd1\x8a\xb3\xac\x2e\xd6\xaf\x4e\x21\x2b\x2b\x94\x31\xda\x5c\x5d\xf1\x86\x13\xaf\xb1\x9e\x26\x01\x2e\xce\xd6\x8b\xb6\x35\x22\xcf\xdc\x3e\xba\x14\xd3\xa4\x41\x93\xce\x69\x12\x82\xcf\x19\x22\x0c\xda\xc1\x23\x47\xac\x95\xb4\xcd\x53\x64\x4a\x66\xa2\x50\xae\x71\x53\x75\xe1\x3a\xb2\x6e\xf4\x5b\x57\x9f\x92\xed\x85\xb5\x17\xd3\x5e\x40\xef\xb9\xdc\xe1\x77\x0f\x5e\xc7\xfc\x47\x3d\x7d\xc9\xbc\xb8\x3c\x9f\xff\xa1\xfe\xc1\x1b\xb7\xbe\x51\xa0\x0f\xa5\x17\xb0\xe9\xec\xb1\xed\x93\xee\xfd\xc4\xfb\x05\x18\xb8\x83\x3e\x8b\x03\x00\x00")
+
+func postgres9_outboxSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres9_outboxSql,
+		"postgres/9_outbox.sql",
+	)
+}
+
+func postgres9_outboxSql() (*asset, error) {
+	bytes, err := postgres9_outboxSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/9_outbox.sql", size: 907, mode: os.FileMode(420), modTime: time.Unix(1792353993, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres10_user_removalSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x92\xd1\x6a\x83\x30\x14\x86\xef\xf3\x14\xe7\xd2\xb1\xf5\x09\xbc\x8a\x4d\x56\xc2\x62\x94\x98\x40\xbb\x1b\xc9\x34\x88\xa3\xea\xd0\x94\xee\xf1\x17\xad\x6e\x52\x3a\xda\x90\x8b\x70\xf8\xf2\x9f\x9f\xff\x9c\xcd\x06\x9e\x9b\xba\xea\x8d\xb3\xa0\xbf\x10\xe6\x8a\x4a\x50\x38\xe2\x14\x1a\xdb\x7c\xd8\x7e\x00\x4c\x08\x6c\x13\xae\x63\x01\x9f\x5d\xdd\xda\x32\x37\x0e\x14\x8b\x69\xa6\x70\x9c\xaa\x77\x10\x89\x02\xa1\x39\x07\x42\x5f\xb1\xe6\x0a\xda\xee\x1c\x3c\x85\x08\x6d\x25\xc5\x8a\xce\x72\xe6\x54\xd6\x2e\x3f\x76\x15\x04\x08\xa0\x2e\x61\x39\x11\xdb\x65\x54\x32\xcc\xfd\xfb\x57\x2a\x95\x2c\xc6\xf2\x00\x6f\xf4\xf0\xe2\xf1\xde\x9a\xa1\x6b\x27\x5c\xd1\xbd\x5a\xbe\x2e\xf8\x88\x98\xc2\xd5\x77\x90\xd3\x60\xfb\xfc\xd2\x59\x6b\x46\x6e\x21\xc5\xd1\x0c\xc3\x85\x19\x91\xb1\xe4\x4c\x5f\x59\x37\xd5\x96\x52\xe1\xfd\xb8\x47\x83\x40\xab\x28\x98\x20\x74\xff\x17\x45\x3e\x3b\xf2\xf7\xdb\xcb\x26\x62\x95\x92\xce\x98\xd8\x41\xa4\x24\xa5\x10\xcc\x9c\x57\xfa\x47\x68\xf1\x7d\x57\x69\x01\x47\x53\x9b\xd5\xf0\x49\x77\x6e\x11\x91\x49\x7a\x3d\xae\xf0\xe6\x52\x4c\xe4\xf5\x56\x84\xe8\x07\xe7\xaa\x94\xea\x4f\x02\x00\x00")
+
+func postgres10_user_removalSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres10_user_removalSql,
+		"postgres/10_user_removal.sql",
+	)
+}
+
+func postgres10_user_removalSql() (*asset, error) {
+	bytes, err := postgres10_user_removalSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/10_user_removal.sql", size: 591, mode: os.FileMode(420), modTime: time.Unix(1792354107, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres11_webhooksSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x9d\x54\xc1\x6e\x9b\x40\x10\xbd\xfb\x2b\xe6\x66\x50\x6d\xa9\xf7\xa8\x07\x0c\x13\x67\x15\x8c\x5d\x58\xd4\xb8\x55\xb5\x22\x61\x9a\xa2\x12\x40\xb0\x89\x63\x55\xfd\xf7\x2c\xc4\x0b\x04\x63\xcb\x2a\xe2\x00\xec\xbc\x79\x3b\xef\xbd\x65\x3e\x87\x4f\x4f\xc9\x63\x19\x49\x82\xb0\x98\xd8\x3e\x5a\x1c\x81\x5b\x0b\x17\x61\x47\xf7\xbf\xf3\xfc\x4f\x05\xc6\x04\x20\x89\x41\x5f\x61\xc8\x1c\xfd\xec\xad\x39\x78\xa1\xeb\xc2\xc6\x67\x2b\xcb\xdf\xc2\x2d\x6e\x67\xaa\xfc\x21\x8d\xaa\x4a\x34\xa0\x7e\xb9\x8f\xd7\xe8\xa3\x67\x63\xf0\x5e\x41\xaa\x79\x12\x9b\xb0\xf6\xc0\x41\x17\x15\xb5\x6d\x05\xb6\xe5\x60\xdd\xe3\xb9\x4c\x35\x8e\xe3\x1d\x1f\x52\xd6\x25\x15\x3d\x94\x24\xcf\x96\xd0\x0b\x65\xb2\x6a\x4b\x7e\xfc\x1c\x6c\xdc\xc1\x6b\x2b\x74\x39\x4c\xff\xfe\x9b\x36\x3b\x2f\x49\x89\x11\x8b\xfb\xfd\xe8\xa0\xfd\x92\x48\x02\x67\x2b\x0c\xb8\xb5\xda\xf0\xef\xc7\x2d\xb3\x7c\x67\x98\x13\xf3\x6a\xa2\x75\x65\x9e\x83\x77\xad\xae\x42\x8b\xa4\xee\x57\xd5\x56\x89\xd0\x4a\x1e\x06\xcc\x5b\xc2\x82\xfb\x88\x60\xe8\xba\x5e\xa7\x0f\x0e\x89\x98\xd2\xe4\x85\xca\x84\x8e\xbc\x6a\xae\x05\x5b\x06\xe8\x33\xcb\x3d\x63\x98\x6e\xa5\xb1\xa3\x26\xf7\xec\xeb\xc2\x71\xd2\xbf\x46\x79\xd1\x6d\xe6\xac\x43\x42\xee\x0b\x3a\x5f\x57\x44\xfb\x34\x8f\xba\xd9\x16\x5b\x8e\xd6\x58\x28\x64\x24\x9f\xab\x6e\xfc\xb1\x7e\x9d\xed\x05\x65\x71\x92\x3d\x4e\xc1\xbe\x41\xfb\x16\x8c\x03\x9a\x79\x60\xb4\x6b\x33\x98\x1e\x24\xa6\xb8\x7e\xf9\x15\x25\xa9\x7a\x32\xcd\x9a\x2e\x92\x92\x9e\x0a\xd9\x12\x32\x8f\xe3\x12\xfd\x71\xba\xcf\x35\x22\xa3\x57\x29\x0e\xb0\xcb\x42\x54\xa3\x4a\xaa\x8a\x3c\xab\x48\xb4\x3b\x6c\x78\xea\x25\x95\x0f\x29\xa8\x2c\xf3\xb2\x9b\x78\x10\xd4\xf7\xef\x17\x11\xb5\xa3\x1e\x70\x3d\x54\x3f\xcc\xa1\xc7\xbe\x86\x83\x4c\xf7\x92\x28\xba\x44\x09\x9d\x84\xa3\xa0\xf7\x93\xfb\x21\xf2\x1d\x78\xd6\xe6\x48\x71\x8f\x9d\xa3\x13\x9c\xff\xcb\x76\x11\xcf\xc0\xc1\xcb\x99\x06\x40\x13\xbe\xdd\xa8\x13\xa5\x23\xfb\xa5\x8b\xa3\xd2\x79\xde\xfb\x37\x3b\xf9\x2e\x9b\x38\xfe\x7a\x73\xf2\xe4\x5f\x8d\x2c\xab\x8f\x6f\x54\x76\xd6\x40\xe1\x05\x00\x00")
+
+func postgres11_webhooksSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres11_webhooksSql,
+		"postgres/11_webhooks.sql",
+	)
+}
+
+func postgres11_webhooksSql() (*asset, error) {
+	bytes, err := postgres11_webhooksSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/11_webhooks.sql", size: 1505, mode: os.FileMode(420), modTime: time.Unix(1792354365, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres12_class_eventsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x91\xcd\x6e\x83\x30\x10\x84\xef\x7e\x8a\x3d\x82\x1a\x9e\x20\x27\x83\x37\x91\x55\x63\x90\x7f\xa4\xd0\x0b\x42\xc1\xaa\x90\x52\x40\x01\x35\xcd\xdb\xd7\x84\x42\x9b\x2a\xaa\x6a\xf9\xe0\xc3\x37\xe3\xd9\xd9\x28\x82\xa7\xb7\xe6\xf5\x5c\x8d\x0e\x6c\x4f\x12\x85\xd4\x20\x18\x1a\x0b\x84\xe3\xa9\x1a\x86\xd2\xbd\xbb\x76\x1c\x20\x20\x00\x4d\x0d\xcb\x89\xf9\x5e\xa3\xe2\x54\xf8\xb7\xcc\x0c\x48\x2b\x04\xe4\x8a\xa7\x54\x15\xf0\x8c\xc5\xc6\xe3\xb3\xfe\x26\xb2\x96\xb3\x45\xba\xe2\x0a\x77\xa8\x50\x26\xa8\x67\xd4\xf9\x5f\x9a\x3a\x84\x4c\x02\x43\x81\x3e\x47\x42\x75\x42\x19\x4e\x66\xe3\xb5\x77\x5f\x06\x06\x0f\xe6\xb7\xd9\x84\xf4\xd5\xf5\xd4\x55\xb7\x8c\x71\x61\x90\x3e\x40\x8e\x67\xe7\x27\xad\xcb\x6a\x04\xc3\x53\xd4\x86\xa6\xb9\x79\xf9\x8e\xc4\x70\x47\xad\x30\xd0\x76\x97\x20\x24\xe1\x96\x2c\x8d\x70\xc9\xf0\x70\xd7\x48\xb9\x8c\x37\xdf\x0f\xef\xee\x83\xdf\x75\x66\x35\x97\x7b\x88\x8d\x42\x84\x60\xc1\x37\xbe\x46\x6f\xfc\x87\xef\x9a\xf1\x5f\xb6\x2b\x3d\xa5\x8d\x7e\xac\x93\x75\x97\x96\x30\x95\xe5\x0f\xd6\xb9\x25\x9f\xf4\xc7\x89\x75\xf9\x01\x00\x00")
+
+func postgres12_class_eventsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres12_class_eventsSql,
+		"postgres/12_class_events.sql",
+	)
+}
+
+func postgres12_class_eventsSql() (*asset, error) {
+	bytes, err := postgres12_class_eventsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/12_class_events.sql", size: 505, mode: os.FileMode(420), modTime: time.Unix(1792354576, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres13_idempotency_keysSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7d\x52\x4d\x73\x82\x30\x10\xbd\xf3\x2b\xf6\x88\x53\xe9\x1f\xf0\x04\x65\xeb\xd0\x62\x70\x30\xcc\x68\x2f\x0c\xca\x56\x32\xad\x60\x93\x58\xf4\xdf\x37\xa1\x28\xce\x68\xbb\xa7\xcc\xcb\x7b\x6f\x3f\x3d\x0f\x1e\x76\x62\x2b\x0b\x4d\x90\xed\x9d\xa7\x14\x7d\x8e\xc0\xfd\x20\x46\x10\x25\xed\xf6\x8d\xa6\x7a\x73\xca\x3f\xe8\xa4\xc0\x75\x00\x0e\x8a\x64\x2e\x4a\xf8\x8d\x2c\x8b\xc2\xfe\x09\x2c\xe1\xc0\xb2\x38\x1e\x1b\x96\xa1\xc3\x10\x1c\x97\xfc\x1e\x4b\xd2\xd7\x81\x94\xce\xab\x42\x55\x00\xc1\x8a\xa3\x7f\x87\xe5\x79\xa0\x74\xa1\x0f\x0a\x84\xea\x40\x68\x2b\xf1\x49\xa0\x2b\x82\x77\x21\x95\x3e\xfb\x40\x2b\x74\xd5\xc1\x36\xbd\x21\xaf\x49\xd4\x5b\x30\x05\x7f\x53\xf9\x68\x9c\x7a\x9b\x3e\x22\xc6\x71\x8a\xa9\x4d\x51\x51\x51\x92\x3c\xff\xbc\x2c\x12\x16\x58\x78\xdd\x94\x57\x6d\x74\xf5\x59\x78\x23\xc9\x8c\xab\xcc\x0b\xdd\x35\x17\xcd\x70\xc1\xfd\xd9\x9c\xbf\x5d\xca\x86\x10\x9f\xfd\x2c\xe6\x50\x37\xad\x3b\xb2\x1a\x3a\xee\x85\x24\xf5\x8f\xc6\xb2\xe6\x69\x34\xf3\xd3\x15\xbc\xe2\x0a\xdc\x7e\xd2\x63\xdb\xcd\xc8\x19\x4d\x9c\xf3\x76\x22\x16\xe2\xf2\x66\x3b\xf9\x90\xc2\xa8\x8e\xc6\x2d\x61\xb7\x2b\xcc\x16\x11\x9b\x42\xc0\x53\x44\x70\x07\x85\x75\xf7\xae\x4e\x21\x6c\xda\xda\x09\xd3\x64\xfe\xc7\x29\x4c\x9c\x1f\x9f\x1b\x2a\x57\x39\x02\x00\x00")
+
+func postgres13_idempotency_keysSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres13_idempotency_keysSql,
+		"postgres/13_idempotency_keys.sql",
+	)
+}
+
+func postgres13_idempotency_keysSql() (*asset, error) {
+	bytes, err := postgres13_idempotency_keysSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/13_idempotency_keys.sql", size: 569, mode: os.FileMode(420), modTime: time.Unix(1792358272, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres14_versionsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x95\x53\x5d\x6f\x9b\x30\x14\x7d\xf7\xaf\x38\x0f\x91\xba\xa9\x4b\xa5\xbd\x0e\xf5\xc1\xe0\x1b\x8a\xe4\xd9\x91\xb1\xd7\xbd\x45\x64\xb5\xb2\x68\x81\x30\xcc\x56\xf5\xdf\xcf\x34\xa4\x0a\x13\x9b\x94\x07\x10\xd8\xe7\xe3\x9e\x6b\xdf\xe5\x12\xb7\xf5\x7e\xd7\x55\xbd\x87\x6b\x19\x97\x96\x0c\x2c\x4f\x25\xe1\xdb\xa1\x0a\xc1\x07\x70\x21\x90\x69\xe9\x3e\x2b\xfc\xf6\x5d\xd8\x1f\x1b\xa4\x45\x5e\x28\x0b\xa5\xe3\xe3\xa4\x84\xa0\x15\x77\xd2\xe2\x63\x32\x51\xa8\x7d\xbd\x8d\x8c\x2b\x15\xd8\x72\x89\x2f\x27\x58\x40\xd5\x79\xfc\xf0\x6d\x8f\xed\x0b\xfa\xef\x1e\x4f\x55\x5f\x6d\xab\xe0\x3f\x20\x1c\xe3\x42\xd5\xc3\x47\xc9\x17\x3c\x77\xfb\xde\x77\xd8\xfe\xaa\xdb\x30\x00\xeb\xbb\x41\xe6\x2d\x5a\xd9\xc7\x77\xed\x9b\x3e\xf5\xbb\x7d\xc3\x32\x43\xdc\x12\x56\x4e\x65\xb6\xd0\xea\x95\xb6\x19\x4b\x7b\xf7\x1e\x86\xac\x33\xaa\x84\x35\x45\x9e\xc7\x34\xbc\xc4\x62\xc1\x52\x8a\x25\x33\xa0\x58\xc1\xe6\x1b\xbd\xc6\x3d\x6e\x0a\x55\x92\xb1\x37\xb0\x0f\x34\x6c\x01\x8a\x1e\xef\xce\x19\x3f\xdd\x0f\x71\x00\x92\x25\xcd\x6d\x6a\x29\xde\x7e\x6f\x47\xa8\x12\x51\x7f\xf8\x3a\xd5\x30\x50\x12\x16\x57\x13\xb6\x58\x40\x72\x95\x3b\x9e\x13\xda\x43\xbb\x0b\x3f\x0f\xc9\x7c\x46\x6a\x9e\xd8\x39\xe2\x39\xc2\x78\x98\x9b\xcb\xa4\xd1\x25\xa5\x95\x36\x84\x53\x0c\x68\x03\xb7\x16\x03\x2d\xf6\x64\x64\x44\x50\x84\x80\x78\xf6\x00\xa3\x1f\x41\x5f\x29\x73\x11\xb1\x36\x3a\x23\xe1\x22\x79\xda\xbc\xe4\x6f\xe7\xf1\x12\x5c\xe1\x3c\x32\xae\x77\x9e\xb4\x43\x1c\x9f\x1b\x26\x4c\x3c\xa7\xff\x55\x72\xe1\x97\x4c\xd1\x73\x1d\xbb\xe8\xcb\x88\xfe\xc7\x15\x9a\x9f\x83\x57\xc6\x74\x10\x92\xd9\x91\x9b\x05\xfe\x01\x64\xc6\x3e\xee\xab\x03\x00\x00")
+
+func postgres14_versionsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres14_versionsSql,
+		"postgres/14_versions.sql",
+	)
+}
+
+func postgres14_versionsSql() (*asset, error) {
+	bytes, err := postgres14_versionsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/14_versions.sql", size: 939, mode: os.FileMode(420), modTime: time.Unix(1792358486, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -185,6 +458,19 @@ var _bindata = map[string]func() (*asset, error){
 	"ddl.go": ddlGo,
 	"ddl_gen.go": ddl_genGo,
 	"postgres/1_init.sql": postgres1_initSql,
+	"postgres/2_external_refs.sql": postgres2_external_refsSql,
+	"postgres/3_lti.sql": postgres3_ltiSql,
+	"postgres/4_roster_members.sql": postgres4_roster_membersSql,
+	"postgres/5_capacity.sql": postgres5_capacitySql,
+	"postgres/6_enrollment_windows.sql": postgres6_enrollment_windowsSql,
+	"postgres/7_guardian_links.sql": postgres7_guardian_linksSql,
+	"postgres/8_announcements.sql": postgres8_announcementsSql,
+	"postgres/9_outbox.sql": postgres9_outboxSql,
+	"postgres/10_user_removal.sql": postgres10_user_removalSql,
+	"postgres/11_webhooks.sql": postgres11_webhooksSql,
+	"postgres/12_class_events.sql": postgres12_class_eventsSql,
+	"postgres/13_idempotency_keys.sql": postgres13_idempotency_keysSql,
+	"postgres/14_versions.sql": postgres14_versionsSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -231,6 +517,19 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"ddl_gen.go": &bintree{ddl_genGo, map[string]*bintree{}},
 	"postgres": &bintree{nil, map[string]*bintree{
 		"1_init.sql": &bintree{postgres1_initSql, map[string]*bintree{}},
+		"2_external_refs.sql": &bintree{postgres2_external_refsSql, map[string]*bintree{}},
+		"3_lti.sql": &bintree{postgres3_ltiSql, map[string]*bintree{}},
+		"4_roster_members.sql": &bintree{postgres4_roster_membersSql, map[string]*bintree{}},
+		"5_capacity.sql": &bintree{postgres5_capacitySql, map[string]*bintree{}},
+		"6_enrollment_windows.sql": &bintree{postgres6_enrollment_windowsSql, map[string]*bintree{}},
+		"7_guardian_links.sql": &bintree{postgres7_guardian_linksSql, map[string]*bintree{}},
+		"8_announcements.sql": &bintree{postgres8_announcementsSql, map[string]*bintree{}},
+		"9_outbox.sql": &bintree{postgres9_outboxSql, map[string]*bintree{}},
+		"10_user_removal.sql": &bintree{postgres10_user_removalSql, map[string]*bintree{}},
+		"11_webhooks.sql": &bintree{postgres11_webhooksSql, map[string]*bintree{}},
+		"12_class_events.sql": &bintree{postgres12_class_eventsSql, map[string]*bintree{}},
+		"13_idempotency_keys.sql": &bintree{postgres13_idempotency_keysSql, map[string]*bintree{}},
+		"14_versions.sql": &bintree{postgres14_versionsSql, map[string]*bintree{}},
 	}},
 }}
 
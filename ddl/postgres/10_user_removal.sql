@@ -0,0 +1,21 @@
+-- +migrate Up
+ALTER TABLE members ADD COLUMN joined_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE TABLE audit_log (
+  id         BIGSERIAL   NOT NULL PRIMARY KEY,
+  reason     TEXT        NOT NULL,
+  action     TEXT        NOT NULL,
+  user_id    UUID        NOT NULL,
+  class_id   UUID,
+  target_id  UUID,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_user_id_idx
+  ON audit_log USING BTREE (user_id);
+CREATE INDEX audit_log_class_id_idx
+  ON audit_log USING BTREE (class_id);
+
+-- +migrate Down
+DROP TABLE audit_log;
+ALTER TABLE members DROP COLUMN joined_at;
@@ -0,0 +1,39 @@
+-- +migrate Up
+CREATE TABLE webhooks (
+  id         UUID        NOT NULL PRIMARY KEY,
+  class_id   UUID        REFERENCES classes (id) ON DELETE CASCADE,
+  url        TEXT        NOT NULL,
+  secret     TEXT        NOT NULL,
+  events     TEXT[]      NOT NULL DEFAULT '{}',
+  created_by UUID        NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhooks_class_id_idx
+  ON webhooks USING BTREE (class_id);
+
+CREATE TABLE webhook_deliveries (
+  id              BIGSERIAL   NOT NULL PRIMARY KEY,
+  webhook_id      UUID        NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+  event_id        TEXT        NOT NULL,
+  event_type      TEXT        NOT NULL,
+  payload         BYTEA       NOT NULL,
+  status          TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+  attempts        INTEGER     NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  response_status INTEGER,
+  last_error      TEXT,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at    TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX webhook_deliveries_webhook_id_event_id_idx
+  ON webhook_deliveries USING BTREE (webhook_id, event_id);
+CREATE INDEX webhook_deliveries_webhook_id_id_idx
+  ON webhook_deliveries USING BTREE (webhook_id, id);
+CREATE INDEX webhook_deliveries_next_attempt_at_idx
+  ON webhook_deliveries USING BTREE (next_attempt_at) WHERE status = 'pending';
+
+-- +migrate Down
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE class_events (
+  id         BIGSERIAL   NOT NULL PRIMARY KEY,
+  class_id   UUID        NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+  type       TEXT        NOT NULL,
+  payload    BYTEA       NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX class_events_class_id_id_idx
+  ON class_events USING BTREE (class_id, id);
+CREATE INDEX class_events_created_at_idx
+  ON class_events USING BTREE (created_at);
+
+-- +migrate Down
+DROP TABLE class_events;
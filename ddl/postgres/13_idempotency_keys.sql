@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE idempotency_keys (
+  user_id       UUID        NOT NULL,
+  key           TEXT        NOT NULL,
+  request_hash  BYTEA       NOT NULL,
+  -- status is NULL while the first request with the key is being served.
+  status        INTEGER,
+  headers       JSONB,
+  body          BYTEA,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at    TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx
+  ON idempotency_keys USING BTREE (expires_at);
+
+-- +migrate Down
+DROP TABLE idempotency_keys;
@@ -0,0 +1,31 @@
+-- +migrate Up
+ALTER TABLE classes ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE members ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- Versions are kept by the database, so that every writer bumps them.
+-- +migrate StatementBegin
+CREATE FUNCTION bump_version() RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    NEW.version := 1;
+  ELSE
+    NEW.version := OLD.version + 1;
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER classes_bump_version
+  BEFORE INSERT OR UPDATE ON classes
+  FOR EACH ROW EXECUTE PROCEDURE bump_version();
+CREATE TRIGGER members_bump_version
+  BEFORE INSERT OR UPDATE ON members
+  FOR EACH ROW EXECUTE PROCEDURE bump_version();
+
+-- +migrate Down
+DROP TRIGGER members_bump_version ON members;
+DROP TRIGGER classes_bump_version ON classes;
+DROP FUNCTION bump_version();
+ALTER TABLE members DROP COLUMN version;
+ALTER TABLE classes DROP COLUMN version;
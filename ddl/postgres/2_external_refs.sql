@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE external_refs (
+  source      TEXT NOT NULL,
+  kind        TEXT NOT NULL,
+  external_id TEXT NOT NULL,
+  id          UUID NOT NULL,
+  PRIMARY KEY(source, kind, external_id)
+);
+
+CREATE INDEX external_refs_source_kind_id_idx
+  ON external_refs USING BTREE (source, kind, id);
+
+-- +migrate Down
+DROP TABLE external_refs;
@@ -0,0 +1,37 @@
+-- +migrate Up
+CREATE TABLE lti_platforms (
+  issuer         TEXT   NOT NULL,
+  client_id      TEXT   NOT NULL,
+  deployment_ids TEXT[] NOT NULL DEFAULT '{}',
+  auth_login_url TEXT   NOT NULL,
+  auth_token_url TEXT   NOT NULL,
+  jwks_url       TEXT   NOT NULL,
+  PRIMARY KEY(issuer, client_id)
+);
+
+CREATE TABLE lti_states (
+  state      TEXT        NOT NULL PRIMARY KEY,
+  nonce      TEXT        NOT NULL,
+  issuer     TEXT        NOT NULL,
+  client_id  TEXT        NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE lti_contexts (
+  class_id        UUID NOT NULL PRIMARY KEY,
+  issuer          TEXT NOT NULL,
+  client_id       TEXT NOT NULL,
+  deployment_id   TEXT NOT NULL,
+  context_id      TEXT NOT NULL,
+  memberships_url TEXT NOT NULL DEFAULT '',
+  FOREIGN KEY ("class_id") REFERENCES classes ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY ("issuer", "client_id") REFERENCES lti_platforms ("issuer", "client_id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX lti_contexts_issuer_deployment_id_context_id_idx
+  ON lti_contexts USING BTREE (issuer, deployment_id, context_id);
+
+-- +migrate Down
+DROP TABLE lti_contexts;
+DROP TABLE lti_states;
+DROP TABLE lti_platforms;
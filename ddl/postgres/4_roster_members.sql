@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE roster_members (
+  provider TEXT      NOT NULL,
+  class_id UUID      NOT NULL,
+  user_id  UUID      NOT NULL,
+  role     USER_ROLE NOT NULL,
+  PRIMARY KEY(provider, class_id, user_id),
+  FOREIGN KEY ("class_id") REFERENCES classes ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- +migrate Down
+DROP TABLE roster_members;
@@ -0,0 +1,29 @@
+-- +migrate Up
+ALTER TABLE classes ADD COLUMN capacity INTEGER CHECK (capacity >= 0);
+
+CREATE TABLE role_capacities (
+  class_id UUID      NOT NULL,
+  role     USER_ROLE NOT NULL,
+  capacity INTEGER   NOT NULL CHECK (capacity >= 0),
+  PRIMARY KEY(class_id, role),
+  FOREIGN KEY ("class_id") REFERENCES classes ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE waitlist_entries (
+  id         BIGSERIAL   NOT NULL PRIMARY KEY,
+  class_id   UUID        NOT NULL,
+  user_id    UUID        NOT NULL,
+  role       USER_ROLE   NOT NULL  DEFAULT 'student' :: USER_ROLE,
+  created_at TIMESTAMPTZ NOT NULL  DEFAULT now(),
+  FOREIGN KEY ("class_id") REFERENCES classes ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX waitlist_entries_class_id_user_id_idx
+  ON waitlist_entries USING BTREE (class_id, user_id);
+CREATE INDEX waitlist_entries_class_id_id_idx
+  ON waitlist_entries USING BTREE (class_id, id);
+
+-- +migrate Down
+DROP TABLE waitlist_entries;
+DROP TABLE role_capacities;
+ALTER TABLE classes DROP COLUMN capacity;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE classes ADD COLUMN enrollment_opens_at TIMESTAMPTZ;
+ALTER TABLE classes ADD COLUMN enrollment_closes_at TIMESTAMPTZ;
+ALTER TABLE classes ADD COLUMN roster_locked BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE classes DROP COLUMN roster_locked;
+ALTER TABLE classes DROP COLUMN enrollment_closes_at;
+ALTER TABLE classes DROP COLUMN enrollment_opens_at;
@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE guardian_links (
+  guardian_id UUID        NOT NULL,
+  student_id  UUID        NOT NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY(guardian_id, student_id)
+);
+
+CREATE INDEX guardian_links_student_id_idx
+  ON guardian_links USING BTREE (student_id);
+
+-- +migrate Down
+DROP TABLE guardian_links;
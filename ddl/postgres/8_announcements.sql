@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE announcements (
+  id         UUID        NOT NULL PRIMARY KEY,
+  class_id   UUID        NOT NULL,
+  author_id  UUID        NOT NULL,
+  body       TEXT        NOT NULL,
+  pinned     BOOLEAN     NOT NULL DEFAULT FALSE,
+  publish_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  published  BOOLEAN     NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  FOREIGN KEY ("class_id") REFERENCES classes ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX announcements_class_id_idx
+  ON announcements USING BTREE (class_id, publish_at);
+CREATE INDEX announcements_unpublished_idx
+  ON announcements USING BTREE (publish_at) WHERE NOT published;
+
+-- +migrate Down
+DROP TABLE announcements;
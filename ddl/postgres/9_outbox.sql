@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE outbox (
+  id              BIGSERIAL   NOT NULL PRIMARY KEY,
+  subject         TEXT        NOT NULL,
+  ordering_key    TEXT        NOT NULL,
+  payload         BYTEA       NOT NULL,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  attempts        INTEGER     NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_error      TEXT
+);
+
+CREATE INDEX outbox_ordering_key_id_idx
+  ON outbox USING BTREE (ordering_key, id);
+
+CREATE TABLE outbox_dead_letters (
+  id           BIGINT      NOT NULL PRIMARY KEY,
+  subject      TEXT        NOT NULL,
+  ordering_key TEXT        NOT NULL,
+  payload      BYTEA       NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL,
+  attempts     INTEGER     NOT NULL,
+  last_error   TEXT        NOT NULL,
+  failed_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +migrate Down
+DROP TABLE outbox_dead_letters;
+DROP TABLE outbox;
@@ -0,0 +1,116 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events are published as CloudEvents 1.0 in structured JSON mode: the envelope and the data
+// are encoded together as one JSON document.
+const (
+	CloudEventsVersion = "1.0"
+	// ContentType is the media type of a structured CloudEvent.
+	ContentType = "application/cloudevents+json"
+)
+
+// Source is the source of every event published by this service.
+const Source = "/classsvc"
+
+// SchemaBase is the base URI of the JSON schemas referenced by the dataschema of events.
+const SchemaBase = "https://schemas.studiously.io/classsvc/"
+
+// TypePrefix prefixes the names of every event type published by this service.
+const TypePrefix = "io.studiously.classsvc."
+
+// ErrNotCloudEvent is returned when decoding a message that is not a CloudEvent.
+var ErrNotCloudEvent = errors.New("not a CloudEvent")
+
+// Type is the type of an event. Names are stable; the version is incremented whenever the
+// data changes in a way that is not backwards compatible.
+type Type struct {
+	Name    string
+	Version int
+}
+
+// String returns the CloudEvents type, such as "io.studiously.classsvc.class.created.v1".
+func (t Type) String() string {
+	return fmt.Sprintf("%s%s.v%d", TypePrefix, t.Name, t.Version)
+}
+
+// Schema returns the URI of the JSON schema of the data of events of type t.
+func (t Type) Schema() string {
+	return fmt.Sprintf("%s%s/v%d.json", SchemaBase, t.Name, t.Version)
+}
+
+// CloudEvent is a CloudEvents 1.0 envelope.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	DataSchema      string          `json:"dataschema"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// NewCloudEvent returns an event of type t with a new ID. subject names the resource the event
+// is about, such as "classes/<id>", and may be empty.
+func NewCloudEvent(t Type, subject string, data interface{}) (*CloudEvent, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudEvent{
+		SpecVersion:     CloudEventsVersion,
+		ID:              uuid.New().String(),
+		Source:          Source,
+		Type:            t.String(),
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		DataSchema:      t.Schema(),
+		Data:            raw,
+	}, nil
+}
+
+// EncodeCloudEvent returns a new event of type t encoded as structured JSON.
+func EncodeCloudEvent(t Type, subject string, data interface{}) ([]byte, error) {
+	e, err := NewCloudEvent(t, subject, data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(e)
+}
+
+// PublishCloudEvent publishes a new event of type t on the bus subject busSubject.
+func PublishCloudEvent(ctx context.Context, bus EventBus, busSubject string, t Type, subject string, data interface{}) error {
+	payload, err := EncodeCloudEvent(t, subject, data)
+	if err != nil {
+		return err
+	}
+	return bus.Publish(ctx, busSubject, payload)
+}
+
+// DecodeCloudEvent decodes a structured CloudEvent and its data into v.
+func DecodeCloudEvent(payload []byte, v interface{}) (*CloudEvent, error) {
+	var e CloudEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, err
+	}
+	if e.SpecVersion == "" || e.Type == "" {
+		return nil, ErrNotCloudEvent
+	}
+	if v != nil && len(e.Data) > 0 {
+		if err := json.Unmarshal(e.Data, v); err != nil {
+			return nil, err
+		}
+	}
+	return &e, nil
+}
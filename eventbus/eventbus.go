@@ -0,0 +1,88 @@
+// Package eventbus publishes events and delivers them to subscribers, so that the rest of the
+// service does not depend on a particular broker.
+//
+// Subjects are dot-separated tokens, as in NATS. A subscription may use "*" to match any one
+// token and a final ">" to match one or more remaining tokens.
+package eventbus
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+)
+
+// Message is an event delivered to a subscriber.
+type Message struct {
+	Subject string
+	Data    []byte
+}
+
+// Handler handles a message delivered to a subscription.
+type Handler func(msg Message)
+
+// AckHandler handles a message delivered to a durable subscription. The message is delivered
+// again later if it returns an error.
+type AckHandler func(msg Message) error
+
+// Subscription is a subscription to a subject.
+type Subscription interface {
+	// Unsubscribe stops delivery to the subscription.
+	Unsubscribe() error
+}
+
+// EventBus publishes events and delivers them to subscribers.
+type EventBus interface {
+	// Publish publishes data on subject. It returns nil only once the bus has accepted the event.
+	Publish(ctx context.Context, subject string, data []byte) error
+	// Subscribe calls handler for every event published on a subject matching subject.
+	// Subscriptions sharing a non-empty queue group split events between them, so that each
+	// event is handled by one of them.
+	Subscribe(subject, queue string, handler Handler) (Subscription, error)
+	// SubscribeDurable calls handler at least once for every event published on a subject
+	// matching subject, until it succeeds. Subscriptions sharing a durable name split events
+	// between them and resume where the last of them left off.
+	SubscribeDurable(subject, durable string, handler AckHandler) (Subscription, error)
+}
+
+// Retry calls subscribe and, if it fails, keeps calling it in the background every interval
+// until it succeeds or ctx is done, logging every failure. Hosts can thus start before the bus is
+// reachable, while subscriptions that succeed at once are in place when Retry returns.
+func Retry(ctx context.Context, interval time.Duration, logger log.Logger, subscribe func() error) {
+	err := subscribe()
+	if err == nil {
+		return
+	}
+	logger.Log("msg", "could not subscribe, retrying", "error", err, "retry_in", interval)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			err := subscribe()
+			if err == nil {
+				logger.Log("msg", "subscribed")
+				return
+			}
+			logger.Log("msg", "could not subscribe, retrying", "error", err, "retry_in", interval)
+		}
+	}()
+}
+
+// Match reports whether subject matches the subscription subject pattern.
+func Match(pattern, subject string) bool {
+	p := strings.Split(pattern, ".")
+	s := strings.Split(subject, ".")
+	for i, token := range p {
+		if token == ">" && i == len(p)-1 {
+			return len(s) > i
+		}
+		if i >= len(s) || (token != "*" && token != s[i]) {
+			return false
+		}
+	}
+	return len(p) == len(s)
+}
@@ -0,0 +1,135 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is an EventBus that delivers events within the process, for tests and single-node
+// deployments. Handlers run on the publishing goroutine, so events are delivered in the order
+// they were published and have been handled by the time Publish returns.
+type Memory struct {
+	// Redelivery is how long a message whose durable handler failed waits before it is
+	// delivered again. It may be changed before the bus is used.
+	Redelivery time.Duration
+
+	mu     sync.Mutex
+	subs   []*memorySubscription
+	queues map[string]int
+}
+
+// NewMemory returns an empty in-process bus.
+func NewMemory() *Memory {
+	return &Memory{Redelivery: AckWait, queues: make(map[string]int)}
+}
+
+type memorySubscription struct {
+	bus     *Memory
+	subject string
+	queue   string
+	handler Handler
+}
+
+func (s *memorySubscription) Unsubscribe() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	for i, sub := range s.bus.subs {
+		if sub == s {
+			s.bus.subs = append(s.bus.subs[:i], s.bus.subs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Publish delivers an event to every matching subscription without a queue group, and to one
+// matching subscription of every queue group, taking turns.
+func (b *Memory) Publish(ctx context.Context, subject string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var handlers []Handler
+	b.mu.Lock()
+	groups := make(map[string][]*memorySubscription)
+	var order []string
+	for _, sub := range b.subs {
+		if !Match(sub.subject, subject) {
+			continue
+		}
+		if sub.queue == "" {
+			handlers = append(handlers, sub.handler)
+			continue
+		}
+		key := sub.queue + " " + sub.subject
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], sub)
+	}
+	for _, key := range order {
+		members := groups[key]
+		handlers = append(handlers, members[b.queues[key]%len(members)].handler)
+		b.queues[key]++
+	}
+	b.mu.Unlock()
+
+	// Handlers are called without the lock held, so they may publish and subscribe themselves.
+	msg := Message{Subject: subject, Data: data}
+	for _, handler := range handlers {
+		handler(msg)
+	}
+	return nil
+}
+
+// Subscribe subscribes to subject, in queue group queue if it is not empty.
+func (b *Memory) Subscribe(subject, queue string, handler Handler) (Subscription, error) {
+	sub := &memorySubscription{b, subject, queue, handler}
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+	return sub, nil
+}
+
+// SubscribeDurable subscribes to subject in queue group durable. Messages whose handler fails are
+// delivered again in the background, every Redelivery, until they are handled or the
+// subscription is unsubscribed. Nothing outlives the process, so events published while no
+// subscriber is subscribed are lost.
+func (b *Memory) SubscribeDurable(subject, durable string, handler AckHandler) (Subscription, error) {
+	sub := &durableSubscription{done: make(chan struct{})}
+	delay := b.Redelivery
+	memSub, err := b.Subscribe(subject, durable, func(msg Message) {
+		if handler(msg) != nil {
+			go sub.redeliver(msg, handler, delay)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sub.Subscription = memSub
+	return sub, nil
+}
+
+type durableSubscription struct {
+	Subscription
+	once sync.Once
+	done chan struct{}
+}
+
+func (s *durableSubscription) redeliver(msg Message, handler AckHandler, delay time.Duration) {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-time.After(delay):
+		}
+		if handler(msg) == nil {
+			return
+		}
+	}
+}
+
+func (s *durableSubscription) Unsubscribe() error {
+	s.once.Do(func() { close(s.done) })
+	return s.Subscription.Unsubscribe()
+}
@@ -0,0 +1,110 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// AckWait is how long a message delivered to a durable subscription may go unacknowledged
+// before it is delivered again.
+const AckWait = 30 * time.Second
+
+// NATS is an EventBus backed by a NATS cluster. It connects on first use and reconnects after
+// failures, so that publishers can keep retrying while NATS is down.
+type NATS struct {
+	url     string
+	timeout time.Duration
+
+	mu sync.Mutex
+	nc *nats.Conn
+}
+
+// NewNATS returns a bus for the NATS cluster at url that waits up to timeout for the server to
+// accept each event.
+func NewNATS(url string, timeout time.Duration) *NATS {
+	return &NATS{url: url, timeout: timeout}
+}
+
+// Publish publishes an event and waits for the server to accept it.
+func (b *NATS) Publish(ctx context.Context, subject string, data []byte) error {
+	nc, err := b.conn()
+	if err != nil {
+		return err
+	}
+	err = nc.Publish(subject, data)
+	if err != nil {
+		return err
+	}
+	timeout := b.timeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	return nc.FlushTimeout(timeout)
+}
+
+// Subscribe subscribes to subject, in queue group queue if it is not empty.
+func (b *NATS) Subscribe(subject, queue string, handler Handler) (Subscription, error) {
+	nc, err := b.conn()
+	if err != nil {
+		return nil, err
+	}
+	cb := func(m *nats.Msg) {
+		handler(Message{Subject: m.Subject, Data: m.Data})
+	}
+	if queue == "" {
+		return nc.Subscribe(subject, cb)
+	}
+	return nc.QueueSubscribe(subject, queue, cb)
+}
+
+// SubscribeDurable subscribes to subject through the JetStream durable consumer named durable,
+// which must be captured by a stream on the cluster. Messages are acknowledged once handler
+// succeeds; the others are delivered again after AckWait.
+func (b *NATS) SubscribeDurable(subject, durable string, handler AckHandler) (Subscription, error) {
+	nc, err := b.conn()
+	if err != nil {
+		return nil, err
+	}
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, err
+	}
+	return js.QueueSubscribe(subject, durable, func(m *nats.Msg) {
+		if handler(Message{Subject: m.Subject, Data: m.Data}) == nil {
+			m.Ack()
+		}
+	}, nats.Durable(durable), nats.ManualAck(), nats.AckWait(AckWait))
+}
+
+// Close closes the connection to NATS, if there is one.
+func (b *NATS) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.nc != nil {
+		b.nc.Close()
+		b.nc = nil
+	}
+}
+
+// Conn returns the connection to NATS, connecting first if needed, so that transports can share
+// it with the bus.
+func (b *NATS) Conn() (*nats.Conn, error) {
+	return b.conn()
+}
+
+func (b *NATS) conn() (*nats.Conn, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.nc != nil && !b.nc.IsClosed() {
+		return b.nc, nil
+	}
+	nc, err := nats.Connect(b.url, nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	b.nc = nc
+	return nc, nil
+}
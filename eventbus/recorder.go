@@ -0,0 +1,72 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+)
+
+// Recorder is a fake EventBus that records every event published to it, for assertions in tests.
+// Events are also delivered to subscribers, in process, like Memory.
+type Recorder struct {
+	// Err, if set, is returned by Publish instead of accepting the event, to simulate an
+	// unavailable broker.
+	Err error
+
+	mu       sync.Mutex
+	messages []Message
+	bus      *Memory
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{bus: NewMemory()}
+}
+
+// Publish records the event and delivers it to subscribers, unless Err is set.
+func (r *Recorder) Publish(ctx context.Context, subject string, data []byte) error {
+	r.mu.Lock()
+	err := r.Err
+	if err == nil {
+		r.messages = append(r.messages, Message{Subject: subject, Data: data})
+	}
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return r.bus.Publish(ctx, subject, data)
+}
+
+// Subscribe subscribes to subject, in queue group queue if it is not empty.
+func (r *Recorder) Subscribe(subject, queue string, handler Handler) (Subscription, error) {
+	return r.bus.Subscribe(subject, queue, handler)
+}
+
+// SubscribeDurable subscribes to subject like Memory does.
+func (r *Recorder) SubscribeDurable(subject, durable string, handler AckHandler) (Subscription, error) {
+	return r.bus.SubscribeDurable(subject, durable, handler)
+}
+
+// Messages returns the events published so far, in order.
+func (r *Recorder) Messages() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Message(nil), r.messages...)
+}
+
+// MessagesOn returns the events published so far on subjects matching pattern, in order.
+func (r *Recorder) MessagesOn(pattern string) []Message {
+	var matched []Message
+	for _, m := range r.Messages() {
+		if Match(pattern, m.Subject) {
+			matched = append(matched, m)
+		}
+	}
+	return matched
+}
+
+// Reset forgets the events published so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.messages = nil
+	r.mu.Unlock()
+}
@@ -0,0 +1,83 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	tests := []struct {
+		name      string
+		err       error
+		publish   []string
+		pattern   string
+		recorded  []string
+		matched   []string
+		delivered []string
+	}{
+		{
+			name:      "records in order",
+			publish:   []string{"classes.created", "members.joined", "classes.deleted"},
+			pattern:   "classes.*",
+			recorded:  []string{"classes.created", "members.joined", "classes.deleted"},
+			matched:   []string{"classes.created", "classes.deleted"},
+			delivered: []string{"classes.created", "classes.deleted"},
+		},
+		{
+			name:      "full wildcard",
+			publish:   []string{"classes.created", "classes.units.created", "classes"},
+			pattern:   "classes.>",
+			recorded:  []string{"classes.created", "classes.units.created", "classes"},
+			matched:   []string{"classes.created", "classes.units.created"},
+			delivered: []string{"classes.created", "classes.units.created"},
+		},
+		{
+			name:    "unavailable",
+			err:     errUnavailable,
+			publish: []string{"classes.created"},
+			pattern: "classes.*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRecorder()
+			r.Err = tt.err
+			var delivered []string
+			_, err := r.Subscribe(tt.pattern, "", func(msg Message) {
+				delivered = append(delivered, msg.Subject)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, subject := range tt.publish {
+				if err := r.Publish(context.Background(), subject, []byte("{}")); err != tt.err {
+					t.Fatalf("Publish(%q) = %v, want %v", subject, err, tt.err)
+				}
+			}
+			if got := subjects(r.Messages()); !reflect.DeepEqual(got, tt.recorded) {
+				t.Errorf("Messages() = %v, want %v", got, tt.recorded)
+			}
+			if got := subjects(r.MessagesOn(tt.pattern)); !reflect.DeepEqual(got, tt.matched) {
+				t.Errorf("MessagesOn(%q) = %v, want %v", tt.pattern, got, tt.matched)
+			}
+			if !reflect.DeepEqual(delivered, tt.delivered) {
+				t.Errorf("delivered %v, want %v", delivered, tt.delivered)
+			}
+			r.Reset()
+			if got := r.Messages(); len(got) != 0 {
+				t.Errorf("Messages() after Reset = %v, want none", subjects(got))
+			}
+		})
+	}
+}
+
+func subjects(messages []Message) []string {
+	var s []string
+	for _, m := range messages {
+		s = append(s, m.Subject)
+	}
+	return s
+}
@@ -0,0 +1,39 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// Endpoints collects all of the endpoints that compose the GraphQL API.
+type Endpoints struct {
+	QueryEndpoint endpoint.Endpoint
+}
+
+func MakeServerEndpoints(s Service, schema *graphqlgo.Schema) Endpoints {
+	return Endpoints{
+		QueryEndpoint: MakeQueryEndpoint(s, schema),
+	}
+}
+
+// MakeQueryEndpoint executes queries and mutations against schema. Every request gets its own
+// loaders, so lookups are batched and cached within a query but never across users.
+func MakeQueryEndpoint(s Service, schema *graphqlgo.Schema) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(queryRequest)
+		ctx = withLoaders(ctx, newLoaders(s))
+		return queryResponse{schema.Exec(ctx, req.Query, req.OperationName, req.Variables)}, nil
+	}
+}
+
+type queryRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type queryResponse struct {
+	*graphqlgo.Response
+}
@@ -0,0 +1,90 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader"
+	"github.com/studiously/classsvc/models"
+)
+
+type contextKey int
+
+const loadersContextKey contextKey = iota
+
+// loaders batch the class and member lookups of a single query. They cache what they load and
+// check visibility for the current user, so they must not outlive the request.
+type loaders struct {
+	classes *dataloader.Loader
+	members *dataloader.Loader
+}
+
+func newLoaders(s Service) *loaders {
+	return &loaders{
+		classes: dataloader.NewBatchedLoader(func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+			classes, err := s.Classes(ctx, classIDs(keys))
+			results := make([]*dataloader.Result, len(keys))
+			for i := range keys {
+				if err != nil {
+					results[i] = &dataloader.Result{Error: err}
+				} else {
+					results[i] = &dataloader.Result{Data: classes[i]}
+				}
+			}
+			return results
+		}),
+		members: dataloader.NewBatchedLoader(func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+			members, err := s.Members(ctx, classIDs(keys))
+			results := make([]*dataloader.Result, len(keys))
+			for i := range keys {
+				if err != nil {
+					results[i] = &dataloader.Result{Error: err}
+				} else {
+					results[i] = &dataloader.Result{Data: members[i]}
+				}
+			}
+			return results
+		}),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersContextKey, l)
+}
+
+// loadClass loads a class, returning nil if the current user may not see it.
+func loadClass(ctx context.Context, classID uuid.UUID) (*models.Class, error) {
+	l := ctx.Value(loadersContextKey).(*loaders)
+	v, err := l.classes.Load(ctx, dataloader.StringKey(classID.String()))()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*models.Class), nil
+}
+
+// loadMembers loads the members of a class, returning nil if the current user may not see it.
+func loadMembers(ctx context.Context, classID uuid.UUID) ([]*models.Member, error) {
+	l := ctx.Value(loadersContextKey).(*loaders)
+	v, err := l.members.Load(ctx, dataloader.StringKey(classID.String()))()
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*models.Member), nil
+}
+
+// forget drops a class from the caches after a mutation changed it.
+func forget(ctx context.Context, classID uuid.UUID) {
+	l := ctx.Value(loadersContextKey).(*loaders)
+	key := dataloader.StringKey(classID.String())
+	l.classes.Clear(ctx, key)
+	l.members.Clear(ctx, key)
+}
+
+func classIDs(keys dataloader.Keys) []uuid.UUID {
+	ids := make([]uuid.UUID, len(keys))
+	for i, key := range keys {
+		// Keys are only ever made from UUIDs.
+		ids[i], _ = uuid.Parse(key.String())
+	}
+	return ids
+}
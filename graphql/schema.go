@@ -0,0 +1,408 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+// Schema describes the graph of classes and members. Every field requires the scope of the
+// corresponding HTTP route, so a token sees the same data over either API.
+const Schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+scalar Time
+
+enum Role {
+	STUDENT
+	TEACHER
+}
+
+type Query {
+	# The class with the given ID, or null if it does not exist or the caller may not see it.
+	class(id: ID!): Class
+	# The classes the caller is enrolled in, and those of the students they are a guardian of.
+	classes: [Class!]!
+	# The caller's own memberships.
+	memberships: [Member!]!
+}
+
+type Mutation {
+	createClass(name: String!): Class!
+	updateClass(id: ID!, name: String, currentUnit: ID): Class!
+	deleteClass(id: ID!): Boolean!
+	# Joins a class. If it is full, the caller is put on its waitlist instead.
+	joinClass(classId: ID!): JoinResult!
+	# Leaves a class, or removes another member when userId is set.
+	leaveClass(classId: ID!, userId: ID): Boolean!
+	setRole(classId: ID!, userId: ID!, role: Role!): Member!
+}
+
+type Class {
+	id: ID!
+	name: String!
+	currentUnit: ID
+	# Guardians only see the teachers, the owner and their own students.
+	members: [Member!]!
+	member(userId: ID!): Member
+}
+
+type Member {
+	userId: ID!
+	role: Role!
+	owner: Boolean!
+	joinedAt: Time!
+	class: Class!
+}
+
+type JoinResult {
+	waitlisted: Boolean!
+	# Null while the caller is on the waitlist.
+	class: Class
+}
+`
+
+// MakeSchema parses Schema with resolvers that read through s, batched by the loaders of each
+// request, and change classes through classes.
+func MakeSchema(s Service, classes classsvc.Service) *graphqlgo.Schema {
+	return graphqlgo.MustParseSchema(Schema, &resolver{s, classes})
+}
+
+// authorize checks that the current token was granted scope.
+func authorize(ctx context.Context, scope string) error {
+	introspection, ok := ctx.Value(introspector.OAuth2IntrospectionContextKey).(oauth2.Introspection)
+	if !ok {
+		return ErrUnauthorized
+	}
+	for _, s := range strings.Fields(introspection.Scope) {
+		if s == scope {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+func parseID(id graphqlgo.ID) (uuid.UUID, error) {
+	u, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.UUID{}, ErrBadRequest
+	}
+	return u, nil
+}
+
+type resolver struct {
+	s       Service
+	classes classsvc.Service
+}
+
+func (r *resolver) Class(ctx context.Context, args struct{ ID graphqlgo.ID }) (*classResolver, error) {
+	if err := authorize(ctx, "classes.get"); err != nil {
+		return nil, err
+	}
+	classID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	class, err := loadClass(ctx, classID)
+	if err != nil || class == nil {
+		return nil, err
+	}
+	return &classResolver{class}, nil
+}
+
+func (r *resolver) Classes(ctx context.Context) ([]*classResolver, error) {
+	if err := authorize(ctx, "classes.list"); err != nil {
+		return nil, err
+	}
+	classIDs, err := r.classes.ListClasses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := []*classResolver{}
+	for _, id := range classIDs {
+		class, err := loadClass(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if class != nil {
+			results = append(results, &classResolver{class})
+		}
+	}
+	return results, nil
+}
+
+func (r *resolver) Memberships(ctx context.Context) ([]*memberResolver, error) {
+	if err := authorize(ctx, "classes.list"); err != nil {
+		return nil, err
+	}
+	members, err := r.s.Memberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*memberResolver, len(members))
+	for i, m := range members {
+		results[i] = &memberResolver{m}
+	}
+	return results, nil
+}
+
+func (r *resolver) CreateClass(ctx context.Context, args struct{ Name string }) (*classResolver, error) {
+	if err := authorize(ctx, "classes.create"); err != nil {
+		return nil, err
+	}
+	name, err := classsvc.ValidateName("/name", args.Name)
+	if err != nil {
+		return nil, err
+	}
+	classID, err := r.classes.CreateClass(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return reload(ctx, *classID)
+}
+
+func (r *resolver) UpdateClass(ctx context.Context, args struct {
+	ID          graphqlgo.ID
+	Name        *string
+	CurrentUnit *graphqlgo.ID
+}) (*classResolver, error) {
+	if err := authorize(ctx, "classes.update"); err != nil {
+		return nil, err
+	}
+	classID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if args.Name != nil {
+		name, err := classsvc.ValidateName("/name", *args.Name)
+		if err != nil {
+			return nil, err
+		}
+		args.Name = &name
+	}
+	var currentUnit *uuid.UUID
+	if args.CurrentUnit != nil {
+		unit, err := parseID(*args.CurrentUnit)
+		if err != nil {
+			return nil, err
+		}
+		currentUnit = &unit
+	}
+	if err := r.classes.UpdateClass(ctx, classID, args.Name, currentUnit); err != nil {
+		return nil, err
+	}
+	return reload(ctx, classID)
+}
+
+func (r *resolver) DeleteClass(ctx context.Context, args struct{ ID graphqlgo.ID }) (bool, error) {
+	if err := authorize(ctx, "classes.delete"); err != nil {
+		return false, err
+	}
+	classID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.classes.DeleteClass(ctx, classID); err != nil {
+		return false, err
+	}
+	forget(ctx, classID)
+	return true, nil
+}
+
+func (r *resolver) JoinClass(ctx context.Context, args struct{ ClassID graphqlgo.ID }) (*joinResultResolver, error) {
+	if err := authorize(ctx, "classes.join"); err != nil {
+		return nil, err
+	}
+	classID, err := parseID(args.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	switch err := r.classes.JoinClass(ctx, classID); err {
+	case nil:
+		class, err := reload(ctx, classID)
+		return &joinResultResolver{class: class}, err
+	case classsvc.ErrWaitlisted:
+		forget(ctx, classID)
+		return &joinResultResolver{waitlisted: true}, nil
+	default:
+		return nil, err
+	}
+}
+
+func (r *resolver) LeaveClass(ctx context.Context, args struct {
+	ClassID graphqlgo.ID
+	UserID  *graphqlgo.ID
+}) (bool, error) {
+	if err := authorize(ctx, "classes.leave"); err != nil {
+		return false, err
+	}
+	classID, err := parseID(args.ClassID)
+	if err != nil {
+		return false, err
+	}
+	var userID *uuid.UUID
+	if args.UserID != nil {
+		id, err := parseID(*args.UserID)
+		if err != nil {
+			return false, err
+		}
+		userID = &id
+	}
+	if err := r.classes.LeaveClass(ctx, userID, classID); err != nil {
+		return false, err
+	}
+	forget(ctx, classID)
+	return true, nil
+}
+
+func (r *resolver) SetRole(ctx context.Context, args struct {
+	ClassID graphqlgo.ID
+	UserID  graphqlgo.ID
+	Role    string
+}) (*memberResolver, error) {
+	if err := authorize(ctx, "classes.members.update"); err != nil {
+		return nil, err
+	}
+	classID, err := parseID(args.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := parseID(args.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var role models.UserRole
+	if err := role.UnmarshalText([]byte(strings.ToLower(args.Role))); err != nil {
+		return nil, ErrBadRequest
+	}
+	if err := r.classes.SetRole(ctx, classID, userID, role); err != nil {
+		return nil, err
+	}
+	forget(ctx, classID)
+	member, err := r.classes.GetMember(ctx, classID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &memberResolver{member}, nil
+}
+
+// reload loads a class again after a mutation changed it.
+func reload(ctx context.Context, classID uuid.UUID) (*classResolver, error) {
+	forget(ctx, classID)
+	class, err := loadClass(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	if class == nil {
+		return nil, classsvc.ErrNotFound
+	}
+	return &classResolver{class}, nil
+}
+
+type classResolver struct {
+	c *models.Class
+}
+
+func (r *classResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(r.c.ID.String())
+}
+
+func (r *classResolver) Name() string {
+	return r.c.Name
+}
+
+func (r *classResolver) CurrentUnit() *graphqlgo.ID {
+	if r.c.CurrentUnit == uuid.Nil {
+		return nil
+	}
+	id := graphqlgo.ID(r.c.CurrentUnit.String())
+	return &id
+}
+
+func (r *classResolver) Members(ctx context.Context) ([]*memberResolver, error) {
+	if err := authorize(ctx, "classes.members.list"); err != nil {
+		return nil, err
+	}
+	members, err := loadMembers(ctx, r.c.ID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*memberResolver, len(members))
+	for i, m := range members {
+		results[i] = &memberResolver{m}
+	}
+	return results, nil
+}
+
+func (r *classResolver) Member(ctx context.Context, args struct{ UserID graphqlgo.ID }) (*memberResolver, error) {
+	if err := authorize(ctx, "classes.members.get"); err != nil {
+		return nil, err
+	}
+	userID, err := parseID(args.UserID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := loadMembers(ctx, r.c.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if m.UserID == userID {
+			return &memberResolver{m}, nil
+		}
+	}
+	return nil, nil
+}
+
+type memberResolver struct {
+	m *models.Member
+}
+
+func (r *memberResolver) UserID() graphqlgo.ID {
+	return graphqlgo.ID(r.m.UserID.String())
+}
+
+func (r *memberResolver) Role() string {
+	return strings.ToUpper(r.m.Role.String())
+}
+
+func (r *memberResolver) Owner() bool {
+	return r.m.Owner
+}
+
+func (r *memberResolver) JoinedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.m.JoinedAt}
+}
+
+func (r *memberResolver) Class(ctx context.Context) (*classResolver, error) {
+	class, err := loadClass(ctx, r.m.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	if class == nil {
+		return nil, classsvc.ErrNotFound
+	}
+	return &classResolver{class}, nil
+}
+
+type joinResultResolver struct {
+	waitlisted bool
+	class      *classResolver
+}
+
+func (r *joinResultResolver) Waitlisted() bool {
+	return r.waitlisted
+}
+
+func (r *joinResultResolver) Class() *classResolver {
+	return r.class
+}
@@ -0,0 +1,30 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+var (
+	ErrUnauthorized = errors.New("token invalid or not found")
+	ErrForbidden    = errors.New("token is missing a scope required by the query")
+	ErrBadRequest   = errors.New("the request is malformed or invalid")
+)
+
+// Service reads classes and members in batches, so that a query touching many classes costs a
+// few queries rather than one per class. It applies the same visibility as classsvc.Service:
+// members see their classes and everyone in them; guardians see the classes of their students,
+// and only the teachers and their own students in them.
+type Service interface {
+	// Memberships gets the memberships of the current user.
+	Memberships(ctx context.Context) ([]*models.Member, error)
+	// Classes gets classes by ID. The result has an entry for every ID, which is nil if the class
+	// does not exist or the current user may not see it.
+	Classes(ctx context.Context, classIDs []uuid.UUID) ([]*models.Class, error)
+	// Members gets the members of classes the current user may see. The result has an entry for
+	// every ID, which is nil if the class does not exist or the current user may not see it.
+	Members(ctx context.Context, classIDs []uuid.UUID) ([][]*models.Member, error)
+}
@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+type postgresService struct {
+	*sql.DB
+}
+
+func New(db *sql.DB) Service {
+	return &postgresService{db}
+}
+
+func (s *postgresService) Memberships(ctx context.Context) ([]*models.Member, error) {
+	subj, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	return models.MembersByUserID(s, subj)
+}
+
+func (s *postgresService) Classes(ctx context.Context, classIDs []uuid.UUID) ([]*models.Class, error) {
+	subj, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	rows, err := s.QueryContext(ctx, `SELECT id, name, current_unit, active, capacity, enrollment_opens_at, enrollment_closes_at, roster_locked, version `+
+		`FROM classes c WHERE id = ANY($1::uuid[]) `+
+		`AND (EXISTS (SELECT 1 FROM members m WHERE m.class_id = c.id AND m.user_id = $2) `+
+		`OR EXISTS (SELECT 1 FROM guardian_links g JOIN members m ON m.user_id = g.student_id WHERE g.guardian_id = $2 AND m.class_id = c.id))`,
+		pq.StringArray(ids(classIDs)), subj)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	classes := make(map[uuid.UUID]*models.Class, len(classIDs))
+	for rows.Next() {
+		var c models.Class
+		if err := rows.Scan(&c.ID, &c.Name, &c.CurrentUnit, &c.Active, &c.Capacity, &c.EnrollmentOpensAt, &c.EnrollmentClosesAt, &c.RosterLocked, &c.Version); err != nil {
+			return nil, err
+		}
+		classes[c.ID] = &c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	results := make([]*models.Class, len(classIDs))
+	for i, id := range classIDs {
+		results[i] = classes[id]
+	}
+	return results, nil
+}
+
+func (s *postgresService) Members(ctx context.Context, classIDs []uuid.UUID) ([][]*models.Member, error) {
+	subj, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	links, err := models.GuardianLinksByGuardianID(s, subj)
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[uuid.UUID]bool, len(links))
+	for _, link := range links {
+		linked[link.StudentID] = true
+	}
+
+	rows, err := s.QueryContext(ctx, `SELECT user_id, class_id, role, owner, joined_at, version FROM members `+
+		`WHERE class_id = ANY($1::uuid[]) ORDER BY joined_at, user_id`, pq.StringArray(ids(classIDs)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	members := make(map[uuid.UUID][]*models.Member, len(classIDs))
+	// A class is visible to its members, and partially to the guardians of its students.
+	member := make(map[uuid.UUID]bool, len(classIDs))
+	guardian := make(map[uuid.UUID]bool, len(classIDs))
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.UserID, &m.ClassID, &m.Role, &m.Owner, &m.JoinedAt, &m.Version); err != nil {
+			return nil, err
+		}
+		members[m.ClassID] = append(members[m.ClassID], &m)
+		if m.UserID == subj {
+			member[m.ClassID] = true
+		}
+		if linked[m.UserID] {
+			guardian[m.ClassID] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([][]*models.Member, len(classIDs))
+	for i, id := range classIDs {
+		switch {
+		case member[id]:
+			results[i] = members[id]
+		case guardian[id]:
+			// Guardians see who teaches and owns the class of their students, but not the other students.
+			visible := []*models.Member{}
+			for _, m := range members[id] {
+				if m.Role == models.UserRoleTeacher || m.Owner || linked[m.UserID] {
+					visible = append(visible, m)
+				}
+			}
+			results[i] = visible
+		}
+	}
+	return results, nil
+}
+
+func ids(uuids []uuid.UUID) []string {
+	s := make([]string, len(uuids))
+	for i, id := range uuids {
+		s[i] = id.String()
+	}
+	return s
+}
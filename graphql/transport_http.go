@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/introspector"
+)
+
+// MakeHTTPHandler serves the GraphQL API at /graphql.
+func MakeHTTPHandler(s Service, schema *graphqlgo.Schema, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s, schema)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorLogger(logger),
+		httptransport.ServerErrorEncoder(encodeError),
+		httptransport.ServerBefore(introspector.ToHTTPContext()),
+	}
+
+	// POST /graphql
+	// Run a query or mutation. Any valid token may query; each field checks the scope of the
+	// matching HTTP route.
+	r.Methods("POST").Path("/graphql").Handler(httptransport.NewServer(
+		introspector.New(introspection)(e.QueryEndpoint),
+		DecodeQueryRequest,
+		encodeResponse,
+		options...,
+	))
+
+	return r
+}
+
+func DecodeQueryRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req queryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Query == "" {
+		return nil, ErrBadRequest
+	}
+	return req, nil
+}
+
+// encodeResponse writes the result of a query. Errors raised by resolvers are part of the
+// result, so the status is 200 whenever the query ran.
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if resp, ok := response.(queryResponse); ok {
+		describeErrors(resp.Errors)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+// errorCodes are the stable codes of the errors of this package.
+var errorCodes = map[error]string{
+	ErrUnauthorized: "unauthorized",
+	ErrForbidden:    "forbidden",
+	ErrBadRequest:   "bad_request",
+}
+
+// describeErrors gives every error raised by a resolver a stable code in its extensions. Errors
+// of this package and of classsvc keep their message; any other error, such as one from the
+// database, is replaced by classsvc.ErrInternal so that nothing about it leaks.
+func describeErrors(errs []*gqlerrors.QueryError) {
+	for _, e := range errs {
+		if e.ResolverError == nil {
+			continue
+		}
+		err, code := e.ResolverError, errorCodes[e.ResolverError]
+		if code == "" {
+			err, code = classsvc.PublicError(err), classsvc.ErrorCode(err)
+		}
+		e.Message = err.Error()
+		e.Extensions = map[string]interface{}{"code": code}
+		if verr, ok := err.(*classsvc.ValidationError); ok {
+			e.Extensions["errors"] = verr.Fields
+		}
+	}
+}
+
+// encodeError writes errors that kept the query from running, in the shape of a GraphQL
+// result so that clients handle them like any other.
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]interface{}{{"message": err.Error()}},
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	case ErrForbidden:
+		return http.StatusForbidden
+	case ErrBadRequest:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
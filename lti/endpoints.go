@@ -0,0 +1,99 @@
+package lti
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+// Endpoints collects all of the endpoints that compose the LTI tool.
+type Endpoints struct {
+	LoginEndpoint       endpoint.Endpoint
+	LaunchEndpoint      endpoint.Endpoint
+	SyncMembersEndpoint endpoint.Endpoint
+	KeySetEndpoint      endpoint.Endpoint
+}
+
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		LoginEndpoint:       MakeLoginEndpoint(s),
+		LaunchEndpoint:      MakeLaunchEndpoint(s),
+		SyncMembersEndpoint: MakeSyncMembersEndpoint(s),
+		KeySetEndpoint:      MakeKeySetEndpoint(s),
+	}
+}
+
+func MakeLoginEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(loginRequest)
+		redirect, e := s.Login(ctx, req.LoginRequest)
+		return loginResponse{redirect, e}, nil
+	}
+}
+
+type loginRequest struct {
+	LoginRequest
+}
+
+type loginResponse struct {
+	RedirectURL string `json:"redirect_url,omitempty"`
+	Error       error  `json:"error,omitempty"`
+}
+
+func (r loginResponse) error() error {
+	return r.Error
+}
+
+func MakeLaunchEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(launchRequest)
+		launch, e := s.Launch(ctx, req.IDToken, req.State)
+		return launchResponse{launch, e}, nil
+	}
+}
+
+type launchRequest struct {
+	IDToken string
+	State   string
+}
+
+type launchResponse struct {
+	Launch *Launch `json:"launch,omitempty"`
+	Error  error   `json:"error,omitempty"`
+}
+
+func (r launchResponse) error() error {
+	return r.Error
+}
+
+func MakeSyncMembersEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(syncMembersRequest)
+		summary, e := s.SyncMembers(ctx, req.ClassID)
+		return syncMembersResponse{summary, e}, nil
+	}
+}
+
+type syncMembersRequest struct {
+	ClassID uuid.UUID
+}
+
+type syncMembersResponse struct {
+	Summary *SyncSummary `json:"summary,omitempty"`
+	Error   error        `json:"error,omitempty"`
+}
+
+func (r syncMembersResponse) error() error {
+	return r.Error
+}
+
+func MakeKeySetEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		ks, e := s.KeySet(ctx)
+		if e != nil {
+			return nil, e
+		}
+		return ks, nil
+	}
+}
@@ -0,0 +1,138 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var ErrUnknownKey = errors.New("signing key not found in key set")
+
+// JSONWebKey is an RSA public key in JWK form.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// KeySet is a JSON Web Key Set.
+type KeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// NewKeySet returns a key set publishing key under kid.
+func NewKeySet(kid string, key *rsa.PublicKey) *KeySet {
+	return &KeySet{Keys: []JSONWebKey{{
+		Kty: "RSA",
+		Alg: "RS256",
+		Use: "sig",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+}
+
+// Key returns the RSA key identified by kid. An empty kid matches a set with a single key.
+func (ks *KeySet) Key(kid string) (*rsa.PublicKey, error) {
+	for _, k := range ks.Keys {
+		if k.Kty != "RSA" || (kid != "" && k.Kid != kid) || (kid == "" && len(ks.Keys) != 1) {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// FetchKeySet downloads the key set published at url.
+func FetchKeySet(client *http.Client, url string) (*KeySet, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", url, resp.Status)
+	}
+	var ks KeySet
+	if err := json.NewDecoder(resp.Body).Decode(&ks); err != nil {
+		return nil, err
+	}
+	return &ks, nil
+}
+
+// KeyRefetchInterval is the least time between two fetches of a platform key set. Tokens naming
+// an unknown key are refused in between, so they cannot be used to make the tool hammer a platform.
+var KeyRefetchInterval = time.Minute
+
+// keyCache caches platform key sets, refetching when a token names an unknown key.
+type keyCache struct {
+	client  *http.Client
+	mtx     sync.Mutex
+	sets    map[string]*KeySet
+	fetched map[string]time.Time
+}
+
+func newKeyCache(client *http.Client) *keyCache {
+	return &keyCache{client: client, sets: make(map[string]*KeySet), fetched: make(map[string]time.Time)}
+}
+
+func (c *keyCache) key(url, kid string) (*rsa.PublicKey, error) {
+	c.mtx.Lock()
+	ks, ok := c.sets[url]
+	if ok {
+		if key, err := ks.Key(kid); err == nil {
+			c.mtx.Unlock()
+			return key, nil
+		}
+	}
+	// Platforms rotate keys, so an unknown kid means the cached set is stale. Failed fetches
+	// count too, so an unreachable platform is not retried on every launch either.
+	if last, ok := c.fetched[url]; ok && time.Since(last) < KeyRefetchInterval {
+		c.mtx.Unlock()
+		return nil, ErrUnknownKey
+	}
+	c.fetched[url] = time.Now()
+	c.mtx.Unlock()
+
+	ks, err := FetchKeySet(c.client, url)
+	if err != nil {
+		return nil, err
+	}
+	c.mtx.Lock()
+	c.sets[url] = ks
+	c.mtx.Unlock()
+	return ks.Key(kid)
+}
+
+// keyfunc returns a jwt.Keyfunc that only accepts RS256 tokens signed by a key from url.
+func (c *keyCache) keyfunc(url string) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodRS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return c.key(url, kid)
+	}
+}
@@ -0,0 +1,105 @@
+// Package lti implements an IMS LTI 1.3 (Advantage) tool that provisions
+// classes and members from LMS launches and the Names and Role Provisioning
+// Service.
+package lti
+
+import (
+	"strings"
+
+	"github.com/studiously/classsvc/models"
+)
+
+// Claims used by LTI 1.3 launches.
+const (
+	ClaimMessageType   = "https://purl.imsglobal.org/spec/lti/claim/message_type"
+	ClaimVersion       = "https://purl.imsglobal.org/spec/lti/claim/version"
+	ClaimDeploymentID  = "https://purl.imsglobal.org/spec/lti/claim/deployment_id"
+	ClaimTargetLinkURI = "https://purl.imsglobal.org/spec/lti/claim/target_link_uri"
+	ClaimResourceLink  = "https://purl.imsglobal.org/spec/lti/claim/resource_link"
+	ClaimRoles         = "https://purl.imsglobal.org/spec/lti/claim/roles"
+	ClaimContext       = "https://purl.imsglobal.org/spec/lti/claim/context"
+	ClaimNamesRoles    = "https://purl.imsglobal.org/spec/lti-nrps/claim/namesroleservice"
+)
+
+const (
+	MessageTypeResourceLink = "LtiResourceLinkRequest"
+	Version                 = "1.3.0"
+
+	// ScopeMembershipReadonly is the OAuth 2 scope required to read NRPS memberships.
+	ScopeMembershipReadonly = "https://purl.imsglobal.org/spec/lti-nrps/scope/contextmembership.readonly"
+	// MembershipMediaType is the media type of NRPS membership containers.
+	MembershipMediaType = "application/vnd.ims.lti-nrps.v2.membershipcontainer+json"
+)
+
+// Context roles from the LIS vocabulary.
+const (
+	RoleAdministrator     = "http://purl.imsglobal.org/vocab/lis/v2/membership#Administrator"
+	RoleContentDeveloper  = "http://purl.imsglobal.org/vocab/lis/v2/membership#ContentDeveloper"
+	RoleInstructor        = "http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"
+	RoleLearner           = "http://purl.imsglobal.org/vocab/lis/v2/membership#Learner"
+	RoleMentor            = "http://purl.imsglobal.org/vocab/lis/v2/membership#Mentor"
+	RoleTeachingAssistant = "http://purl.imsglobal.org/vocab/lis/v2/membership/Instructor#TeachingAssistant"
+)
+
+// Member statuses reported by NRPS.
+const (
+	StatusActive   = "Active"
+	StatusInactive = "Inactive"
+)
+
+// Context is the LMS course or section a launch came from.
+type Context struct {
+	ID    string   `json:"id"`
+	Label string   `json:"label,omitempty"`
+	Title string   `json:"title,omitempty"`
+	Type  []string `json:"type,omitempty"`
+}
+
+// Member is an entry of an NRPS membership container.
+type Member struct {
+	Status     string   `json:"status,omitempty"`
+	UserID     string   `json:"user_id"`
+	Roles      []string `json:"roles"`
+	Name       string   `json:"name,omitempty"`
+	GivenName  string   `json:"given_name,omitempty"`
+	FamilyName string   `json:"family_name,omitempty"`
+	Email      string   `json:"email,omitempty"`
+}
+
+// MembershipContainer is the body returned by an NRPS context membership request.
+type MembershipContainer struct {
+	ID      string   `json:"id"`
+	Context Context  `json:"context"`
+	Members []Member `json:"members"`
+}
+
+// TranslateRoles derives a class role from a set of LTI roles. Instructors,
+// teaching assistants, content developers and administrators become teachers;
+// learners become students. Mentors and other roles do not translate.
+func TranslateRoles(roles []string) (models.UserRole, bool) {
+	var (
+		role models.UserRole
+		ok   bool
+	)
+	for _, r := range roles {
+		// Simple names are allowed for context roles, so compare the final segment only.
+		name := r
+		if i := strings.LastIndexAny(name, "#/"); i >= 0 {
+			name = name[i+1:]
+		}
+		switch name {
+		case "Instructor", "TeachingAssistant", "ContentDeveloper", "Administrator":
+			return models.UserRoleTeacher, true
+		case "Learner":
+			role, ok = models.UserRoleStudent, true
+		}
+	}
+	return role, ok
+}
+
+// userSource is the external_refs source for users of a platform; LTI subjects are only unique per issuer.
+func userSource(issuer string) string {
+	return "lti:" + issuer
+}
+
+const kindUser = "user"
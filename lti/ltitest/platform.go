@@ -0,0 +1,224 @@
+// Package ltitest provides a fake LTI 1.3 platform for exercising the tool
+// end to end without an LMS.
+package ltitest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/studiously/classsvc/lti"
+	"github.com/studiously/classsvc/models"
+)
+
+const keyID = "ltitest"
+
+// User is a platform user taking part in a launch.
+type User struct {
+	Sub   string
+	Roles []string
+}
+
+// Platform is an in-process LTI platform. It signs launch tokens, publishes its keys,
+// issues service access tokens and serves NRPS memberships for every context.
+// Authorization requests are answered by Launch rather than served over HTTP.
+type Platform struct {
+	Issuer       string
+	ClientID     string
+	DeploymentID string
+	// ToolKeySetURL, when set, is used to verify client assertions sent to the token endpoint.
+	ToolKeySetURL string
+
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mtx     sync.Mutex
+	members map[string][]lti.Member
+	tokens  map[string]bool
+}
+
+// NewPlatform starts a platform that has registered the tool as clientID.
+func NewPlatform(clientID string) (*Platform, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	p := &Platform{
+		ClientID:     clientID,
+		DeploymentID: "deployment-1",
+		key:          key,
+		members:      make(map[string][]lti.Member),
+		tokens:       make(map[string]bool),
+	}
+	m := http.NewServeMux()
+	m.HandleFunc("/jwks", p.serveKeySet)
+	m.HandleFunc("/token", p.serveToken)
+	m.HandleFunc("/memberships/", p.serveMemberships)
+	p.server = httptest.NewServer(m)
+	p.Issuer = p.server.URL
+	return p, nil
+}
+
+// Close shuts down the platform.
+func (p *Platform) Close() {
+	p.server.Close()
+}
+
+// Registration returns the platform as it should be registered with the tool.
+func (p *Platform) Registration() models.LtiPlatform {
+	return models.LtiPlatform{
+		Issuer:        p.Issuer,
+		ClientID:      p.ClientID,
+		DeploymentIDs: models.StringSlice{p.DeploymentID},
+		AuthLoginURL:  p.server.URL + "/auth",
+		AuthTokenURL:  p.server.URL + "/token",
+		JwksURL:       p.server.URL + "/jwks",
+	}
+}
+
+// MembershipsURL is the NRPS endpoint for a context.
+func (p *Platform) MembershipsURL(contextID string) string {
+	return p.server.URL + "/memberships/" + url.PathEscape(contextID)
+}
+
+// SetMembers replaces the roster served for a context.
+func (p *Platform) SetMembers(contextID string, members []lti.Member) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.members[contextID] = members
+}
+
+// IDToken signs a resource link launch for user in context.
+func (p *Platform) IDToken(nonce string, user User, context lti.Context) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                 p.Issuer,
+		"aud":                 p.ClientID,
+		"sub":                 user.Sub,
+		"iat":                 now.Unix(),
+		"exp":                 now.Add(5 * time.Minute).Unix(),
+		"nonce":               nonce,
+		lti.ClaimMessageType:  lti.MessageTypeResourceLink,
+		lti.ClaimVersion:      lti.Version,
+		lti.ClaimDeploymentID: p.DeploymentID,
+		lti.ClaimResourceLink: map[string]interface{}{"id": "resource-" + context.ID},
+		lti.ClaimRoles:        user.Roles,
+		lti.ClaimContext:      context,
+		lti.ClaimNamesRoles: map[string]interface{}{
+			"context_memberships_url": p.MembershipsURL(context.ID),
+			"service_versions":        []string{"2.0"},
+		},
+	})
+	token.Header["kid"] = keyID
+	return token.SignedString(p.key)
+}
+
+// Launch performs a complete launch against the tool: it initiates login at loginURL,
+// answers the resulting authorization request and posts the id_token to the tool's
+// redirect_uri. The tool's response to the launch is returned unread.
+func (p *Platform) Launch(loginURL string, user User, context lti.Context) (*http.Response, error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.PostForm(loginURL, url.Values{
+		"iss":               {p.Issuer},
+		"login_hint":        {user.Sub},
+		"target_link_uri":   {loginURL},
+		"client_id":         {p.ClientID},
+		"lti_deployment_id": {p.DeploymentID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		return nil, fmt.Errorf("login: %s", resp.Status)
+	}
+	auth, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(auth.String(), p.server.URL+"/auth") {
+		return nil, fmt.Errorf("login redirected to %s", auth)
+	}
+	q := auth.Query()
+	if q.Get("client_id") != p.ClientID || q.Get("response_mode") != "form_post" {
+		return nil, errors.New("malformed authorization request")
+	}
+	idToken, err := p.IDToken(q.Get("nonce"), user, context)
+	if err != nil {
+		return nil, err
+	}
+	return client.PostForm(q.Get("redirect_uri"), url.Values{
+		"id_token": {idToken},
+		"state":    {q.Get("state")},
+	})
+}
+
+func (p *Platform) serveKeySet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lti.NewKeySet(keyID, &p.key.PublicKey))
+}
+
+func (p *Platform) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || r.FormValue("grant_type") != "client_credentials" {
+		http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+		return
+	}
+	if p.ToolKeySetURL != "" {
+		_, err := jwt.Parse(r.FormValue("client_assertion"), func(t *jwt.Token) (interface{}, error) {
+			ks, err := lti.FetchKeySet(http.DefaultClient, p.ToolKeySetURL)
+			if err != nil {
+				return nil, err
+			}
+			kid, _ := t.Header["kid"].(string)
+			return ks.Key(kid)
+		})
+		if err != nil {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	token := fmt.Sprintf("%x", b)
+	p.mtx.Lock()
+	p.tokens[token] = true
+	p.mtx.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"scope":        r.FormValue("scope"),
+	})
+}
+
+func (p *Platform) serveMemberships(w http.ResponseWriter, r *http.Request) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if !p.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	contextID, err := url.PathUnescape(strings.TrimPrefix(r.URL.Path, "/memberships/"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", lti.MembershipMediaType)
+	json.NewEncoder(w).Encode(lti.MembershipContainer{
+		ID:      p.MembershipsURL(contextID),
+		Context: lti.Context{ID: contextID},
+		Members: p.members[contextID],
+	})
+}
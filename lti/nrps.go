@@ -0,0 +1,118 @@
+package lti
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/studiously/classsvc/models"
+)
+
+var linkNext = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// memberships reads every page of a context membership container.
+func (s *postgresService) memberships(ctx context.Context, platform *models.LtiPlatform, membershipsURL string) ([]Member, error) {
+	token, err := s.accessToken(ctx, platform, ScopeMembershipReadonly)
+	if err != nil {
+		return nil, err
+	}
+	var members []Member
+	for next := membershipsURL; next != ""; {
+		req, err := http.NewRequest("GET", next, nil)
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", MembershipMediaType)
+		resp, err := s.config.Client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var container MembershipContainer
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("memberships %s: %s", next, resp.Status)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&container)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, container.Members...)
+
+		next = ""
+		if m := linkNext.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			next = m[1]
+		}
+	}
+	return members, nil
+}
+
+// accessToken requests a platform access token using the client credentials grant with a
+// signed JWT client assertion, as required by LTI Advantage services.
+func (s *postgresService) accessToken(ctx context.Context, platform *models.LtiPlatform, scopes ...string) (string, error) {
+	if s.config.Key == nil {
+		return "", ErrNoToolKey
+	}
+	now := time.Now()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": platform.ClientID,
+		"sub": platform.ClientID,
+		"aud": platform.AuthTokenURL,
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+		"jti": random(),
+	})
+	assertion.Header["kid"] = s.config.KeyID
+	signed, err := assertion.SignedString(s.config.Key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {signed},
+		"scope":                 {strings.Join(scopes, " ")},
+	}
+	req, err := http.NewRequest("POST", platform.AuthTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token %s: %s", platform.AuthTokenURL, resp.Status)
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	return body.AccessToken, nil
+}
+
+// remarshal converts a decoded claim into a typed value.
+func remarshal(claim interface{}, v interface{}) error {
+	if claim == nil {
+		return ErrBadRequest
+	}
+	b, err := json.Marshal(claim)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,90 @@
+package lti
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+var (
+	ErrBadRequest             = errors.New("the request is malformed or invalid")
+	ErrUnknownPlatform        = errors.New("platform is not registered")
+	ErrInvalidState           = errors.New("login state is invalid or expired")
+	ErrInvalidToken           = errors.New("launch token is invalid")
+	ErrUnknownDeployment      = errors.New("deployment is not registered for platform")
+	ErrNoRole                 = errors.New("launch roles do not map onto a class role")
+	ErrContextNotProvisioned  = errors.New("class must be created by an instructor launch")
+	ErrClassInactive          = errors.New("class linked to context has been deleted")
+	ErrNotFound               = errors.New("class is not linked to an LTI context")
+	ErrForbidden              = errors.New("user is not allowed to perform action")
+	ErrMembershipsUnsupported = errors.New("platform did not offer the names and role provisioning service")
+	ErrNoToolKey              = errors.New("tool private key is not configured")
+)
+
+// LoginRequest is an OIDC third-party initiated login sent by a platform.
+type LoginRequest struct {
+	Issuer         string
+	LoginHint      string
+	TargetLinkURI  string
+	LtiMessageHint string
+	ClientID       string
+	DeploymentID   string
+}
+
+// Launch describes the outcome of a successful resource link launch.
+type Launch struct {
+	ClassID uuid.UUID       `json:"class_id"`
+	UserID  uuid.UUID       `json:"user_id"`
+	Role    models.UserRole `json:"role"`
+	// Created is set when the launch created the class.
+	Created bool `json:"created"`
+	// Waitlisted is set when the class had no seat left and the user was put on its waitlist.
+	Waitlisted bool `json:"waitlisted,omitempty"`
+	// Token is a short-lived JWT asserting the launch, signed with the tool key. Applications
+	// must verify it against the tool key set rather than trust the other fields.
+	Token string `json:"token,omitempty"`
+}
+
+// LaunchTokenTTL is how long a launch token is valid for.
+var LaunchTokenTTL = time.Minute
+
+// SyncSummary describes the changes made by a roster sync.
+type SyncSummary struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+	// Waitlisted counts users put on the class waitlist because it had no seat left for them.
+	Waitlisted int `json:"waitlisted"`
+}
+
+// Config configures the tool side of the LTI integration.
+type Config struct {
+	// LaunchURL is the redirect_uri registered with platforms, i.e. the tool's launch endpoint.
+	LaunchURL string
+	// Key signs client assertions for platform token requests and is published in the tool's key set.
+	Key *rsa.PrivateKey
+	// KeyID identifies Key in the published key set.
+	KeyID string
+	// Client is used for requests to platforms. It defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Service implements the tool side of LTI 1.3 launches and roster provisioning.
+type Service interface {
+	// Login validates a third-party initiated login and returns the platform
+	// authorization URL the user agent must be redirected to.
+	Login(ctx context.Context, req LoginRequest) (string, error)
+	// Launch validates a launch id_token, creating the class linked to the LMS context
+	// if needed and enrolling the launching user.
+	Launch(ctx context.Context, idToken, state string) (*Launch, error)
+	// SyncMembers reconciles a linked class with the platform's Names and Role Provisioning Service.
+	// When the context carries a subject, the current user must own the class.
+	SyncMembers(ctx context.Context, classID uuid.UUID) (*SyncSummary, error)
+	// KeySet returns the tool's public keys.
+	KeySet(ctx context.Context) (*KeySet, error)
+}
@@ -0,0 +1,520 @@
+package lti
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+type postgresService struct {
+	*sql.DB
+	config Config
+	keys   *keyCache
+	record classsvc.RecordFunc
+}
+
+func New(db *sql.DB, config Config) Service {
+	return NewWithRecorder(db, config, nil)
+}
+
+// NewWithRecorder returns a Service that records every change to a class with record, in the
+// transaction that makes it, like the classsvc Service does.
+func NewWithRecorder(db *sql.DB, config Config, record classsvc.RecordFunc) Service {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	return &postgresService{db, config, newKeyCache(config.Client), record}
+}
+
+func (s *postgresService) Login(ctx context.Context, req LoginRequest) (string, error) {
+	if req.Issuer == "" || req.LoginHint == "" {
+		return "", ErrBadRequest
+	}
+	platform, err := s.platform(req.Issuer, req.ClientID)
+	if err != nil {
+		return "", err
+	}
+	if req.DeploymentID != "" && !hasDeployment(platform, req.DeploymentID) {
+		return "", ErrUnknownDeployment
+	}
+	state := models.LtiState{
+		State:     random(),
+		Nonce:     random(),
+		Issuer:    platform.Issuer,
+		ClientID:  platform.ClientID,
+		CreatedAt: time.Now(),
+	}
+	if err := state.Save(s); err != nil {
+		return "", err
+	}
+
+	redirectURI := s.config.LaunchURL
+	if redirectURI == "" {
+		redirectURI = req.TargetLinkURI
+	}
+	u, err := url.Parse(platform.AuthLoginURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("scope", "openid")
+	q.Set("response_type", "id_token")
+	q.Set("response_mode", "form_post")
+	q.Set("prompt", "none")
+	q.Set("client_id", platform.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("login_hint", req.LoginHint)
+	if req.LtiMessageHint != "" {
+		q.Set("lti_message_hint", req.LtiMessageHint)
+	}
+	q.Set("state", state.State)
+	q.Set("nonce", state.Nonce)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// platform finds a registered platform. The client ID is optional in login requests,
+// in which case the issuer must have a single registration.
+func (s *postgresService) platform(issuer, clientID string) (*models.LtiPlatform, error) {
+	if clientID != "" {
+		platform, err := models.LtiPlatformByIssuerClientID(s, issuer, clientID)
+		if err == sql.ErrNoRows {
+			return nil, ErrUnknownPlatform
+		}
+		return platform, err
+	}
+	rows, err := s.Query("SELECT client_id FROM lti_platforms WHERE issuer=$1 LIMIT 2;", issuer)
+	if err != nil {
+		return nil, err
+	}
+	var clientIDs []string
+	for rows.Next() {
+		if err := rows.Scan(&clientID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		clientIDs = append(clientIDs, clientID)
+	}
+	rows.Close()
+	if len(clientIDs) != 1 {
+		return nil, ErrUnknownPlatform
+	}
+	return models.LtiPlatformByIssuerClientID(s, issuer, clientIDs[0])
+}
+
+func (s *postgresService) Launch(ctx context.Context, idToken, state string) (*Launch, error) {
+	if idToken == "" || state == "" {
+		return nil, ErrBadRequest
+	}
+	// States are single use and only valid for a few minutes after login.
+	var nonce, issuer, clientID string
+	err := s.QueryRowContext(ctx, "DELETE FROM lti_states WHERE state=$1 AND created_at > now() - interval '10 minutes' RETURNING nonce, issuer, client_id;", state).
+		Scan(&nonce, &issuer, &clientID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, ErrInvalidState
+	default:
+		return nil, err
+	}
+	platform, err := models.LtiPlatformByIssuerClientID(s, issuer, clientID)
+	if err != nil {
+		return nil, ErrUnknownPlatform
+	}
+
+	claims, err := verifyIDToken(idToken, s.keys.keyfunc(platform.JwksURL), platform, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if t, _ := claims[ClaimMessageType].(string); t != MessageTypeResourceLink {
+		return nil, ErrBadRequest
+	}
+	if v, _ := claims[ClaimVersion].(string); v != Version {
+		return nil, ErrBadRequest
+	}
+	deploymentID, _ := claims[ClaimDeploymentID].(string)
+	if !hasDeployment(platform, deploymentID) {
+		return nil, ErrUnknownDeployment
+	}
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, ErrInvalidToken
+	}
+	var lctx Context
+	if err := remarshal(claims[ClaimContext], &lctx); err != nil || lctx.ID == "" {
+		return nil, ErrBadRequest
+	}
+	var roles []string
+	if err := remarshal(claims[ClaimRoles], &roles); err != nil {
+		return nil, ErrBadRequest
+	}
+	role, ok := TranslateRoles(roles)
+	if !ok {
+		return nil, ErrNoRole
+	}
+	var nrps struct {
+		ContextMembershipsURL string `json:"context_memberships_url"`
+	}
+	remarshal(claims[ClaimNamesRoles], &nrps)
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	launch, err := s.launch(ctx, tx, platform, deploymentID, lctx, nrps.ContextMembershipsURL, sub, role)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	launch.Token, err = s.launchToken(launch)
+	if err != nil {
+		return nil, err
+	}
+	return launch, nil
+}
+
+func (s *postgresService) launch(ctx context.Context, tx *sql.Tx, platform *models.LtiPlatform, deploymentID string, lctx Context, membershipsURL, sub string, role models.UserRole) (*Launch, error) {
+	userID, err := resolveUser(tx, platform.Issuer, sub)
+	if err != nil {
+		return nil, err
+	}
+	launch := Launch{UserID: userID, Role: role}
+	p := classsvc.Provision(tx, s.record)
+
+	link, err := models.LtiContextByIssuerDeploymentIDContextID(tx, platform.Issuer, deploymentID, lctx.ID)
+	switch err {
+	case nil:
+		class, err := models.ClassByID(tx, link.ClassID)
+		if err != nil {
+			return nil, err
+		}
+		if !class.Active {
+			return nil, ErrClassInactive
+		}
+		if membershipsURL != "" && membershipsURL != link.MembershipsURL {
+			link.MembershipsURL = membershipsURL
+			if err := link.Save(tx); err != nil {
+				return nil, err
+			}
+		}
+		launch.ClassID = class.ID
+	case sql.ErrNoRows:
+		// Only instructors may create classes; learners launching first would otherwise own them.
+		if role != models.UserRoleTeacher {
+			return nil, ErrContextNotProvisioned
+		}
+		class, err := p.CreateClass(ctx, contextName(lctx))
+		if err != nil {
+			return nil, err
+		}
+		link := models.LtiContext{
+			ClassID:        class.ID,
+			Issuer:         platform.Issuer,
+			ClientID:       platform.ClientID,
+			DeploymentID:   deploymentID,
+			ContextID:      lctx.ID,
+			MembershipsURL: membershipsURL,
+		}
+		if err := link.Save(tx); err != nil {
+			return nil, err
+		}
+		if err := p.SetOwner(ctx, class.ID, &userID); err != nil {
+			return nil, err
+		}
+		launch.ClassID = class.ID
+		launch.Created = true
+		return &launch, nil
+	default:
+		return nil, err
+	}
+
+	member, err := models.MemberByUserIDClassID(tx, userID, launch.ClassID)
+	switch err {
+	case nil:
+		if member.Owner || member.Role == role {
+			launch.Role = member.Role
+			return &launch, nil
+		}
+		// A role without a seat left keeps the user in their current role.
+		switch err := p.SetRole(ctx, launch.ClassID, userID, role); err {
+		case nil:
+		case classsvc.ErrClassFull:
+			launch.Role = member.Role
+		default:
+			return nil, err
+		}
+		return &launch, nil
+	case sql.ErrNoRows:
+		switch err := p.Enroll(ctx, launch.ClassID, userID, role); err {
+		case nil:
+		case classsvc.ErrWaitlisted:
+			launch.Waitlisted = true
+		default:
+			return nil, err
+		}
+		return &launch, nil
+	default:
+		return nil, err
+	}
+}
+
+// launchToken signs a short-lived token asserting launch, so that the application users are
+// redirected to can trust it. It is empty if the tool has no key.
+func (s *postgresService) launchToken(launch *Launch) (string, error) {
+	if s.config.Key == nil {
+		return "", nil
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":        s.config.LaunchURL,
+		"sub":        launch.UserID.String(),
+		"class_id":   launch.ClassID.String(),
+		"role":       launch.Role,
+		"created":    launch.Created,
+		"waitlisted": launch.Waitlisted,
+		"iat":        now.Unix(),
+		"exp":        now.Add(LaunchTokenTTL).Unix(),
+		"jti":        random(),
+	})
+	token.Header["kid"] = s.config.KeyID
+	return token.SignedString(s.config.Key)
+}
+
+func (s *postgresService) SyncMembers(ctx context.Context, classID uuid.UUID) (*SyncSummary, error) {
+	if subj, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID); ok {
+		member, err := models.MemberByUserIDClassID(s, subj, classID)
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !member.Owner {
+			return nil, ErrForbidden
+		}
+	}
+	link, err := models.LtiContextByClassID(s, classID)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if link.MembershipsURL == "" {
+		return nil, ErrMembershipsUnsupported
+	}
+	platform, err := link.LtiPlatform(s)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberships(ctx, platform, link.MembershipsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := syncMembers(ctx, classsvc.Provision(tx, s.record), tx, platform.Issuer, classID, members)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return summary, nil
+}
+
+// syncMembers applies a platform roster to a class. Members who are not on the roster are
+// only removed when they were provisioned through this platform, so users who joined the
+// class directly are left alone. Owners are never changed. Changes go through p, so seat limits,
+// waitlists and roster locks apply as they do to users; members whose new role has no seat left
+// keep their current role.
+func syncMembers(ctx context.Context, p *classsvc.Provisioning, tx *sql.Tx, issuer string, classID uuid.UUID, roster []Member) (*SyncSummary, error) {
+	var summary SyncSummary
+
+	// Users are added in roster order, so the waitlist follows the platform's order too.
+	var order []uuid.UUID
+	wanted := make(map[uuid.UUID]models.UserRole, len(roster))
+	for _, m := range roster {
+		if m.Status == StatusInactive {
+			continue
+		}
+		role, ok := TranslateRoles(m.Roles)
+		if !ok {
+			continue
+		}
+		userID, err := resolveUser(tx, issuer, m.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := wanted[userID]; !ok {
+			order = append(order, userID)
+		}
+		wanted[userID] = role
+	}
+
+	existing, err := models.MembersByClassID(tx, classID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range existing {
+		role, ok := wanted[member.UserID]
+		delete(wanted, member.UserID)
+		if member.Owner {
+			continue
+		}
+		if ok {
+			if member.Role != role {
+				switch err := p.SetRole(ctx, classID, member.UserID, role); err {
+				case nil:
+					summary.Updated++
+				case classsvc.ErrClassFull:
+				default:
+					return nil, err
+				}
+			}
+			continue
+		}
+		refs, err := models.ExternalRefsBySourceKindID(tx, userSource(issuer), kindUser, member.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(refs) == 0 {
+			continue
+		}
+		if err := p.Remove(ctx, classID, member.UserID); err != nil {
+			return nil, err
+		}
+		summary.Removed++
+	}
+	for _, userID := range order {
+		role, ok := wanted[userID]
+		if !ok {
+			continue
+		}
+		switch err := p.Enroll(ctx, classID, userID, role); err {
+		case nil:
+			summary.Added++
+		case classsvc.ErrWaitlisted:
+			summary.Waitlisted++
+		default:
+			return nil, err
+		}
+	}
+	return &summary, nil
+}
+
+func (s *postgresService) KeySet(ctx context.Context) (*KeySet, error) {
+	if s.config.Key == nil {
+		return &KeySet{Keys: []JSONWebKey{}}, nil
+	}
+	return NewKeySet(s.config.KeyID, &s.config.Key.PublicKey), nil
+}
+
+// resolveUser maps a platform subject onto a user ID, allocating one on first sight.
+func resolveUser(tx *sql.Tx, issuer, sub string) (uuid.UUID, error) {
+	ref, err := models.ExternalRefBySourceKindExternalID(tx, userSource(issuer), kindUser, sub)
+	switch err {
+	case nil:
+		return ref.ID, nil
+	case sql.ErrNoRows:
+		ref = &models.ExternalRef{Source: userSource(issuer), Kind: kindUser, ExternalID: sub, ID: uuid.New()}
+		return ref.ID, ref.Save(tx)
+	default:
+		return uuid.Nil, err
+	}
+}
+
+func hasDeployment(platform *models.LtiPlatform, deploymentID string) bool {
+	for _, d := range platform.DeploymentIDs {
+		if d == deploymentID {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyIDToken checks the signature of a launch id_token against the keys of platform, and
+// that it was issued by platform to the tool, is current, and answers the login that sent nonce.
+func verifyIDToken(idToken string, keyfunc jwt.Keyfunc, platform *models.LtiPlatform, nonce string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(idToken, keyfunc)
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	if !claims.VerifyIssuer(platform.Issuer, true) || !verifyAudience(claims, platform.ClientID) {
+		return nil, ErrInvalidToken
+	}
+	// jwt-go only checks exp and iat when they are present, but LTI requires both.
+	now := time.Now().Unix()
+	if !claims.VerifyExpiresAt(now, true) || !claims.VerifyIssuedAt(now, true) {
+		return nil, ErrInvalidToken
+	}
+	if n, _ := claims["nonce"].(string); n == "" || n != nonce {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// verifyAudience checks aud, which may be a string or an array. When there are several
+// audiences the authorized party must be the tool.
+func verifyAudience(claims jwt.MapClaims, clientID string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		found := false
+		for _, a := range aud {
+			if a == clientID {
+				found = true
+			}
+		}
+		if len(aud) > 1 {
+			azp, _ := claims["azp"].(string)
+			return found && azp == clientID
+		}
+		return found
+	default:
+		return false
+	}
+}
+
+func contextName(c Context) string {
+	switch {
+	case c.Title != "":
+		return c.Title
+	case c.Label != "":
+		return c.Label
+	default:
+		return c.ID
+	}
+}
+
+func random() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
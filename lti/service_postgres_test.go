@@ -0,0 +1,121 @@
+package lti
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/studiously/classsvc/models"
+)
+
+func TestVerifyIDToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(NewKeySet("platform", &key.PublicKey))
+	}))
+	defer keys.Close()
+	platform := &models.LtiPlatform{Issuer: "https://lms.example.com", ClientID: "tool", JwksURL: keys.URL}
+	keyfunc := newKeyCache(keys.Client()).keyfunc(platform.JwksURL)
+
+	now := time.Now()
+	// claims returns valid claims for the login that sent nonce "n-1", changed by change.
+	claims := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":   platform.Issuer,
+			"aud":   platform.ClientID,
+			"sub":   "user-1",
+			"iat":   now.Unix(),
+			"exp":   now.Add(5 * time.Minute).Unix(),
+			"nonce": "n-1",
+		}
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+	sign := func(method jwt.SigningMethod, signingKey interface{}, c jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, c)
+		token.Header["kid"] = "platform"
+		s, err := token.SignedString(signingKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", sign(jwt.SigningMethodRS256, key, claims(nil)), true},
+		{"audience list with azp", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["aud"] = []string{"tool", "other"}
+			c["azp"] = "tool"
+		})), true},
+		{"single audience in a list", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["aud"] = []string{"tool"}
+		})), true},
+		{"expired", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["exp"] = now.Add(-time.Minute).Unix()
+		})), false},
+		{"no exp", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			delete(c, "exp")
+		})), false},
+		{"issued in the future", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["iat"] = now.Add(time.Hour).Unix()
+		})), false},
+		{"no iat", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			delete(c, "iat")
+		})), false},
+		{"other nonce", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["nonce"] = "n-2"
+		})), false},
+		{"no nonce", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			delete(c, "nonce")
+		})), false},
+		{"other audience", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["aud"] = "other"
+		})), false},
+		{"no audience", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			delete(c, "aud")
+		})), false},
+		{"audience list without azp", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["aud"] = []string{"tool", "other"}
+		})), false},
+		{"audience list with other azp", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["aud"] = []string{"tool", "other"}
+			c["azp"] = "other"
+		})), false},
+		{"other issuer", sign(jwt.SigningMethodRS256, key, claims(func(c jwt.MapClaims) {
+			c["iss"] = "https://evil.example.com"
+		})), false},
+		{"signed by another key", sign(jwt.SigningMethodRS256, other, claims(nil)), false},
+		{"signed with HS256", sign(jwt.SigningMethodHS256, []byte("secret"), claims(nil)), false},
+		{"unsigned", sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(nil)), false},
+		{"malformed", "not.a.token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifyIDToken(tt.token, keyfunc, platform, "n-1")
+			switch {
+			case tt.valid && err != nil:
+				t.Errorf("err = %v; want the token accepted", err)
+			case !tt.valid && err != ErrInvalidToken:
+				t.Errorf("err = %v; want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
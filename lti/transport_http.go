@@ -0,0 +1,207 @@
+package lti
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/introspector"
+)
+
+// MakeHTTPHandler mounts the tool endpoints under /lti/. Successful launches are redirected
+// to appURL with a launch query parameter holding the signed launch token, which the application
+// verifies against /lti/jwks.json; when appURL is empty the launch is returned as JSON instead.
+func MakeHTTPHandler(s Service, appURL string, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorLogger(logger),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// GET, POST /lti/login
+	// OIDC third-party initiated login. Platforms are authenticated by the launch token, not by Hydra.
+	loginServer := httptransport.NewServer(
+		e.LoginEndpoint,
+		DecodeLoginRequest,
+		encodeLoginResponse,
+		options...,
+	)
+	r.Methods("GET", "POST").Path("/lti/login").Handler(loginServer)
+
+	// POST /lti/launch
+	// Resource link launch, posted by the platform with id_token and state.
+	r.Methods("POST").Path("/lti/launch").Handler(httptransport.NewServer(
+		e.LaunchEndpoint,
+		DecodeLaunchRequest,
+		makeLaunchResponseEncoder(appURL),
+		options...,
+	))
+
+	// GET /lti/jwks.json
+	// The tool's public keys, used by platforms to verify client assertions.
+	r.Methods("GET").Path("/lti/jwks.json").Handler(httptransport.NewServer(
+		e.KeySetEndpoint,
+		func(context.Context, *http.Request) (interface{}, error) { return nil, nil },
+		encodeResponse,
+		options...,
+	))
+
+	// POST /lti/classes/{classID}/sync
+	// Reconcile a linked class with the platform roster.
+	r.Methods("POST").Path("/lti/classes/{classID}/sync").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.lti.sync")(e.SyncMembersEndpoint),
+		DecodeSyncMembersRequest,
+		encodeResponse,
+		append(options, httptransport.ServerBefore(introspector.ToHTTPContext()))...,
+	))
+
+	return classsvc.WithCorrelationID(r, logger)
+}
+
+func DecodeLoginRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, ErrBadRequest
+	}
+	return loginRequest{LoginRequest{
+		Issuer:         r.Form.Get("iss"),
+		LoginHint:      r.Form.Get("login_hint"),
+		TargetLinkURI:  r.Form.Get("target_link_uri"),
+		LtiMessageHint: r.Form.Get("lti_message_hint"),
+		ClientID:       r.Form.Get("client_id"),
+		DeploymentID:   r.Form.Get("lti_deployment_id"),
+	}}, nil
+}
+
+func encodeLoginResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(loginResponse)
+	if resp.Error != nil {
+		encodeError(ctx, resp.Error, w)
+		return nil
+	}
+	w.Header().Set("Location", resp.RedirectURL)
+	w.WriteHeader(http.StatusFound)
+	return nil
+}
+
+func DecodeLaunchRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, ErrBadRequest
+	}
+	return launchRequest{
+		IDToken: r.PostForm.Get("id_token"),
+		State:   r.PostForm.Get("state"),
+	}, nil
+}
+
+func makeLaunchResponseEncoder(appURL string) httptransport.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		resp := response.(launchResponse)
+		if resp.Error != nil || appURL == "" {
+			return encodeResponse(ctx, w, response)
+		}
+		// The application cannot tell who launched without a token it can verify.
+		if resp.Launch.Token == "" {
+			encodeError(ctx, ErrNoToolKey, w)
+			return nil
+		}
+		u, err := url.Parse(appURL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		q.Set("launch", resp.Launch.Token)
+		u.RawQuery = q.Encode()
+		w.Header().Set("Location", u.String())
+		w.WriteHeader(http.StatusFound)
+		return nil
+	}
+}
+
+func DecodeSyncMembersRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	classID, err := uuid.Parse(vars["classID"])
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	return syncMembersRequest{classID}, nil
+}
+
+// errorer is implemented by all concrete response types that may contain
+// errors.
+type errorer interface {
+	error() error
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if e, ok := response.(errorer); ok && e.error() != nil {
+		encodeError(ctx, e.error(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+// problems answers errors as problem+json, like the class service does.
+var problems = classsvc.Problems{Code: errorCode, Status: codeFrom}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	problems.EncodeError(ctx, err, w)
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrBadRequest, ErrUnknownPlatform, ErrUnknownDeployment:
+		return http.StatusBadRequest
+	case ErrInvalidState, ErrInvalidToken:
+		return http.StatusUnauthorized
+	case ErrNoRole, ErrContextNotProvisioned, ErrForbidden:
+		return http.StatusForbidden
+	case ErrNotFound, classsvc.ErrNotFound:
+		return http.StatusNotFound
+	case ErrClassInactive:
+		return http.StatusGone
+	case ErrMembershipsUnsupported, classsvc.ErrRosterLocked, classsvc.ErrEnrollmentClosed, classsvc.ErrClassFull:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorCode gives the stable code of err, which clients can match on. Errors of classsvc keep
+// their own codes.
+func errorCode(err error) string {
+	switch err {
+	case ErrBadRequest:
+		return "bad_request"
+	case ErrUnknownPlatform:
+		return "unknown_platform"
+	case ErrUnknownDeployment:
+		return "unknown_deployment"
+	case ErrInvalidState:
+		return "invalid_state"
+	case ErrInvalidToken:
+		return "invalid_token"
+	case ErrNoRole:
+		return "no_role"
+	case ErrContextNotProvisioned:
+		return "context_not_provisioned"
+	case ErrForbidden:
+		return "forbidden"
+	case ErrNotFound:
+		return "not_found"
+	case ErrClassInactive:
+		return "class_inactive"
+	case ErrMembershipsUnsupported:
+		return "memberships_unsupported"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/introspector"
+)
+
+// EventVersion is the version of the event types published for changes.
+// It is incremented whenever Event or a payload changes in a way that is not backwards compatible.
+const EventVersion = 1
+
+// Event is the data of the CloudEvent published for every change made through the Service.
+// Before and After hold the full state of the changed resource; Before is null for resources
+// that were created and After is null for resources that were removed. The payload types are
+// listed with the subjects in messaging.go.
+type Event struct {
+	// Actor is the user who made the change. It is omitted for changes the service makes on its
+	// own, such as promotions from a waitlist.
+	Actor   *uuid.UUID  `json:"actor,omitempty"`
+	ClassID *uuid.UUID  `json:"class_id,omitempty"`
+	UserID  *uuid.UUID  `json:"user_id,omitempty"`
+	Before  interface{} `json:"before"`
+	After   interface{} `json:"after"`
+}
+
+func newEvent(ctx context.Context, change classsvc.Change) Event {
+	e := Event{
+		ClassID: change.ClassID,
+		UserID:  change.UserID,
+		Before:  change.Before,
+		After:   change.After,
+	}
+	if actor, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID); ok && !change.System {
+		e.Actor = &actor
+	}
+	return e
+}
+
+// eventSubject is the CloudEvents subject of a change: the class it changed, or else the user.
+func eventSubject(change classsvc.Change) string {
+	switch {
+	case change.ClassID != nil:
+		return "classes/" + change.ClassID.String()
+	case change.UserID != nil:
+		return "users/" + change.UserID.String()
+	default:
+		return ""
+	}
+}
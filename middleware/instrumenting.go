@@ -115,3 +115,75 @@ func (im instrumentingMiddleware) GetMember(ctx context.Context, classID, userID
 	}(time.Now())
 	return im.next.GetMember(ctx, classID, userID)
 }
+
+func (im instrumentingMiddleware) GetCapacity(ctx context.Context, classID uuid.UUID) (capacity *classsvc.Capacity, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "GetCapacity", "error", fmt.Sprint(err != nil)}
+		im.requestCount.With(lvs...).Add(1)
+		im.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return im.next.GetCapacity(ctx, classID)
+}
+
+func (im instrumentingMiddleware) SetCapacity(ctx context.Context, classID uuid.UUID, capacity classsvc.Capacity) (err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "SetCapacity", "error", fmt.Sprint(err != nil)}
+		im.requestCount.With(lvs...).Add(1)
+		im.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return im.next.SetCapacity(ctx, classID, capacity)
+}
+
+func (im instrumentingMiddleware) ListWaitlist(ctx context.Context, classID uuid.UUID) (waitlist []*models.WaitlistEntry, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "ListWaitlist", "error", fmt.Sprint(err != nil)}
+		im.requestCount.With(lvs...).Add(1)
+		im.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return im.next.ListWaitlist(ctx, classID)
+}
+
+func (im instrumentingMiddleware) GetEnrollmentSettings(ctx context.Context, classID uuid.UUID) (settings *classsvc.EnrollmentSettings, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "GetEnrollmentSettings", "error", fmt.Sprint(err != nil)}
+		im.requestCount.With(lvs...).Add(1)
+		im.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return im.next.GetEnrollmentSettings(ctx, classID)
+}
+
+func (im instrumentingMiddleware) SetEnrollmentSettings(ctx context.Context, classID uuid.UUID, settings classsvc.EnrollmentSettings) (err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "SetEnrollmentSettings", "error", fmt.Sprint(err != nil)}
+		im.requestCount.With(lvs...).Add(1)
+		im.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return im.next.SetEnrollmentSettings(ctx, classID, settings)
+}
+
+func (im instrumentingMiddleware) LinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) (err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "LinkGuardian", "error", fmt.Sprint(err != nil)}
+		im.requestCount.With(lvs...).Add(1)
+		im.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return im.next.LinkGuardian(ctx, guardianID, studentID)
+}
+
+func (im instrumentingMiddleware) UnlinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) (err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "UnlinkGuardian", "error", fmt.Sprint(err != nil)}
+		im.requestCount.With(lvs...).Add(1)
+		im.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return im.next.UnlinkGuardian(ctx, guardianID, studentID)
+}
+
+func (im instrumentingMiddleware) ListStudents(ctx context.Context, guardianID uuid.UUID) (students []uuid.UUID, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "ListStudents", "error", fmt.Sprint(err != nil)}
+		im.requestCount.With(lvs...).Add(1)
+		im.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return im.next.ListStudents(ctx, guardianID)
+}
@@ -172,6 +172,121 @@ func (lm loggingMiddleware) GetMember(ctx context.Context, classID, userID uuid.
 	return lm.next.GetMember(ctx, classID, userID)
 }
 
+func (lm loggingMiddleware) GetCapacity(ctx context.Context, classID uuid.UUID) (capacity *classsvc.Capacity, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"action", "GetCapacity",
+			"user", subj(ctx).String(),
+			"client", cli(ctx),
+			"class", classID.String(),
+			"duration", time.Since(begin),
+			"error", err,
+		)
+	}(time.Now())
+	return lm.next.GetCapacity(ctx, classID)
+}
+
+func (lm loggingMiddleware) SetCapacity(ctx context.Context, classID uuid.UUID, capacity classsvc.Capacity) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"action", "SetCapacity",
+			"user", subj(ctx).String(),
+			"client", cli(ctx),
+			"class", classID.String(),
+			"duration", time.Since(begin),
+			"error", err,
+		)
+	}(time.Now())
+	return lm.next.SetCapacity(ctx, classID, capacity)
+}
+
+func (lm loggingMiddleware) ListWaitlist(ctx context.Context, classID uuid.UUID) (waitlist []*models.WaitlistEntry, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"action", "ListWaitlist",
+			"user", subj(ctx).String(),
+			"client", cli(ctx),
+			"class", classID.String(),
+			"duration", time.Since(begin),
+			"error", err,
+		)
+	}(time.Now())
+	return lm.next.ListWaitlist(ctx, classID)
+}
+
+func (lm loggingMiddleware) GetEnrollmentSettings(ctx context.Context, classID uuid.UUID) (settings *classsvc.EnrollmentSettings, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"action", "GetEnrollmentSettings",
+			"user", subj(ctx).String(),
+			"client", cli(ctx),
+			"class", classID.String(),
+			"duration", time.Since(begin),
+			"error", err,
+		)
+	}(time.Now())
+	return lm.next.GetEnrollmentSettings(ctx, classID)
+}
+
+func (lm loggingMiddleware) SetEnrollmentSettings(ctx context.Context, classID uuid.UUID, settings classsvc.EnrollmentSettings) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"action", "SetEnrollmentSettings",
+			"user", subj(ctx).String(),
+			"client", cli(ctx),
+			"class", classID.String(),
+			"locked", settings.Locked,
+			"duration", time.Since(begin),
+			"error", err,
+		)
+	}(time.Now())
+	return lm.next.SetEnrollmentSettings(ctx, classID, settings)
+}
+
+func (lm loggingMiddleware) LinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"action", "LinkGuardian",
+			"user", subj(ctx).String(),
+			"client", cli(ctx),
+			"guardian", guardianID.String(),
+			"student", studentID.String(),
+			"duration", time.Since(begin),
+			"error", err,
+		)
+	}(time.Now())
+	return lm.next.LinkGuardian(ctx, guardianID, studentID)
+}
+
+func (lm loggingMiddleware) UnlinkGuardian(ctx context.Context, guardianID, studentID uuid.UUID) (err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"action", "UnlinkGuardian",
+			"user", subj(ctx).String(),
+			"client", cli(ctx),
+			"guardian", guardianID.String(),
+			"student", studentID.String(),
+			"duration", time.Since(begin),
+			"error", err,
+		)
+	}(time.Now())
+	return lm.next.UnlinkGuardian(ctx, guardianID, studentID)
+}
+
+func (lm loggingMiddleware) ListStudents(ctx context.Context, guardianID uuid.UUID) (students []uuid.UUID, err error) {
+	defer func(begin time.Time) {
+		lm.logger.Log(
+			"action", "ListStudents",
+			"user", subj(ctx).String(),
+			"client", cli(ctx),
+			"guardian", guardianID.String(),
+			"duration", time.Since(begin),
+			"error", err,
+		)
+	}(time.Now())
+	return lm.next.ListStudents(ctx, guardianID)
+}
+
 func cli(ctx context.Context) string {
 	return ctx.Value(introspector.OAuth2IntrospectionContextKey).(oauth2.Introspection).ClientID
 }
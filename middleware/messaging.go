@@ -2,84 +2,98 @@ package middleware
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 
-	"github.com/google/uuid"
-	"github.com/nats-io/go-nats"
-	"github.com/studiously/classsvc/models"
 	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/eventbus"
+	"github.com/studiously/classsvc/outbox"
 )
 
+// Every subject carries a CloudEvent whose data is an Event, published through the outbox. The
+// CloudEvents types are listed in events. The types of the Before and After payloads are listed
+// with each subject; "-" means the payload is always null.
 const (
+	// SubjCreateClass: before -, after classsvc.ClassState. The actor is the owner of the new class.
+	SubjCreateClass = "classes.create"
+	// SubjUpdateClass: before classsvc.ClassState, after classsvc.ClassState.
+	SubjUpdateClass = "classes.update"
+	// SubjDeleteClass: before classsvc.ClassState, after classsvc.ClassState, which is no longer active.
 	SubjDeleteClass = "classes.delete"
-	SubjLeaveClass  = "classes.leave"
+	// SubjJoinClass: before -, after models.Member.
+	SubjJoinClass = "classes.join"
+	// SubjLeaveClass: before models.Member, after -. UserID is the member who left or was removed.
+	SubjLeaveClass = "classes.leave"
+	// SubjSetRole: before models.Member, after models.Member.
+	SubjSetRole = "classes.members.role"
+	// SubjTransferOwnership: before models.Member, after models.Member. UserID is the new owner.
+	SubjTransferOwnership = "classes.members.owner"
+	// SubjSetCapacity: before classsvc.Capacity, after classsvc.Capacity.
+	SubjSetCapacity = "classes.capacity.update"
+	// SubjJoinWaitlist: before -, after models.WaitlistEntry.
+	SubjJoinWaitlist = "classes.waitlist.join"
+	// SubjLeaveWaitlist: before models.WaitlistEntry, after -.
+	SubjLeaveWaitlist = "classes.waitlist.leave"
+	// SubjPromoteWaitlist is published when a waitlisted user is enrolled into a freed seat.
+	// Before models.WaitlistEntry, after models.Member. It has no actor.
+	SubjPromoteWaitlist = "classes.waitlist.promote"
+	// SubjSetEnrollmentSettings: before classsvc.EnrollmentSettings, after classsvc.EnrollmentSettings.
+	SubjSetEnrollmentSettings = "classes.enrollment.update"
+	// SubjLinkGuardian: before -, after models.GuardianLink. UserID is the student.
+	SubjLinkGuardian = "classes.guardians.link"
+	// SubjUnlinkGuardian: before models.GuardianLink, after -. UserID is the student.
+	SubjUnlinkGuardian = "classes.guardians.unlink"
 )
 
-func Messaging(nc *nats.Conn) (Middleware, error) {
-	ec, err := nats.NewEncodedConn(nc, nats.JSON_ENCODER)
-	if err != nil {
-		return nil, err
-	}
-	return func(next classsvc.Service) classsvc.Service {
-		return messagingMiddleware{ec, next}
-	}, nil
-}
-
-type messagingMiddleware struct {
-	nc   *nats.EncodedConn
-	next classsvc.Service
-}
-
-func (mm messagingMiddleware) ListClasses(ctx context.Context) ([]uuid.UUID, error) {
-	return mm.next.ListClasses(ctx)
-}
-
-func (mm messagingMiddleware) GetClass(ctx context.Context, classID uuid.UUID) (*models.Class, error) {
-	return mm.next.GetClass(ctx, classID)
-}
-
-func (mm messagingMiddleware) CreateClass(ctx context.Context, name string) (*uuid.UUID, error) {
-	return mm.next.CreateClass(ctx, name)
-}
-
-func (mm messagingMiddleware) UpdateClass(ctx context.Context, classID uuid.UUID, name *string, currentUnit *uuid.UUID) error {
-	return mm.next.UpdateClass(ctx, classID, name, currentUnit)
+// events maps every kind of change to the subject its events are published on and the name
+// of their CloudEvents type. Names are stable; consumers should match on them.
+var events = map[classsvc.ChangeKind]struct {
+	subject string
+	name    string
+}{
+	classsvc.ChangeCreateClass:           {SubjCreateClass, "class.created"},
+	classsvc.ChangeUpdateClass:           {SubjUpdateClass, "class.updated"},
+	classsvc.ChangeDeleteClass:           {SubjDeleteClass, "class.deleted"},
+	classsvc.ChangeJoinClass:             {SubjJoinClass, "member.joined"},
+	classsvc.ChangeLeaveClass:            {SubjLeaveClass, "member.left"},
+	classsvc.ChangeSetRole:               {SubjSetRole, "member.role_changed"},
+	classsvc.ChangeTransferOwnership:     {SubjTransferOwnership, "member.ownership_transferred"},
+	classsvc.ChangeSetCapacity:           {SubjSetCapacity, "capacity.updated"},
+	classsvc.ChangeJoinWaitlist:          {SubjJoinWaitlist, "waitlist.joined"},
+	classsvc.ChangeLeaveWaitlist:         {SubjLeaveWaitlist, "waitlist.left"},
+	classsvc.ChangePromoteWaitlist:       {SubjPromoteWaitlist, "waitlist.promoted"},
+	classsvc.ChangeSetEnrollmentSettings: {SubjSetEnrollmentSettings, "enrollment.updated"},
+	classsvc.ChangeLinkGuardian:          {SubjLinkGuardian, "guardian.linked"},
+	classsvc.ChangeUnlinkGuardian:        {SubjUnlinkGuardian, "guardian.unlinked"},
 }
 
-func (mm messagingMiddleware) DeleteClass(ctx context.Context, classID uuid.UUID) (err error) {
-	defer func() {
-		if err == nil {
-			mm.nc.Publish(SubjDeleteClass, struct {
-				ClassID uuid.UUID `json:"class_id"`
-			}{classID})
+// Outbox returns a classsvc.RecordFunc that writes a CloudEvent carrying an Event for every
+// change to the outbox, from which an outbox.Relay publishes it. Events of a class are
+// published in order. The event ID is fixed when the change is made, so consumers can discard
+// events that are delivered twice.
+func Outbox() classsvc.RecordFunc {
+	return func(ctx context.Context, tx *sql.Tx, change classsvc.Change) error {
+		event, ok := events[change.Kind]
+		if !ok {
+			return fmt.Errorf("no subject for %s", change.Kind)
 		}
-	}()
-	return mm.next.DeleteClass(ctx, classID)
-}
-
-func (mm messagingMiddleware) JoinClass(ctx context.Context, classID uuid.UUID) (error) {
-	return mm.next.JoinClass(ctx, classID)
-}
-
-func (mm messagingMiddleware) LeaveClass(ctx context.Context, userID *uuid.UUID, classID uuid.UUID) (err error) {
-	defer func() {
-		if err == nil {
-			mm.nc.Publish(SubjLeaveClass, struct {
-				ClassID uuid.UUID `json:"class_id"`
-				UserID  *uuid.UUID `json:"user_id,omitempty"`
-			}{classID, userID})
+		t := eventbus.Type{Name: event.name, Version: EventVersion}
+		payload, err := eventbus.EncodeCloudEvent(t, eventSubject(change), newEvent(ctx, change))
+		if err != nil {
+			return err
 		}
-	}()
-	return mm.next.LeaveClass(ctx, userID, classID)
-}
-
-func (mm messagingMiddleware) SetRole(ctx context.Context, classID, userID uuid.UUID, role models.UserRole) error {
-	return mm.next.SetRole(ctx, classID, userID, role)
-}
-
-func (mm messagingMiddleware) ListMembers(ctx context.Context, classID uuid.UUID) ([]*models.Member, error) {
-	return mm.next.ListMembers(ctx, classID)
+		return outbox.Write(tx, event.subject, orderingKey(change), payload)
+	}
 }
 
-func (mm messagingMiddleware) GetMember(ctx context.Context, classID, userID uuid.UUID) (member *models.Member, err error) {
-	return mm.next.GetMember(ctx, classID, userID)
+// orderingKey orders the events of a class, or of a user for changes outside any class.
+func orderingKey(change classsvc.Change) string {
+	switch {
+	case change.ClassID != nil:
+		return change.ClassID.String()
+	case change.UserID != nil:
+		return "user:" + change.UserID.String()
+	default:
+		return ""
+	}
 }
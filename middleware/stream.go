@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/eventbus"
+	"github.com/studiously/classsvc/stream"
+)
+
+// streamed lists the kinds of change pushed to the members of a class by the stream package.
+var streamed = map[classsvc.ChangeKind]bool{
+	classsvc.ChangeUpdateClass:       true,
+	classsvc.ChangeDeleteClass:       true,
+	classsvc.ChangeJoinClass:         true,
+	classsvc.ChangeLeaveClass:        true,
+	classsvc.ChangeSetRole:           true,
+	classsvc.ChangeTransferOwnership: true,
+	classsvc.ChangePromoteWaitlist:   true,
+}
+
+// Stream returns a classsvc.RecordFunc that writes the CloudEvent of every membership and class
+// change to the stream of its class.
+func Stream() classsvc.RecordFunc {
+	return func(ctx context.Context, tx *sql.Tx, change classsvc.Change) error {
+		if !streamed[change.Kind] || change.ClassID == nil {
+			return nil
+		}
+		event := events[change.Kind]
+		t := eventbus.Type{Name: event.name, Version: EventVersion}
+		payload, err := eventbus.EncodeCloudEvent(t, eventSubject(change), newEvent(ctx, change))
+		if err != nil {
+			return err
+		}
+		return stream.Write(tx, *change.ClassID, event.name, payload)
+	}
+}
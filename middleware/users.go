@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/eventbus"
+)
+
+// SubjDeleteUser is published by the user service when an account is deleted. Its payload is
+// {"user_id": "<uuid>"}, either on its own or as the data of a CloudEvent.
+const SubjDeleteUser = "users.delete"
+
+// UserDeletions subscribes to subject and removes every deleted user from their classes with
+// classsvc.RemoveUser, recording the changes with record. Hosts share a durable subscription, so
+// each deletion is handled by one of them, and deletions that fail are retried.
+func UserDeletions(bus eventbus.EventBus, subject string, db *sql.DB, record classsvc.RecordFunc, logger log.Logger) (eventbus.Subscription, error) {
+	return bus.SubscribeDurable(subject, "classsvc-users", func(msg eventbus.Message) error {
+		var payload struct {
+			UserID uuid.UUID `json:"user_id"`
+		}
+		_, err := eventbus.DecodeCloudEvent(msg.Data, &payload)
+		if err == eventbus.ErrNotCloudEvent {
+			err = json.Unmarshal(msg.Data, &payload)
+		}
+		if err != nil || payload.UserID == uuid.Nil {
+			// Malformed deletions are dropped, as they would fail again.
+			logger.Log("subject", msg.Subject, "msg", "malformed user deletion", "error", err)
+			return nil
+		}
+		removal, err := classsvc.RemoveUser(context.Background(), db, record, payload.UserID)
+		if err != nil {
+			logger.Log("subject", msg.Subject, "user", payload.UserID, "error", err)
+			return err
+		}
+		logger.Log(
+			"subject", msg.Subject,
+			"user", payload.UserID,
+			"memberships", removal.Memberships,
+			"transferred", removal.Transferred,
+			"archived", removal.Archived,
+			"waitlist_entries", removal.WaitlistEntries,
+			"guardian_links", removal.GuardianLinks,
+			"external_ids", removal.ExternalIDs,
+		)
+		return nil
+	})
+}
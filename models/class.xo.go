@@ -2,19 +2,29 @@
 package models
 
 // GENERATED BY XO. DO NOT EDIT.
+//
+// Edited by hand after generation: Insert executes its statement rather than scanning the id
+// back, as the statement has no RETURNING clause. Carry the edit over when regenerating.
 
 import (
+	"database/sql"
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Class represents a row from 'public.classes'.
 type Class struct {
-	ID          uuid.UUID `json:"id"`           // id
-	Name        string    `json:"name"`         // name
-	CurrentUnit uuid.UUID `json:"current_unit"` // current_unit
-	Active      bool      `json:"-"`       // active
+	ID                 uuid.UUID     `json:"id"`           // id
+	Name               string        `json:"name"`         // name
+	CurrentUnit        uuid.UUID     `json:"current_unit"` // current_unit
+	Active             bool          `json:"-"`            // active
+	Capacity           sql.NullInt64 `json:"-"`            // capacity
+	EnrollmentOpensAt  pq.NullTime   `json:"-"`            // enrollment_opens_at
+	EnrollmentClosesAt pq.NullTime   `json:"-"`            // enrollment_closes_at
+	RosterLocked       bool          `json:"-"`            // roster_locked
+	Version            int64         `json:"version"`      // version
 
 	// xo fields
 	_exists, _deleted bool
@@ -41,14 +51,14 @@ func (c *Class) Insert(db XODB) error {
 
 	// sql insert query, primary key must be provided
 	const sqlstr = `INSERT INTO public.classes (` +
-		`id, name, current_unit, active` +
+		`id, name, current_unit, active, capacity, enrollment_opens_at, enrollment_closes_at, roster_locked, version` +
 		`) VALUES (` +
-		`$1, $2, $3, $4` +
+		`$1, $2, $3, $4, $5, $6, $7, $8, $9` +
 		`)`
 
 	// run query
-	XOLog(sqlstr, c.ID, c.Name, c.CurrentUnit, c.Active)
-	err = db.QueryRow(sqlstr, c.ID, c.Name, c.CurrentUnit, c.Active).Scan(&c.ID)
+	XOLog(sqlstr, c.ID, c.Name, c.CurrentUnit, c.Active, c.Capacity, c.EnrollmentOpensAt, c.EnrollmentClosesAt, c.RosterLocked, c.Version)
+	_, err = db.Exec(sqlstr, c.ID, c.Name, c.CurrentUnit, c.Active, c.Capacity, c.EnrollmentOpensAt, c.EnrollmentClosesAt, c.RosterLocked, c.Version)
 	if err != nil {
 		return err
 	}
@@ -75,14 +85,14 @@ func (c *Class) Update(db XODB) error {
 
 	// sql query
 	const sqlstr = `UPDATE public.classes SET (` +
-		`name, current_unit, active` +
+		`name, current_unit, active, capacity, enrollment_opens_at, enrollment_closes_at, roster_locked, version` +
 		`) = ( ` +
-		`$1, $2, $3` +
-		`) WHERE id = $4`
+		`$1, $2, $3, $4, $5, $6, $7, $8` +
+		`) WHERE id = $9`
 
 	// run query
-	XOLog(sqlstr, c.Name, c.CurrentUnit, c.Active, c.ID)
-	_, err = db.Exec(sqlstr, c.Name, c.CurrentUnit, c.Active, c.ID)
+	XOLog(sqlstr, c.Name, c.CurrentUnit, c.Active, c.Capacity, c.EnrollmentOpensAt, c.EnrollmentClosesAt, c.RosterLocked, c.Version, c.ID)
+	_, err = db.Exec(sqlstr, c.Name, c.CurrentUnit, c.Active, c.Capacity, c.EnrollmentOpensAt, c.EnrollmentClosesAt, c.RosterLocked, c.Version, c.ID)
 	return err
 }
 
@@ -108,18 +118,18 @@ func (c *Class) Upsert(db XODB) error {
 
 	// sql query
 	const sqlstr = `INSERT INTO public.classes (` +
-		`id, name, current_unit, active` +
+		`id, name, current_unit, active, capacity, enrollment_opens_at, enrollment_closes_at, roster_locked, version` +
 		`) VALUES (` +
-		`$1, $2, $3, $4` +
+		`$1, $2, $3, $4, $5, $6, $7, $8, $9` +
 		`) ON CONFLICT (id) DO UPDATE SET (` +
-		`id, name, current_unit, active` +
+		`id, name, current_unit, active, capacity, enrollment_opens_at, enrollment_closes_at, roster_locked, version` +
 		`) = (` +
-		`EXCLUDED.id, EXCLUDED.name, EXCLUDED.current_unit, EXCLUDED.active` +
+		`EXCLUDED.id, EXCLUDED.name, EXCLUDED.current_unit, EXCLUDED.active, EXCLUDED.capacity, EXCLUDED.enrollment_opens_at, EXCLUDED.enrollment_closes_at, EXCLUDED.roster_locked, EXCLUDED.version` +
 		`)`
 
 	// run query
-	XOLog(sqlstr, c.ID, c.Name, c.CurrentUnit, c.Active)
-	_, err = db.Exec(sqlstr, c.ID, c.Name, c.CurrentUnit, c.Active)
+	XOLog(sqlstr, c.ID, c.Name, c.CurrentUnit, c.Active, c.Capacity, c.EnrollmentOpensAt, c.EnrollmentClosesAt, c.RosterLocked, c.Version)
+	_, err = db.Exec(sqlstr, c.ID, c.Name, c.CurrentUnit, c.Active, c.Capacity, c.EnrollmentOpensAt, c.EnrollmentClosesAt, c.RosterLocked, c.Version)
 	if err != nil {
 		return err
 	}
@@ -168,7 +178,7 @@ func ClassByID(db XODB, id uuid.UUID) (*Class, error) {
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`id, name, current_unit, active ` +
+		`id, name, current_unit, active, capacity, enrollment_opens_at, enrollment_closes_at, roster_locked, version ` +
 		`FROM public.classes ` +
 		`WHERE id = $1`
 
@@ -178,7 +188,7 @@ func ClassByID(db XODB, id uuid.UUID) (*Class, error) {
 		_exists: true,
 	}
 
-	err = db.QueryRow(sqlstr, id).Scan(&c.ID, &c.Name, &c.CurrentUnit, &c.Active)
+	err = db.QueryRow(sqlstr, id).Scan(&c.ID, &c.Name, &c.CurrentUnit, &c.Active, &c.Capacity, &c.EnrollmentOpensAt, &c.EnrollmentClosesAt, &c.RosterLocked, &c.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -2,19 +2,27 @@
 package models
 
 // GENERATED BY XO. DO NOT EDIT.
+//
+// Edited by hand after generation: xo keys members on class_id alone, so Update, Upsert and
+// Delete were changed to use the (user_id, class_id) primary key, and Insert executes its
+// statement rather than scanning class_id back, as the statement has no RETURNING clause.
+// Carry the edits over when regenerating.
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // Member represents a row from 'public.members'.
 type Member struct {
-	UserID  uuid.UUID `json:"user_id"`  // user_id
-	ClassID uuid.UUID `json:"class_id"` // class_id
-	Role    UserRole  `json:"role"`     // role
-	Owner   bool      `json:"owner"`    // owner
+	UserID   uuid.UUID `json:"user_id"`   // user_id
+	ClassID  uuid.UUID `json:"class_id"`  // class_id
+	Role     UserRole  `json:"role"`      // role
+	Owner    bool      `json:"owner"`     // owner
+	JoinedAt time.Time `json:"joined_at"` // joined_at
+	Version  int64     `json:"version"`   // version
 
 	// xo fields
 	_exists, _deleted bool
@@ -41,14 +49,14 @@ func (m *Member) Insert(db XODB) error {
 
 	// sql insert query, primary key must be provided
 	const sqlstr = `INSERT INTO public.members (` +
-		`user_id, class_id, role, owner` +
+		`user_id, class_id, role, owner, joined_at, version` +
 		`) VALUES (` +
-		`$1, $2, $3, $4` +
+		`$1, $2, $3, $4, $5, $6` +
 		`)`
 
 	// run query
-	XOLog(sqlstr, m.UserID, m.ClassID, m.Role, m.Owner)
-	err = db.QueryRow(sqlstr, m.UserID, m.ClassID, m.Role, m.Owner).Scan(&m.ClassID)
+	XOLog(sqlstr, m.UserID, m.ClassID, m.Role, m.Owner, m.JoinedAt, m.Version)
+	_, err = db.Exec(sqlstr, m.UserID, m.ClassID, m.Role, m.Owner, m.JoinedAt, m.Version)
 	if err != nil {
 		return err
 	}
@@ -75,14 +83,14 @@ func (m *Member) Update(db XODB) error {
 
 	// sql query
 	const sqlstr = `UPDATE public.members SET (` +
-		`user_id, role, owner` +
+		`role, owner, joined_at, version` +
 		`) = ( ` +
-		`$1, $2, $3` +
-		`) WHERE class_id = $4`
+		`$1, $2, $3, $4` +
+		`) WHERE user_id = $5 AND class_id = $6`
 
 	// run query
-	XOLog(sqlstr, m.UserID, m.Role, m.Owner, m.ClassID)
-	_, err = db.Exec(sqlstr, m.UserID, m.Role, m.Owner, m.ClassID)
+	XOLog(sqlstr, m.Role, m.Owner, m.JoinedAt, m.Version, m.UserID, m.ClassID)
+	_, err = db.Exec(sqlstr, m.Role, m.Owner, m.JoinedAt, m.Version, m.UserID, m.ClassID)
 	return err
 }
 
@@ -108,18 +116,18 @@ func (m *Member) Upsert(db XODB) error {
 
 	// sql query
 	const sqlstr = `INSERT INTO public.members (` +
-		`user_id, class_id, role, owner` +
+		`user_id, class_id, role, owner, joined_at, version` +
 		`) VALUES (` +
-		`$1, $2, $3, $4` +
-		`) ON CONFLICT (class_id) DO UPDATE SET (` +
-		`user_id, class_id, role, owner` +
+		`$1, $2, $3, $4, $5, $6` +
+		`) ON CONFLICT (user_id, class_id) DO UPDATE SET (` +
+		`user_id, class_id, role, owner, joined_at, version` +
 		`) = (` +
-		`EXCLUDED.user_id, EXCLUDED.class_id, EXCLUDED.role, EXCLUDED.owner` +
+		`EXCLUDED.user_id, EXCLUDED.class_id, EXCLUDED.role, EXCLUDED.owner, EXCLUDED.joined_at, EXCLUDED.version` +
 		`)`
 
 	// run query
-	XOLog(sqlstr, m.UserID, m.ClassID, m.Role, m.Owner)
-	_, err = db.Exec(sqlstr, m.UserID, m.ClassID, m.Role, m.Owner)
+	XOLog(sqlstr, m.UserID, m.ClassID, m.Role, m.Owner, m.JoinedAt, m.Version)
+	_, err = db.Exec(sqlstr, m.UserID, m.ClassID, m.Role, m.Owner, m.JoinedAt, m.Version)
 	if err != nil {
 		return err
 	}
@@ -145,11 +153,11 @@ func (m *Member) Delete(db XODB) error {
 	}
 
 	// sql query
-	const sqlstr = `DELETE FROM public.members WHERE class_id = $1`
+	const sqlstr = `DELETE FROM public.members WHERE user_id = $1 AND class_id = $2`
 
 	// run query
-	XOLog(sqlstr, m.ClassID)
-	_, err = db.Exec(sqlstr, m.ClassID)
+	XOLog(sqlstr, m.UserID, m.ClassID)
+	_, err = db.Exec(sqlstr, m.UserID, m.ClassID)
 	if err != nil {
 		return err
 	}
@@ -175,7 +183,7 @@ func MembersByClassID(db XODB, classID uuid.UUID) ([]*Member, error) {
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`user_id, class_id, role, owner ` +
+		`user_id, class_id, role, owner, joined_at, version ` +
 		`FROM public.members ` +
 		`WHERE class_id = $1`
 
@@ -195,7 +203,7 @@ func MembersByClassID(db XODB, classID uuid.UUID) ([]*Member, error) {
 		}
 
 		// scan
-		err = q.Scan(&m.UserID, &m.ClassID, &m.Role, &m.Owner)
+		err = q.Scan(&m.UserID, &m.ClassID, &m.Role, &m.Owner, &m.JoinedAt, &m.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -214,7 +222,7 @@ func MemberByUserIDClassID(db XODB, userID uuid.UUID, classID uuid.UUID) (*Membe
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`user_id, class_id, role, owner ` +
+		`user_id, class_id, role, owner, joined_at, version ` +
 		`FROM public.members ` +
 		`WHERE user_id = $1 AND class_id = $2`
 
@@ -224,7 +232,7 @@ func MemberByUserIDClassID(db XODB, userID uuid.UUID, classID uuid.UUID) (*Membe
 		_exists: true,
 	}
 
-	err = db.QueryRow(sqlstr, userID, classID).Scan(&m.UserID, &m.ClassID, &m.Role, &m.Owner)
+	err = db.QueryRow(sqlstr, userID, classID).Scan(&m.UserID, &m.ClassID, &m.Role, &m.Owner, &m.JoinedAt, &m.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +248,7 @@ func MembersByUserID(db XODB, userID uuid.UUID) ([]*Member, error) {
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`user_id, class_id, role, owner ` +
+		`user_id, class_id, role, owner, joined_at, version ` +
 		`FROM public.members ` +
 		`WHERE user_id = $1`
 
@@ -260,7 +268,7 @@ func MembersByUserID(db XODB, userID uuid.UUID) ([]*Member, error) {
 		}
 
 		// scan
-		err = q.Scan(&m.UserID, &m.ClassID, &m.Role, &m.Owner)
+		err = q.Scan(&m.UserID, &m.ClassID, &m.Role, &m.Owner, &m.JoinedAt, &m.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,64 @@
+package oneroster
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Endpoints collects all of the endpoints that compose the OneRoster service.
+type Endpoints struct {
+	ImportEndpoint endpoint.Endpoint
+	ExportEndpoint endpoint.Endpoint
+}
+
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		ImportEndpoint: MakeImportEndpoint(s),
+		ExportEndpoint: MakeExportEndpoint(s),
+	}
+}
+
+func MakeImportEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(importRequest)
+		summary, e := s.Import(ctx, bytes.NewReader(req.Bundle), int64(len(req.Bundle)))
+		return importResponse{summary, e}, nil
+	}
+}
+
+type importRequest struct {
+	Bundle []byte
+}
+
+type importResponse struct {
+	Summary *Summary `json:"summary,omitempty"`
+	Error   error    `json:"error,omitempty"`
+}
+
+func (r importResponse) error() error {
+	return r.Error
+}
+
+func MakeExportEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(exportRequest)
+		var buf bytes.Buffer
+		e := s.Export(ctx, &buf, req.Options)
+		return exportResponse{buf.Bytes(), e}, nil
+	}
+}
+
+type exportRequest struct {
+	Options ExportOptions
+}
+
+type exportResponse struct {
+	Bundle []byte
+	Error  error `json:"error,omitempty"`
+}
+
+func (r exportResponse) error() error {
+	return r.Error
+}
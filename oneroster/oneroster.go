@@ -0,0 +1,318 @@
+// Package oneroster imports and exports class rosters as IMS OneRoster 1.1
+// CSV bundles.
+package oneroster
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/studiously/classsvc/models"
+)
+
+// Source is the value of external_refs.source for records created by OneRoster imports.
+const Source = "oneroster"
+
+// Kinds of records tracked in external_refs.
+const (
+	KindClass = "class"
+	KindUser  = "user"
+)
+
+// Processing modes of a file, as given by manifest.csv. A bulk file holds every record of its
+// kind, so records missing from it are to be deleted; a delta file only holds changes.
+const (
+	ModeBulk   = "bulk"
+	ModeDelta  = "delta"
+	ModeAbsent = "absent"
+)
+
+// Status values used by OneRoster records.
+const (
+	StatusActive      = "active"
+	StatusToBeDeleted = "tobedeleted"
+)
+
+// Bundle file names.
+const (
+	FileManifest         = "manifest.csv"
+	FileOrgs             = "orgs.csv"
+	FileAcademicSessions = "academicSessions.csv"
+	FileCourses          = "courses.csv"
+	FileClasses          = "classes.csv"
+	FileUsers            = "users.csv"
+	FileEnrollments      = "enrollments.csv"
+)
+
+var (
+	ErrMissingFile   = errors.New("bundle is missing a required file")
+	ErrMissingColumn = errors.New("file is missing a required column")
+	ErrBadReference  = errors.New("record references an unknown sourcedId")
+)
+
+// Org is a row of orgs.csv.
+type Org struct {
+	SourcedID       string
+	Status          string
+	Name            string
+	Type            string
+	Identifier      string
+	ParentSourcedID string
+}
+
+// Class is a row of classes.csv.
+type Class struct {
+	SourcedID       string
+	Status          string
+	Title           string
+	CourseSourcedID string
+	ClassCode       string
+	ClassType       string
+	SchoolSourcedID string
+	TermSourcedIDs  []string
+}
+
+// User is a row of users.csv.
+type User struct {
+	SourcedID     string
+	Status        string
+	EnabledUser   bool
+	OrgSourcedIDs []string
+	Role          string
+	Username      string
+	GivenName     string
+	FamilyName    string
+	Identifier    string
+	Email         string
+}
+
+// Enrollment is a row of enrollments.csv.
+type Enrollment struct {
+	SourcedID       string
+	Status          string
+	ClassSourcedID  string
+	SchoolSourcedID string
+	UserSourcedID   string
+	Role            string
+	Primary         bool
+}
+
+// Roster is the parsed contents of a bundle.
+type Roster struct {
+	// Manifest holds the properties of manifest.csv, such as "file.classes".
+	Manifest    map[string]string
+	Orgs        []Org
+	Classes     []Class
+	Users       []User
+	Enrollments []Enrollment
+}
+
+// Bulk reports whether the manifest marks file, such as FileClasses, as a bulk file. Bundles
+// without a manifest are treated as delta bundles.
+func (r *Roster) Bulk(file string) bool {
+	return r.Manifest["file."+strings.TrimSuffix(file, ".csv")] == ModeBulk
+}
+
+// TranslateRole maps a OneRoster role onto a class role. Roles that have no
+// place in a class roster (guardians, parents, relatives) are not translated.
+func TranslateRole(role string) (models.UserRole, bool) {
+	switch strings.ToLower(role) {
+	case "teacher", "administrator", "aide", "proctor":
+		return models.UserRoleTeacher, true
+	case "student":
+		return models.UserRoleStudent, true
+	default:
+		return 0, false
+	}
+}
+
+// Read parses a OneRoster bundle. orgs.csv, classes.csv, users.csv and
+// enrollments.csv must be present, and manifest.csv is read if it is; other files
+// are ignored.
+func Read(r io.ReaderAt, size int64) (*Roster, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		// Some SIS exports nest the files under a directory.
+		name := f.Name
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		files[name] = f
+	}
+
+	roster := Roster{Manifest: make(map[string]string)}
+	if _, ok := files[FileManifest]; ok {
+		err = readFile(files, FileManifest, []string{"propertyName", "value"}, func(row record) {
+			roster.Manifest[row.get("propertyName")] = row.get("value")
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = readFile(files, FileOrgs, []string{"sourcedId", "name", "type"}, func(row record) {
+		roster.Orgs = append(roster.Orgs, Org{
+			SourcedID:       row.get("sourcedId"),
+			Status:          row.status(),
+			Name:            row.get("name"),
+			Type:            row.get("type"),
+			Identifier:      row.get("identifier"),
+			ParentSourcedID: row.get("parentSourcedId"),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = readFile(files, FileClasses, []string{"sourcedId", "title", "schoolSourcedId"}, func(row record) {
+		roster.Classes = append(roster.Classes, Class{
+			SourcedID:       row.get("sourcedId"),
+			Status:          row.status(),
+			Title:           row.get("title"),
+			CourseSourcedID: row.get("courseSourcedId"),
+			ClassCode:       row.get("classCode"),
+			ClassType:       row.get("classType"),
+			SchoolSourcedID: row.get("schoolSourcedId"),
+			TermSourcedIDs:  row.list("termSourcedIds"),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = readFile(files, FileUsers, []string{"sourcedId", "role"}, func(row record) {
+		roster.Users = append(roster.Users, User{
+			SourcedID:     row.get("sourcedId"),
+			Status:        row.status(),
+			EnabledUser:   row.get("enabledUser") != "false",
+			OrgSourcedIDs: row.list("orgSourcedIds"),
+			Role:          row.get("role"),
+			Username:      row.get("username"),
+			GivenName:     row.get("givenName"),
+			FamilyName:    row.get("familyName"),
+			Identifier:    row.get("identifier"),
+			Email:         row.get("email"),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = readFile(files, FileEnrollments, []string{"sourcedId", "classSourcedId", "userSourcedId", "role"}, func(row record) {
+		roster.Enrollments = append(roster.Enrollments, Enrollment{
+			SourcedID:       row.get("sourcedId"),
+			Status:          row.status(),
+			ClassSourcedID:  row.get("classSourcedId"),
+			SchoolSourcedID: row.get("schoolSourcedId"),
+			UserSourcedID:   row.get("userSourcedId"),
+			Role:            row.get("role"),
+			Primary:         row.get("primary") == "true",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &roster, roster.validate()
+}
+
+// validate checks that every class, user and enrollment refers to records present in the bundle.
+func (r *Roster) validate() error {
+	orgs := make(map[string]bool)
+	for _, o := range r.Orgs {
+		orgs[o.SourcedID] = true
+	}
+	classes := make(map[string]bool)
+	for _, c := range r.Classes {
+		if !orgs[c.SchoolSourcedID] {
+			return fmt.Errorf("%v: class %q school %q", ErrBadReference, c.SourcedID, c.SchoolSourcedID)
+		}
+		classes[c.SourcedID] = true
+	}
+	users := make(map[string]bool)
+	for _, u := range r.Users {
+		users[u.SourcedID] = true
+	}
+	for _, e := range r.Enrollments {
+		if !classes[e.ClassSourcedID] {
+			return fmt.Errorf("%v: enrollment %q class %q", ErrBadReference, e.SourcedID, e.ClassSourcedID)
+		}
+		if !users[e.UserSourcedID] {
+			return fmt.Errorf("%v: enrollment %q user %q", ErrBadReference, e.SourcedID, e.UserSourcedID)
+		}
+	}
+	return nil
+}
+
+type record struct {
+	columns map[string]int
+	fields  []string
+}
+
+func (r record) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+func (r record) list(column string) []string {
+	v := r.get(column)
+	if v == "" {
+		return nil
+	}
+	parts := strings.Split(v, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+func (r record) status() string {
+	if s := r.get("status"); s != "" {
+		return s
+	}
+	return StatusActive
+}
+
+func readFile(files map[string]*zip.File, name string, required []string, fn func(record)) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%v: %s", ErrMissingFile, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	cr := csv.NewReader(rc)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		// Strip the byte order mark Excel likes to prepend.
+		columns[strings.TrimPrefix(strings.TrimSpace(h), "\ufeff")] = i
+	}
+	for _, c := range required {
+		if _, ok := columns[c]; !ok {
+			return fmt.Errorf("%v: %s %s", ErrMissingColumn, name, c)
+		}
+	}
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		fn(record{columns, fields})
+	}
+}
@@ -0,0 +1,157 @@
+package oneroster
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/studiously/classsvc/models"
+)
+
+// bundle zips files, named by their path in the archive.
+func bundle(t *testing.T, files map[string]string) *bytes.Reader {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(contents))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+// validFiles returns a small bundle with one school, one class, a teacher and a student.
+func validFiles() map[string]string {
+	return map[string]string{
+		FileManifest: "propertyName,value\nfile.classes,bulk\nfile.enrollments,delta\n",
+		FileOrgs:     "sourcedId,name,type\nschool-1,Lincoln High,school\n",
+		FileClasses:  "sourcedId,status,title,schoolSourcedId,termSourcedIds\nclass-1,,Algebra I,school-1,\"term-1, term-2\"\n",
+		FileUsers:    "sourcedId,role,enabledUser,givenName\nteacher-1,teacher,true,Ada\nstudent-1,student,false,Alan\n",
+		FileEnrollments: "sourcedId,classSourcedId,userSourcedId,role,primary,status\n" +
+			"e-1,class-1,teacher-1,teacher,true,active\n" +
+			"e-2,class-1,student-1,student,false,tobedeleted\n",
+	}
+}
+
+func TestRead(t *testing.T) {
+	r := bundle(t, validFiles())
+	roster, err := Read(r, r.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !roster.Bulk(FileClasses) || roster.Bulk(FileEnrollments) || roster.Bulk(FileUsers) {
+		t.Errorf("manifest = %v; want only classes in bulk", roster.Manifest)
+	}
+	wantClasses := []Class{{
+		SourcedID:       "class-1",
+		Status:          StatusActive,
+		Title:           "Algebra I",
+		SchoolSourcedID: "school-1",
+		TermSourcedIDs:  []string{"term-1", "term-2"},
+	}}
+	if !reflect.DeepEqual(roster.Classes, wantClasses) {
+		t.Errorf("classes = %+v; want %+v", roster.Classes, wantClasses)
+	}
+	if len(roster.Users) != 2 || !roster.Users[0].EnabledUser || roster.Users[1].EnabledUser {
+		t.Errorf("users = %+v; want teacher-1 enabled and student-1 disabled", roster.Users)
+	}
+	wantEnrollments := []Enrollment{
+		{SourcedID: "e-1", Status: StatusActive, ClassSourcedID: "class-1", UserSourcedID: "teacher-1", Role: "teacher", Primary: true},
+		{SourcedID: "e-2", Status: StatusToBeDeleted, ClassSourcedID: "class-1", UserSourcedID: "student-1", Role: "student"},
+	}
+	if !reflect.DeepEqual(roster.Enrollments, wantEnrollments) {
+		t.Errorf("enrollments = %+v; want %+v", roster.Enrollments, wantEnrollments)
+	}
+}
+
+func TestReadNestedWithByteOrderMark(t *testing.T) {
+	files := make(map[string]string)
+	for name, contents := range validFiles() {
+		files["export-2024/"+name] = "\ufeff" + contents
+	}
+	r := bundle(t, files)
+	roster, err := Read(r, r.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roster.Classes) != 1 || roster.Classes[0].SourcedID != "class-1" {
+		t.Errorf("classes = %+v; want class-1", roster.Classes)
+	}
+}
+
+func TestReadWithoutManifest(t *testing.T) {
+	files := validFiles()
+	delete(files, FileManifest)
+	r := bundle(t, files)
+	roster, err := Read(r, r.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roster.Bulk(FileClasses) {
+		t.Error("a bundle without a manifest is read as bulk; want delta")
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(files map[string]string)
+		want   error
+	}{
+		{"missing users", func(files map[string]string) {
+			delete(files, FileUsers)
+		}, ErrMissingFile},
+		{"missing column", func(files map[string]string) {
+			files[FileEnrollments] = "sourcedId,classSourcedId,userSourcedId\ne-1,class-1,teacher-1\n"
+		}, ErrMissingColumn},
+		{"unknown school", func(files map[string]string) {
+			files[FileClasses] = "sourcedId,title,schoolSourcedId\nclass-1,Algebra I,school-2\n"
+		}, ErrBadReference},
+		{"unknown class", func(files map[string]string) {
+			files[FileEnrollments] = "sourcedId,classSourcedId,userSourcedId,role\ne-1,class-2,teacher-1,teacher\n"
+		}, ErrBadReference},
+		{"unknown user", func(files map[string]string) {
+			files[FileEnrollments] = "sourcedId,classSourcedId,userSourcedId,role\ne-1,class-1,teacher-2,teacher\n"
+		}, ErrBadReference},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := validFiles()
+			tt.change(files)
+			r := bundle(t, files)
+			_, err := Read(r, r.Size())
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want.Error()) {
+				t.Errorf("err = %v; want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateRole(t *testing.T) {
+	tests := []struct {
+		role string
+		want models.UserRole
+		ok   bool
+	}{
+		{"teacher", models.UserRoleTeacher, true},
+		{"Administrator", models.UserRoleTeacher, true},
+		{"aide", models.UserRoleTeacher, true},
+		{"student", models.UserRoleStudent, true},
+		{"guardian", 0, false},
+		{"parent", 0, false},
+	}
+	for _, tt := range tests {
+		role, ok := TranslateRole(tt.role)
+		if role != tt.want || ok != tt.ok {
+			t.Errorf("TranslateRole(%q) = %v, %t; want %v, %t", tt.role, role, ok, tt.want, tt.ok)
+		}
+	}
+}
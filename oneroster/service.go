@@ -0,0 +1,52 @@
+package oneroster
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrUnauthorized = errors.New("token invalid or not found")
+	ErrBadRequest   = errors.New("the request is malformed or invalid")
+)
+
+// InvalidBundleError is returned when an uploaded bundle cannot be parsed or fails validation.
+type InvalidBundleError struct {
+	Err error
+}
+
+func (e InvalidBundleError) Error() string {
+	return "invalid OneRoster bundle: " + e.Err.Error()
+}
+
+// Summary describes the changes made by an import.
+type Summary struct {
+	ClassesCreated     int      `json:"classes_created"`
+	ClassesUpdated     int      `json:"classes_updated"`
+	ClassesDeactivated int      `json:"classes_deactivated"`
+	MembersAdded       int      `json:"members_added"`
+	MembersUpdated     int      `json:"members_updated"`
+	MembersRemoved     int      `json:"members_removed"`
+	MembersWaitlisted  int      `json:"members_waitlisted"`
+	Warnings           []string `json:"warnings,omitempty"`
+}
+
+// ExportOptions controls the org and academic session that exported classes are attached to.
+// classsvc does not track either, so a single org and term are written for the whole bundle.
+type ExportOptions struct {
+	OrgSourcedID  string
+	OrgName       string
+	TermSourcedID string
+	TermTitle     string
+	SchoolYear    string
+}
+
+// Service imports and exports OneRoster bundles.
+type Service interface {
+	// Import applies a OneRoster bundle to classes and members in a single transaction.
+	// Seat limits, waitlists, roster locks and enrollment windows apply to the changes it makes.
+	Import(ctx context.Context, r io.ReaderAt, size int64) (*Summary, error)
+	// Export writes all active classes and their members as a OneRoster bundle.
+	Export(ctx context.Context, w io.Writer, opts ExportOptions) error
+}
@@ -0,0 +1,512 @@
+package oneroster
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/models"
+)
+
+type postgresService struct {
+	*sql.DB
+	record classsvc.RecordFunc
+}
+
+func New(db *sql.DB) Service {
+	return &postgresService{db, nil}
+}
+
+// NewWithRecorder returns a Service that records every change an import makes to a class with
+// record, in the transaction of the import, like the classsvc Service does.
+func NewWithRecorder(db *sql.DB, record classsvc.RecordFunc) Service {
+	return &postgresService{db, record}
+}
+
+func (s *postgresService) Import(ctx context.Context, r io.ReaderAt, size int64) (*Summary, error) {
+	roster, err := Read(r, size)
+	if err != nil {
+		return nil, InvalidBundleError{err}
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := importRoster(ctx, classsvc.Provision(tx, s.record), tx, roster)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return summary, nil
+}
+
+// importRoster applies roster through p, so seat limits, waitlists, roster locks and enrollment
+// windows apply as they do to users, and every change is recorded. Changes they refuse are
+// skipped with a warning rather than failing the bundle.
+func importRoster(ctx context.Context, p *classsvc.Provisioning, tx *sql.Tx, roster *Roster) (*Summary, error) {
+	var summary Summary
+	warn := func(format string, args ...interface{}) {
+		summary.Warnings = append(summary.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	users := make(map[string]uuid.UUID, len(roster.Users))
+	for _, u := range roster.Users {
+		id, ok, err := resolveUser(tx, u.SourcedID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			warn("user %s: sourcedId is neither a user ID nor mapped onto one", u.SourcedID)
+			continue
+		}
+		users[u.SourcedID] = id
+	}
+
+	// classes maps sourcedIds onto active classes; deactivated classes are left out so
+	// that their enrollments are skipped. listed holds every class in the bundle.
+	classes := make(map[string]uuid.UUID, len(roster.Classes))
+	listed := make(map[uuid.UUID]bool, len(roster.Classes))
+	for _, c := range roster.Classes {
+		ref, err := models.ExternalRefBySourceKindExternalID(tx, Source, KindClass, c.SourcedID)
+		switch {
+		case err == sql.ErrNoRows:
+			if c.Status == StatusToBeDeleted {
+				continue
+			}
+			class, err := p.CreateClass(ctx, c.Title)
+			if err != nil {
+				return nil, err
+			}
+			ref := models.ExternalRef{Source: Source, Kind: KindClass, ExternalID: c.SourcedID, ID: class.ID}
+			if err := ref.Save(tx); err != nil {
+				return nil, err
+			}
+			classes[c.SourcedID] = class.ID
+			listed[class.ID] = true
+			summary.ClassesCreated++
+		case err != nil:
+			return nil, err
+		default:
+			listed[ref.ID] = true
+			class, err := models.ClassByID(tx, ref.ID)
+			if err != nil {
+				return nil, err
+			}
+			if c.Status == StatusToBeDeleted {
+				if class.Active {
+					if err := deactivate(ctx, p, tx, class.ID); err != nil {
+						return nil, err
+					}
+					summary.ClassesDeactivated++
+				}
+				continue
+			}
+			if class.Name != c.Title || !class.Active {
+				if err := p.ActivateClass(ctx, class.ID); err != nil {
+					return nil, err
+				}
+				if err := p.RenameClass(ctx, class.ID, c.Title); err != nil {
+					return nil, err
+				}
+				summary.ClassesUpdated++
+			}
+			classes[c.SourcedID] = class.ID
+		}
+	}
+	// A bulk classes.csv lists every class, so imported classes missing from it are deleted.
+	if roster.Bulk(FileClasses) {
+		n, err := deactivateUnlisted(ctx, p, tx, listed)
+		if err != nil {
+			return nil, err
+		}
+		summary.ClassesDeactivated += n
+	}
+
+	owned := make(map[uuid.UUID]bool)
+	enrolled := make(map[uuid.UUID]map[uuid.UUID]bool)
+	for _, e := range roster.Enrollments {
+		classID, ok := classes[e.ClassSourcedID]
+		if !ok {
+			continue
+		}
+		userID, ok := users[e.UserSourcedID]
+		if !ok {
+			continue
+		}
+		role, ok := TranslateRole(e.Role)
+		if !ok {
+			warn("enrollment %s: role %q has no class role", e.SourcedID, e.Role)
+			continue
+		}
+		if e.Status != StatusToBeDeleted {
+			if enrolled[classID] == nil {
+				enrolled[classID] = make(map[uuid.UUID]bool)
+			}
+			enrolled[classID][userID] = true
+		}
+		if _, ok := owned[classID]; !ok {
+			var hasOwner bool
+			err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM members WHERE class_id=$1 AND owner);", classID).Scan(&hasOwner)
+			if err != nil {
+				return nil, err
+			}
+			owned[classID] = hasOwner
+		}
+
+		member, err := models.MemberByUserIDClassID(tx, userID, classID)
+		switch {
+		case err == sql.ErrNoRows:
+			if e.Status == StatusToBeDeleted {
+				// Users still waiting for a seat leave the waitlist.
+				if err := removeMember(ctx, p, tx, classID, userID); err != nil && !refused(err) {
+					return nil, err
+				}
+				continue
+			}
+			// The first primary teacher in the bundle takes ownership of a class that has none.
+			if role == models.UserRoleTeacher && e.Primary && !owned[classID] {
+				err = p.SetOwner(ctx, classID, &userID)
+				if err == nil {
+					owned[classID] = true
+				}
+			} else {
+				err = p.Enroll(ctx, classID, userID, role)
+			}
+			switch {
+			case err == nil:
+				summary.MembersAdded++
+			case err == classsvc.ErrWaitlisted:
+				summary.MembersWaitlisted++
+			case refused(err):
+				warn("enrollment %s: %v", e.SourcedID, err)
+				continue
+			default:
+				return nil, err
+			}
+			// Only members the import added are recorded, so that a bulk import never
+			// removes users who joined the class some other way.
+			rm := models.RosterMember{Provider: Source, ClassID: classID, UserID: userID, Role: role}
+			if err := rm.Upsert(tx); err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		case e.Status == StatusToBeDeleted:
+			if member.Owner {
+				warn("enrollment %s: owner cannot be removed by import", e.SourcedID)
+				continue
+			}
+			err := removeMember(ctx, p, tx, classID, userID)
+			switch {
+			case err == nil:
+				summary.MembersRemoved++
+			case refused(err):
+				warn("enrollment %s: %v", e.SourcedID, err)
+			default:
+				return nil, err
+			}
+		case member.Role != role:
+			err := p.SetRole(ctx, classID, userID, role)
+			switch {
+			case err == nil:
+				summary.MembersUpdated++
+			case refused(err):
+				warn("enrollment %s: %v", e.SourcedID, err)
+			default:
+				return nil, err
+			}
+		}
+	}
+	// A bulk enrollments.csv lists every enrollment of the classes in the bundle, so members
+	// the import added who are missing from it are removed.
+	if roster.Bulk(FileEnrollments) {
+		for _, classID := range classes {
+			n, warnings, err := removeUnlisted(ctx, p, tx, classID, enrolled[classID])
+			if err != nil {
+				return nil, err
+			}
+			summary.MembersRemoved += n
+			summary.Warnings = append(summary.Warnings, warnings...)
+		}
+	}
+	return &summary, nil
+}
+
+// refused reports whether err is classsvc refusing a change to a roster, which an import skips
+// with a warning.
+func refused(err error) bool {
+	switch err {
+	case classsvc.ErrRosterLocked, classsvc.ErrEnrollmentClosed, classsvc.ErrClassFull, classsvc.ErrMustSetOwner:
+		return true
+	}
+	return false
+}
+
+// deactivate deactivates an imported class like DeleteClass does and forgets the members the
+// import added to it.
+func deactivate(ctx context.Context, p *classsvc.Provisioning, tx *sql.Tx, classID uuid.UUID) error {
+	if err := p.DeactivateClass(ctx, classID); err != nil {
+		return err
+	}
+	_, err := tx.Exec("DELETE FROM roster_members WHERE provider=$1 AND class_id=$2;", Source, classID)
+	return err
+}
+
+// deactivateUnlisted deactivates the active imported classes that are not in listed.
+func deactivateUnlisted(ctx context.Context, p *classsvc.Provisioning, tx *sql.Tx, listed map[uuid.UUID]bool) (int, error) {
+	rows, err := tx.Query("SELECT r.id FROM external_refs r JOIN classes c ON c.id = r.id WHERE r.source=$1 AND r.kind=$2 AND c.active;", Source, KindClass)
+	if err != nil {
+		return 0, err
+	}
+	var unlisted []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if !listed[id] {
+			unlisted = append(unlisted, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for _, id := range unlisted {
+		if err := deactivate(ctx, p, tx, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(unlisted), nil
+}
+
+// removeUnlisted removes the members of a class that an import added and that are not in
+// enrolled. Owners, and members classsvc refuses to remove, are kept with a warning.
+func removeUnlisted(ctx context.Context, p *classsvc.Provisioning, tx *sql.Tx, classID uuid.UUID, enrolled map[uuid.UUID]bool) (int, []string, error) {
+	managed, err := models.RosterMembersByProviderClassID(tx, Source, classID)
+	if err != nil {
+		return 0, nil, err
+	}
+	var (
+		removed  int
+		warnings []string
+	)
+	for _, rm := range managed {
+		if enrolled[rm.UserID] {
+			continue
+		}
+		member, err := models.MemberByUserIDClassID(tx, rm.UserID, classID)
+		switch {
+		case err == sql.ErrNoRows:
+			// The user already left, or is still waiting for a seat.
+			if err := removeMember(ctx, p, tx, classID, rm.UserID); err != nil && !refused(err) {
+				return 0, nil, err
+			}
+			continue
+		case err != nil:
+			return 0, nil, err
+		case member.Owner:
+			warnings = append(warnings, fmt.Sprintf("class %s: owner %s is missing from the bulk enrollments but cannot be removed by import", classID, rm.UserID))
+			continue
+		}
+		err = removeMember(ctx, p, tx, classID, rm.UserID)
+		switch {
+		case err == nil:
+			removed++
+		case refused(err):
+			warnings = append(warnings, fmt.Sprintf("class %s: member %s is missing from the bulk enrollments: %v", classID, rm.UserID, err))
+		default:
+			return 0, nil, err
+		}
+	}
+	return removed, warnings, nil
+}
+
+// removeMember removes a user from a class or its waitlist like LeaveClass does, along with the
+// record of the import having added them.
+func removeMember(ctx context.Context, p *classsvc.Provisioning, tx *sql.Tx, classID, userID uuid.UUID) error {
+	if err := p.Remove(ctx, classID, userID); err != nil {
+		return err
+	}
+	_, err := tx.Exec("DELETE FROM roster_members WHERE provider=$1 AND class_id=$2 AND user_id=$3;", Source, classID, userID)
+	return err
+}
+
+// resolveUser maps a user sourcedId onto a user ID. SourcedIds that are UUIDs are taken to be
+// user IDs; anything else must have been mapped onto a user ID in external_refs. Users are not
+// created by imports, so an unknown sourcedId resolves to nothing rather than to a made-up ID
+// no one can sign in as.
+func resolveUser(tx *sql.Tx, sourcedID string) (uuid.UUID, bool, error) {
+	if id, err := uuid.Parse(sourcedID); err == nil {
+		return id, true, nil
+	}
+	ref, err := models.ExternalRefBySourceKindExternalID(tx, Source, KindUser, sourcedID)
+	switch err {
+	case nil:
+		return ref.ID, true, nil
+	case sql.ErrNoRows:
+		return uuid.Nil, false, nil
+	default:
+		return uuid.Nil, false, err
+	}
+}
+
+// sourcedID is the inverse of resolveUser and of the class mapping of imports.
+func (s *postgresService) sourcedID(kind string, id uuid.UUID) (string, error) {
+	refs, err := models.ExternalRefsBySourceKindID(s, Source, kind, id)
+	if err != nil {
+		return "", err
+	}
+	if len(refs) > 0 {
+		return refs[0].ExternalID, nil
+	}
+	return id.String(), nil
+}
+
+func (s *postgresService) Export(ctx context.Context, w io.Writer, opts ExportOptions) error {
+	opts = opts.withDefaults()
+
+	rows, err := s.QueryContext(ctx, "SELECT id, name FROM classes WHERE active ORDER BY name;")
+	if err != nil {
+		return err
+	}
+	var classes []models.Class
+	for rows.Next() {
+		var c models.Class
+		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+			rows.Close()
+			return err
+		}
+		classes = append(classes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var (
+		courseRows     [][]string
+		classRows      [][]string
+		enrollmentRows [][]string
+		userOrder      []uuid.UUID
+		userRoles      = make(map[uuid.UUID]models.UserRole)
+		userSourcedIDs = make(map[uuid.UUID]string)
+	)
+	for _, class := range classes {
+		classSourcedID, err := s.sourcedID(KindClass, class.ID)
+		if err != nil {
+			return err
+		}
+		courseRows = append(courseRows, []string{classSourcedID, StatusActive, "", opts.TermSourcedID, class.Name, "", "", opts.OrgSourcedID, "", ""})
+		classRows = append(classRows, []string{classSourcedID, StatusActive, "", class.Name, "", classSourcedID, "", "scheduled", "", opts.OrgSourcedID, opts.TermSourcedID, "", "", ""})
+
+		members, err := models.MembersByClassID(s, class.ID)
+		if err != nil {
+			return err
+		}
+		for _, m := range members {
+			userSourcedID, ok := userSourcedIDs[m.UserID]
+			if !ok {
+				userSourcedID, err = s.sourcedID(KindUser, m.UserID)
+				if err != nil {
+					return err
+				}
+				userSourcedIDs[m.UserID] = userSourcedID
+				userOrder = append(userOrder, m.UserID)
+			}
+			if m.Role > userRoles[m.UserID] {
+				userRoles[m.UserID] = m.Role
+			}
+			enrollmentRows = append(enrollmentRows, []string{
+				classSourcedID + "-" + userSourcedID, StatusActive, "", classSourcedID, opts.OrgSourcedID,
+				userSourcedID, m.Role.String(), strconv.FormatBool(m.Owner), "", "",
+			})
+		}
+	}
+	var userRows [][]string
+	for _, id := range userOrder {
+		userRows = append(userRows, []string{userSourcedIDs[id], StatusActive, "", "true", opts.OrgSourcedID, userRoles[id].String(), "", "", "", "", "", "", "", "", "", "", "", ""})
+	}
+
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name   string
+		header []string
+		rows   [][]string
+	}{
+		{FileManifest, []string{"propertyName", "value"}, [][]string{
+			{"manifest.version", "1.0"},
+			{"oneroster.version", "1.1"},
+			{"file.academicSessions", "bulk"},
+			{"file.categories", "absent"},
+			{"file.classes", "bulk"},
+			{"file.classResources", "absent"},
+			{"file.courses", "bulk"},
+			{"file.courseResources", "absent"},
+			{"file.demographics", "absent"},
+			{"file.enrollments", "bulk"},
+			{"file.lineItems", "absent"},
+			{"file.orgs", "bulk"},
+			{"file.resources", "absent"},
+			{"file.results", "absent"},
+			{"file.users", "bulk"},
+			{"source.systemName", "classsvc"},
+		}},
+		{FileOrgs, []string{"sourcedId", "status", "dateLastModified", "name", "type", "identifier", "parentSourcedId"}, [][]string{
+			{opts.OrgSourcedID, StatusActive, "", opts.OrgName, "school", "", ""},
+		}},
+		{FileAcademicSessions, []string{"sourcedId", "status", "dateLastModified", "title", "type", "startDate", "endDate", "parentSourcedId", "schoolYear"}, [][]string{
+			{opts.TermSourcedID, StatusActive, "", opts.TermTitle, "schoolYear", "", "", "", opts.SchoolYear},
+		}},
+		{FileCourses, []string{"sourcedId", "status", "dateLastModified", "schoolYearSourcedId", "title", "courseCode", "grades", "orgSourcedId", "subjects", "subjectCodes"}, courseRows},
+		{FileClasses, []string{"sourcedId", "status", "dateLastModified", "title", "grades", "courseSourcedId", "classCode", "classType", "location", "schoolSourcedId", "termSourcedIds", "subjects", "subjectCodes", "periods"}, classRows},
+		{FileUsers, []string{"sourcedId", "status", "dateLastModified", "enabledUser", "orgSourcedIds", "role", "username", "userIds", "givenName", "familyName", "middleName", "identifier", "email", "sms", "phone", "agentSourcedIds", "grades", "password"}, userRows},
+		{FileEnrollments, []string{"sourcedId", "status", "dateLastModified", "classSourcedId", "schoolSourcedId", "userSourcedId", "role", "primary", "beginDate", "endDate"}, enrollmentRows},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		cw := csv.NewWriter(fw)
+		cw.Write(f.header)
+		cw.WriteAll(f.rows)
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (o ExportOptions) withDefaults() ExportOptions {
+	if o.OrgSourcedID == "" {
+		o.OrgSourcedID = "classsvc"
+	}
+	if o.OrgName == "" {
+		o.OrgName = "Studiously"
+	}
+	if o.TermSourcedID == "" {
+		o.TermSourcedID = "classsvc-term"
+	}
+	if o.TermTitle == "" {
+		o.TermTitle = "Current term"
+	}
+	if o.SchoolYear == "" {
+		o.SchoolYear = strconv.Itoa(time.Now().Year())
+	}
+	return o
+}
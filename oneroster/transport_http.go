@@ -0,0 +1,146 @@
+package oneroster
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/introspector"
+)
+
+// maxBundleSize limits the size of uploaded bundles.
+const maxBundleSize = 32 << 20
+
+func MakeHTTPHandler(s Service, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorLogger(logger),
+		httptransport.ServerErrorEncoder(encodeError),
+		httptransport.ServerBefore(introspector.ToHTTPContext()),
+	}
+
+	// POST /oneroster/import
+	// Apply a OneRoster bundle, uploaded either as the raw request body or as the "bundle" form file.
+	r.Methods("POST").Path("/oneroster/import").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.oneroster.import")(e.ImportEndpoint),
+		DecodeImportRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// GET /oneroster/export
+	// Download all active classes as a OneRoster bundle.
+	r.Methods("GET").Path("/oneroster/export").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.oneroster.export")(e.ExportEndpoint),
+		DecodeExportRequest,
+		encodeExportResponse,
+		options...,
+	))
+
+	return classsvc.WithCorrelationID(r, logger)
+}
+
+func DecodeImportRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxBundleSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		f, _, err := r.FormFile("bundle")
+		if err != nil {
+			return nil, ErrBadRequest
+		}
+		defer f.Close()
+		bundle, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, ErrBadRequest
+		}
+		return importRequest{bundle}, nil
+	}
+	bundle, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(bundle) == 0 {
+		return nil, ErrBadRequest
+	}
+	return importRequest{bundle}, nil
+}
+
+func DecodeExportRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	return exportRequest{ExportOptions{
+		OrgSourcedID:  q.Get("org"),
+		OrgName:       q.Get("org_name"),
+		TermSourcedID: q.Get("term"),
+		TermTitle:     q.Get("term_title"),
+		SchoolYear:    q.Get("school_year"),
+	}}, nil
+}
+
+func encodeExportResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(exportResponse)
+	if resp.Error != nil {
+		encodeError(ctx, resp.Error, w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="oneroster.zip"`)
+	_, err := w.Write(resp.Bundle)
+	return err
+}
+
+// errorer is implemented by all concrete response types that may contain
+// errors.
+type errorer interface {
+	error() error
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if e, ok := response.(errorer); ok && e.error() != nil {
+		encodeError(ctx, e.error(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+// problems answers errors as problem+json, like the class service does.
+var problems = classsvc.Problems{Code: errorCode, Status: codeFrom}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	problems.EncodeError(ctx, err, w)
+}
+
+func codeFrom(err error) int {
+	switch err.(type) {
+	case InvalidBundleError:
+		return http.StatusBadRequest
+	}
+	switch err {
+	case ErrBadRequest:
+		return http.StatusBadRequest
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorCode gives the stable code of err, which clients can match on.
+func errorCode(err error) string {
+	switch err.(type) {
+	case InvalidBundleError:
+		return "invalid_bundle"
+	}
+	switch err {
+	case ErrBadRequest:
+		return "bad_request"
+	case ErrUnauthorized:
+		return "unauthorized"
+	default:
+		return ""
+	}
+}
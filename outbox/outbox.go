@@ -0,0 +1,236 @@
+// Package outbox publishes events reliably. Events are written to the outbox table in the
+// same transaction as the change they describe, and a Relay publishes them afterwards,
+// retrying until the broker accepts them.
+//
+// Events with the same ordering key are published in the order they were written. An event
+// that keeps failing is moved to outbox_dead_letters after MaxAttempts, which lets the events
+// behind it through.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/studiously/classsvc/eventbus"
+)
+
+// Write adds an event to the outbox as part of tx.
+func Write(tx *sql.Tx, subject, orderingKey string, payload []byte) error {
+	_, err := tx.Exec("INSERT INTO outbox (subject, ordering_key, payload) VALUES ($1, $2, $3);", subject, orderingKey, payload)
+	return err
+}
+
+// Report counts what a Relay did in one pass.
+type Report struct {
+	Published    int
+	Failed       int
+	DeadLettered int
+}
+
+// Relay publishes events from the outbox.
+type Relay struct {
+	db  *sql.DB
+	bus eventbus.EventBus
+
+	// MaxAttempts is the number of times an event is tried before it is dead-lettered.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the delay before an event is retried, which doubles
+	// with every failed attempt.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// BatchSize limits the number of events published per pass.
+	BatchSize int
+}
+
+// NewRelay returns a Relay with default limits that publishes events to bus.
+func NewRelay(db *sql.DB, bus eventbus.EventBus) *Relay {
+	return &Relay{
+		db:          db,
+		bus:         bus,
+		MaxAttempts: 10,
+		MinBackoff:  time.Second,
+		MaxBackoff:  10 * time.Minute,
+		BatchSize:   100,
+	}
+}
+
+type message struct {
+	id       int64
+	subject  string
+	payload  []byte
+	attempts int
+}
+
+// Relay makes one pass over the outbox, trying the oldest pending event of every ordering key
+// that is due. Several relays may run at once; each event is claimed by one of them.
+func (r *Relay) Relay(ctx context.Context) (Report, error) {
+	var report Report
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return report, err
+	}
+	rows, err := tx.Query("SELECT o.id, o.subject, o.payload, o.attempts FROM outbox o "+
+		"WHERE o.next_attempt_at <= now() "+
+		"AND NOT EXISTS (SELECT 1 FROM outbox p WHERE p.ordering_key = o.ordering_key AND p.id < o.id) "+
+		"ORDER BY o.id LIMIT $1 FOR UPDATE SKIP LOCKED;", r.BatchSize)
+	if err != nil {
+		tx.Rollback()
+		return report, err
+	}
+	var messages []message
+	for rows.Next() {
+		var m message
+		if err := rows.Scan(&m.id, &m.subject, &m.payload, &m.attempts); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return report, err
+		}
+		messages = append(messages, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback()
+		return report, err
+	}
+	for _, m := range messages {
+		published, err := r.relay(ctx, tx, m, &report)
+		if err != nil {
+			tx.Rollback()
+			return Report{}, err
+		}
+		if !published {
+			// The broker is most likely unavailable; leave the rest for the next pass rather
+			// than waiting on every event.
+			break
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return Report{}, err
+	}
+	return report, nil
+}
+
+// relay publishes a message, then deletes it, schedules a retry or dead-letters it.
+// It reports whether the message was published.
+func (r *Relay) relay(ctx context.Context, tx *sql.Tx, m message, report *Report) (bool, error) {
+	publishErr := r.bus.Publish(ctx, m.subject, m.payload)
+	if publishErr == nil {
+		report.Published++
+		_, err := tx.Exec("DELETE FROM outbox WHERE id=$1;", m.id)
+		return true, err
+	}
+	m.attempts++
+	if m.attempts >= r.MaxAttempts {
+		report.DeadLettered++
+		_, err := tx.Exec("INSERT INTO outbox_dead_letters (id, subject, ordering_key, payload, created_at, attempts, last_error) "+
+			"SELECT id, subject, ordering_key, payload, created_at, $2, $3 FROM outbox WHERE id=$1;", m.id, m.attempts, publishErr.Error())
+		if err != nil {
+			return false, err
+		}
+		_, err = tx.Exec("DELETE FROM outbox WHERE id=$1;", m.id)
+		return false, err
+	}
+	report.Failed++
+	_, err := tx.Exec("UPDATE outbox SET attempts=$2, next_attempt_at=$3, last_error=$4 WHERE id=$1;",
+		m.id, m.attempts, time.Now().Add(r.backoff(m.attempts)), publishErr.Error())
+	return false, err
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.MinBackoff
+	for i := 1; i < attempts && d < r.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+	return d
+}
+
+// Run relays events every interval until ctx is done. A pass that publishes anything is
+// followed immediately by another, so that events queued behind it are not held back.
+func (r *Relay) Run(ctx context.Context, interval time.Duration, logger log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := r.Relay(ctx)
+		if err != nil {
+			logger.Log("error", err)
+		} else if report.Published+report.Failed+report.DeadLettered > 0 {
+			logger.Log(
+				"published", report.Published,
+				"failed", report.Failed,
+				"dead_lettered", report.DeadLettered,
+			)
+		}
+		if err == nil && report.Published > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				continue
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Redrive moves a dead-lettered event back into the outbox to be tried again.
+func Redrive(db *sql.DB, id int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	res, err := tx.Exec("INSERT INTO outbox (subject, ordering_key, payload, created_at) "+
+		"SELECT subject, ordering_key, payload, created_at FROM outbox_dead_letters WHERE id=$1;", id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return sql.ErrNoRows
+	}
+	_, err = tx.Exec("DELETE FROM outbox_dead_letters WHERE id=$1;", id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeadLetter is an event that could not be published.
+type DeadLetter struct {
+	ID        int64
+	Subject   string
+	Attempts  int
+	LastError string
+	FailedAt  time.Time
+}
+
+// DeadLetters lists dead-lettered events, oldest first.
+func DeadLetters(db *sql.DB) ([]DeadLetter, error) {
+	rows, err := db.Query("SELECT id, subject, attempts, last_error, failed_at FROM outbox_dead_letters ORDER BY id;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var letters []DeadLetter
+	for rows.Next() {
+		var l DeadLetter
+		if err := rows.Scan(&l.ID, &l.Subject, &l.Attempts, &l.LastError, &l.FailedAt); err != nil {
+			return nil, err
+		}
+		letters = append(letters, l)
+	}
+	return letters, rows.Err()
+}
@@ -0,0 +1,34 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	r := &Relay{MinBackoff: time.Second, MaxBackoff: time.Minute}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{100, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := r.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffMinAboveMax(t *testing.T) {
+	r := &Relay{MinBackoff: time.Hour, MaxBackoff: time.Minute}
+	if got := r.backoff(1); got != time.Minute {
+		t.Errorf("backoff(1) = %v, want %v", got, time.Minute)
+	}
+}
@@ -0,0 +1,2534 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: classsvc.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Role int32
+
+const (
+	Role_ROLE_UNSPECIFIED Role = 0
+	Role_STUDENT          Role = 1
+	Role_TEACHER          Role = 2
+)
+
+var Role_name = map[int32]string{
+	0: "ROLE_UNSPECIFIED",
+	1: "STUDENT",
+	2: "TEACHER",
+}
+
+var Role_value = map[string]int32{
+	"ROLE_UNSPECIFIED": 0,
+	"STUDENT":          1,
+	"TEACHER":          2,
+}
+
+func (x Role) String() string {
+	return proto.EnumName(Role_name, int32(x))
+}
+
+func (Role) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{0}
+}
+
+type Class struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CurrentUnit          string   `protobuf:"bytes,3,opt,name=current_unit,json=currentUnit,proto3" json:"current_unit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Class) Reset()         { *m = Class{} }
+func (m *Class) String() string { return proto.CompactTextString(m) }
+func (*Class) ProtoMessage()    {}
+func (*Class) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{0}
+}
+
+func (m *Class) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Class.Unmarshal(m, b)
+}
+func (m *Class) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Class.Marshal(b, m, deterministic)
+}
+func (m *Class) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Class.Merge(m, src)
+}
+func (m *Class) XXX_Size() int {
+	return xxx_messageInfo_Class.Size(m)
+}
+func (m *Class) XXX_DiscardUnknown() {
+	xxx_messageInfo_Class.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Class proto.InternalMessageInfo
+
+func (m *Class) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Class) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Class) GetCurrentUnit() string {
+	if m != nil {
+		return m.CurrentUnit
+	}
+	return ""
+}
+
+type Member struct {
+	UserId               string               `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ClassId              string               `protobuf:"bytes,2,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	Role                 Role                 `protobuf:"varint,3,opt,name=role,proto3,enum=pb.Role" json:"role,omitempty"`
+	Owner                bool                 `protobuf:"varint,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	JoinedAt             *timestamp.Timestamp `protobuf:"bytes,5,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Member) Reset()         { *m = Member{} }
+func (m *Member) String() string { return proto.CompactTextString(m) }
+func (*Member) ProtoMessage()    {}
+func (*Member) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{1}
+}
+
+func (m *Member) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Member.Unmarshal(m, b)
+}
+func (m *Member) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Member.Marshal(b, m, deterministic)
+}
+func (m *Member) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Member.Merge(m, src)
+}
+func (m *Member) XXX_Size() int {
+	return xxx_messageInfo_Member.Size(m)
+}
+func (m *Member) XXX_DiscardUnknown() {
+	xxx_messageInfo_Member.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Member proto.InternalMessageInfo
+
+func (m *Member) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Member) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+func (m *Member) GetRole() Role {
+	if m != nil {
+		return m.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (m *Member) GetOwner() bool {
+	if m != nil {
+		return m.Owner
+	}
+	return false
+}
+
+func (m *Member) GetJoinedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.JoinedAt
+	}
+	return nil
+}
+
+type WaitlistEntry struct {
+	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ClassId              string               `protobuf:"bytes,2,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	UserId               string               `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role                 Role                 `protobuf:"varint,4,opt,name=role,proto3,enum=pb.Role" json:"role,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *WaitlistEntry) Reset()         { *m = WaitlistEntry{} }
+func (m *WaitlistEntry) String() string { return proto.CompactTextString(m) }
+func (*WaitlistEntry) ProtoMessage()    {}
+func (*WaitlistEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{2}
+}
+
+func (m *WaitlistEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WaitlistEntry.Unmarshal(m, b)
+}
+func (m *WaitlistEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WaitlistEntry.Marshal(b, m, deterministic)
+}
+func (m *WaitlistEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WaitlistEntry.Merge(m, src)
+}
+func (m *WaitlistEntry) XXX_Size() int {
+	return xxx_messageInfo_WaitlistEntry.Size(m)
+}
+func (m *WaitlistEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_WaitlistEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WaitlistEntry proto.InternalMessageInfo
+
+func (m *WaitlistEntry) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *WaitlistEntry) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+func (m *WaitlistEntry) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *WaitlistEntry) GetRole() Role {
+	if m != nil {
+		return m.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (m *WaitlistEntry) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+type Capacity struct {
+	// Unset means unlimited.
+	Seats *wrappers.Int32Value `protobuf:"bytes,1,opt,name=seats,proto3" json:"seats,omitempty"`
+	// Keyed by role name, such as "student".
+	Roles                map[string]int32 `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Capacity) Reset()         { *m = Capacity{} }
+func (m *Capacity) String() string { return proto.CompactTextString(m) }
+func (*Capacity) ProtoMessage()    {}
+func (*Capacity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{3}
+}
+
+func (m *Capacity) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Capacity.Unmarshal(m, b)
+}
+func (m *Capacity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Capacity.Marshal(b, m, deterministic)
+}
+func (m *Capacity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Capacity.Merge(m, src)
+}
+func (m *Capacity) XXX_Size() int {
+	return xxx_messageInfo_Capacity.Size(m)
+}
+func (m *Capacity) XXX_DiscardUnknown() {
+	xxx_messageInfo_Capacity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Capacity proto.InternalMessageInfo
+
+func (m *Capacity) GetSeats() *wrappers.Int32Value {
+	if m != nil {
+		return m.Seats
+	}
+	return nil
+}
+
+func (m *Capacity) GetRoles() map[string]int32 {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+type EnrollmentSettings struct {
+	// Unset bounds leave the window open-ended.
+	OpensAt              *timestamp.Timestamp `protobuf:"bytes,1,opt,name=opens_at,json=opensAt,proto3" json:"opens_at,omitempty"`
+	ClosesAt             *timestamp.Timestamp `protobuf:"bytes,2,opt,name=closes_at,json=closesAt,proto3" json:"closes_at,omitempty"`
+	Locked               bool                 `protobuf:"varint,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *EnrollmentSettings) Reset()         { *m = EnrollmentSettings{} }
+func (m *EnrollmentSettings) String() string { return proto.CompactTextString(m) }
+func (*EnrollmentSettings) ProtoMessage()    {}
+func (*EnrollmentSettings) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{4}
+}
+
+func (m *EnrollmentSettings) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnrollmentSettings.Unmarshal(m, b)
+}
+func (m *EnrollmentSettings) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnrollmentSettings.Marshal(b, m, deterministic)
+}
+func (m *EnrollmentSettings) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnrollmentSettings.Merge(m, src)
+}
+func (m *EnrollmentSettings) XXX_Size() int {
+	return xxx_messageInfo_EnrollmentSettings.Size(m)
+}
+func (m *EnrollmentSettings) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnrollmentSettings.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnrollmentSettings proto.InternalMessageInfo
+
+func (m *EnrollmentSettings) GetOpensAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.OpensAt
+	}
+	return nil
+}
+
+func (m *EnrollmentSettings) GetClosesAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ClosesAt
+	}
+	return nil
+}
+
+func (m *EnrollmentSettings) GetLocked() bool {
+	if m != nil {
+		return m.Locked
+	}
+	return false
+}
+
+type ListClassesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListClassesRequest) Reset()         { *m = ListClassesRequest{} }
+func (m *ListClassesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListClassesRequest) ProtoMessage()    {}
+func (*ListClassesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{5}
+}
+
+func (m *ListClassesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListClassesRequest.Unmarshal(m, b)
+}
+func (m *ListClassesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListClassesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListClassesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListClassesRequest.Merge(m, src)
+}
+func (m *ListClassesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListClassesRequest.Size(m)
+}
+func (m *ListClassesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListClassesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListClassesRequest proto.InternalMessageInfo
+
+type ListClassesReply struct {
+	Classes              []string `protobuf:"bytes,1,rep,name=classes,proto3" json:"classes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListClassesReply) Reset()         { *m = ListClassesReply{} }
+func (m *ListClassesReply) String() string { return proto.CompactTextString(m) }
+func (*ListClassesReply) ProtoMessage()    {}
+func (*ListClassesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{6}
+}
+
+func (m *ListClassesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListClassesReply.Unmarshal(m, b)
+}
+func (m *ListClassesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListClassesReply.Marshal(b, m, deterministic)
+}
+func (m *ListClassesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListClassesReply.Merge(m, src)
+}
+func (m *ListClassesReply) XXX_Size() int {
+	return xxx_messageInfo_ListClassesReply.Size(m)
+}
+func (m *ListClassesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListClassesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListClassesReply proto.InternalMessageInfo
+
+func (m *ListClassesReply) GetClasses() []string {
+	if m != nil {
+		return m.Classes
+	}
+	return nil
+}
+
+type GetClassRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetClassRequest) Reset()         { *m = GetClassRequest{} }
+func (m *GetClassRequest) String() string { return proto.CompactTextString(m) }
+func (*GetClassRequest) ProtoMessage()    {}
+func (*GetClassRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{7}
+}
+
+func (m *GetClassRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetClassRequest.Unmarshal(m, b)
+}
+func (m *GetClassRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetClassRequest.Marshal(b, m, deterministic)
+}
+func (m *GetClassRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetClassRequest.Merge(m, src)
+}
+func (m *GetClassRequest) XXX_Size() int {
+	return xxx_messageInfo_GetClassRequest.Size(m)
+}
+func (m *GetClassRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetClassRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetClassRequest proto.InternalMessageInfo
+
+func (m *GetClassRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+type GetClassReply struct {
+	Class                *Class   `protobuf:"bytes,1,opt,name=class,proto3" json:"class,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetClassReply) Reset()         { *m = GetClassReply{} }
+func (m *GetClassReply) String() string { return proto.CompactTextString(m) }
+func (*GetClassReply) ProtoMessage()    {}
+func (*GetClassReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{8}
+}
+
+func (m *GetClassReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetClassReply.Unmarshal(m, b)
+}
+func (m *GetClassReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetClassReply.Marshal(b, m, deterministic)
+}
+func (m *GetClassReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetClassReply.Merge(m, src)
+}
+func (m *GetClassReply) XXX_Size() int {
+	return xxx_messageInfo_GetClassReply.Size(m)
+}
+func (m *GetClassReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetClassReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetClassReply proto.InternalMessageInfo
+
+func (m *GetClassReply) GetClass() *Class {
+	if m != nil {
+		return m.Class
+	}
+	return nil
+}
+
+type CreateClassRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateClassRequest) Reset()         { *m = CreateClassRequest{} }
+func (m *CreateClassRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateClassRequest) ProtoMessage()    {}
+func (*CreateClassRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{9}
+}
+
+func (m *CreateClassRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateClassRequest.Unmarshal(m, b)
+}
+func (m *CreateClassRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateClassRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateClassRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateClassRequest.Merge(m, src)
+}
+func (m *CreateClassRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateClassRequest.Size(m)
+}
+func (m *CreateClassRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateClassRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateClassRequest proto.InternalMessageInfo
+
+func (m *CreateClassRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type CreateClassReply struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateClassReply) Reset()         { *m = CreateClassReply{} }
+func (m *CreateClassReply) String() string { return proto.CompactTextString(m) }
+func (*CreateClassReply) ProtoMessage()    {}
+func (*CreateClassReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{10}
+}
+
+func (m *CreateClassReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateClassReply.Unmarshal(m, b)
+}
+func (m *CreateClassReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateClassReply.Marshal(b, m, deterministic)
+}
+func (m *CreateClassReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateClassReply.Merge(m, src)
+}
+func (m *CreateClassReply) XXX_Size() int {
+	return xxx_messageInfo_CreateClassReply.Size(m)
+}
+func (m *CreateClassReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateClassReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateClassReply proto.InternalMessageInfo
+
+func (m *CreateClassReply) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+type UpdateClassRequest struct {
+	ClassId string `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	// Unset fields are left unchanged.
+	Name                 *wrappers.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CurrentUnit          *wrappers.StringValue `protobuf:"bytes,3,opt,name=current_unit,json=currentUnit,proto3" json:"current_unit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UpdateClassRequest) Reset()         { *m = UpdateClassRequest{} }
+func (m *UpdateClassRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateClassRequest) ProtoMessage()    {}
+func (*UpdateClassRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{11}
+}
+
+func (m *UpdateClassRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateClassRequest.Unmarshal(m, b)
+}
+func (m *UpdateClassRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateClassRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateClassRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateClassRequest.Merge(m, src)
+}
+func (m *UpdateClassRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateClassRequest.Size(m)
+}
+func (m *UpdateClassRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateClassRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateClassRequest proto.InternalMessageInfo
+
+func (m *UpdateClassRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+func (m *UpdateClassRequest) GetName() *wrappers.StringValue {
+	if m != nil {
+		return m.Name
+	}
+	return nil
+}
+
+func (m *UpdateClassRequest) GetCurrentUnit() *wrappers.StringValue {
+	if m != nil {
+		return m.CurrentUnit
+	}
+	return nil
+}
+
+type UpdateClassReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateClassReply) Reset()         { *m = UpdateClassReply{} }
+func (m *UpdateClassReply) String() string { return proto.CompactTextString(m) }
+func (*UpdateClassReply) ProtoMessage()    {}
+func (*UpdateClassReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{12}
+}
+
+func (m *UpdateClassReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateClassReply.Unmarshal(m, b)
+}
+func (m *UpdateClassReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateClassReply.Marshal(b, m, deterministic)
+}
+func (m *UpdateClassReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateClassReply.Merge(m, src)
+}
+func (m *UpdateClassReply) XXX_Size() int {
+	return xxx_messageInfo_UpdateClassReply.Size(m)
+}
+func (m *UpdateClassReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateClassReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateClassReply proto.InternalMessageInfo
+
+type DeleteClassRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteClassRequest) Reset()         { *m = DeleteClassRequest{} }
+func (m *DeleteClassRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteClassRequest) ProtoMessage()    {}
+func (*DeleteClassRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{13}
+}
+
+func (m *DeleteClassRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteClassRequest.Unmarshal(m, b)
+}
+func (m *DeleteClassRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteClassRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteClassRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteClassRequest.Merge(m, src)
+}
+func (m *DeleteClassRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteClassRequest.Size(m)
+}
+func (m *DeleteClassRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteClassRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteClassRequest proto.InternalMessageInfo
+
+func (m *DeleteClassRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+type DeleteClassReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteClassReply) Reset()         { *m = DeleteClassReply{} }
+func (m *DeleteClassReply) String() string { return proto.CompactTextString(m) }
+func (*DeleteClassReply) ProtoMessage()    {}
+func (*DeleteClassReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{14}
+}
+
+func (m *DeleteClassReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteClassReply.Unmarshal(m, b)
+}
+func (m *DeleteClassReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteClassReply.Marshal(b, m, deterministic)
+}
+func (m *DeleteClassReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteClassReply.Merge(m, src)
+}
+func (m *DeleteClassReply) XXX_Size() int {
+	return xxx_messageInfo_DeleteClassReply.Size(m)
+}
+func (m *DeleteClassReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteClassReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteClassReply proto.InternalMessageInfo
+
+type JoinClassRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JoinClassRequest) Reset()         { *m = JoinClassRequest{} }
+func (m *JoinClassRequest) String() string { return proto.CompactTextString(m) }
+func (*JoinClassRequest) ProtoMessage()    {}
+func (*JoinClassRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{15}
+}
+
+func (m *JoinClassRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JoinClassRequest.Unmarshal(m, b)
+}
+func (m *JoinClassRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JoinClassRequest.Marshal(b, m, deterministic)
+}
+func (m *JoinClassRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JoinClassRequest.Merge(m, src)
+}
+func (m *JoinClassRequest) XXX_Size() int {
+	return xxx_messageInfo_JoinClassRequest.Size(m)
+}
+func (m *JoinClassRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_JoinClassRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JoinClassRequest proto.InternalMessageInfo
+
+func (m *JoinClassRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+type JoinClassReply struct {
+	// Set when the class was full and the caller was put on its waitlist instead.
+	Waitlisted           bool     `protobuf:"varint,1,opt,name=waitlisted,proto3" json:"waitlisted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JoinClassReply) Reset()         { *m = JoinClassReply{} }
+func (m *JoinClassReply) String() string { return proto.CompactTextString(m) }
+func (*JoinClassReply) ProtoMessage()    {}
+func (*JoinClassReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{16}
+}
+
+func (m *JoinClassReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JoinClassReply.Unmarshal(m, b)
+}
+func (m *JoinClassReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JoinClassReply.Marshal(b, m, deterministic)
+}
+func (m *JoinClassReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JoinClassReply.Merge(m, src)
+}
+func (m *JoinClassReply) XXX_Size() int {
+	return xxx_messageInfo_JoinClassReply.Size(m)
+}
+func (m *JoinClassReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_JoinClassReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JoinClassReply proto.InternalMessageInfo
+
+func (m *JoinClassReply) GetWaitlisted() bool {
+	if m != nil {
+		return m.Waitlisted
+	}
+	return false
+}
+
+type LeaveClassRequest struct {
+	ClassId string `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	// Set to remove another user; empty removes the caller.
+	UserId               string   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaveClassRequest) Reset()         { *m = LeaveClassRequest{} }
+func (m *LeaveClassRequest) String() string { return proto.CompactTextString(m) }
+func (*LeaveClassRequest) ProtoMessage()    {}
+func (*LeaveClassRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{17}
+}
+
+func (m *LeaveClassRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaveClassRequest.Unmarshal(m, b)
+}
+func (m *LeaveClassRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaveClassRequest.Marshal(b, m, deterministic)
+}
+func (m *LeaveClassRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaveClassRequest.Merge(m, src)
+}
+func (m *LeaveClassRequest) XXX_Size() int {
+	return xxx_messageInfo_LeaveClassRequest.Size(m)
+}
+func (m *LeaveClassRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaveClassRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaveClassRequest proto.InternalMessageInfo
+
+func (m *LeaveClassRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+func (m *LeaveClassRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type LeaveClassReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaveClassReply) Reset()         { *m = LeaveClassReply{} }
+func (m *LeaveClassReply) String() string { return proto.CompactTextString(m) }
+func (*LeaveClassReply) ProtoMessage()    {}
+func (*LeaveClassReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{18}
+}
+
+func (m *LeaveClassReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaveClassReply.Unmarshal(m, b)
+}
+func (m *LeaveClassReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaveClassReply.Marshal(b, m, deterministic)
+}
+func (m *LeaveClassReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaveClassReply.Merge(m, src)
+}
+func (m *LeaveClassReply) XXX_Size() int {
+	return xxx_messageInfo_LeaveClassReply.Size(m)
+}
+func (m *LeaveClassReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaveClassReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaveClassReply proto.InternalMessageInfo
+
+type SetRoleRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role                 Role     `protobuf:"varint,3,opt,name=role,proto3,enum=pb.Role" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetRoleRequest) Reset()         { *m = SetRoleRequest{} }
+func (m *SetRoleRequest) String() string { return proto.CompactTextString(m) }
+func (*SetRoleRequest) ProtoMessage()    {}
+func (*SetRoleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{19}
+}
+
+func (m *SetRoleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRoleRequest.Unmarshal(m, b)
+}
+func (m *SetRoleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRoleRequest.Marshal(b, m, deterministic)
+}
+func (m *SetRoleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRoleRequest.Merge(m, src)
+}
+func (m *SetRoleRequest) XXX_Size() int {
+	return xxx_messageInfo_SetRoleRequest.Size(m)
+}
+func (m *SetRoleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRoleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRoleRequest proto.InternalMessageInfo
+
+func (m *SetRoleRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+func (m *SetRoleRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *SetRoleRequest) GetRole() Role {
+	if m != nil {
+		return m.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+type SetRoleReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetRoleReply) Reset()         { *m = SetRoleReply{} }
+func (m *SetRoleReply) String() string { return proto.CompactTextString(m) }
+func (*SetRoleReply) ProtoMessage()    {}
+func (*SetRoleReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{20}
+}
+
+func (m *SetRoleReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRoleReply.Unmarshal(m, b)
+}
+func (m *SetRoleReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRoleReply.Marshal(b, m, deterministic)
+}
+func (m *SetRoleReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRoleReply.Merge(m, src)
+}
+func (m *SetRoleReply) XXX_Size() int {
+	return xxx_messageInfo_SetRoleReply.Size(m)
+}
+func (m *SetRoleReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRoleReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRoleReply proto.InternalMessageInfo
+
+type ListMembersRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListMembersRequest) Reset()         { *m = ListMembersRequest{} }
+func (m *ListMembersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListMembersRequest) ProtoMessage()    {}
+func (*ListMembersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{21}
+}
+
+func (m *ListMembersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMembersRequest.Unmarshal(m, b)
+}
+func (m *ListMembersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMembersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListMembersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMembersRequest.Merge(m, src)
+}
+func (m *ListMembersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListMembersRequest.Size(m)
+}
+func (m *ListMembersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMembersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMembersRequest proto.InternalMessageInfo
+
+func (m *ListMembersRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+type ListMembersReply struct {
+	Members              []*Member `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListMembersReply) Reset()         { *m = ListMembersReply{} }
+func (m *ListMembersReply) String() string { return proto.CompactTextString(m) }
+func (*ListMembersReply) ProtoMessage()    {}
+func (*ListMembersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{22}
+}
+
+func (m *ListMembersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMembersReply.Unmarshal(m, b)
+}
+func (m *ListMembersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMembersReply.Marshal(b, m, deterministic)
+}
+func (m *ListMembersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMembersReply.Merge(m, src)
+}
+func (m *ListMembersReply) XXX_Size() int {
+	return xxx_messageInfo_ListMembersReply.Size(m)
+}
+func (m *ListMembersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMembersReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMembersReply proto.InternalMessageInfo
+
+func (m *ListMembersReply) GetMembers() []*Member {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+type GetMemberRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMemberRequest) Reset()         { *m = GetMemberRequest{} }
+func (m *GetMemberRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMemberRequest) ProtoMessage()    {}
+func (*GetMemberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{23}
+}
+
+func (m *GetMemberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMemberRequest.Unmarshal(m, b)
+}
+func (m *GetMemberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMemberRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMemberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMemberRequest.Merge(m, src)
+}
+func (m *GetMemberRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMemberRequest.Size(m)
+}
+func (m *GetMemberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMemberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMemberRequest proto.InternalMessageInfo
+
+func (m *GetMemberRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+func (m *GetMemberRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type GetMemberReply struct {
+	Member               *Member  `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMemberReply) Reset()         { *m = GetMemberReply{} }
+func (m *GetMemberReply) String() string { return proto.CompactTextString(m) }
+func (*GetMemberReply) ProtoMessage()    {}
+func (*GetMemberReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{24}
+}
+
+func (m *GetMemberReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMemberReply.Unmarshal(m, b)
+}
+func (m *GetMemberReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMemberReply.Marshal(b, m, deterministic)
+}
+func (m *GetMemberReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMemberReply.Merge(m, src)
+}
+func (m *GetMemberReply) XXX_Size() int {
+	return xxx_messageInfo_GetMemberReply.Size(m)
+}
+func (m *GetMemberReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMemberReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMemberReply proto.InternalMessageInfo
+
+func (m *GetMemberReply) GetMember() *Member {
+	if m != nil {
+		return m.Member
+	}
+	return nil
+}
+
+type GetCapacityRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCapacityRequest) Reset()         { *m = GetCapacityRequest{} }
+func (m *GetCapacityRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCapacityRequest) ProtoMessage()    {}
+func (*GetCapacityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{25}
+}
+
+func (m *GetCapacityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCapacityRequest.Unmarshal(m, b)
+}
+func (m *GetCapacityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCapacityRequest.Marshal(b, m, deterministic)
+}
+func (m *GetCapacityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCapacityRequest.Merge(m, src)
+}
+func (m *GetCapacityRequest) XXX_Size() int {
+	return xxx_messageInfo_GetCapacityRequest.Size(m)
+}
+func (m *GetCapacityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCapacityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCapacityRequest proto.InternalMessageInfo
+
+func (m *GetCapacityRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+type GetCapacityReply struct {
+	Capacity             *Capacity `protobuf:"bytes,1,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetCapacityReply) Reset()         { *m = GetCapacityReply{} }
+func (m *GetCapacityReply) String() string { return proto.CompactTextString(m) }
+func (*GetCapacityReply) ProtoMessage()    {}
+func (*GetCapacityReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{26}
+}
+
+func (m *GetCapacityReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCapacityReply.Unmarshal(m, b)
+}
+func (m *GetCapacityReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCapacityReply.Marshal(b, m, deterministic)
+}
+func (m *GetCapacityReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCapacityReply.Merge(m, src)
+}
+func (m *GetCapacityReply) XXX_Size() int {
+	return xxx_messageInfo_GetCapacityReply.Size(m)
+}
+func (m *GetCapacityReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCapacityReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCapacityReply proto.InternalMessageInfo
+
+func (m *GetCapacityReply) GetCapacity() *Capacity {
+	if m != nil {
+		return m.Capacity
+	}
+	return nil
+}
+
+type SetCapacityRequest struct {
+	ClassId              string    `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	Capacity             *Capacity `protobuf:"bytes,2,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SetCapacityRequest) Reset()         { *m = SetCapacityRequest{} }
+func (m *SetCapacityRequest) String() string { return proto.CompactTextString(m) }
+func (*SetCapacityRequest) ProtoMessage()    {}
+func (*SetCapacityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{27}
+}
+
+func (m *SetCapacityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetCapacityRequest.Unmarshal(m, b)
+}
+func (m *SetCapacityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetCapacityRequest.Marshal(b, m, deterministic)
+}
+func (m *SetCapacityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetCapacityRequest.Merge(m, src)
+}
+func (m *SetCapacityRequest) XXX_Size() int {
+	return xxx_messageInfo_SetCapacityRequest.Size(m)
+}
+func (m *SetCapacityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetCapacityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetCapacityRequest proto.InternalMessageInfo
+
+func (m *SetCapacityRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+func (m *SetCapacityRequest) GetCapacity() *Capacity {
+	if m != nil {
+		return m.Capacity
+	}
+	return nil
+}
+
+type SetCapacityReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetCapacityReply) Reset()         { *m = SetCapacityReply{} }
+func (m *SetCapacityReply) String() string { return proto.CompactTextString(m) }
+func (*SetCapacityReply) ProtoMessage()    {}
+func (*SetCapacityReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{28}
+}
+
+func (m *SetCapacityReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetCapacityReply.Unmarshal(m, b)
+}
+func (m *SetCapacityReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetCapacityReply.Marshal(b, m, deterministic)
+}
+func (m *SetCapacityReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetCapacityReply.Merge(m, src)
+}
+func (m *SetCapacityReply) XXX_Size() int {
+	return xxx_messageInfo_SetCapacityReply.Size(m)
+}
+func (m *SetCapacityReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetCapacityReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetCapacityReply proto.InternalMessageInfo
+
+type ListWaitlistRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWaitlistRequest) Reset()         { *m = ListWaitlistRequest{} }
+func (m *ListWaitlistRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWaitlistRequest) ProtoMessage()    {}
+func (*ListWaitlistRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{29}
+}
+
+func (m *ListWaitlistRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWaitlistRequest.Unmarshal(m, b)
+}
+func (m *ListWaitlistRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWaitlistRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWaitlistRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWaitlistRequest.Merge(m, src)
+}
+func (m *ListWaitlistRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWaitlistRequest.Size(m)
+}
+func (m *ListWaitlistRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWaitlistRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWaitlistRequest proto.InternalMessageInfo
+
+func (m *ListWaitlistRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+type ListWaitlistReply struct {
+	Waitlist             []*WaitlistEntry `protobuf:"bytes,1,rep,name=waitlist,proto3" json:"waitlist,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListWaitlistReply) Reset()         { *m = ListWaitlistReply{} }
+func (m *ListWaitlistReply) String() string { return proto.CompactTextString(m) }
+func (*ListWaitlistReply) ProtoMessage()    {}
+func (*ListWaitlistReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{30}
+}
+
+func (m *ListWaitlistReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWaitlistReply.Unmarshal(m, b)
+}
+func (m *ListWaitlistReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWaitlistReply.Marshal(b, m, deterministic)
+}
+func (m *ListWaitlistReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWaitlistReply.Merge(m, src)
+}
+func (m *ListWaitlistReply) XXX_Size() int {
+	return xxx_messageInfo_ListWaitlistReply.Size(m)
+}
+func (m *ListWaitlistReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWaitlistReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWaitlistReply proto.InternalMessageInfo
+
+func (m *ListWaitlistReply) GetWaitlist() []*WaitlistEntry {
+	if m != nil {
+		return m.Waitlist
+	}
+	return nil
+}
+
+type GetEnrollmentSettingsRequest struct {
+	ClassId              string   `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetEnrollmentSettingsRequest) Reset()         { *m = GetEnrollmentSettingsRequest{} }
+func (m *GetEnrollmentSettingsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetEnrollmentSettingsRequest) ProtoMessage()    {}
+func (*GetEnrollmentSettingsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{31}
+}
+
+func (m *GetEnrollmentSettingsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetEnrollmentSettingsRequest.Unmarshal(m, b)
+}
+func (m *GetEnrollmentSettingsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetEnrollmentSettingsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetEnrollmentSettingsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetEnrollmentSettingsRequest.Merge(m, src)
+}
+func (m *GetEnrollmentSettingsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetEnrollmentSettingsRequest.Size(m)
+}
+func (m *GetEnrollmentSettingsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetEnrollmentSettingsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetEnrollmentSettingsRequest proto.InternalMessageInfo
+
+func (m *GetEnrollmentSettingsRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+type GetEnrollmentSettingsReply struct {
+	Settings             *EnrollmentSettings `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *GetEnrollmentSettingsReply) Reset()         { *m = GetEnrollmentSettingsReply{} }
+func (m *GetEnrollmentSettingsReply) String() string { return proto.CompactTextString(m) }
+func (*GetEnrollmentSettingsReply) ProtoMessage()    {}
+func (*GetEnrollmentSettingsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{32}
+}
+
+func (m *GetEnrollmentSettingsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetEnrollmentSettingsReply.Unmarshal(m, b)
+}
+func (m *GetEnrollmentSettingsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetEnrollmentSettingsReply.Marshal(b, m, deterministic)
+}
+func (m *GetEnrollmentSettingsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetEnrollmentSettingsReply.Merge(m, src)
+}
+func (m *GetEnrollmentSettingsReply) XXX_Size() int {
+	return xxx_messageInfo_GetEnrollmentSettingsReply.Size(m)
+}
+func (m *GetEnrollmentSettingsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetEnrollmentSettingsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetEnrollmentSettingsReply proto.InternalMessageInfo
+
+func (m *GetEnrollmentSettingsReply) GetSettings() *EnrollmentSettings {
+	if m != nil {
+		return m.Settings
+	}
+	return nil
+}
+
+type SetEnrollmentSettingsRequest struct {
+	ClassId              string              `protobuf:"bytes,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	Settings             *EnrollmentSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *SetEnrollmentSettingsRequest) Reset()         { *m = SetEnrollmentSettingsRequest{} }
+func (m *SetEnrollmentSettingsRequest) String() string { return proto.CompactTextString(m) }
+func (*SetEnrollmentSettingsRequest) ProtoMessage()    {}
+func (*SetEnrollmentSettingsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{33}
+}
+
+func (m *SetEnrollmentSettingsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetEnrollmentSettingsRequest.Unmarshal(m, b)
+}
+func (m *SetEnrollmentSettingsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetEnrollmentSettingsRequest.Marshal(b, m, deterministic)
+}
+func (m *SetEnrollmentSettingsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetEnrollmentSettingsRequest.Merge(m, src)
+}
+func (m *SetEnrollmentSettingsRequest) XXX_Size() int {
+	return xxx_messageInfo_SetEnrollmentSettingsRequest.Size(m)
+}
+func (m *SetEnrollmentSettingsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetEnrollmentSettingsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetEnrollmentSettingsRequest proto.InternalMessageInfo
+
+func (m *SetEnrollmentSettingsRequest) GetClassId() string {
+	if m != nil {
+		return m.ClassId
+	}
+	return ""
+}
+
+func (m *SetEnrollmentSettingsRequest) GetSettings() *EnrollmentSettings {
+	if m != nil {
+		return m.Settings
+	}
+	return nil
+}
+
+type SetEnrollmentSettingsReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetEnrollmentSettingsReply) Reset()         { *m = SetEnrollmentSettingsReply{} }
+func (m *SetEnrollmentSettingsReply) String() string { return proto.CompactTextString(m) }
+func (*SetEnrollmentSettingsReply) ProtoMessage()    {}
+func (*SetEnrollmentSettingsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{34}
+}
+
+func (m *SetEnrollmentSettingsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetEnrollmentSettingsReply.Unmarshal(m, b)
+}
+func (m *SetEnrollmentSettingsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetEnrollmentSettingsReply.Marshal(b, m, deterministic)
+}
+func (m *SetEnrollmentSettingsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetEnrollmentSettingsReply.Merge(m, src)
+}
+func (m *SetEnrollmentSettingsReply) XXX_Size() int {
+	return xxx_messageInfo_SetEnrollmentSettingsReply.Size(m)
+}
+func (m *SetEnrollmentSettingsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetEnrollmentSettingsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetEnrollmentSettingsReply proto.InternalMessageInfo
+
+type LinkGuardianRequest struct {
+	GuardianId           string   `protobuf:"bytes,1,opt,name=guardian_id,json=guardianId,proto3" json:"guardian_id,omitempty"`
+	StudentId            string   `protobuf:"bytes,2,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinkGuardianRequest) Reset()         { *m = LinkGuardianRequest{} }
+func (m *LinkGuardianRequest) String() string { return proto.CompactTextString(m) }
+func (*LinkGuardianRequest) ProtoMessage()    {}
+func (*LinkGuardianRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{35}
+}
+
+func (m *LinkGuardianRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LinkGuardianRequest.Unmarshal(m, b)
+}
+func (m *LinkGuardianRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LinkGuardianRequest.Marshal(b, m, deterministic)
+}
+func (m *LinkGuardianRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinkGuardianRequest.Merge(m, src)
+}
+func (m *LinkGuardianRequest) XXX_Size() int {
+	return xxx_messageInfo_LinkGuardianRequest.Size(m)
+}
+func (m *LinkGuardianRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinkGuardianRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinkGuardianRequest proto.InternalMessageInfo
+
+func (m *LinkGuardianRequest) GetGuardianId() string {
+	if m != nil {
+		return m.GuardianId
+	}
+	return ""
+}
+
+func (m *LinkGuardianRequest) GetStudentId() string {
+	if m != nil {
+		return m.StudentId
+	}
+	return ""
+}
+
+type LinkGuardianReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinkGuardianReply) Reset()         { *m = LinkGuardianReply{} }
+func (m *LinkGuardianReply) String() string { return proto.CompactTextString(m) }
+func (*LinkGuardianReply) ProtoMessage()    {}
+func (*LinkGuardianReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{36}
+}
+
+func (m *LinkGuardianReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LinkGuardianReply.Unmarshal(m, b)
+}
+func (m *LinkGuardianReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LinkGuardianReply.Marshal(b, m, deterministic)
+}
+func (m *LinkGuardianReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinkGuardianReply.Merge(m, src)
+}
+func (m *LinkGuardianReply) XXX_Size() int {
+	return xxx_messageInfo_LinkGuardianReply.Size(m)
+}
+func (m *LinkGuardianReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinkGuardianReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinkGuardianReply proto.InternalMessageInfo
+
+type UnlinkGuardianRequest struct {
+	GuardianId           string   `protobuf:"bytes,1,opt,name=guardian_id,json=guardianId,proto3" json:"guardian_id,omitempty"`
+	StudentId            string   `protobuf:"bytes,2,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnlinkGuardianRequest) Reset()         { *m = UnlinkGuardianRequest{} }
+func (m *UnlinkGuardianRequest) String() string { return proto.CompactTextString(m) }
+func (*UnlinkGuardianRequest) ProtoMessage()    {}
+func (*UnlinkGuardianRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{37}
+}
+
+func (m *UnlinkGuardianRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnlinkGuardianRequest.Unmarshal(m, b)
+}
+func (m *UnlinkGuardianRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnlinkGuardianRequest.Marshal(b, m, deterministic)
+}
+func (m *UnlinkGuardianRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnlinkGuardianRequest.Merge(m, src)
+}
+func (m *UnlinkGuardianRequest) XXX_Size() int {
+	return xxx_messageInfo_UnlinkGuardianRequest.Size(m)
+}
+func (m *UnlinkGuardianRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnlinkGuardianRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnlinkGuardianRequest proto.InternalMessageInfo
+
+func (m *UnlinkGuardianRequest) GetGuardianId() string {
+	if m != nil {
+		return m.GuardianId
+	}
+	return ""
+}
+
+func (m *UnlinkGuardianRequest) GetStudentId() string {
+	if m != nil {
+		return m.StudentId
+	}
+	return ""
+}
+
+type UnlinkGuardianReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnlinkGuardianReply) Reset()         { *m = UnlinkGuardianReply{} }
+func (m *UnlinkGuardianReply) String() string { return proto.CompactTextString(m) }
+func (*UnlinkGuardianReply) ProtoMessage()    {}
+func (*UnlinkGuardianReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{38}
+}
+
+func (m *UnlinkGuardianReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnlinkGuardianReply.Unmarshal(m, b)
+}
+func (m *UnlinkGuardianReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnlinkGuardianReply.Marshal(b, m, deterministic)
+}
+func (m *UnlinkGuardianReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnlinkGuardianReply.Merge(m, src)
+}
+func (m *UnlinkGuardianReply) XXX_Size() int {
+	return xxx_messageInfo_UnlinkGuardianReply.Size(m)
+}
+func (m *UnlinkGuardianReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnlinkGuardianReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnlinkGuardianReply proto.InternalMessageInfo
+
+type ListStudentsRequest struct {
+	GuardianId           string   `protobuf:"bytes,1,opt,name=guardian_id,json=guardianId,proto3" json:"guardian_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListStudentsRequest) Reset()         { *m = ListStudentsRequest{} }
+func (m *ListStudentsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListStudentsRequest) ProtoMessage()    {}
+func (*ListStudentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{39}
+}
+
+func (m *ListStudentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListStudentsRequest.Unmarshal(m, b)
+}
+func (m *ListStudentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListStudentsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListStudentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListStudentsRequest.Merge(m, src)
+}
+func (m *ListStudentsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListStudentsRequest.Size(m)
+}
+func (m *ListStudentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListStudentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListStudentsRequest proto.InternalMessageInfo
+
+func (m *ListStudentsRequest) GetGuardianId() string {
+	if m != nil {
+		return m.GuardianId
+	}
+	return ""
+}
+
+type ListStudentsReply struct {
+	Students             []string `protobuf:"bytes,1,rep,name=students,proto3" json:"students,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListStudentsReply) Reset()         { *m = ListStudentsReply{} }
+func (m *ListStudentsReply) String() string { return proto.CompactTextString(m) }
+func (*ListStudentsReply) ProtoMessage()    {}
+func (*ListStudentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb93f0b89bc25075, []int{40}
+}
+
+func (m *ListStudentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListStudentsReply.Unmarshal(m, b)
+}
+func (m *ListStudentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListStudentsReply.Marshal(b, m, deterministic)
+}
+func (m *ListStudentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListStudentsReply.Merge(m, src)
+}
+func (m *ListStudentsReply) XXX_Size() int {
+	return xxx_messageInfo_ListStudentsReply.Size(m)
+}
+func (m *ListStudentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListStudentsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListStudentsReply proto.InternalMessageInfo
+
+func (m *ListStudentsReply) GetStudents() []string {
+	if m != nil {
+		return m.Students
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("pb.Role", Role_name, Role_value)
+	proto.RegisterType((*Class)(nil), "pb.Class")
+	proto.RegisterType((*Member)(nil), "pb.Member")
+	proto.RegisterType((*WaitlistEntry)(nil), "pb.WaitlistEntry")
+	proto.RegisterType((*Capacity)(nil), "pb.Capacity")
+	proto.RegisterMapType((map[string]int32)(nil), "pb.Capacity.RolesEntry")
+	proto.RegisterType((*EnrollmentSettings)(nil), "pb.EnrollmentSettings")
+	proto.RegisterType((*ListClassesRequest)(nil), "pb.ListClassesRequest")
+	proto.RegisterType((*ListClassesReply)(nil), "pb.ListClassesReply")
+	proto.RegisterType((*GetClassRequest)(nil), "pb.GetClassRequest")
+	proto.RegisterType((*GetClassReply)(nil), "pb.GetClassReply")
+	proto.RegisterType((*CreateClassRequest)(nil), "pb.CreateClassRequest")
+	proto.RegisterType((*CreateClassReply)(nil), "pb.CreateClassReply")
+	proto.RegisterType((*UpdateClassRequest)(nil), "pb.UpdateClassRequest")
+	proto.RegisterType((*UpdateClassReply)(nil), "pb.UpdateClassReply")
+	proto.RegisterType((*DeleteClassRequest)(nil), "pb.DeleteClassRequest")
+	proto.RegisterType((*DeleteClassReply)(nil), "pb.DeleteClassReply")
+	proto.RegisterType((*JoinClassRequest)(nil), "pb.JoinClassRequest")
+	proto.RegisterType((*JoinClassReply)(nil), "pb.JoinClassReply")
+	proto.RegisterType((*LeaveClassRequest)(nil), "pb.LeaveClassRequest")
+	proto.RegisterType((*LeaveClassReply)(nil), "pb.LeaveClassReply")
+	proto.RegisterType((*SetRoleRequest)(nil), "pb.SetRoleRequest")
+	proto.RegisterType((*SetRoleReply)(nil), "pb.SetRoleReply")
+	proto.RegisterType((*ListMembersRequest)(nil), "pb.ListMembersRequest")
+	proto.RegisterType((*ListMembersReply)(nil), "pb.ListMembersReply")
+	proto.RegisterType((*GetMemberRequest)(nil), "pb.GetMemberRequest")
+	proto.RegisterType((*GetMemberReply)(nil), "pb.GetMemberReply")
+	proto.RegisterType((*GetCapacityRequest)(nil), "pb.GetCapacityRequest")
+	proto.RegisterType((*GetCapacityReply)(nil), "pb.GetCapacityReply")
+	proto.RegisterType((*SetCapacityRequest)(nil), "pb.SetCapacityRequest")
+	proto.RegisterType((*SetCapacityReply)(nil), "pb.SetCapacityReply")
+	proto.RegisterType((*ListWaitlistRequest)(nil), "pb.ListWaitlistRequest")
+	proto.RegisterType((*ListWaitlistReply)(nil), "pb.ListWaitlistReply")
+	proto.RegisterType((*GetEnrollmentSettingsRequest)(nil), "pb.GetEnrollmentSettingsRequest")
+	proto.RegisterType((*GetEnrollmentSettingsReply)(nil), "pb.GetEnrollmentSettingsReply")
+	proto.RegisterType((*SetEnrollmentSettingsRequest)(nil), "pb.SetEnrollmentSettingsRequest")
+	proto.RegisterType((*SetEnrollmentSettingsReply)(nil), "pb.SetEnrollmentSettingsReply")
+	proto.RegisterType((*LinkGuardianRequest)(nil), "pb.LinkGuardianRequest")
+	proto.RegisterType((*LinkGuardianReply)(nil), "pb.LinkGuardianReply")
+	proto.RegisterType((*UnlinkGuardianRequest)(nil), "pb.UnlinkGuardianRequest")
+	proto.RegisterType((*UnlinkGuardianReply)(nil), "pb.UnlinkGuardianReply")
+	proto.RegisterType((*ListStudentsRequest)(nil), "pb.ListStudentsRequest")
+	proto.RegisterType((*ListStudentsReply)(nil), "pb.ListStudentsReply")
+}
+
+func init() { proto.RegisterFile("classsvc.proto", fileDescriptor_cb93f0b89bc25075) }
+
+var fileDescriptor_cb93f0b89bc25075 = []byte{
+	// 1261 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xed, 0x6f, 0xdb, 0x44,
+	0x18, 0x9f, 0x9d, 0xa4, 0x71, 0x9e, 0x74, 0x99, 0x73, 0x4d, 0xd6, 0xcc, 0x94, 0xad, 0x9c, 0xf8,
+	0x10, 0xa1, 0x35, 0xdd, 0xb2, 0xb1, 0x75, 0x13, 0x13, 0x74, 0x6d, 0x5a, 0x82, 0x4a, 0x99, 0xec,
+	0x86, 0x09, 0xf8, 0x50, 0x39, 0xc9, 0x51, 0x99, 0x3a, 0xb6, 0xb1, 0x2f, 0xad, 0xfa, 0xbf, 0xf0,
+	0x05, 0x3e, 0x20, 0x21, 0xf1, 0x47, 0xa2, 0xbb, 0xb3, 0x1d, 0xbf, 0x24, 0x69, 0x18, 0xe2, 0x9b,
+	0xef, 0x79, 0xf9, 0x3d, 0x6f, 0x77, 0xcf, 0xcf, 0x50, 0x1b, 0xd9, 0x66, 0x10, 0x04, 0x57, 0xa3,
+	0x8e, 0xe7, 0xbb, 0xd4, 0x45, 0xb2, 0x37, 0xd4, 0x1e, 0x5d, 0xb8, 0xee, 0x85, 0x4d, 0x76, 0xb9,
+	0x64, 0x38, 0xfd, 0x79, 0x97, 0x5a, 0x13, 0x12, 0x50, 0x73, 0xe2, 0x09, 0x23, 0xed, 0x61, 0xd6,
+	0xe0, 0xda, 0x37, 0x3d, 0x8f, 0xf8, 0x81, 0xd0, 0xe3, 0x53, 0x28, 0x1d, 0x30, 0x58, 0x54, 0x03,
+	0xd9, 0x1a, 0xb7, 0xa4, 0x6d, 0xa9, 0x5d, 0xd1, 0x65, 0x6b, 0x8c, 0x10, 0x14, 0x1d, 0x73, 0x42,
+	0x5a, 0x32, 0x97, 0xf0, 0x6f, 0xf4, 0x09, 0xac, 0x8f, 0xa6, 0xbe, 0x4f, 0x1c, 0x7a, 0x3e, 0x75,
+	0x2c, 0xda, 0x2a, 0x70, 0x5d, 0x35, 0x94, 0x0d, 0x1c, 0x8b, 0xe2, 0xbf, 0x24, 0x58, 0xfb, 0x96,
+	0x4c, 0x86, 0xc4, 0x47, 0x9b, 0x50, 0x9e, 0x06, 0xc4, 0x3f, 0x8f, 0x61, 0xd7, 0xd8, 0xb1, 0x3f,
+	0x46, 0x0f, 0x40, 0xe1, 0xa5, 0x30, 0x8d, 0x80, 0x2f, 0xf3, 0x73, 0x7f, 0x8c, 0xb6, 0xa0, 0xe8,
+	0xbb, 0x36, 0xe1, 0xc8, 0xb5, 0xae, 0xd2, 0xf1, 0x86, 0x1d, 0xdd, 0xb5, 0x89, 0xce, 0xa5, 0xa8,
+	0x01, 0x25, 0xf7, 0xda, 0x21, 0x7e, 0xab, 0xb8, 0x2d, 0xb5, 0x15, 0x5d, 0x1c, 0xd0, 0x4b, 0xa8,
+	0xfc, 0xe2, 0x5a, 0x0e, 0x19, 0x9f, 0x9b, 0xb4, 0x55, 0xda, 0x96, 0xda, 0xd5, 0xae, 0xd6, 0x11,
+	0x65, 0x77, 0xa2, 0xb2, 0x3b, 0x67, 0x51, 0x5f, 0x74, 0x45, 0x18, 0xef, 0x53, 0xfc, 0xb7, 0x04,
+	0x77, 0xdf, 0x9b, 0x16, 0xb5, 0xad, 0x80, 0xf6, 0x1c, 0xea, 0xdf, 0x24, 0x9a, 0x50, 0xe0, 0x4d,
+	0x58, 0x92, 0x69, 0xa2, 0xba, 0x42, 0xaa, 0xba, 0xa8, 0x84, 0xe2, 0xdc, 0x12, 0x5e, 0x01, 0x8c,
+	0x7c, 0x62, 0xd2, 0x55, 0xb3, 0xad, 0x84, 0xd6, 0xfb, 0x14, 0xff, 0x29, 0x81, 0x72, 0x60, 0x7a,
+	0xe6, 0xc8, 0xa2, 0x37, 0xe8, 0x29, 0x94, 0x02, 0x62, 0xd2, 0x80, 0x27, 0x5b, 0xed, 0x7e, 0x94,
+	0x83, 0xe8, 0x3b, 0xf4, 0x59, 0xf7, 0x7b, 0xd3, 0x9e, 0x12, 0x5d, 0x58, 0xa2, 0x1d, 0x28, 0xb1,
+	0x14, 0x82, 0x96, 0xbc, 0x5d, 0x68, 0x57, 0xbb, 0x9b, 0x2c, 0xb3, 0x08, 0x8f, 0xa7, 0x18, 0xf0,
+	0x26, 0xe8, 0xc2, 0x4a, 0xdb, 0x03, 0x98, 0x09, 0x91, 0x0a, 0x85, 0x4b, 0x72, 0x13, 0x0e, 0x92,
+	0x7d, 0xb2, 0x61, 0x5c, 0x31, 0x78, 0xde, 0x98, 0x92, 0x2e, 0x0e, 0xaf, 0xe5, 0x3d, 0x09, 0xff,
+	0x26, 0x01, 0xea, 0x39, 0xbe, 0x6b, 0xdb, 0x13, 0xe2, 0x50, 0x83, 0x50, 0x6a, 0x39, 0x17, 0x01,
+	0xfa, 0x1c, 0x14, 0xd7, 0x23, 0x4e, 0xc0, 0x0a, 0x97, 0x6e, 0x2d, 0xbc, 0xcc, 0x6d, 0xf7, 0x29,
+	0x1b, 0xef, 0xc8, 0x76, 0x03, 0xc2, 0xfd, 0xe4, 0xdb, 0xc7, 0x2b, 0x8c, 0xf7, 0x29, 0xba, 0x0f,
+	0x6b, 0xb6, 0x3b, 0xba, 0x24, 0x62, 0x40, 0x8a, 0x1e, 0x9e, 0x70, 0x03, 0xd0, 0x89, 0x15, 0x50,
+	0x7e, 0xed, 0x49, 0xa0, 0x93, 0x5f, 0xa7, 0x24, 0xa0, 0xf8, 0x31, 0xa8, 0x29, 0xa9, 0x67, 0xdf,
+	0xa0, 0x16, 0x88, 0x71, 0x13, 0xd6, 0xe6, 0x42, 0x3c, 0x7d, 0x12, 0xe0, 0xc7, 0x70, 0xef, 0x98,
+	0x08, 0xe3, 0x10, 0x20, 0x75, 0x57, 0xa4, 0xd4, 0x5d, 0xc1, 0x4f, 0xe0, 0xee, 0xcc, 0x9a, 0x01,
+	0x3f, 0x82, 0x12, 0xd7, 0x85, 0x7d, 0xa8, 0xf0, 0x51, 0x70, 0xb5, 0x90, 0xe3, 0x36, 0xa0, 0x03,
+	0x3e, 0xf8, 0x54, 0x88, 0xe8, 0x4d, 0x4a, 0xb3, 0x37, 0x89, 0x77, 0x40, 0x4d, 0x59, 0x32, 0xf8,
+	0x25, 0xa9, 0xfc, 0x21, 0x01, 0x1a, 0x78, 0xe3, 0x2c, 0xf2, 0x62, 0x0f, 0xf4, 0x24, 0xb1, 0x08,
+	0xaa, 0xdd, 0xad, 0x5c, 0xeb, 0x0d, 0xea, 0x5b, 0xce, 0x85, 0xb8, 0x69, 0xdc, 0x12, 0x7d, 0x39,
+	0x67, 0x4d, 0xdc, 0xe6, 0x99, 0x5a, 0x22, 0x08, 0xd4, 0x54, 0x8e, 0x9e, 0x7d, 0x83, 0x77, 0x01,
+	0x1d, 0x12, 0x9b, 0xac, 0x9c, 0x37, 0x03, 0x49, 0x39, 0x30, 0x90, 0x1d, 0x50, 0xbf, 0x71, 0x2d,
+	0x67, 0xf5, 0xb9, 0xd5, 0x12, 0xe6, 0xac, 0xb3, 0x0f, 0x01, 0xae, 0xc3, 0x8d, 0x41, 0x84, 0xb9,
+	0xa2, 0x27, 0x24, 0xf8, 0x18, 0xea, 0x27, 0xc4, 0xbc, 0x5a, 0xb9, 0xb9, 0x89, 0x2d, 0x22, 0x27,
+	0xb7, 0x08, 0xae, 0xc3, 0xbd, 0x24, 0x10, 0x4b, 0x7e, 0x08, 0x35, 0x83, 0x50, 0xbe, 0x4b, 0x3e,
+	0x1c, 0x78, 0xf9, 0x86, 0xc5, 0x35, 0x58, 0x8f, 0x63, 0x84, 0x5d, 0x67, 0xaf, 0x42, 0x6c, 0xf4,
+	0x55, 0x5a, 0xb6, 0x07, 0x6a, 0xca, 0x81, 0x35, 0xed, 0x53, 0x28, 0x4f, 0xc4, 0x99, 0x3f, 0xa3,
+	0x6a, 0x17, 0x58, 0x54, 0x61, 0xa2, 0x47, 0x2a, 0x7c, 0x04, 0xea, 0x31, 0x09, 0x1d, 0xff, 0x4b,
+	0xe7, 0x9e, 0x43, 0x2d, 0x81, 0xc3, 0xe2, 0x63, 0x58, 0x13, 0x41, 0xc2, 0xe7, 0x96, 0x0c, 0x1f,
+	0x6a, 0x58, 0xa1, 0xec, 0x89, 0x86, 0xeb, 0x70, 0x85, 0x42, 0xbf, 0x00, 0x35, 0xe5, 0xc0, 0x02,
+	0xb5, 0x41, 0x19, 0x85, 0x82, 0x30, 0xd4, 0x7a, 0x72, 0xc9, 0xea, 0xb1, 0x16, 0xff, 0x00, 0xc8,
+	0xf8, 0x37, 0xe1, 0x52, 0xd0, 0xf2, 0x52, 0x68, 0x04, 0xaa, 0x91, 0x49, 0x0c, 0x3f, 0x81, 0x0d,
+	0x36, 0x95, 0x88, 0xec, 0x56, 0x28, 0xef, 0x2d, 0xd4, 0xd3, 0x1e, 0xac, 0xbe, 0x1d, 0x50, 0xa2,
+	0xbb, 0x1e, 0x4e, 0xb2, 0xce, 0x92, 0x48, 0x71, 0xa8, 0x1e, 0x9b, 0xe0, 0x57, 0xb0, 0x75, 0x4c,
+	0x68, 0x9e, 0x09, 0x56, 0x08, 0xff, 0x0e, 0xb4, 0x05, 0xae, 0x2c, 0x8f, 0x2e, 0x28, 0x41, 0x28,
+	0x08, 0xfb, 0x7c, 0x9f, 0xe5, 0x31, 0xc7, 0x3c, 0xb6, 0xc3, 0x13, 0xd8, 0x32, 0x3e, 0x2c, 0x99,
+	0x54, 0x38, 0x79, 0xc5, 0x70, 0x5b, 0xa0, 0x19, 0x0b, 0x0b, 0xc0, 0x03, 0x36, 0x0f, 0xe7, 0xf2,
+	0x78, 0x6a, 0xfa, 0x63, 0xcb, 0x74, 0xa2, 0x1c, 0x1e, 0x41, 0xf5, 0x22, 0x14, 0xcd, 0xd2, 0x80,
+	0x48, 0xd4, 0x1f, 0xa3, 0x8f, 0x01, 0x02, 0x3a, 0x1d, 0xb3, 0xcd, 0x1a, 0xdf, 0xfb, 0x4a, 0x28,
+	0xe9, 0x8f, 0xf1, 0x06, 0xd4, 0xd3, 0xb0, 0x2c, 0xd6, 0x7b, 0x68, 0x0e, 0x1c, 0xfb, 0x7f, 0x88,
+	0xd6, 0x84, 0x8d, 0x2c, 0x30, 0x8b, 0xf7, 0x42, 0xdc, 0x35, 0x43, 0xd8, 0x05, 0xab, 0x46, 0xc3,
+	0xbb, 0x50, 0x4f, 0xfb, 0xb1, 0x49, 0x6b, 0xa0, 0x84, 0x01, 0x23, 0x0a, 0x8e, 0xcf, 0x9f, 0xbd,
+	0x80, 0xa2, 0x2e, 0xfe, 0x0a, 0x55, 0xfd, 0xbb, 0x93, 0xde, 0xf9, 0xe0, 0xd4, 0x78, 0xd7, 0x3b,
+	0xe8, 0x1f, 0xf5, 0x7b, 0x87, 0xea, 0x1d, 0x54, 0x85, 0xb2, 0x71, 0x36, 0x38, 0xec, 0x9d, 0x9e,
+	0xa9, 0x12, 0x3b, 0x9c, 0xf5, 0xf6, 0x0f, 0xbe, 0xee, 0xe9, 0xaa, 0xdc, 0xfd, 0xbd, 0x02, 0xe5,
+	0x90, 0xe6, 0xd1, 0x1b, 0xa8, 0x26, 0x58, 0x1f, 0xf1, 0xb9, 0xe6, 0x7f, 0x0e, 0xb4, 0x46, 0x4e,
+	0xce, 0x2a, 0xbd, 0x83, 0x9e, 0x83, 0x12, 0x11, 0x3b, 0xda, 0x60, 0x36, 0x99, 0x9f, 0x02, 0xad,
+	0x9e, 0x16, 0x0a, 0xaf, 0x37, 0x50, 0x4d, 0x50, 0xb6, 0x08, 0x9a, 0x67, 0x7b, 0xad, 0x91, 0x93,
+	0xc7, 0xee, 0x09, 0x76, 0x14, 0xee, 0x79, 0x4a, 0xd7, 0x1a, 0x39, 0x79, 0xec, 0x9e, 0xe0, 0x45,
+	0xe1, 0x9e, 0x67, 0x56, 0xad, 0x91, 0x93, 0x0b, 0xf7, 0x97, 0x50, 0x89, 0x39, 0x11, 0x71, 0xa3,
+	0x2c, 0xa3, 0x6a, 0x28, 0x23, 0x15, 0x8e, 0xaf, 0x01, 0x66, 0x8c, 0x86, 0x9a, 0xbc, 0xa3, 0x59,
+	0xaa, 0xd4, 0x36, 0xb2, 0x62, 0xe1, 0xfb, 0x14, 0xca, 0x21, 0x2d, 0x21, 0x0e, 0x9e, 0xe6, 0x41,
+	0x4d, 0x4d, 0xc9, 0xe2, 0x32, 0x13, 0x44, 0x34, 0x9b, 0x6c, 0x9a, 0xca, 0xb4, 0x46, 0x4e, 0x1e,
+	0x97, 0x19, 0xb3, 0x88, 0x28, 0x33, 0x4b, 0x4e, 0x1a, 0xca, 0x48, 0xe3, 0xb8, 0x09, 0x5e, 0x10,
+	0x71, 0xf3, 0xcc, 0xa2, 0x35, 0x72, 0xf2, 0xd8, 0xdd, 0xc8, 0xba, 0x1b, 0x0b, 0xdc, 0x8d, 0xbc,
+	0xfb, 0x57, 0xb0, 0x9e, 0x5c, 0xdb, 0x68, 0x33, 0x2a, 0x2f, 0xb3, 0xfa, 0xb5, 0x66, 0x5e, 0x21,
+	0x10, 0x7e, 0x82, 0xe6, 0xdc, 0xcd, 0x8b, 0xb6, 0xc3, 0x8c, 0x17, 0xae, 0x50, 0xed, 0xe1, 0x12,
+	0x8b, 0x18, 0xdc, 0x58, 0x0c, 0x6e, 0xdc, 0x0a, 0x6e, 0x2c, 0x03, 0xe7, 0xb5, 0xcf, 0xb6, 0x51,
+	0x54, 0x7b, 0x6e, 0xf1, 0x69, 0xcd, 0xbc, 0x42, 0x20, 0x1c, 0x41, 0x2d, 0xbd, 0xd1, 0xd0, 0x03,
+	0xfe, 0x88, 0xe6, 0xad, 0x4f, 0x6d, 0x73, 0x9e, 0x2a, 0x35, 0x85, 0x68, 0x95, 0xcd, 0xa6, 0x90,
+	0x59, 0x8a, 0x5a, 0x33, 0xaf, 0xe0, 0x08, 0x6f, 0x8b, 0x3f, 0xca, 0xde, 0x70, 0xb8, 0xc6, 0x7f,
+	0x95, 0x9f, 0xfd, 0x33, 0x00, 0x72, 0x48, 0x00, 0xd9, 0xfa, 0x0f, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ClassesClient is the client API for Classes service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ClassesClient interface {
+	ListClasses(ctx context.Context, in *ListClassesRequest, opts ...grpc.CallOption) (*ListClassesReply, error)
+	GetClass(ctx context.Context, in *GetClassRequest, opts ...grpc.CallOption) (*GetClassReply, error)
+	CreateClass(ctx context.Context, in *CreateClassRequest, opts ...grpc.CallOption) (*CreateClassReply, error)
+	UpdateClass(ctx context.Context, in *UpdateClassRequest, opts ...grpc.CallOption) (*UpdateClassReply, error)
+	DeleteClass(ctx context.Context, in *DeleteClassRequest, opts ...grpc.CallOption) (*DeleteClassReply, error)
+	JoinClass(ctx context.Context, in *JoinClassRequest, opts ...grpc.CallOption) (*JoinClassReply, error)
+	LeaveClass(ctx context.Context, in *LeaveClassRequest, opts ...grpc.CallOption) (*LeaveClassReply, error)
+	SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*SetRoleReply, error)
+	ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersReply, error)
+	GetMember(ctx context.Context, in *GetMemberRequest, opts ...grpc.CallOption) (*GetMemberReply, error)
+	GetCapacity(ctx context.Context, in *GetCapacityRequest, opts ...grpc.CallOption) (*GetCapacityReply, error)
+	SetCapacity(ctx context.Context, in *SetCapacityRequest, opts ...grpc.CallOption) (*SetCapacityReply, error)
+	ListWaitlist(ctx context.Context, in *ListWaitlistRequest, opts ...grpc.CallOption) (*ListWaitlistReply, error)
+	GetEnrollmentSettings(ctx context.Context, in *GetEnrollmentSettingsRequest, opts ...grpc.CallOption) (*GetEnrollmentSettingsReply, error)
+	SetEnrollmentSettings(ctx context.Context, in *SetEnrollmentSettingsRequest, opts ...grpc.CallOption) (*SetEnrollmentSettingsReply, error)
+	LinkGuardian(ctx context.Context, in *LinkGuardianRequest, opts ...grpc.CallOption) (*LinkGuardianReply, error)
+	UnlinkGuardian(ctx context.Context, in *UnlinkGuardianRequest, opts ...grpc.CallOption) (*UnlinkGuardianReply, error)
+	ListStudents(ctx context.Context, in *ListStudentsRequest, opts ...grpc.CallOption) (*ListStudentsReply, error)
+}
+
+type classesClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewClassesClient(cc *grpc.ClientConn) ClassesClient {
+	return &classesClient{cc}
+}
+
+func (c *classesClient) ListClasses(ctx context.Context, in *ListClassesRequest, opts ...grpc.CallOption) (*ListClassesReply, error) {
+	out := new(ListClassesReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/ListClasses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) GetClass(ctx context.Context, in *GetClassRequest, opts ...grpc.CallOption) (*GetClassReply, error) {
+	out := new(GetClassReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/GetClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) CreateClass(ctx context.Context, in *CreateClassRequest, opts ...grpc.CallOption) (*CreateClassReply, error) {
+	out := new(CreateClassReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/CreateClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) UpdateClass(ctx context.Context, in *UpdateClassRequest, opts ...grpc.CallOption) (*UpdateClassReply, error) {
+	out := new(UpdateClassReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/UpdateClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) DeleteClass(ctx context.Context, in *DeleteClassRequest, opts ...grpc.CallOption) (*DeleteClassReply, error) {
+	out := new(DeleteClassReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/DeleteClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) JoinClass(ctx context.Context, in *JoinClassRequest, opts ...grpc.CallOption) (*JoinClassReply, error) {
+	out := new(JoinClassReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/JoinClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) LeaveClass(ctx context.Context, in *LeaveClassRequest, opts ...grpc.CallOption) (*LeaveClassReply, error) {
+	out := new(LeaveClassReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/LeaveClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*SetRoleReply, error) {
+	out := new(SetRoleReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/SetRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersReply, error) {
+	out := new(ListMembersReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/ListMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) GetMember(ctx context.Context, in *GetMemberRequest, opts ...grpc.CallOption) (*GetMemberReply, error) {
+	out := new(GetMemberReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/GetMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) GetCapacity(ctx context.Context, in *GetCapacityRequest, opts ...grpc.CallOption) (*GetCapacityReply, error) {
+	out := new(GetCapacityReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/GetCapacity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) SetCapacity(ctx context.Context, in *SetCapacityRequest, opts ...grpc.CallOption) (*SetCapacityReply, error) {
+	out := new(SetCapacityReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/SetCapacity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) ListWaitlist(ctx context.Context, in *ListWaitlistRequest, opts ...grpc.CallOption) (*ListWaitlistReply, error) {
+	out := new(ListWaitlistReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/ListWaitlist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) GetEnrollmentSettings(ctx context.Context, in *GetEnrollmentSettingsRequest, opts ...grpc.CallOption) (*GetEnrollmentSettingsReply, error) {
+	out := new(GetEnrollmentSettingsReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/GetEnrollmentSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) SetEnrollmentSettings(ctx context.Context, in *SetEnrollmentSettingsRequest, opts ...grpc.CallOption) (*SetEnrollmentSettingsReply, error) {
+	out := new(SetEnrollmentSettingsReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/SetEnrollmentSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) LinkGuardian(ctx context.Context, in *LinkGuardianRequest, opts ...grpc.CallOption) (*LinkGuardianReply, error) {
+	out := new(LinkGuardianReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/LinkGuardian", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) UnlinkGuardian(ctx context.Context, in *UnlinkGuardianRequest, opts ...grpc.CallOption) (*UnlinkGuardianReply, error) {
+	out := new(UnlinkGuardianReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/UnlinkGuardian", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classesClient) ListStudents(ctx context.Context, in *ListStudentsRequest, opts ...grpc.CallOption) (*ListStudentsReply, error) {
+	out := new(ListStudentsReply)
+	err := c.cc.Invoke(ctx, "/pb.Classes/ListStudents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClassesServer is the server API for Classes service.
+type ClassesServer interface {
+	ListClasses(context.Context, *ListClassesRequest) (*ListClassesReply, error)
+	GetClass(context.Context, *GetClassRequest) (*GetClassReply, error)
+	CreateClass(context.Context, *CreateClassRequest) (*CreateClassReply, error)
+	UpdateClass(context.Context, *UpdateClassRequest) (*UpdateClassReply, error)
+	DeleteClass(context.Context, *DeleteClassRequest) (*DeleteClassReply, error)
+	JoinClass(context.Context, *JoinClassRequest) (*JoinClassReply, error)
+	LeaveClass(context.Context, *LeaveClassRequest) (*LeaveClassReply, error)
+	SetRole(context.Context, *SetRoleRequest) (*SetRoleReply, error)
+	ListMembers(context.Context, *ListMembersRequest) (*ListMembersReply, error)
+	GetMember(context.Context, *GetMemberRequest) (*GetMemberReply, error)
+	GetCapacity(context.Context, *GetCapacityRequest) (*GetCapacityReply, error)
+	SetCapacity(context.Context, *SetCapacityRequest) (*SetCapacityReply, error)
+	ListWaitlist(context.Context, *ListWaitlistRequest) (*ListWaitlistReply, error)
+	GetEnrollmentSettings(context.Context, *GetEnrollmentSettingsRequest) (*GetEnrollmentSettingsReply, error)
+	SetEnrollmentSettings(context.Context, *SetEnrollmentSettingsRequest) (*SetEnrollmentSettingsReply, error)
+	LinkGuardian(context.Context, *LinkGuardianRequest) (*LinkGuardianReply, error)
+	UnlinkGuardian(context.Context, *UnlinkGuardianRequest) (*UnlinkGuardianReply, error)
+	ListStudents(context.Context, *ListStudentsRequest) (*ListStudentsReply, error)
+}
+
+func RegisterClassesServer(s *grpc.Server, srv ClassesServer) {
+	s.RegisterService(&_Classes_serviceDesc, srv)
+}
+
+func _Classes_ListClasses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClassesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).ListClasses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/ListClasses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).ListClasses(ctx, req.(*ListClassesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_GetClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).GetClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/GetClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).GetClass(ctx, req.(*GetClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_CreateClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).CreateClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/CreateClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).CreateClass(ctx, req.(*CreateClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_UpdateClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).UpdateClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/UpdateClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).UpdateClass(ctx, req.(*UpdateClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_DeleteClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).DeleteClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/DeleteClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).DeleteClass(ctx, req.(*DeleteClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_JoinClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).JoinClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/JoinClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).JoinClass(ctx, req.(*JoinClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_LeaveClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).LeaveClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/LeaveClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).LeaveClass(ctx, req.(*LeaveClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_SetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).SetRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/SetRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).SetRole(ctx, req.(*SetRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).ListMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/ListMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).ListMembers(ctx, req.(*ListMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_GetMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).GetMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/GetMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).GetMember(ctx, req.(*GetMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_GetCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).GetCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/GetCapacity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).GetCapacity(ctx, req.(*GetCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_SetCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).SetCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/SetCapacity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).SetCapacity(ctx, req.(*SetCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_ListWaitlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWaitlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).ListWaitlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/ListWaitlist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).ListWaitlist(ctx, req.(*ListWaitlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_GetEnrollmentSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEnrollmentSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).GetEnrollmentSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/GetEnrollmentSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).GetEnrollmentSettings(ctx, req.(*GetEnrollmentSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_SetEnrollmentSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEnrollmentSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).SetEnrollmentSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/SetEnrollmentSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).SetEnrollmentSettings(ctx, req.(*SetEnrollmentSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_LinkGuardian_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkGuardianRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).LinkGuardian(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/LinkGuardian",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).LinkGuardian(ctx, req.(*LinkGuardianRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_UnlinkGuardian_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlinkGuardianRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).UnlinkGuardian(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/UnlinkGuardian",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).UnlinkGuardian(ctx, req.(*UnlinkGuardianRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Classes_ListStudents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStudentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassesServer).ListStudents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Classes/ListStudents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassesServer).ListStudents(ctx, req.(*ListStudentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Classes_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Classes",
+	HandlerType: (*ClassesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListClasses",
+			Handler:    _Classes_ListClasses_Handler,
+		},
+		{
+			MethodName: "GetClass",
+			Handler:    _Classes_GetClass_Handler,
+		},
+		{
+			MethodName: "CreateClass",
+			Handler:    _Classes_CreateClass_Handler,
+		},
+		{
+			MethodName: "UpdateClass",
+			Handler:    _Classes_UpdateClass_Handler,
+		},
+		{
+			MethodName: "DeleteClass",
+			Handler:    _Classes_DeleteClass_Handler,
+		},
+		{
+			MethodName: "JoinClass",
+			Handler:    _Classes_JoinClass_Handler,
+		},
+		{
+			MethodName: "LeaveClass",
+			Handler:    _Classes_LeaveClass_Handler,
+		},
+		{
+			MethodName: "SetRole",
+			Handler:    _Classes_SetRole_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _Classes_ListMembers_Handler,
+		},
+		{
+			MethodName: "GetMember",
+			Handler:    _Classes_GetMember_Handler,
+		},
+		{
+			MethodName: "GetCapacity",
+			Handler:    _Classes_GetCapacity_Handler,
+		},
+		{
+			MethodName: "SetCapacity",
+			Handler:    _Classes_SetCapacity_Handler,
+		},
+		{
+			MethodName: "ListWaitlist",
+			Handler:    _Classes_ListWaitlist_Handler,
+		},
+		{
+			MethodName: "GetEnrollmentSettings",
+			Handler:    _Classes_GetEnrollmentSettings_Handler,
+		},
+		{
+			MethodName: "SetEnrollmentSettings",
+			Handler:    _Classes_SetEnrollmentSettings_Handler,
+		},
+		{
+			MethodName: "LinkGuardian",
+			Handler:    _Classes_LinkGuardian_Handler,
+		},
+		{
+			MethodName: "UnlinkGuardian",
+			Handler:    _Classes_UnlinkGuardian_Handler,
+		},
+		{
+			MethodName: "ListStudents",
+			Handler:    _Classes_ListStudents_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "classsvc.proto",
+}
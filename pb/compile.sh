@@ -0,0 +1,6 @@
+#!/usr/bin/env sh
+
+# Generates classsvc.pb.go from classsvc.proto. Requires protoc and protoc-gen-go.
+# See also: https://github.com/grpc/grpc-go/tree/master/examples
+
+protoc classsvc.proto --go_out=plugins=grpc:.
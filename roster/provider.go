@@ -0,0 +1,43 @@
+// Package roster keeps classes and members in step with external rosters,
+// such as a student information system, on a schedule.
+package roster
+
+import (
+	"context"
+
+	"github.com/studiously/classsvc/models"
+)
+
+// Class is a class as an external roster knows it.
+type Class struct {
+	// ExternalID identifies the class within the provider.
+	ExternalID string `json:"id"`
+	Name       string `json:"name"`
+}
+
+// Enrollment places a user in a class.
+type Enrollment struct {
+	ClassExternalID string `json:"class_id"`
+	// UserExternalID identifies the user within the provider. IDs that are UUIDs are taken
+	// to be Studiously user IDs; others must be mapped onto one in external_refs.
+	UserExternalID string          `json:"user_id"`
+	Role           models.UserRole `json:"role"`
+	// Primary marks the teacher who owns a class the provider creates.
+	Primary bool `json:"primary,omitempty"`
+}
+
+// Roster is a complete snapshot of the classes and enrollments a provider manages.
+type Roster struct {
+	Classes     []Class      `json:"classes"`
+	Enrollments []Enrollment `json:"enrollments"`
+}
+
+// RosterProvider is an external source of classes and enrollments.
+type RosterProvider interface {
+	// Name identifies the provider. It scopes the external IDs the provider
+	// hands out, so it must not change between syncs.
+	Name() string
+	// Roster returns the provider's current roster. Classes the provider has
+	// synced before but leaves out of the roster are deactivated.
+	Roster(ctx context.Context) (*Roster, error)
+}
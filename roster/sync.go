@@ -0,0 +1,398 @@
+package roster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/models"
+)
+
+// ConflictPolicy decides what happens when a member was changed in classsvc
+// since the last sync and the provider disagrees with the change.
+type ConflictPolicy int
+
+const (
+	// PreferLocal keeps changes made in classsvc: a role set locally is kept, a
+	// member who left is not re-added, and a member whose role was changed locally
+	// is not removed when they drop off the roster.
+	PreferLocal ConflictPolicy = iota
+	// PreferProvider makes the provider authoritative for the members it manages.
+	PreferProvider
+)
+
+// ParseConflictPolicy parses "local" or "provider".
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch s {
+	case "", "local":
+		return PreferLocal, nil
+	case "provider":
+		return PreferProvider, nil
+	default:
+		return 0, fmt.Errorf("unknown conflict policy %q", s)
+	}
+}
+
+// Report describes the changes made by a sync.
+type Report struct {
+	ClassesCreated     int
+	ClassesUpdated     int
+	ClassesDeactivated int
+	MembersAdded       int
+	MembersUpdated     int
+	MembersRemoved     int
+	// MembersWaitlisted counts users put on the waitlist of a full class.
+	MembersWaitlisted int
+	// Conflicts lists members left alone because of local changes or because classsvc refused
+	// the change, and users whose external ID is not mapped onto a user ID.
+	Conflicts []string
+}
+
+// Syncer reconciles classes and members with roster providers.
+//
+// Members are only managed by a provider once it has synced them; members
+// who joined a class directly are never changed or removed by a sync. The
+// last role each provider set is remembered in roster_members, which lets
+// the syncer tell local changes from provider changes. Owners are never
+// demoted or removed.
+//
+// Changes go through classsvc.Provisioning, so seat limits, waitlists, roster
+// locks and enrollment windows apply to them, and each one is recorded.
+type Syncer struct {
+	db     *sql.DB
+	policy ConflictPolicy
+	record classsvc.RecordFunc
+}
+
+func NewSyncer(db *sql.DB, policy ConflictPolicy) *Syncer {
+	return &Syncer{db, policy, nil}
+}
+
+// NewSyncerWithRecorder returns a Syncer that records every change to a class with record, in
+// the transaction of the sync, like the classsvc Service does.
+func NewSyncerWithRecorder(db *sql.DB, policy ConflictPolicy, record classsvc.RecordFunc) *Syncer {
+	return &Syncer{db, policy, record}
+}
+
+// Sync fetches the provider's roster and applies it in a single transaction.
+func (s *Syncer) Sync(ctx context.Context, p RosterProvider) (*Report, error) {
+	r, err := p.Roster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.apply(ctx, classsvc.Provision(tx, s.record), tx, p.Name(), r)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return report, nil
+}
+
+// Run syncs the provider every interval until ctx is done, logging each result.
+func (s *Syncer) Run(ctx context.Context, p RosterProvider, interval time.Duration, logger log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		begin := time.Now()
+		report, err := s.Sync(ctx, p)
+		if err != nil {
+			logger.Log("provider", p.Name(), "duration", time.Since(begin), "error", err)
+		} else {
+			logger.Log(
+				"provider", p.Name(),
+				"classes_created", report.ClassesCreated,
+				"classes_updated", report.ClassesUpdated,
+				"classes_deactivated", report.ClassesDeactivated,
+				"members_added", report.MembersAdded,
+				"members_updated", report.MembersUpdated,
+				"members_removed", report.MembersRemoved,
+				"members_waitlisted", report.MembersWaitlisted,
+				"conflicts", len(report.Conflicts),
+				"duration", time.Since(begin),
+			)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Syncer) apply(ctx context.Context, p *classsvc.Provisioning, tx *sql.Tx, provider string, r *Roster) (*Report, error) {
+	var report Report
+	source := "roster:" + provider
+
+	classes := make(map[string]uuid.UUID, len(r.Classes))
+	for _, c := range r.Classes {
+		ref, err := models.ExternalRefBySourceKindExternalID(tx, source, "class", c.ExternalID)
+		switch err {
+		case nil:
+			class, err := models.ClassByID(tx, ref.ID)
+			if err != nil {
+				return nil, err
+			}
+			if class.Name != c.Name || !class.Active {
+				if err := p.ActivateClass(ctx, class.ID); err != nil {
+					return nil, err
+				}
+				if err := p.RenameClass(ctx, class.ID, c.Name); err != nil {
+					return nil, err
+				}
+				report.ClassesUpdated++
+			}
+			classes[c.ExternalID] = class.ID
+		case sql.ErrNoRows:
+			class, err := p.CreateClass(ctx, c.Name)
+			if err != nil {
+				return nil, err
+			}
+			ref := models.ExternalRef{Source: source, Kind: "class", ExternalID: c.ExternalID, ID: class.ID}
+			if err := ref.Save(tx); err != nil {
+				return nil, err
+			}
+			classes[c.ExternalID] = class.ID
+			report.ClassesCreated++
+		default:
+			return nil, err
+		}
+	}
+
+	// Classes synced before but missing from the roster are deactivated like DeleteClass does.
+	rows, err := tx.Query("SELECT r.external_id, c.id FROM external_refs r JOIN classes c ON c.id = r.id WHERE r.source=$1 AND r.kind='class' AND c.active;", source)
+	if err != nil {
+		return nil, err
+	}
+	var stale []uuid.UUID
+	for rows.Next() {
+		var externalID string
+		var classID uuid.UUID
+		if err := rows.Scan(&externalID, &classID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if _, ok := classes[externalID]; !ok {
+			stale = append(stale, classID)
+		}
+	}
+	rows.Close()
+	for _, classID := range stale {
+		if err := p.DeactivateClass(ctx, classID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec("DELETE FROM roster_members WHERE provider=$1 AND class_id=$2;", provider, classID); err != nil {
+			return nil, err
+		}
+		report.ClassesDeactivated++
+	}
+
+	enrollments := make(map[uuid.UUID]*wantedMembers, len(classes))
+	for _, classID := range classes {
+		enrollments[classID] = &wantedMembers{enrollments: make(map[uuid.UUID]Enrollment)}
+	}
+	for _, e := range r.Enrollments {
+		classID, ok := classes[e.ClassExternalID]
+		if !ok {
+			return nil, fmt.Errorf("enrollment of %s references unknown class %s", e.UserExternalID, e.ClassExternalID)
+		}
+		userID, ok, err := resolveUser(tx, source, e.UserExternalID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			report.Conflicts = append(report.Conflicts, fmt.Sprintf("class %s user %s: not mapped onto a user ID", e.ClassExternalID, e.UserExternalID))
+			continue
+		}
+		enrollments[classID].add(userID, e)
+	}
+	for classID, wanted := range enrollments {
+		if err := s.applyMembers(ctx, p, tx, provider, classID, wanted, &report); err != nil {
+			return nil, err
+		}
+	}
+	return &report, nil
+}
+
+// wantedMembers holds the enrollments of a class in roster order, so that users are added, and
+// waitlisted, in the order the provider lists them.
+type wantedMembers struct {
+	order       []uuid.UUID
+	enrollments map[uuid.UUID]Enrollment
+}
+
+func (w *wantedMembers) add(userID uuid.UUID, e Enrollment) {
+	if _, ok := w.enrollments[userID]; !ok {
+		w.order = append(w.order, userID)
+	}
+	w.enrollments[userID] = e
+}
+
+func (s *Syncer) applyMembers(ctx context.Context, p *classsvc.Provisioning, tx *sql.Tx, provider string, classID uuid.UUID, wanted *wantedMembers, report *Report) error {
+	synced, err := models.RosterMembersByProviderClassID(tx, provider, classID)
+	if err != nil {
+		return err
+	}
+	last := make(map[uuid.UUID]*models.RosterMember, len(synced))
+	for _, rm := range synced {
+		last[rm.UserID] = rm
+	}
+	members, err := models.MembersByClassID(tx, classID)
+	if err != nil {
+		return err
+	}
+	current := make(map[uuid.UUID]*models.Member, len(members))
+	hasOwner := false
+	for _, m := range members {
+		current[m.UserID] = m
+		hasOwner = hasOwner || m.Owner
+	}
+	entries, err := models.WaitlistEntriesByClassID(tx, classID)
+	if err != nil {
+		return err
+	}
+	waiting := make(map[uuid.UUID]bool, len(entries))
+	for _, entry := range entries {
+		waiting[entry.UserID] = true
+	}
+	conflict := func(userID uuid.UUID, reason string) {
+		report.Conflicts = append(report.Conflicts, fmt.Sprintf("class %s member %s: %s", classID, userID, reason))
+	}
+
+	for _, userID := range wanted.order {
+		e := wanted.enrollments[userID]
+		member, enrolled := current[userID]
+		base, managed := last[userID]
+		switch {
+		case !enrolled && waiting[userID]:
+			// Still waiting for a seat.
+		case !enrolled && managed && s.policy == PreferLocal:
+			// Synced before and removed locally since.
+			conflict(userID, "left the class")
+			continue
+		case !enrolled:
+			if e.Primary && e.Role == models.UserRoleTeacher && !hasOwner {
+				err = p.SetOwner(ctx, classID, &userID)
+				hasOwner = err == nil
+			} else {
+				err = p.Enroll(ctx, classID, userID, e.Role)
+			}
+			switch {
+			case err == nil:
+				report.MembersAdded++
+			case err == classsvc.ErrWaitlisted:
+				report.MembersWaitlisted++
+			case refused(err):
+				conflict(userID, err.Error())
+				continue
+			default:
+				return err
+			}
+		case member.Owner || member.Role == e.Role:
+		case !managed:
+			// Joined directly; the provider does not manage this member.
+		case member.Role != base.Role && s.policy == PreferLocal:
+			conflict(userID, "role changed locally")
+		default:
+			err = p.SetRole(ctx, classID, userID, e.Role)
+			switch {
+			case err == nil:
+				report.MembersUpdated++
+			case refused(err):
+				// The last synced role is kept, so the change is tried again on the next sync.
+				conflict(userID, err.Error())
+				continue
+			default:
+				return err
+			}
+		}
+		if !managed && (enrolled || waiting[userID]) {
+			// Only members the sync added are managed by the provider; members who joined
+			// directly stay unmanaged even when the roster lists them.
+			continue
+		}
+		rm := models.RosterMember{Provider: provider, ClassID: classID, UserID: userID, Role: e.Role}
+		if managed {
+			rm = *base
+			rm.Role = e.Role
+		}
+		if err := rm.Save(tx); err != nil {
+			return err
+		}
+	}
+
+	for userID, base := range last {
+		if _, ok := wanted.enrollments[userID]; ok {
+			continue
+		}
+		// Dropped from the roster.
+		member, enrolled := current[userID]
+		switch {
+		case !enrolled && !waiting[userID]:
+		case enrolled && member.Owner:
+			conflict(userID, "owner dropped from roster")
+		case enrolled && member.Role != base.Role && s.policy == PreferLocal:
+			conflict(userID, "role changed locally; now managed locally")
+		default:
+			// Users still waiting for a seat leave the waitlist.
+			err = p.Remove(ctx, classID, userID)
+			switch {
+			case err == nil:
+				if enrolled {
+					report.MembersRemoved++
+				}
+			case refused(err):
+				// The member stays managed, so the removal is tried again on the next sync.
+				conflict(userID, err.Error())
+				continue
+			default:
+				return err
+			}
+		}
+		if err := base.Delete(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refused reports whether err is classsvc refusing a change to a roster, which a sync reports as
+// a conflict.
+func refused(err error) bool {
+	switch err {
+	case classsvc.ErrRosterLocked, classsvc.ErrEnrollmentClosed, classsvc.ErrClassFull, classsvc.ErrMustSetOwner:
+		return true
+	}
+	return false
+}
+
+// resolveUser maps a provider user ID onto a user ID. UUIDs are taken as-is; anything else
+// must be mapped onto a user in external_refs, with source "roster:<provider>" and kind
+// "user". Users are created by the identity provider, not by syncs, so unmapped IDs resolve
+// to nothing.
+func resolveUser(tx *sql.Tx, source, externalID string) (uuid.UUID, bool, error) {
+	if id, err := uuid.Parse(externalID); err == nil {
+		return id, true, nil
+	}
+	ref, err := models.ExternalRefBySourceKindExternalID(tx, source, "user", externalID)
+	switch err {
+	case nil:
+		return ref.ID, true, nil
+	case sql.ErrNoRows:
+		return uuid.Nil, false, nil
+	default:
+		return uuid.Nil, false, err
+	}
+}
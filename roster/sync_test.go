@@ -0,0 +1,273 @@
+package roster
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/dbtest"
+	"github.com/studiously/classsvc/models"
+)
+
+// staticProvider serves a roster held in memory.
+type staticProvider struct {
+	roster Roster
+}
+
+func (p *staticProvider) Name() string {
+	return "sis"
+}
+
+func (p *staticProvider) Roster(_ context.Context) (*Roster, error) {
+	return &p.roster, nil
+}
+
+// change makes a change to classes outside of a sync, as a user of classsvc would.
+func change(t *testing.T, db *sql.DB, fn func(p *classsvc.Provisioning) error) {
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fn(classsvc.Provision(tx, nil)); err != nil {
+		tx.Rollback()
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// roles returns the role of every member of a class, with owners as "owner".
+func roles(t *testing.T, db *sql.DB, classID uuid.UUID) map[uuid.UUID]string {
+	members, err := models.MembersByClassID(db, classID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles := make(map[uuid.UUID]string, len(members))
+	for _, m := range members {
+		roles[m.UserID] = m.Role.String()
+		if m.Owner {
+			roles[m.UserID] = "owner"
+		}
+	}
+	return roles
+}
+
+func hasConflict(report *Report, userID, reason string) bool {
+	for _, c := range report.Conflicts {
+		if strings.Contains(c, userID) && strings.HasSuffix(c, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSyncConflictPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ConflictPolicy
+		// want are the roles of the members after the second sync.
+		want func(teacher, left, promoted, direct uuid.UUID) map[uuid.UUID]string
+		// conflicts are the users the second sync reports, with their reason.
+		conflicts map[string]string
+	}{
+		{
+			name:   "local",
+			policy: PreferLocal,
+			want: func(teacher, left, promoted, direct uuid.UUID) map[uuid.UUID]string {
+				return map[uuid.UUID]string{teacher: "owner", promoted: "teacher", direct: "student"}
+			},
+			conflicts: map[string]string{"left": "left the class", "promoted": "role changed locally"},
+		},
+		{
+			name:   "provider",
+			policy: PreferProvider,
+			want: func(teacher, left, promoted, direct uuid.UUID) map[uuid.UUID]string {
+				return map[uuid.UUID]string{teacher: "owner", left: "student", promoted: "student", direct: "student"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := dbtest.Open(t)
+			defer db.Close()
+			ctx := context.Background()
+			teacher, left, promoted, direct := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+			ids := map[string]uuid.UUID{"left": left, "promoted": promoted}
+			p := &staticProvider{Roster{
+				Classes: []Class{{ExternalID: "c-1", Name: "Algebra I"}},
+				Enrollments: []Enrollment{
+					{ClassExternalID: "c-1", UserExternalID: teacher.String(), Role: models.UserRoleTeacher, Primary: true},
+					{ClassExternalID: "c-1", UserExternalID: left.String(), Role: models.UserRoleStudent},
+					{ClassExternalID: "c-1", UserExternalID: promoted.String(), Role: models.UserRoleStudent},
+					{ClassExternalID: "c-1", UserExternalID: "sis-7", Role: models.UserRoleStudent},
+				},
+			}}
+			syncer := NewSyncer(db, tt.policy)
+
+			report, err := syncer.Sync(ctx, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.ClassesCreated != 1 || report.MembersAdded != 3 {
+				t.Errorf("first sync = %+v; want 1 class and 3 members added", report)
+			}
+			if !hasConflict(report, "sis-7", "not mapped onto a user ID") {
+				t.Errorf("conflicts = %q; want sis-7 reported as not mapped", report.Conflicts)
+			}
+			ref, err := models.ExternalRefBySourceKindExternalID(db, "roster:sis", "class", "c-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			classID := ref.ID
+
+			// Changes made in classsvc since the first sync.
+			change(t, db, func(p *classsvc.Provisioning) error {
+				if err := p.Remove(ctx, classID, left); err != nil {
+					return err
+				}
+				if err := p.SetRole(ctx, classID, promoted, models.UserRoleTeacher); err != nil {
+					return err
+				}
+				return p.Enroll(ctx, classID, direct, models.UserRoleStudent)
+			})
+
+			report, err = syncer.Sync(ctx, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := roles(t, db, classID)
+			want := tt.want(teacher, left, promoted, direct)
+			if len(got) != len(want) {
+				t.Errorf("members = %v; want %v", got, want)
+			}
+			for userID, role := range want {
+				if got[userID] != role {
+					t.Errorf("member %s = %q; want %q", userID, got[userID], role)
+				}
+			}
+			for name, reason := range tt.conflicts {
+				if !hasConflict(report, ids[name].String(), reason) {
+					t.Errorf("conflicts = %q; want %s reported as %q", report.Conflicts, name, reason)
+				}
+			}
+			if len(report.Conflicts) != len(tt.conflicts)+1 {
+				t.Errorf("conflicts = %q; want %d", report.Conflicts, len(tt.conflicts)+1)
+			}
+		})
+	}
+}
+
+func TestSyncDroppedMembers(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ConflictPolicy
+		// kept reports whether the student whose role was changed locally stays enrolled.
+		kept bool
+	}{
+		{"local", PreferLocal, true},
+		{"provider", PreferProvider, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := dbtest.Open(t)
+			defer db.Close()
+			ctx := context.Background()
+			teacher, changed, dropped, direct := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+			p := &staticProvider{Roster{
+				Classes: []Class{{ExternalID: "c-1", Name: "Algebra I"}},
+				Enrollments: []Enrollment{
+					{ClassExternalID: "c-1", UserExternalID: teacher.String(), Role: models.UserRoleTeacher, Primary: true},
+					{ClassExternalID: "c-1", UserExternalID: changed.String(), Role: models.UserRoleStudent},
+					{ClassExternalID: "c-1", UserExternalID: dropped.String(), Role: models.UserRoleStudent},
+				},
+			}}
+			syncer := NewSyncer(db, tt.policy)
+			if _, err := syncer.Sync(ctx, p); err != nil {
+				t.Fatal(err)
+			}
+			ref, err := models.ExternalRefBySourceKindExternalID(db, "roster:sis", "class", "c-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			change(t, db, func(p *classsvc.Provisioning) error {
+				if err := p.SetRole(ctx, ref.ID, changed, models.UserRoleTeacher); err != nil {
+					return err
+				}
+				return p.Enroll(ctx, ref.ID, direct, models.UserRoleStudent)
+			})
+
+			// Everyone but the owner drops off the roster.
+			p.roster.Enrollments = p.roster.Enrollments[:1]
+			report, err := syncer.Sync(ctx, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := roles(t, db, ref.ID)
+			if _, ok := got[dropped]; ok {
+				t.Error("dropped member is still enrolled")
+			}
+			if _, ok := got[direct]; !ok {
+				t.Error("member who joined directly was removed")
+			}
+			if got[teacher] != "owner" {
+				t.Errorf("owner = %q; want kept", got[teacher])
+			}
+			if _, ok := got[changed]; ok != tt.kept {
+				t.Errorf("member whose role changed locally enrolled = %t; want %t", ok, tt.kept)
+			}
+			if tt.kept && !hasConflict(report, changed.String(), "role changed locally; now managed locally") {
+				t.Errorf("conflicts = %q; want the local role change reported", report.Conflicts)
+			}
+		})
+	}
+}
+
+func TestSyncDeactivatesStaleClasses(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	ctx := context.Background()
+	p := &staticProvider{Roster{
+		Classes: []Class{{ExternalID: "c-1", Name: "Algebra I"}, {ExternalID: "c-2", Name: "Geometry"}},
+	}}
+	syncer := NewSyncer(db, PreferLocal)
+	if _, err := syncer.Sync(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+
+	p.roster.Classes = []Class{{ExternalID: "c-1", Name: "Algebra II"}}
+	report, err := syncer.Sync(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ClassesUpdated != 1 || report.ClassesDeactivated != 1 {
+		t.Errorf("report = %+v; want 1 class renamed and 1 deactivated", report)
+	}
+	ref, err := models.ExternalRefBySourceKindExternalID(db, "roster:sis", "class", "c-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	class, err := models.ClassByID(db, ref.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if class.Active {
+		t.Error("class missing from the roster is still active")
+	}
+
+	// Listing it again brings it back.
+	p.roster.Classes = append(p.roster.Classes, Class{ExternalID: "c-2", Name: "Geometry"})
+	if _, err := syncer.Sync(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	class, err = models.ClassByID(db, ref.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !class.Active {
+		t.Error("class listed again is still inactive")
+	}
+}
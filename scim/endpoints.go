@@ -0,0 +1,140 @@
+package scim
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+// Endpoints collects all of the endpoints that compose the SCIM service.
+type Endpoints struct {
+	ListGroupsEndpoint  endpoint.Endpoint
+	GetGroupEndpoint    endpoint.Endpoint
+	CreateGroupEndpoint endpoint.Endpoint
+	PatchGroupEndpoint  endpoint.Endpoint
+	DeleteGroupEndpoint endpoint.Endpoint
+}
+
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		ListGroupsEndpoint:  MakeListGroupsEndpoint(s),
+		GetGroupEndpoint:    MakeGetGroupEndpoint(s),
+		CreateGroupEndpoint: MakeCreateGroupEndpoint(s),
+		PatchGroupEndpoint:  MakePatchGroupEndpoint(s),
+		DeleteGroupEndpoint: MakeDeleteGroupEndpoint(s),
+	}
+}
+
+func MakeListGroupsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(listGroupsRequest)
+		list, e := s.ListGroups(ctx, req.Query)
+		if e == nil {
+			for _, g := range list.Resources {
+				locate(ctx, g)
+			}
+		}
+		return listGroupsResponse{list, e}, nil
+	}
+}
+
+type listGroupsRequest struct {
+	Query Query
+}
+
+type listGroupsResponse struct {
+	List  *ListResponse
+	Error error
+}
+
+func (r listGroupsResponse) error() error {
+	return r.Error
+}
+
+func MakeGetGroupEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getGroupRequest)
+		g, e := s.GetGroup(ctx, req.GroupID)
+		if e == nil {
+			locate(ctx, g)
+		}
+		return groupResponse{g, e}, nil
+	}
+}
+
+type getGroupRequest struct {
+	GroupID uuid.UUID
+}
+
+// groupResponse carries a single group, written with a Location header.
+type groupResponse struct {
+	Group *Group
+	Error error
+}
+
+func (r groupResponse) error() error {
+	return r.Error
+}
+
+func MakeCreateGroupEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createGroupRequest)
+		g, e := s.CreateGroup(ctx, req.Group)
+		if e == nil {
+			locate(ctx, g)
+		}
+		return createGroupResponse{groupResponse{g, e}}, nil
+	}
+}
+
+type createGroupRequest struct {
+	Group Group
+}
+
+type createGroupResponse struct {
+	groupResponse
+}
+
+func MakePatchGroupEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(patchGroupRequest)
+		g, e := s.PatchGroup(ctx, req.GroupID, req.Operations)
+		if e == nil {
+			locate(ctx, g)
+		}
+		return groupResponse{g, e}, nil
+	}
+}
+
+type patchGroupRequest struct {
+	GroupID    uuid.UUID
+	Operations []PatchOperation
+}
+
+func MakeDeleteGroupEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(deleteGroupRequest)
+		e := s.DeleteGroup(ctx, req.GroupID)
+		return deleteGroupResponse{e}, nil
+	}
+}
+
+type deleteGroupRequest struct {
+	GroupID uuid.UUID
+}
+
+type deleteGroupResponse struct {
+	Error error
+}
+
+func (r deleteGroupResponse) error() error {
+	return r.Error
+}
+
+// locate sets the location of a group from the base URL of the request.
+func locate(ctx context.Context, g *Group) {
+	if base, ok := ctx.Value(baseURLContextKey).(string); ok && g.Meta != nil {
+		g.Meta.Location = base + "/Groups/" + g.ID.String()
+	}
+}
@@ -0,0 +1,303 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filters are compiled to SQL over "classes c". Expressions in a value path such as
+// members[value eq "..."] are compiled over "members m" instead, which is also how
+// PATCH paths select the members they apply to.
+//
+// All supported attributes are strings with caseExact false, so comparisons ignore case.
+
+type scope int
+
+const (
+	groupScope scope = iota
+	memberScope
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tokens = append(tokens, token{tokenPunct, string(c)})
+			i++
+		case c == '"':
+			// Strings are JSON strings, escapes included.
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				return nil, invalidFilter("unterminated string")
+			}
+			var text string
+			if err := json.Unmarshal([]byte(s[i:j+1]), &text); err != nil {
+				return nil, invalidFilter("malformed string " + s[i:j+1])
+			}
+			tokens = append(tokens, token{tokenString, text})
+			i = j + 1
+		default:
+			j := i
+			for ; j < len(s) && isWordByte(s[j]); j++ {
+			}
+			if j == i {
+				return nil, invalidFilter(fmt.Sprintf("unexpected %q", c))
+			}
+			tokens = append(tokens, token{tokenWord, s[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+func isWordByte(c byte) bool {
+	return c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || strings.IndexByte(":._-$+", c) >= 0)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	scope  scope
+	// args holds query arguments; placeholders are numbered after any arguments already present.
+	args []interface{}
+}
+
+// compileFilter compiles filter into a SQL condition in the given scope, appending its
+// arguments to args.
+func compileFilter(filter string, sc scope, args []interface{}) (string, []interface{}, error) {
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(tokens) == 0 {
+		return "", nil, invalidFilter("empty filter")
+	}
+	p := &parser{tokens: tokens, scope: sc, args: args}
+	cond, err := p.or()
+	if err != nil {
+		return "", nil, err
+	}
+	if t, ok := p.peek(); ok {
+		return "", nil, invalidFilter("unexpected " + t.text)
+	}
+	return cond, p.args, nil
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, invalidFilter("unexpected end of filter")
+	}
+	p.pos++
+	return t, nil
+}
+
+func (p *parser) keyword(word string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokenWord && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != tokenPunct || t.text != punct {
+		return invalidFilter(fmt.Sprintf("expected %s, got %s", punct, t.text))
+	}
+	return nil
+}
+
+func (p *parser) or() (string, error) {
+	cond, err := p.and()
+	if err != nil {
+		return "", err
+	}
+	for p.keyword("or") {
+		right, err := p.and()
+		if err != nil {
+			return "", err
+		}
+		cond = "(" + cond + " OR " + right + ")"
+	}
+	return cond, nil
+}
+
+func (p *parser) and() (string, error) {
+	cond, err := p.not()
+	if err != nil {
+		return "", err
+	}
+	for p.keyword("and") {
+		right, err := p.not()
+		if err != nil {
+			return "", err
+		}
+		cond = "(" + cond + " AND " + right + ")"
+	}
+	return cond, nil
+}
+
+func (p *parser) not() (string, error) {
+	if p.keyword("not") {
+		if err := p.expect("("); err != nil {
+			return "", err
+		}
+		cond, err := p.or()
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + cond + ")", p.expect(")")
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if t.kind == tokenPunct && t.text == "(" {
+		cond, err := p.or()
+		if err != nil {
+			return "", err
+		}
+		return "(" + cond + ")", p.expect(")")
+	}
+	if t.kind != tokenWord {
+		return "", invalidFilter("expected attribute, got " + t.text)
+	}
+	attr := attribute(t.text)
+	if next, ok := p.peek(); ok && next.kind == tokenPunct && next.text == "[" {
+		if p.scope != groupScope || attr != "members" {
+			return "", invalidFilter("value filters are only supported on members")
+		}
+		p.pos++
+		p.scope = memberScope
+		cond, err := p.or()
+		p.scope = groupScope
+		if err != nil {
+			return "", err
+		}
+		return memberExists(cond), p.expect("]")
+	}
+	op, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if op.kind != tokenWord {
+		return "", invalidFilter("expected operator, got " + op.text)
+	}
+	if strings.EqualFold(op.text, "pr") {
+		return p.compare(attr, "pr", nil)
+	}
+	value, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if value.kind != tokenString {
+		return "", invalidFilter(fmt.Sprintf("%s must be compared with a string", t.text))
+	}
+	return p.compare(attr, strings.ToLower(op.text), &value.text)
+}
+
+// attribute normalizes an attribute path: names are case-insensitive and may be
+// qualified with the Group schema URN.
+func attribute(path string) string {
+	path = strings.ToLower(path)
+	return strings.TrimPrefix(path, strings.ToLower(SchemaGroup)+":")
+}
+
+func (p *parser) compare(attr, op string, value *string) (string, error) {
+	var column string
+	switch {
+	case p.scope == groupScope && attr == "id":
+		column = "c.id::text"
+	case p.scope == groupScope && attr == "displayname":
+		column = "c.name"
+	case p.scope == groupScope && attr == "members" && op == "pr":
+		return memberExists("TRUE"), nil
+	case p.scope == groupScope && (attr == "members.value" || attr == "members.type"):
+		p.scope = memberScope
+		cond, err := p.compare(strings.TrimPrefix(attr, "members."), op, value)
+		p.scope = groupScope
+		if err != nil {
+			return "", err
+		}
+		return memberExists(cond), nil
+	case p.scope == memberScope && attr == "value":
+		column = "m.user_id::text"
+	case p.scope == memberScope && attr == "type":
+		column = "'User'"
+	default:
+		return "", invalidFilter("unsupported attribute " + attr)
+	}
+	column = "lower(" + column + ")"
+	if op == "pr" {
+		return "(" + column + " IS NOT NULL AND " + column + " <> '')", nil
+	}
+	p.args = append(p.args, *value)
+	arg := "lower($" + strconv.Itoa(len(p.args)) + ")"
+	switch op {
+	case "eq":
+		return column + " = " + arg, nil
+	case "ne":
+		return column + " <> " + arg, nil
+	case "co":
+		return "strpos(" + column + ", " + arg + ") > 0", nil
+	case "sw":
+		return "strpos(" + column + ", " + arg + ") = 1", nil
+	case "ew":
+		return "right(" + column + ", length(" + arg + ")) = " + arg, nil
+	case "gt":
+		return column + " > " + arg, nil
+	case "ge":
+		return column + " >= " + arg, nil
+	case "lt":
+		return column + " < " + arg, nil
+	case "le":
+		return column + " <= " + arg, nil
+	default:
+		return "", invalidFilter("unsupported operator " + op)
+	}
+}
+
+func memberExists(cond string) string {
+	return "EXISTS (SELECT 1 FROM members m WHERE m.class_id = c.id AND " + cond + ")"
+}
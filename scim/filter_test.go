@@ -0,0 +1,122 @@
+package scim
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompileFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		scope  scope
+		args   []interface{}
+		want   string
+		params []interface{}
+	}{
+		{
+			filter: `displayName eq "Math"`,
+			want:   "lower(c.name) = lower($1)",
+			params: []interface{}{"Math"},
+		},
+		{
+			filter: `DISPLAYNAME Sw "ma"`,
+			want:   "strpos(lower(c.name), lower($1)) = 1",
+			params: []interface{}{"ma"},
+		},
+		{
+			filter: `urn:ietf:params:scim:schemas:core:2.0:Group:displayName co "at"`,
+			want:   "strpos(lower(c.name), lower($1)) > 0",
+			params: []interface{}{"at"},
+		},
+		{
+			filter: `displayName ew "h"`,
+			want:   "right(lower(c.name), length(lower($1))) = lower($1)",
+			params: []interface{}{"h"},
+		},
+		{
+			filter: `displayName pr`,
+			want:   "(lower(c.name) IS NOT NULL AND lower(c.name) <> '')",
+		},
+		{
+			filter: `members pr`,
+			want:   "EXISTS (SELECT 1 FROM members m WHERE m.class_id = c.id AND TRUE)",
+		},
+		{
+			filter: `id eq "a" or displayName ne "b" and displayName gt "c"`,
+			want:   "(lower(c.id::text) = lower($1) OR (lower(c.name) <> lower($2) AND lower(c.name) > lower($3)))",
+			params: []interface{}{"a", "b", "c"},
+		},
+		{
+			filter: `not (displayName le "m") and (id eq "x")`,
+			want:   "(NOT (lower(c.name) <= lower($1)) AND (lower(c.id::text) = lower($2)))",
+			params: []interface{}{"m", "x"},
+		},
+		{
+			filter: `members[value eq "u1" or type eq "User"]`,
+			want:   "EXISTS (SELECT 1 FROM members m WHERE m.class_id = c.id AND (lower(m.user_id::text) = lower($1) OR lower('User') = lower($2)))",
+			params: []interface{}{"u1", "User"},
+		},
+		{
+			filter: `members.value eq "u1"`,
+			want:   "EXISTS (SELECT 1 FROM members m WHERE m.class_id = c.id AND lower(m.user_id::text) = lower($1))",
+			params: []interface{}{"u1"},
+		},
+		{
+			filter: `displayName eq "say \"hi\""`,
+			args:   []interface{}{"existing"},
+			want:   "lower(c.name) = lower($2)",
+			params: []interface{}{"existing", `say "hi"`},
+		},
+		{
+			filter: `value ge "u"`,
+			scope:  memberScope,
+			want:   "lower(m.user_id::text) >= lower($1)",
+			params: []interface{}{"u"},
+		},
+	}
+	for _, tt := range tests {
+		cond, params, err := compileFilter(tt.filter, tt.scope, tt.args)
+		if err != nil {
+			t.Errorf("compileFilter(%q) failed: %v", tt.filter, err)
+			continue
+		}
+		if cond != tt.want {
+			t.Errorf("compileFilter(%q) = %q, want %q", tt.filter, cond, tt.want)
+		}
+		if !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("compileFilter(%q) args = %v, want %v", tt.filter, params, tt.params)
+		}
+	}
+}
+
+func TestCompileFilterInvalid(t *testing.T) {
+	tests := []struct {
+		filter string
+		scope  scope
+	}{
+		{filter: ``},
+		{filter: `   `},
+		{filter: `displayName`},
+		{filter: `displayName eq`},
+		{filter: `displayName eq Math`},
+		{filter: `displayName eq "Math`},
+		{filter: `displayName xx "Math"`},
+		{filter: `externalId eq "x"`},
+		{filter: `displayName eq "a" extra`},
+		{filter: `(displayName eq "a"`},
+		{filter: `not displayName eq "a"`},
+		{filter: `displayName[value eq "a"]`},
+		{filter: `members[value eq "a"`},
+		{filter: `displayName eq "a" and`},
+		{filter: `displayName eq "a" & id eq "b"`},
+		{filter: `displayName eq "a"`, scope: memberScope},
+		{filter: `members[value eq "a"]`, scope: memberScope},
+	}
+	for _, tt := range tests {
+		_, _, err := compileFilter(tt.filter, tt.scope, nil)
+		bad, ok := err.(BadRequestError)
+		if !ok || bad.ScimType != "invalidFilter" {
+			t.Errorf("compileFilter(%q) = %v, want an invalidFilter error", tt.filter, err)
+		}
+	}
+}
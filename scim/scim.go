@@ -0,0 +1,95 @@
+// Package scim exposes classes as SCIM 2.0 (RFC 7643, RFC 7644) Groups so that
+// identity providers can provision class membership.
+//
+// A class is a Group whose members are the users enrolled in it. Groups created
+// over SCIM are created like CreateClass, members added to a group join it like
+// JoinClass (as students), removed members leave it like LeaveClass, and deleting
+// a group deactivates the class like DeleteClass.
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaClass        = "urn:studiously:params:scim:schemas:extension:class:2.0:Group"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// MediaType is the content type of SCIM requests and responses.
+	MediaType = "application/scim+json"
+)
+
+// Group is a class as a SCIM Group resource.
+type Group struct {
+	Schemas     []string  `json:"schemas"`
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"displayName"`
+	// Members is omitted when excluded from a response.
+	Members []Member `json:"members,omitempty"`
+	// Class carries the attributes of the class extension schema.
+	Class *ClassExtension `json:"urn:studiously:params:scim:schemas:extension:class:2.0:Group,omitempty"`
+	Meta  *Meta           `json:"meta,omitempty"`
+}
+
+// ClassExtension holds class attributes that have no counterpart in the core Group schema.
+type ClassExtension struct {
+	// Owner is the user who owns the class. When a group is created, the owner is
+	// enrolled as a teacher; without one, the class has no owner until one is set.
+	Owner *uuid.UUID `json:"owner,omitempty"`
+}
+
+// Member is a user enrolled in a class.
+type Member struct {
+	Value uuid.UUID `json:"value"`
+	Type  string    `json:"type,omitempty"`
+}
+
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+// ListResponse is a page of query results.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []*Group `json:"Resources"`
+}
+
+// PatchOp is the body of a PATCH request.
+type PatchOp struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single add, remove or replace operation.
+type PatchOperation struct {
+	// Op is matched case-insensitively, as some identity providers send "Add" and "Remove".
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Query selects and pages groups.
+type Query struct {
+	// Filter is a SCIM filter expression over id, displayName and members.
+	Filter string
+	// StartIndex is the 1-based index of the first result.
+	StartIndex int
+	// Count is the maximum number of results.
+	Count int
+	// ExcludeMembers leaves members out of the results, as requested by excludedAttributes=members.
+	ExcludeMembers bool
+}
+
+func isSchema(s, schema string) bool {
+	return strings.EqualFold(s, schema)
+}
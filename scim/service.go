@@ -0,0 +1,55 @@
+package scim
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnauthorized = errors.New("token invalid or not found")
+	ErrNotFound     = errors.New("group not found")
+	// ErrMustSetOwner is returned when an operation would remove the owner from a class,
+	// which LeaveClass does not allow until ownership is transferred.
+	ErrMustSetOwner = errors.New("cannot remove the owner of a class unless a new owner is set")
+)
+
+// BadRequestError is a 400 error with a SCIM error type such as "invalidFilter",
+// "invalidPath", "invalidValue" or "noTarget".
+type BadRequestError struct {
+	ScimType string
+	Detail   string
+}
+
+func (e BadRequestError) Error() string {
+	return e.Detail
+}
+
+func invalidFilter(detail string) error {
+	return BadRequestError{"invalidFilter", detail}
+}
+
+func invalidPath(detail string) error {
+	return BadRequestError{"invalidPath", detail}
+}
+
+func invalidValue(detail string) error {
+	return BadRequestError{"invalidValue", detail}
+}
+
+// Service provisions classes as SCIM Groups. It acts on behalf of an identity
+// provider rather than a user, so it is not limited to classes the caller is
+// enrolled in.
+type Service interface {
+	// ListGroups returns a page of active classes matching q.
+	ListGroups(ctx context.Context, q Query) (*ListResponse, error)
+	// GetGroup returns an active class and its members.
+	GetGroup(ctx context.Context, id uuid.UUID) (*Group, error)
+	// CreateGroup creates a class with the given members, ignoring any ID in g.
+	CreateGroup(ctx context.Context, g Group) (*Group, error)
+	// PatchGroup applies PATCH operations to a class atomically.
+	PatchGroup(ctx context.Context, id uuid.UUID, ops []PatchOperation) (*Group, error)
+	// DeleteGroup deactivates a class and removes its members.
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+}
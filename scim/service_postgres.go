@@ -0,0 +1,410 @@
+package scim
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/models"
+)
+
+type postgresService struct {
+	*sql.DB
+	record classsvc.RecordFunc
+}
+
+func New(db *sql.DB) Service {
+	return &postgresService{db, nil}
+}
+
+// NewWithRecorder returns a Service that records every change to a class with record, in the
+// transaction that makes it, like the classsvc Service does.
+func NewWithRecorder(db *sql.DB, record classsvc.RecordFunc) Service {
+	return &postgresService{db, record}
+}
+
+func (s *postgresService) ListGroups(ctx context.Context, q Query) (*ListResponse, error) {
+	where := "c.active"
+	var args []interface{}
+	if q.Filter != "" {
+		var cond string
+		var err error
+		cond, args, err = compileFilter(q.Filter, groupScope, args)
+		if err != nil {
+			return nil, err
+		}
+		where += " AND " + cond
+	}
+	if q.StartIndex < 1 {
+		q.StartIndex = 1
+	}
+	if q.Count < 0 {
+		q.Count = 0
+	}
+	resp := &ListResponse{
+		Schemas:    []string{SchemaListResponse},
+		StartIndex: q.StartIndex,
+		Resources:  []*Group{},
+	}
+	err := s.QueryRowContext(ctx, "SELECT count(*) FROM classes c WHERE "+where+";", args...).Scan(&resp.TotalResults)
+	if err != nil {
+		return nil, err
+	}
+	if q.Count == 0 {
+		return resp, nil
+	}
+	args = append(args, q.StartIndex-1, q.Count)
+	rows, err := s.QueryContext(ctx, "SELECT c.id, c.name FROM classes c WHERE "+where+
+		" ORDER BY c.name, c.id OFFSET $"+strconv.Itoa(len(args)-1)+" LIMIT $"+strconv.Itoa(len(args))+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	groups := make(map[uuid.UUID]*Group)
+	var ids []string
+	for rows.Next() {
+		g := newGroup()
+		if err := rows.Scan(&g.ID, &g.DisplayName); err != nil {
+			return nil, err
+		}
+		resp.Resources = append(resp.Resources, g)
+		groups[g.ID] = g
+		ids = append(ids, g.ID.String())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	resp.ItemsPerPage = len(resp.Resources)
+	if q.ExcludeMembers || len(ids) == 0 {
+		return resp, nil
+	}
+	rows, err = s.QueryContext(ctx, "SELECT class_id, user_id, owner FROM members WHERE class_id = ANY($1::uuid[]) ORDER BY class_id, user_id;", pq.StringArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var classID, userID uuid.UUID
+		var owner bool
+		if err := rows.Scan(&classID, &userID, &owner); err != nil {
+			return nil, err
+		}
+		groups[classID].addMember(userID, owner)
+	}
+	return resp, rows.Err()
+}
+
+func (s *postgresService) GetGroup(ctx context.Context, id uuid.UUID) (*Group, error) {
+	return group(s, id)
+}
+
+func (s *postgresService) CreateGroup(ctx context.Context, g Group) (*Group, error) {
+	if strings.TrimSpace(g.DisplayName) == "" {
+		return nil, invalidValue("displayName is required")
+	}
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	p := classsvc.Provision(tx, s.record)
+	class, err := p.CreateClass(ctx, g.DisplayName)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if g.Class != nil && g.Class.Owner != nil {
+		err = p.SetOwner(ctx, class.ID, g.Class.Owner)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	err = addMembers(ctx, p, class.ID, g.Members)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	created, err := group(tx, class.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *postgresService) PatchGroup(ctx context.Context, id uuid.UUID, ops []PatchOperation) (*Group, error) {
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = activeClass(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	p := classsvc.Provision(tx, s.record)
+	for _, op := range ops {
+		err = patch(ctx, tx, p, id, op)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	patched, err := group(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return patched, nil
+}
+
+func (s *postgresService) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = classsvc.Provision(tx, s.record).DeactivateClass(ctx, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func patch(ctx context.Context, tx *sql.Tx, p *classsvc.Provisioning, classID uuid.UUID, op PatchOperation) error {
+	path := attribute(op.Path)
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+		replace := strings.EqualFold(op.Op, "replace")
+		switch {
+		case path == "":
+			var value struct {
+				DisplayName *string         `json:"displayName"`
+				Members     *[]Member       `json:"members"`
+				Class       *ClassExtension `json:"urn:studiously:params:scim:schemas:extension:class:2.0:Group"`
+			}
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return invalidValue("value must be a Group object")
+			}
+			if value.DisplayName != nil {
+				if err := rename(ctx, p, classID, *value.DisplayName); err != nil {
+					return err
+				}
+			}
+			if value.Class != nil && value.Class.Owner != nil {
+				if err := p.SetOwner(ctx, classID, value.Class.Owner); err != nil {
+					return err
+				}
+			}
+			if value.Members != nil {
+				if replace {
+					return replaceMembers(ctx, tx, p, classID, *value.Members)
+				}
+				return addMembers(ctx, p, classID, *value.Members)
+			}
+			return nil
+		case path == "displayname":
+			var name string
+			if err := json.Unmarshal(op.Value, &name); err != nil {
+				return invalidValue("displayName must be a string")
+			}
+			return rename(ctx, p, classID, name)
+		case path == "members":
+			var members []Member
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return invalidValue("members must be an array of members")
+			}
+			if replace {
+				return replaceMembers(ctx, tx, p, classID, members)
+			}
+			return addMembers(ctx, p, classID, members)
+		case path == strings.ToLower(SchemaClass)+":owner":
+			var owner uuid.UUID
+			if err := json.Unmarshal(op.Value, &owner); err != nil {
+				return invalidValue("owner must be a user ID")
+			}
+			return p.SetOwner(ctx, classID, &owner)
+		}
+	case "remove":
+		switch {
+		case path == "":
+			return BadRequestError{"noTarget", "remove requires a path"}
+		case path == "displayname":
+			return invalidValue("displayName is required")
+		case path == "members":
+			// Some identity providers list the members to remove in the value.
+			if len(op.Value) > 0 {
+				var members []Member
+				if err := json.Unmarshal(op.Value, &members); err != nil {
+					return invalidValue("members must be an array of members")
+				}
+				ids := make([]uuid.UUID, len(members))
+				for i, m := range members {
+					ids[i] = m.Value
+				}
+				return removeMembers(ctx, p, classID, ids)
+			}
+			return removeMatching(ctx, tx, p, classID, "TRUE", nil)
+		case strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]"):
+			// Filters are compiled from the original path, as values are case-sensitive.
+			filter := op.Path[strings.Index(op.Path, "[")+1 : len(op.Path)-1]
+			cond, args, err := compileFilter(filter, memberScope, []interface{}{classID})
+			if err != nil {
+				return invalidPath(err.Error())
+			}
+			return removeMatching(ctx, tx, p, classID, cond, args)
+		case path == strings.ToLower(SchemaClass)+":owner":
+			return p.SetOwner(ctx, classID, nil)
+		}
+	default:
+		return BadRequestError{"invalidSyntax", "unsupported operation " + op.Op}
+	}
+	return invalidPath("unsupported path " + op.Path)
+}
+
+func rename(ctx context.Context, p *classsvc.Provisioning, classID uuid.UUID, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return invalidValue("displayName is required")
+	}
+	return p.RenameClass(ctx, classID, name)
+}
+
+// addMembers enrolls users as students like JoinClass does, putting them on the waitlist if the
+// class is full. Users already enrolled or waitlisted are left as they are.
+func addMembers(ctx context.Context, p *classsvc.Provisioning, classID uuid.UUID, members []Member) error {
+	for _, m := range members {
+		err := p.Enroll(ctx, classID, m.Value, models.UserRoleStudent)
+		if err != nil && err != classsvc.ErrWaitlisted {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeMembers un-enrolls users like LeaveClass does.
+func removeMembers(ctx context.Context, p *classsvc.Provisioning, classID uuid.UUID, userIDs []uuid.UUID) error {
+	for _, userID := range userIDs {
+		if err := p.Remove(ctx, classID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeMatching removes the members matching a member-scope condition. It fails with
+// noTarget if there are none.
+func removeMatching(ctx context.Context, tx *sql.Tx, p *classsvc.Provisioning, classID uuid.UUID, cond string, args []interface{}) error {
+	if args == nil {
+		args = []interface{}{classID}
+	}
+	rows, err := tx.Query("SELECT m.user_id FROM members m WHERE m.class_id = $1 AND "+cond+";", args...)
+	if err != nil {
+		return err
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if len(ids) == 0 {
+		return BadRequestError{"noTarget", "no members matched"}
+	}
+	return removeMembers(ctx, p, classID, ids)
+}
+
+// replaceMembers makes members the exact membership of a class. The owner must be kept.
+func replaceMembers(ctx context.Context, tx *sql.Tx, p *classsvc.Provisioning, classID uuid.UUID, members []Member) error {
+	keep := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		keep[m.Value] = true
+	}
+	current, err := models.MembersByClassID(tx, classID)
+	if err != nil {
+		return err
+	}
+	var remove []uuid.UUID
+	for _, m := range current {
+		if !keep[m.UserID] {
+			remove = append(remove, m.UserID)
+		}
+	}
+	if err := removeMembers(ctx, p, classID, remove); err != nil {
+		return err
+	}
+	return addMembers(ctx, p, classID, members)
+}
+
+func activeClass(db models.XODB, id uuid.UUID) (*models.Class, error) {
+	class, err := models.ClassByID(db, id)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+	if !class.Active {
+		return nil, ErrNotFound
+	}
+	return class, nil
+}
+
+func group(db models.XODB, id uuid.UUID) (*Group, error) {
+	class, err := activeClass(db, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := models.MembersByClassID(db, id)
+	if err != nil {
+		return nil, err
+	}
+	g := newGroup()
+	g.ID = class.ID
+	g.DisplayName = class.Name
+	for _, m := range members {
+		g.addMember(m.UserID, m.Owner)
+	}
+	return g, nil
+}
+
+func newGroup() *Group {
+	return &Group{
+		Schemas: []string{SchemaGroup, SchemaClass},
+		Members: []Member{},
+		Class:   &ClassExtension{},
+		Meta:    &Meta{ResourceType: "Group"},
+	}
+}
+
+func (g *Group) addMember(userID uuid.UUID, owner bool) {
+	g.Members = append(g.Members, Member{Value: userID, Type: "User"})
+	if owner {
+		id := userID
+		g.Class.Owner = &id
+	}
+}
@@ -0,0 +1,266 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/introspector"
+)
+
+const (
+	// defaultCount is the page size used when a query does not set count.
+	defaultCount = 100
+	// maxCount limits the page size of queries.
+	maxCount = 1000
+)
+
+type contextKey int
+
+const baseURLContextKey contextKey = iota
+
+func MakeHTTPHandler(s Service, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorLogger(logger),
+		httptransport.ServerErrorEncoder(encodeError),
+		httptransport.ServerBefore(introspector.ToHTTPContext(), baseURLToContext),
+	}
+	scoped := introspector.New(introspection, "classes.scim")
+
+	// GET /scim/v2/Groups
+	// List classes, filtered with the filter parameter and paged with startIndex and count.
+	r.Methods("GET").Path("/scim/v2/Groups").Handler(httptransport.NewServer(
+		scoped(e.ListGroupsEndpoint),
+		DecodeListGroupsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// POST /scim/v2/Groups
+	// Create a class.
+	r.Methods("POST").Path("/scim/v2/Groups").Handler(httptransport.NewServer(
+		scoped(e.CreateGroupEndpoint),
+		DecodeCreateGroupRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// GET /scim/v2/Groups/:id
+	// Get a class and its members.
+	r.Methods("GET").Path("/scim/v2/Groups/{id}").Handler(httptransport.NewServer(
+		scoped(e.GetGroupEndpoint),
+		DecodeGetGroupRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// PATCH /scim/v2/Groups/:id
+	// Rename a class, add or remove members, or set its owner.
+	r.Methods("PATCH").Path("/scim/v2/Groups/{id}").Handler(httptransport.NewServer(
+		scoped(e.PatchGroupEndpoint),
+		DecodePatchGroupRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// DELETE /scim/v2/Groups/:id
+	// Deactivate a class.
+	r.Methods("DELETE").Path("/scim/v2/Groups/{id}").Handler(httptransport.NewServer(
+		scoped(e.DeleteGroupEndpoint),
+		DecodeDeleteGroupRequest,
+		encodeResponse,
+		options...,
+	))
+
+	return r
+}
+
+// baseURLToContext stores the URL of the SCIM service root, which group locations are relative to.
+func baseURLToContext(ctx context.Context, r *http.Request) context.Context {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return context.WithValue(ctx, baseURLContextKey, scheme+"://"+r.Host+"/scim/v2")
+}
+
+func DecodeListGroupsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	query := Query{
+		Filter:     q.Get("filter"),
+		StartIndex: 1,
+		Count:      defaultCount,
+	}
+	if v := q.Get("startIndex"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidValue("startIndex must be an integer")
+		}
+		query.StartIndex = i
+	}
+	if v := q.Get("count"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidValue("count must be an integer")
+		}
+		query.Count = i
+	}
+	if query.Count > maxCount {
+		query.Count = maxCount
+	}
+	for _, attr := range strings.Split(q.Get("excludedAttributes"), ",") {
+		if attribute(strings.TrimSpace(attr)) == "members" {
+			query.ExcludeMembers = true
+		}
+	}
+	return listGroupsRequest{query}, nil
+}
+
+func DecodeGetGroupRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := groupID(r)
+	if err != nil {
+		return nil, err
+	}
+	return getGroupRequest{id}, nil
+}
+
+func DecodeCreateGroupRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var g Group
+	if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+		return nil, BadRequestError{"invalidSyntax", "request body is not a Group"}
+	}
+	if !hasSchema(g.Schemas, SchemaGroup) {
+		return nil, BadRequestError{"invalidSyntax", "schemas must include " + SchemaGroup}
+	}
+	return createGroupRequest{g}, nil
+}
+
+func DecodePatchGroupRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := groupID(r)
+	if err != nil {
+		return nil, err
+	}
+	var op PatchOp
+	if err := json.NewDecoder(r.Body).Decode(&op); err != nil {
+		return nil, BadRequestError{"invalidSyntax", "request body is not a PatchOp"}
+	}
+	if !hasSchema(op.Schemas, SchemaPatchOp) {
+		return nil, BadRequestError{"invalidSyntax", "schemas must include " + SchemaPatchOp}
+	}
+	return patchGroupRequest{id, op.Operations}, nil
+}
+
+func DecodeDeleteGroupRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := groupID(r)
+	if err != nil {
+		return nil, err
+	}
+	return deleteGroupRequest{id}, nil
+}
+
+// groupID reads the group ID from the path. IDs that are not UUIDs cannot name a group.
+func groupID(r *http.Request) (uuid.UUID, error) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		return uuid.Nil, ErrNotFound
+	}
+	return id, nil
+}
+
+func hasSchema(schemas []string, schema string) bool {
+	for _, s := range schemas {
+		if isSchema(s, schema) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorer is implemented by all concrete response types that may contain
+// errors.
+type errorer interface {
+	error() error
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if e, ok := response.(errorer); ok && e.error() != nil {
+		encodeError(ctx, e.error(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", MediaType)
+	switch resp := response.(type) {
+	case listGroupsResponse:
+		return json.NewEncoder(w).Encode(resp.List)
+	case createGroupResponse:
+		w.Header().Set("Location", resp.Group.Meta.Location)
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(resp.Group)
+	case groupResponse:
+		w.Header().Set("Location", resp.Group.Meta.Location)
+		return json.NewEncoder(w).Encode(resp.Group)
+	case deleteGroupResponse:
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	default:
+		return json.NewEncoder(w).Encode(response)
+	}
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	code := codeFrom(err)
+	body := struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		ScimType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail"`
+	}{
+		Schemas: []string{SchemaError},
+		Status:  strconv.Itoa(code),
+		Detail:  err.Error(),
+	}
+	switch err := err.(type) {
+	case BadRequestError:
+		body.ScimType = err.ScimType
+	}
+	if err == ErrMustSetOwner || err == classsvc.ErrMustSetOwner {
+		body.ScimType = "mutability"
+	}
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+func codeFrom(err error) int {
+	switch err.(type) {
+	case BadRequestError:
+		return http.StatusBadRequest
+	}
+	switch err {
+	case ErrNotFound, classsvc.ErrNotFound:
+		return http.StatusNotFound
+	case ErrMustSetOwner, classsvc.ErrMustSetOwner:
+		return http.StatusBadRequest
+	case classsvc.ErrRosterLocked, classsvc.ErrEnrollmentClosed, classsvc.ErrClassFull:
+		return http.StatusConflict
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
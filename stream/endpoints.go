@@ -0,0 +1,40 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+// Endpoints collects all of the endpoints that compose the stream service. Events themselves
+// are streamed by the HTTP handler once the request is authorized.
+type Endpoints struct {
+	AuthorizeEndpoint endpoint.Endpoint
+}
+
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		AuthorizeEndpoint: MakeAuthorizeEndpoint(s),
+	}
+}
+
+func MakeAuthorizeEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(authorizeRequest)
+		e := s.Authorize(ctx, req.ClassID)
+		return authorizeResponse{e}, nil
+	}
+}
+
+type authorizeRequest struct {
+	ClassID uuid.UUID
+}
+
+type authorizeResponse struct {
+	Error error
+}
+
+func (r authorizeResponse) error() error {
+	return r.Error
+}
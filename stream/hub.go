@@ -0,0 +1,119 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Hub listens for committed events and wakes the streams of their classes. It also prunes
+// events once they are older than Retention.
+type Hub struct {
+	db  *sql.DB
+	dsn string
+
+	// Retention is how long events are kept for clients to resume from.
+	Retention time.Duration
+
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[chan struct{}]bool
+}
+
+// NewHub returns a Hub that listens on the Postgres database at dsn, which must be the database
+// behind db.
+func NewHub(db *sql.DB, dsn string) *Hub {
+	return &Hub{
+		db:        db,
+		dsn:       dsn,
+		Retention: 24 * time.Hour,
+		subs:      make(map[uuid.UUID]map[chan struct{}]bool),
+	}
+}
+
+// Subscribe returns a channel that receives a value whenever events of a class may have been
+// committed. Wake-ups are coalesced, so readers must read every event after the last one they
+// saw. cancel releases the subscription.
+func (h *Hub) Subscribe(classID uuid.UUID) (wake <-chan struct{}, cancel func()) {
+	c := make(chan struct{}, 1)
+	h.mu.Lock()
+	if h.subs[classID] == nil {
+		h.subs[classID] = make(map[chan struct{}]bool)
+	}
+	h.subs[classID][c] = true
+	h.mu.Unlock()
+	return c, func() {
+		h.mu.Lock()
+		delete(h.subs[classID], c)
+		if len(h.subs[classID]) == 0 {
+			delete(h.subs, classID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// wake wakes the streams of a class, or of every class if classID is nil.
+func (h *Hub) wake(classID *uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id, subs := range h.subs {
+		if classID != nil && id != *classID {
+			continue
+		}
+		for c := range subs {
+			select {
+			case c <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Run listens for events until ctx is done.
+func (h *Hub) Run(ctx context.Context, logger log.Logger) {
+	listener := pq.NewListener(h.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Log("error", err)
+		}
+		if ev == pq.ListenerEventReconnected {
+			// Notifications may have been missed while disconnected.
+			h.wake(nil)
+		}
+	})
+	defer listener.Close()
+	if err := listener.Listen(channel); err != nil {
+		logger.Log("msg", "could not listen for class events", "error", err)
+	}
+
+	prune := time.NewTicker(10 * time.Minute)
+	defer prune.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			if n == nil {
+				h.wake(nil)
+				continue
+			}
+			classID, err := uuid.Parse(n.Extra)
+			if err != nil {
+				continue
+			}
+			h.wake(&classID)
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		case <-prune.C:
+			n, err := Prune(ctx, h.db, h.Retention)
+			if err != nil {
+				logger.Log("error", err)
+			} else if n > 0 {
+				logger.Log("pruned", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+var (
+	ErrUnauthorized = errors.New("token invalid or not found")
+	ErrNotFound     = errors.New("class not found or user is not allowed to access it")
+)
+
+// Service reads the event streams of classes. Only members of a class may follow its stream.
+type Service interface {
+	// Authorize checks that the current user may follow the stream of a class.
+	Authorize(ctx context.Context, classID uuid.UUID) error
+	// Resume returns the ID after which to continue a stream. lastEventID is the ID of the last
+	// event the client saw, or 0 for a new stream, which starts after the newest event. ok is
+	// false if the client missed events that are no longer kept, or sent an unknown ID; it
+	// should reload the class before following the stream again.
+	Resume(ctx context.Context, classID uuid.UUID, lastEventID int64) (after int64, ok bool, err error)
+	// Events gets up to limit events of a class after the event with ID after, oldest first.
+	Events(ctx context.Context, classID uuid.UUID, after int64, limit int) ([]*Event, error)
+}
+
+type postgresService struct {
+	*sql.DB
+}
+
+func New(db *sql.DB) Service {
+	return &postgresService{db}
+}
+
+func (s *postgresService) Authorize(ctx context.Context, classID uuid.UUID) error {
+	subj, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
+	if !ok {
+		return ErrUnauthorized
+	}
+	_, err := models.MemberByUserIDClassID(s, subj, classID)
+	switch err {
+	case nil:
+		return nil
+	case sql.ErrNoRows:
+		return ErrNotFound
+	default:
+		return err
+	}
+}
+
+func (s *postgresService) Resume(ctx context.Context, classID uuid.UUID, lastEventID int64) (int64, bool, error) {
+	if err := s.Authorize(ctx, classID); err != nil {
+		return 0, false, err
+	}
+	if lastEventID > 0 {
+		var exists bool
+		err := s.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM class_events WHERE id = $1 AND class_id = $2);",
+			lastEventID, classID).Scan(&exists)
+		if err != nil {
+			return 0, false, err
+		}
+		if exists {
+			return lastEventID, true, nil
+		}
+	}
+	var newest int64
+	err := s.QueryRowContext(ctx, "SELECT COALESCE(max(id), 0) FROM class_events WHERE class_id = $1;", classID).Scan(&newest)
+	if err != nil {
+		return 0, false, err
+	}
+	return newest, lastEventID == 0, nil
+}
+
+func (s *postgresService) Events(ctx context.Context, classID uuid.UUID, after int64, limit int) ([]*Event, error) {
+	// Members who leave or are removed stop receiving events.
+	if err := s.Authorize(ctx, classID); err != nil {
+		return nil, err
+	}
+	rows, err := s.QueryContext(ctx, "SELECT id, type, payload FROM class_events WHERE class_id = $1 AND id > $2 "+
+		"ORDER BY id LIMIT $3;", classID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []*Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.Type, &e.Payload); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,47 @@
+// Package stream pushes the changes of a class to its members in real time, as Server-Sent
+// Events.
+//
+// Changes are written to class_events in the transaction that makes them, and Postgres
+// notifies every host once they commit. Every change to a class locks the class, so the events
+// of a class commit in ID order, and clients resume after the ID of the last event they saw.
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// channel is the Postgres notification channel on which the IDs of changed classes are sent.
+const channel = "class_events"
+
+// Event is an event in the stream of a class.
+type Event struct {
+	ID int64
+	// Type is the name of the event type, such as "member.joined".
+	Type string
+	// Payload is the CloudEvent, encoded as JSON.
+	Payload []byte
+}
+
+// Write adds an event to the stream of a class as part of tx. Followers are notified when tx
+// commits.
+func Write(tx *sql.Tx, classID uuid.UUID, typ string, payload []byte) error {
+	_, err := tx.Exec("INSERT INTO class_events (class_id, type, payload) VALUES ($1, $2, $3);", classID, typ, payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("SELECT pg_notify($1, $2);", channel, classID.String())
+	return err
+}
+
+// Prune deletes events older than retention and returns how many were deleted.
+func Prune(ctx context.Context, db *sql.DB, retention time.Duration) (int64, error) {
+	res, err := db.ExecContext(ctx, "DELETE FROM class_events WHERE created_at < $1;", time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
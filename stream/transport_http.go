@@ -0,0 +1,195 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/introspector"
+)
+
+var ErrBadRequest = errors.New("the request is malformed or invalid")
+
+const (
+	// batchSize limits the number of events read at once.
+	batchSize = 100
+	// heartbeat is the interval of comments sent to keep idle connections open.
+	heartbeat = 15 * time.Second
+	// retry is the reconnection delay suggested to clients, in milliseconds.
+	retry = 3000
+)
+
+// MakeHTTPHandler serves the event stream of a class at /classes/{classID}/stream.
+func MakeHTTPHandler(s Service, hub *Hub, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+
+	// GET /classes/:classID/stream
+	// Follow the joins, leaves, role changes and updates of a class as Server-Sent Events.
+	// Reconnecting clients resume after Last-Event-ID, or the last_event_id query parameter.
+	r.Methods("GET").Path("/classes/{classID}/stream").Handler(&streamHandler{
+		s:         s,
+		hub:       hub,
+		authorize: introspector.New(introspection, "classes.members.list")(e.AuthorizeEndpoint),
+		logger:    logger,
+	})
+
+	return classsvc.WithCorrelationID(r, logger)
+}
+
+type streamHandler struct {
+	s         Service
+	hub       *Hub
+	authorize endpoint.Endpoint
+	logger    log.Logger
+}
+
+func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := introspector.ToHTTPContext()(r.Context(), r)
+	classID, err := uuid.Parse(mux.Vars(r)["classID"])
+	if err != nil {
+		encodeError(ctx, ErrBadRequest, w)
+		return
+	}
+	lastEventID, err := DecodeLastEventID(r)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	response, err := h.authorize(ctx, authorizeRequest{classID})
+	if err == nil {
+		err = response.(authorizeResponse).error()
+	}
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		encodeError(ctx, errors.New("streaming is not supported"), w)
+		return
+	}
+
+	// Subscribe before reading, so that no event committed in between is missed.
+	wake, cancel := h.hub.Subscribe(classID)
+	defer cancel()
+	after, resumed, err := h.s.Resume(ctx, classID, lastEventID)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprintf(w, "retry: %d\n\n", retry)
+	if !resumed {
+		// The client missed events; it should reload the class and follow from here.
+		fmt.Fprintf(w, "id: %d\nevent: reset\ndata: {}\n\n", after)
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		events, err := h.s.Events(ctx, classID, after, batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				if err != ErrNotFound {
+					h.logger.Log("class", classID, "correlation_id", classsvc.CorrelationID(ctx), "error", err)
+				}
+				fmt.Fprintf(w, "event: close\ndata: %s\n\n", errorData(ctx, err))
+				flusher.Flush()
+			}
+			return
+		}
+		for _, e := range events {
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Payload)
+			after = e.ID
+		}
+		if len(events) > 0 {
+			flusher.Flush()
+		}
+		if len(events) == batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// DecodeLastEventID gets the ID of the last event a client saw, which browsers send in the
+// Last-Event-ID header when they reconnect. Clients that cannot set headers may use the
+// last_event_id query parameter instead. It returns 0 for new streams.
+func DecodeLastEventID(r *http.Request) (int64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 0 {
+		return 0, ErrBadRequest
+	}
+	return id, nil
+}
+
+// errorData describes err in the close event of a stream, as a problem like the one the stream
+// would have been refused with.
+func errorData(ctx context.Context, err error) []byte {
+	data, _ := json.Marshal(problems.Problem(ctx, err))
+	return data
+}
+
+// problems answers errors as problem+json, like the class service does.
+var problems = classsvc.Problems{Code: errorCode, Status: codeFrom}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	problems.EncodeError(ctx, err, w)
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	case ErrBadRequest:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorCode gives the stable code of err, which clients can match on.
+func errorCode(err error) string {
+	switch err {
+	case ErrNotFound:
+		return "not_found"
+	case ErrUnauthorized:
+		return "unauthorized"
+	case ErrBadRequest:
+		return "bad_request"
+	default:
+		return ""
+	}
+}
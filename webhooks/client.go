@@ -0,0 +1,148 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errPrivateAddress is returned when a webhook URL points at an address that is not public.
+var errPrivateAddress = errors.New("webhook host is not a public address")
+
+// privateNetworks are the networks, besides loopback, link-local and multicast ones, that
+// webhooks may not be delivered to.
+var privateNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+	"fc00::/7",
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = n
+	}
+	return networks
+}
+
+// isPublic reports whether ip is a public unicast address.
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialPublic refuses connections to addresses that are not public. It runs after the host has
+// been resolved, so a webhook cannot reach the internal network through a name it controls.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// NewClient returns a client that delivers to public addresses only, does not use a proxy and
+// does not follow redirects, so that webhooks cannot be used to reach the internal network.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialPublic,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// responseError is returned when a webhook responds with a status other than 2xx.
+type responseError struct {
+	status string
+}
+
+func (e responseError) Error() string {
+	return fmt.Sprintf("webhook responded %s", e.status)
+}
+
+// describe returns the reason a delivery failed, as shown in the delivery log. Transport errors
+// are summarized, as their details describe the network the service runs in.
+func describe(err error) string {
+	switch err := err.(type) {
+	case responseError:
+		return err.Error()
+	case *url.Error:
+		if err.Timeout() {
+			return "webhook did not respond in time"
+		}
+		if op, ok := err.Err.(*net.OpError); ok && op.Err == errPrivateAddress {
+			return errPrivateAddress.Error()
+		}
+	}
+	return "could not connect to webhook"
+}
+
+// validURL checks and normalizes a webhook URL. Only https URLs to hosts that are not obviously
+// internal are accepted unless insecure is set; the address a host resolves to is checked when
+// deliveries are sent.
+func validURL(raw string, insecure bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) > maxURLLength {
+		return "", ErrInvalidURL
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return "", ErrInvalidURL
+	}
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && insecure:
+	default:
+		return "", ErrInvalidURL
+	}
+	if !insecure {
+		host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return "", ErrInvalidURL
+		}
+		if ip := net.ParseIP(host); ip != nil && !isPublic(ip) {
+			return "", ErrInvalidURL
+		}
+	}
+	return u.String(), nil
+}
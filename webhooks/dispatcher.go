@@ -0,0 +1,193 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/studiously/classsvc/eventbus"
+)
+
+// Report counts what a Dispatcher did in one pass.
+type Report struct {
+	Delivered int
+	Failed    int
+	GaveUp    int
+}
+
+// Dispatcher sends queued deliveries to webhooks.
+type Dispatcher struct {
+	db *sql.DB
+
+	// Client sends deliveries. Its timeout bounds every attempt. The default client only
+	// delivers to public addresses; see NewClient.
+	Client *http.Client
+	// MaxAttempts is the number of times a delivery is tried before it is marked failed.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the delay before a delivery is retried, which doubles
+	// with every failed attempt.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// BatchSize limits the number of deliveries sent per pass.
+	BatchSize int
+	// Lease is how long a delivery is reserved for the dispatcher sending it. It must be longer
+	// than the client timeout.
+	Lease time.Duration
+}
+
+// NewDispatcher returns a Dispatcher with default limits.
+func NewDispatcher(db *sql.DB) *Dispatcher {
+	return &Dispatcher{
+		db:          db,
+		Client:      NewClient(10 * time.Second),
+		MaxAttempts: 8,
+		MinBackoff:  10 * time.Second,
+		MaxBackoff:  time.Hour,
+		BatchSize:   50,
+		Lease:       time.Minute,
+	}
+}
+
+type delivery struct {
+	id       int64
+	url      string
+	secret   string
+	typ      string
+	payload  []byte
+	attempts int
+}
+
+// Dispatch makes one pass over the deliveries that are due. Several dispatchers may run at
+// once; each delivery is leased by one of them while it is sent.
+func (d *Dispatcher) Dispatch(ctx context.Context) (Report, error) {
+	var report Report
+	rows, err := d.db.QueryContext(ctx, "UPDATE webhook_deliveries wd SET next_attempt_at = now() + $2::bigint * interval '1 millisecond' "+
+		"FROM webhooks w WHERE w.id = wd.webhook_id AND wd.id IN ("+
+		"SELECT id FROM webhook_deliveries WHERE status = $3 AND next_attempt_at <= now() "+
+		"ORDER BY next_attempt_at LIMIT $1 FOR UPDATE SKIP LOCKED) "+
+		"RETURNING wd.id, w.url, w.secret, wd.event_type, wd.payload, wd.attempts;",
+		d.BatchSize, int64(d.Lease/time.Millisecond), StatusPending)
+	if err != nil {
+		return report, err
+	}
+	var due []delivery
+	for rows.Next() {
+		var dv delivery
+		if err := rows.Scan(&dv.id, &dv.url, &dv.secret, &dv.typ, &dv.payload, &dv.attempts); err != nil {
+			rows.Close()
+			return report, err
+		}
+		due = append(due, dv)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return report, err
+	}
+	for _, dv := range due {
+		if err := d.deliver(ctx, dv, &report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// deliver sends a delivery and records the outcome.
+func (d *Dispatcher) deliver(ctx context.Context, dv delivery, report *Report) error {
+	status, sendErr := d.send(ctx, dv)
+	var code *int
+	if status != 0 {
+		code = &status
+	}
+	dv.attempts++
+	if sendErr == nil {
+		report.Delivered++
+		_, err := d.db.ExecContext(ctx, "UPDATE webhook_deliveries SET status = $2, attempts = $3, response_status = $4, "+
+			"last_error = NULL, delivered_at = now() WHERE id = $1;", dv.id, StatusDelivered, dv.attempts, code)
+		return err
+	}
+	if dv.attempts >= d.MaxAttempts {
+		report.GaveUp++
+		_, err := d.db.ExecContext(ctx, "UPDATE webhook_deliveries SET status = $2, attempts = $3, response_status = $4, "+
+			"last_error = $5 WHERE id = $1;", dv.id, StatusFailed, dv.attempts, code, describe(sendErr))
+		return err
+	}
+	report.Failed++
+	_, err := d.db.ExecContext(ctx, "UPDATE webhook_deliveries SET attempts = $2, response_status = $3, last_error = $4, "+
+		"next_attempt_at = $5 WHERE id = $1;", dv.id, dv.attempts, code, describe(sendErr), time.Now().Add(d.backoff(dv.attempts)))
+	return err
+}
+
+// send posts a delivery and returns the response status, if there was a response. Any status
+// other than 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, dv delivery) (int, error) {
+	req, err := http.NewRequest("POST", dv.url, bytes.NewReader(dv.payload))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", eventbus.ContentType)
+	req.Header.Set("User-Agent", "classsvc-webhooks")
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(dv.id, 10))
+	req.Header.Set(HeaderEvent, dv.typ)
+	req.Header.Set(HeaderSignature, Sign(dv.secret, time.Now(), dv.payload))
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, responseError{resp.Status}
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	b := d.MinBackoff
+	for i := 1; i < attempts && b < d.MaxBackoff; i++ {
+		b *= 2
+	}
+	if b > d.MaxBackoff {
+		b = d.MaxBackoff
+	}
+	return b
+}
+
+// Run dispatches deliveries every interval until ctx is done. A pass that delivers anything is
+// followed immediately by another.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration, logger log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := d.Dispatch(ctx)
+		if err != nil {
+			logger.Log("error", err)
+		} else if report.Delivered+report.Failed+report.GaveUp > 0 {
+			logger.Log(
+				"delivered", report.Delivered,
+				"failed", report.Failed,
+				"gave_up", report.GaveUp,
+			)
+		}
+		if err == nil && report.Delivered > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				continue
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+// Endpoints collects all of the endpoints that compose the webhooks service.
+type Endpoints struct {
+	ListWebhooksEndpoint   endpoint.Endpoint
+	CreateWebhookEndpoint  endpoint.Endpoint
+	GetWebhookEndpoint     endpoint.Endpoint
+	DeleteWebhookEndpoint  endpoint.Endpoint
+	ListDeliveriesEndpoint endpoint.Endpoint
+	RedeliverEndpoint      endpoint.Endpoint
+}
+
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		ListWebhooksEndpoint:   MakeListWebhooksEndpoint(s),
+		CreateWebhookEndpoint:  MakeCreateWebhookEndpoint(s),
+		GetWebhookEndpoint:     MakeGetWebhookEndpoint(s),
+		DeleteWebhookEndpoint:  MakeDeleteWebhookEndpoint(s),
+		ListDeliveriesEndpoint: MakeListDeliveriesEndpoint(s),
+		RedeliverEndpoint:      MakeRedeliverEndpoint(s),
+	}
+}
+
+func MakeListWebhooksEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(listWebhooksRequest)
+		webhooks, e := s.ListWebhooks(ctx, req.ClassID)
+		return listWebhooksResponse{webhooks, e}, nil
+	}
+}
+
+type listWebhooksRequest struct {
+	// ClassID is nil for organization-wide webhooks.
+	ClassID *uuid.UUID
+}
+
+type listWebhooksResponse struct {
+	Webhooks []*models.Webhook
+	Error    error
+}
+
+func (r listWebhooksResponse) error() error {
+	return r.Error
+}
+
+func MakeCreateWebhookEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createWebhookRequest)
+		w, e := s.CreateWebhook(ctx, req.ClassID, req.Subscription)
+		return createWebhookResponse{w, e}, nil
+	}
+}
+
+type createWebhookRequest struct {
+	// ClassID is nil for organization-wide webhooks.
+	ClassID      *uuid.UUID
+	Subscription Subscription
+}
+
+// createWebhookResponse is written with 201 Created.
+type createWebhookResponse struct {
+	Webhook *models.Webhook
+	Error   error
+}
+
+func (r createWebhookResponse) error() error {
+	return r.Error
+}
+
+func MakeGetWebhookEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getWebhookRequest)
+		w, e := s.GetWebhook(ctx, req.WebhookID)
+		return getWebhookResponse{w, e}, nil
+	}
+}
+
+type getWebhookRequest struct {
+	WebhookID uuid.UUID
+}
+
+type getWebhookResponse struct {
+	Webhook *models.Webhook
+	Error   error
+}
+
+func (r getWebhookResponse) error() error {
+	return r.Error
+}
+
+func MakeDeleteWebhookEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(deleteWebhookRequest)
+		e := s.DeleteWebhook(ctx, req.WebhookID)
+		return deleteWebhookResponse{e}, nil
+	}
+}
+
+type deleteWebhookRequest struct {
+	WebhookID uuid.UUID
+}
+
+type deleteWebhookResponse struct {
+	Error error
+}
+
+func (r deleteWebhookResponse) error() error {
+	return r.Error
+}
+
+func MakeListDeliveriesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(listDeliveriesRequest)
+		page, e := s.ListDeliveries(ctx, req.WebhookID, req.Query)
+		return listDeliveriesResponse{page, e}, nil
+	}
+}
+
+type listDeliveriesRequest struct {
+	WebhookID uuid.UUID
+	Query     Query
+}
+
+type listDeliveriesResponse struct {
+	Page  *DeliveryPage
+	Error error
+}
+
+func (r listDeliveriesResponse) error() error {
+	return r.Error
+}
+
+func MakeRedeliverEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(redeliverRequest)
+		d, e := s.Redeliver(ctx, req.WebhookID, req.DeliveryID)
+		return redeliverResponse{d, e}, nil
+	}
+}
+
+type redeliverRequest struct {
+	WebhookID  uuid.UUID
+	DeliveryID int64
+}
+
+// redeliverResponse is written with 202 Accepted.
+type redeliverResponse struct {
+	Delivery *models.WebhookDelivery
+	Error    error
+}
+
+func (r redeliverResponse) error() error {
+	return r.Error
+}
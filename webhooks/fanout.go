@@ -0,0 +1,128 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/studiously/classsvc/eventbus"
+)
+
+// Subjects lists the bus subjects whose events are delivered to webhooks.
+var Subjects = []string{"classes.>"}
+
+// versionSuffix matches the version at the end of CloudEvents types.
+var versionSuffix = regexp.MustCompile(`\.v[0-9]+$`)
+
+// Subscribe queues every event published on Subjects for delivery to the webhooks that
+// subscribe to it. Hosts share a durable subscription, so each event is queued by one of them,
+// and events that could not be queued are retried.
+func Subscribe(bus eventbus.EventBus, db *sql.DB, logger log.Logger) ([]eventbus.Subscription, error) {
+	var subs []eventbus.Subscription
+	for _, subject := range Subjects {
+		sub, err := bus.SubscribeDurable(subject, "webhooks", func(msg eventbus.Message) error {
+			if _, err := eventbus.DecodeCloudEvent(msg.Data, nil); err != nil {
+				// Events that cannot be decoded are dropped, as they would fail again.
+				logger.Log("subject", msg.Subject, "msg", "malformed event", "error", err)
+				return nil
+			}
+			n, err := Enqueue(context.Background(), db, msg.Data)
+			if err != nil {
+				logger.Log("subject", msg.Subject, "error", err)
+				return err
+			}
+			if n > 0 {
+				logger.Log("subject", msg.Subject, "queued", n)
+			}
+			return nil
+		})
+		if err != nil {
+			for _, sub := range subs {
+				sub.Unsubscribe()
+			}
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// Enqueue queues a CloudEvent for delivery to every webhook of its class, and every
+// organization-wide webhook, whose filter matches its type. It returns the number of
+// deliveries queued. An event is queued at most once per webhook.
+func Enqueue(ctx context.Context, db *sql.DB, payload []byte) (int, error) {
+	e, err := eventbus.DecodeCloudEvent(payload, nil)
+	if err != nil {
+		return 0, err
+	}
+	name := eventName(e.Type)
+	rows, err := db.QueryContext(ctx, "SELECT id, events FROM webhooks WHERE class_id IS NULL OR class_id = $1;", classOf(e.Subject))
+	if err != nil {
+		return 0, err
+	}
+	var matched []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		var events pq.StringArray
+		if err := rows.Scan(&id, &events); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if matches(events, name) {
+			matched = append(matched, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	queued := 0
+	for _, id := range matched {
+		res, err := db.ExecContext(ctx, "INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload) "+
+			"VALUES ($1, $2, $3, $4) ON CONFLICT (webhook_id, event_id) DO NOTHING;", id, e.ID, e.Type, payload)
+		if err != nil {
+			return queued, err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			queued++
+		}
+	}
+	return queued, nil
+}
+
+// eventName returns the name filters match against: the CloudEvents type without the prefix
+// and version, such as "member.joined".
+func eventName(t string) string {
+	return versionSuffix.ReplaceAllString(strings.TrimPrefix(t, eventbus.TypePrefix), "")
+}
+
+// classOf returns the class of a CloudEvents subject such as "classes/<id>/announcements/<id>",
+// or nil if the event is not about a class.
+func classOf(subject string) *uuid.UUID {
+	parts := strings.Split(subject, "/")
+	if len(parts) < 2 || parts[0] != "classes" {
+		return nil
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// matches reports whether an event named name passes a webhook's filters.
+func matches(filters []string, name string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if eventbus.Match(f, name) {
+			return true
+		}
+	}
+	return false
+}
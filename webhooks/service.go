@@ -0,0 +1,72 @@
+// Package webhooks delivers class events to partners over HTTP. Webhooks subscribe to the
+// events of one class, or of every class in the organization, and receive each matching
+// CloudEvent as a signed POST. Failed deliveries are retried with exponential backoff and every
+// delivery is kept in a log, from which it can be redelivered.
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/studiously/classsvc/models"
+)
+
+var (
+	ErrUnauthorized  = errors.New("token invalid or not found")
+	ErrNotFound      = errors.New("webhook not found or user is not allowed to access it")
+	ErrForbidden     = errors.New("only class owners and organization admins can manage webhooks")
+	ErrInvalidURL    = errors.New("webhook URL must be an absolute https URL to a public host")
+	ErrInvalidSecret = errors.New("webhook secret must be at least 16 characters")
+	ErrInvalidFilter = errors.New("event filters must be event type names such as member.joined, optionally with * or a final >")
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Subscription describes a webhook to create.
+type Subscription struct {
+	URL string `json:"url"`
+	// Secret signs deliveries. If it is empty, a secret is generated.
+	Secret string `json:"secret"`
+	// Events filters the events delivered by type name, such as "member.joined",
+	// "waitlist.*" or "member.>". An empty filter delivers every event.
+	Events []string `json:"events"`
+}
+
+// Query selects a page of deliveries.
+type Query struct {
+	Offset int
+	Limit  int
+}
+
+// DeliveryPage is a page of the delivery log of a webhook, newest first.
+type DeliveryPage struct {
+	Deliveries []*models.WebhookDelivery `json:"deliveries"`
+	// Total is the number of deliveries across all pages.
+	Total int `json:"total"`
+}
+
+// Service manages webhooks and their delivery logs. Class owners manage the webhooks of their
+// classes; organization admins manage every webhook, including organization-wide ones.
+//
+// Secrets are only returned when a webhook is created.
+type Service interface {
+	// ListWebhooks lists the webhooks of a class, or the organization-wide webhooks if classID is nil.
+	ListWebhooks(ctx context.Context, classID *uuid.UUID) ([]*models.Webhook, error)
+	// CreateWebhook subscribes to the events of a class, or of every class if classID is nil.
+	CreateWebhook(ctx context.Context, classID *uuid.UUID, sub Subscription) (*models.Webhook, error)
+	// GetWebhook gets a webhook.
+	GetWebhook(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error)
+	// DeleteWebhook deletes a webhook and its delivery log.
+	DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error
+	// ListDeliveries gets a page of the delivery log of a webhook.
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, q Query) (*DeliveryPage, error)
+	// Redeliver schedules a delivery to be sent again as soon as possible, with a fresh
+	// allowance of attempts.
+	Redeliver(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (*models.WebhookDelivery, error)
+}
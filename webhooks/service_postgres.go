@@ -0,0 +1,303 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/classsvc/models"
+	"github.com/studiously/introspector"
+)
+
+const (
+	// maxURLLength limits the length of webhook URLs.
+	maxURLLength = 2000
+	// minSecretLength is the shortest secret accepted from clients.
+	minSecretLength = 16
+	// maxFilters limits the number of event filters of a webhook.
+	maxFilters = 50
+)
+
+// filterPattern matches event filters: dot-separated type name tokens or "*", optionally
+// followed by a final ">", or ">" alone.
+var filterPattern = regexp.MustCompile(`^(>|([a-z_]+|\*)(\.([a-z_]+|\*))*(\.>)?)$`)
+
+type postgresService struct {
+	*sql.DB
+	insecure bool
+}
+
+func New(db *sql.DB) Service {
+	return &postgresService{db, false}
+}
+
+// NewInsecure returns a Service that also accepts http URLs and URLs to internal hosts, such as
+// a local "classsvc webhooks stub". It is meant for development only.
+func NewInsecure(db *sql.DB) Service {
+	return &postgresService{db, true}
+}
+
+func (s *postgresService) ListWebhooks(ctx context.Context, classID *uuid.UUID) ([]*models.Webhook, error) {
+	if err := s.authorize(ctx, classID); err != nil {
+		return nil, err
+	}
+	var (
+		webhooks []*models.Webhook
+		err      error
+	)
+	if classID != nil {
+		webhooks, err = models.WebhooksByClassID(s, classID)
+	} else {
+		webhooks, err = organizationWebhooks(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range webhooks {
+		w.Secret = ""
+	}
+	if webhooks == nil {
+		webhooks = []*models.Webhook{}
+	}
+	return webhooks, nil
+}
+
+func (s *postgresService) CreateWebhook(ctx context.Context, classID *uuid.UUID, sub Subscription) (*models.Webhook, error) {
+	if err := s.authorize(ctx, classID); err != nil {
+		return nil, err
+	}
+	subj, _ := ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
+	target, err := validURL(sub.URL, s.insecure)
+	if err != nil {
+		return nil, err
+	}
+	secret := sub.Secret
+	switch {
+	case secret == "":
+		secret, err = newSecret()
+		if err != nil {
+			return nil, err
+		}
+	case len(secret) < minSecretLength:
+		return nil, ErrInvalidSecret
+	}
+	events, err := validFilters(sub.Events)
+	if err != nil {
+		return nil, err
+	}
+	w := &models.Webhook{
+		ID:        uuid.New(),
+		ClassID:   classID,
+		URL:       target,
+		Secret:    secret,
+		Events:    events,
+		CreatedBy: subj,
+		CreatedAt: time.Now(),
+	}
+	err = w.Insert(s)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *postgresService) GetWebhook(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error) {
+	w, err := s.webhook(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	w.Secret = ""
+	return w, nil
+}
+
+func (s *postgresService) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	w, err := s.webhook(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+	return w.Delete(s)
+}
+
+func (s *postgresService) ListDeliveries(ctx context.Context, webhookID uuid.UUID, q Query) (*DeliveryPage, error) {
+	if _, err := s.webhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Limit < 0 {
+		q.Limit = 0
+	}
+	page := &DeliveryPage{Deliveries: []*models.WebhookDelivery{}}
+	err := s.QueryRowContext(ctx, "SELECT count(*) FROM webhook_deliveries WHERE webhook_id = $1;", webhookID).Scan(&page.Total)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.QueryContext(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE webhook_id = $1 "+
+		"ORDER BY id DESC OFFSET $2 LIMIT $3;", webhookID, q.Offset, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Deliveries = append(page.Deliveries, d)
+	}
+	return page, rows.Err()
+}
+
+func (s *postgresService) Redeliver(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (*models.WebhookDelivery, error) {
+	if _, err := s.webhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	row := s.QueryRowContext(ctx, "UPDATE webhook_deliveries SET status = $3, attempts = 0, next_attempt_at = now() "+
+		"WHERE id = $1 AND webhook_id = $2 RETURNING "+deliveryColumns+";", deliveryID, webhookID, StatusPending)
+	d, err := scanDelivery(row)
+	switch err {
+	case nil:
+		return d, nil
+	case sql.ErrNoRows:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// webhook gets a webhook the current user may manage.
+func (s *postgresService) webhook(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error) {
+	w, err := models.WebhookByID(s, webhookID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+	err = s.authorize(ctx, w.ClassID)
+	if err == ErrForbidden {
+		// Do not reveal webhooks to users who cannot manage them.
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// authorize checks that the current user may manage the webhooks of a class, or the
+// organization-wide webhooks if classID is nil. Users outside the class cannot see that it
+// exists.
+func (s *postgresService) authorize(ctx context.Context, classID *uuid.UUID) error {
+	subj, ok := ctx.Value(introspector.SubjectContextKey).(uuid.UUID)
+	if !ok {
+		return ErrUnauthorized
+	}
+	if isAdmin(ctx) {
+		if classID == nil {
+			return nil
+		}
+		_, err := models.ClassByID(s, *classID)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+	if classID == nil {
+		return ErrForbidden
+	}
+	member, err := models.MemberByUserIDClassID(s, subj, *classID)
+	switch {
+	case err == sql.ErrNoRows:
+		return ErrNotFound
+	case err != nil:
+		return err
+	case !member.Owner:
+		return ErrForbidden
+	}
+	return nil
+}
+
+// organizationWebhooks lists the webhooks that are not limited to a class.
+func organizationWebhooks(db models.XODB) ([]*models.Webhook, error) {
+	rows, err := db.Query("SELECT id, class_id, url, secret, events, created_by, created_at " +
+		"FROM webhooks WHERE class_id IS NULL ORDER BY id;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		var w models.Webhook
+		err = rows.Scan(&w.ID, &w.ClassID, &w.URL, &w.Secret, &w.Events, &w.CreatedBy, &w.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &w)
+	}
+	return webhooks, rows.Err()
+}
+
+func isAdmin(ctx context.Context) bool {
+	introspection, ok := ctx.Value(introspector.OAuth2IntrospectionContextKey).(oauth2.Introspection)
+	if !ok {
+		return false
+	}
+	for _, scope := range strings.Fields(introspection.Scope) {
+		if scope == classsvc.AdminScope {
+			return true
+		}
+	}
+	return false
+}
+
+func validFilters(filters []string) (pq.StringArray, error) {
+	if len(filters) > maxFilters {
+		return nil, ErrInvalidFilter
+	}
+	events := pq.StringArray{}
+	for _, f := range filters {
+		f = strings.TrimSpace(f)
+		if !filterPattern.MatchString(f) {
+			return nil, ErrInvalidFilter
+		}
+		events = append(events, f)
+	}
+	return events, nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// deliveryColumns lists the columns of webhook_deliveries in the order scanDelivery reads them.
+const deliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, " +
+	"response_status, last_error, created_at, delivered_at"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDelivery(row scanner) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/studiously/classsvc/eventbus"
+)
+
+// Headers sent with every delivery.
+const (
+	// HeaderSignature carries the signature of a delivery, as "t=<unix time>,v1=<hex HMAC>".
+	HeaderSignature = "X-Classsvc-Signature"
+	// HeaderDelivery carries the ID of the delivery, which is the same for every attempt.
+	HeaderDelivery = "X-Classsvc-Delivery"
+	// HeaderEvent carries the CloudEvents type of the delivered event.
+	HeaderEvent = "X-Classsvc-Event"
+)
+
+var ErrInvalidSignature = errors.New("webhook signature is missing, invalid or too old")
+
+// Sign returns the signature header of a delivery of body sent at t. The signature is the
+// HMAC-SHA256, keyed with the webhook secret, of the Unix time t, a period and the body.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+// Verify checks the signature header of a delivery of body. Signatures older than tolerance
+// are rejected to prevent replays; a zero tolerance accepts any age.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			sigs = append(sigs, kv[1])
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		age := time.Since(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrInvalidSignature
+		}
+	}
+	expected := mac(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Receiver returns a handler that accepts deliveries signed with secret and passes their
+// events to handle. It answers 204 when handle succeeds, 401 for bad signatures, 400 for
+// payloads that are not CloudEvents and 500 when handle fails, which causes a retry. It serves
+// as a local stub for testing webhooks, and as a starting point for receivers written in Go.
+func Receiver(secret string, tolerance time.Duration, handle func(delivery string, e *eventbus.CloudEvent) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := Verify(secret, r.Header.Get(HeaderSignature), body, tolerance); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		e, err := eventbus.DecodeCloudEvent(body, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := handle(r.Header.Get(HeaderDelivery), e); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
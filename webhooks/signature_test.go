@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"class.created"}`)
+	now := time.Now()
+	sig := Sign("secret", now, body)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		header    string
+		body      []byte
+		tolerance time.Duration
+		valid     bool
+	}{
+		{"valid", "secret", sig, body, 5 * time.Minute, true},
+		{"wrong secret", "other", sig, body, 5 * time.Minute, false},
+		{"tampered body", "secret", sig, []byte(`{"type":"class.deleted"}`), 5 * time.Minute, false},
+		{"missing header", "secret", "", body, 5 * time.Minute, false},
+		{"missing timestamp", "secret", "v1=" + mac("secret", ts, body), body, 5 * time.Minute, false},
+		{"malformed timestamp", "secret", "t=now,v1=" + mac("secret", "now", body), body, 0, false},
+		{"missing signature", "secret", "t=" + ts, body, 5 * time.Minute, false},
+		{"one of several signatures", "secret", "t=" + ts + ",v1=deadbeef,v1=" + mac("secret", ts, body), body, 5 * time.Minute, true},
+		{"spaces between parts", "secret", "t=" + ts + ", v1=" + mac("secret", ts, body), body, 5 * time.Minute, true},
+		{"too old", "secret", Sign("secret", now.Add(-10*time.Minute), body), body, 5 * time.Minute, false},
+		{"too far ahead", "secret", Sign("secret", now.Add(10*time.Minute), body), body, 5 * time.Minute, false},
+		{"old without tolerance", "secret", Sign("secret", now.Add(-24*time.Hour), body), body, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.body, tt.tolerance)
+			switch {
+			case tt.valid && err != nil:
+				t.Errorf("Verify(%q) = %v, want nil", tt.header, err)
+			case !tt.valid && err != ErrInvalidSignature:
+				t.Errorf("Verify(%q) = %v, want ErrInvalidSignature", tt.header, err)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	at := time.Unix(1500000000, 0)
+	tests := []struct {
+		secret string
+		body   string
+		want   string
+	}{
+		{"secret", "", "t=1500000000,v1=" + mac("secret", "1500000000", nil)},
+		{"secret", "{}", "t=1500000000,v1=" + mac("secret", "1500000000", []byte("{}"))},
+	}
+	for _, tt := range tests {
+		if got := Sign(tt.secret, at, []byte(tt.body)); got != tt.want {
+			t.Errorf("Sign(%q, %q) = %q, want %q", tt.secret, tt.body, got, tt.want)
+		}
+	}
+	if Sign("a", at, nil) == Sign("b", at, nil) {
+		t.Error("Sign gave the same signature for different secrets")
+	}
+}
@@ -0,0 +1,304 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/ory/hydra/oauth2"
+	"github.com/studiously/classsvc/classsvc"
+	"github.com/studiously/introspector"
+)
+
+var ErrBadRequest = errors.New("the request is malformed or invalid")
+
+const (
+	// defaultLimit is the page size used when a request does not set limit.
+	defaultLimit = 20
+	// maxLimit limits the page size of requests.
+	maxLimit = 100
+)
+
+// MakeHTTPHandler serves class webhooks under /classes/{classID}/webhooks and every webhook,
+// including organization-wide ones, under /webhooks.
+func MakeHTTPHandler(s Service, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	return MakeIdempotentHTTPHandler(s, nil, introspection, logger)
+}
+
+// MakeIdempotentHTTPHandler serves webhooks like MakeHTTPHandler, and serves mutating requests
+// with a classsvc.IdempotencyKeyHeader at most once per key with idempotency, if it is not nil.
+func MakeIdempotentHTTPHandler(s Service, idempotency *classsvc.Idempotency, introspection oauth2.Introspector, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	if idempotency != nil {
+		e = claim(e, idempotency)
+	}
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorLogger(logger),
+		httptransport.ServerErrorEncoder(encodeError),
+		httptransport.ServerBefore(introspector.ToHTTPContext()),
+	}
+
+	// GET /classes/:classID/webhooks
+	r.Methods("GET").Path("/classes/{classID}/webhooks").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.webhooks.list")(e.ListWebhooksEndpoint),
+		DecodeListWebhooksRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// POST /classes/:classID/webhooks
+	// Subscribe to the events of a class. The secret is only returned in the response.
+	r.Methods("POST").Path("/classes/{classID}/webhooks").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.webhooks.create")(e.CreateWebhookEndpoint),
+		DecodeCreateWebhookRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// GET /webhooks
+	// List organization-wide webhooks.
+	r.Methods("GET").Path("/webhooks").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.webhooks.list")(e.ListWebhooksEndpoint),
+		DecodeListWebhooksRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// POST /webhooks
+	// Subscribe to the events of every class.
+	r.Methods("POST").Path("/webhooks").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.webhooks.create")(e.CreateWebhookEndpoint),
+		DecodeCreateWebhookRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// GET /webhooks/:webhookID
+	r.Methods("GET").Path("/webhooks/{webhookID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.webhooks.get")(e.GetWebhookEndpoint),
+		DecodeGetWebhookRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// DELETE /webhooks/:webhookID
+	r.Methods("DELETE").Path("/webhooks/{webhookID}").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.webhooks.delete")(e.DeleteWebhookEndpoint),
+		DecodeDeleteWebhookRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// GET /webhooks/:webhookID/deliveries
+	// List the delivery log, newest first, paged with offset and limit.
+	r.Methods("GET").Path("/webhooks/{webhookID}/deliveries").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.webhooks.deliveries.list")(e.ListDeliveriesEndpoint),
+		DecodeListDeliveriesRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// POST /webhooks/:webhookID/deliveries/:deliveryID/redeliver
+	r.Methods("POST").Path("/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver").Handler(httptransport.NewServer(
+		introspector.New(introspection, "classes.webhooks.deliveries.redeliver")(e.RedeliverEndpoint),
+		DecodeRedeliverRequest,
+		encodeResponse,
+		options...,
+	))
+
+	var h http.Handler = r
+	if idempotency != nil {
+		h = idempotency.Handler(h)
+	}
+	return classsvc.WithCorrelationID(h, logger)
+}
+
+// claim returns e with every endpoint claiming idempotency keys with i.
+func claim(e Endpoints, i *classsvc.Idempotency) Endpoints {
+	return Endpoints{
+		ListWebhooksEndpoint:   i.Claim(e.ListWebhooksEndpoint),
+		CreateWebhookEndpoint:  i.Claim(e.CreateWebhookEndpoint),
+		GetWebhookEndpoint:     i.Claim(e.GetWebhookEndpoint),
+		DeleteWebhookEndpoint:  i.Claim(e.DeleteWebhookEndpoint),
+		ListDeliveriesEndpoint: i.Claim(e.ListDeliveriesEndpoint),
+		RedeliverEndpoint:      i.Claim(e.RedeliverEndpoint),
+	}
+}
+
+func DecodeListWebhooksRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, err := classVar(r)
+	if err != nil {
+		return nil, err
+	}
+	return listWebhooksRequest{classID}, nil
+}
+
+func DecodeCreateWebhookRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	classID, err := classVar(r)
+	if err != nil {
+		return nil, err
+	}
+	req := createWebhookRequest{ClassID: classID}
+	if err := json.NewDecoder(r.Body).Decode(&req.Subscription); err != nil {
+		return nil, ErrBadRequest
+	}
+	return req, nil
+}
+
+func DecodeGetWebhookRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	webhookID, err := webhookVar(r)
+	if err != nil {
+		return nil, err
+	}
+	return getWebhookRequest{webhookID}, nil
+}
+
+func DecodeDeleteWebhookRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	webhookID, err := webhookVar(r)
+	if err != nil {
+		return nil, err
+	}
+	return deleteWebhookRequest{webhookID}, nil
+}
+
+func DecodeListDeliveriesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	webhookID, err := webhookVar(r)
+	if err != nil {
+		return nil, err
+	}
+	q := r.URL.Query()
+	query := Query{Limit: defaultLimit}
+	if v := q.Get("offset"); v != "" {
+		query.Offset, err = strconv.Atoi(v)
+		if err != nil || query.Offset < 0 {
+			return nil, ErrBadRequest
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		query.Limit, err = strconv.Atoi(v)
+		if err != nil || query.Limit < 0 {
+			return nil, ErrBadRequest
+		}
+	}
+	if query.Limit > maxLimit {
+		query.Limit = maxLimit
+	}
+	return listDeliveriesRequest{webhookID, query}, nil
+}
+
+func DecodeRedeliverRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	webhookID, err := webhookVar(r)
+	if err != nil {
+		return nil, err
+	}
+	deliveryID, err := strconv.ParseInt(mux.Vars(r)["deliveryID"], 10, 64)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return redeliverRequest{webhookID, deliveryID}, nil
+}
+
+// classVar gets the class of a request, or nil for organization-wide routes.
+func classVar(r *http.Request) (*uuid.UUID, error) {
+	v, ok := mux.Vars(r)["classID"]
+	if !ok {
+		return nil, nil
+	}
+	classID, err := uuid.Parse(v)
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	return &classID, nil
+}
+
+func webhookVar(r *http.Request) (uuid.UUID, error) {
+	webhookID, err := uuid.Parse(mux.Vars(r)["webhookID"])
+	if err != nil {
+		return uuid.Nil, ErrNotFound
+	}
+	return webhookID, nil
+}
+
+// errorer is implemented by all concrete response types that may contain
+// errors.
+type errorer interface {
+	error() error
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if e, ok := response.(errorer); ok && e.error() != nil {
+		encodeError(ctx, e.error(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch resp := response.(type) {
+	case listWebhooksResponse:
+		return json.NewEncoder(w).Encode(resp.Webhooks)
+	case createWebhookResponse:
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(resp.Webhook)
+	case getWebhookResponse:
+		return json.NewEncoder(w).Encode(resp.Webhook)
+	case deleteWebhookResponse:
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	case listDeliveriesResponse:
+		return json.NewEncoder(w).Encode(resp.Page)
+	case redeliverResponse:
+		w.WriteHeader(http.StatusAccepted)
+		return json.NewEncoder(w).Encode(resp.Delivery)
+	default:
+		return json.NewEncoder(w).Encode(response)
+	}
+}
+
+// problems answers errors as problem+json, like the class service does.
+var problems = classsvc.Problems{Code: errorCode, Status: codeFrom}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	problems.EncodeError(ctx, err, w)
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	case ErrForbidden:
+		return http.StatusForbidden
+	case ErrInvalidURL, ErrInvalidSecret, ErrInvalidFilter, ErrBadRequest:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorCode gives the stable code of err, which clients can match on.
+func errorCode(err error) string {
+	switch err {
+	case ErrNotFound:
+		return "not_found"
+	case ErrUnauthorized:
+		return "unauthorized"
+	case ErrForbidden:
+		return "forbidden"
+	case ErrInvalidURL:
+		return "invalid_url"
+	case ErrInvalidSecret:
+		return "invalid_secret"
+	case ErrInvalidFilter:
+		return "invalid_filter"
+	case ErrBadRequest:
+		return "bad_request"
+	default:
+		return ""
+	}
+}